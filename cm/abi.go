@@ -136,6 +136,21 @@ func PointerToU64[T any](v *T) uint64 { return uint64(uintptr(unsafe.Pointer(v))
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
 func U64ToPointer[T any](v uint64) *T { return (*T)(unsafePointer(uintptr(v))) }
 
+// Pin keeps the value pointed to by p reachable by the garbage collector until [Unpin] is called.
+// Used by generated bindings to retain the results of an exported function until
+// its [post-return] function is called by the Component Model.
+//
+// [post-return]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#canon-lift
+func Pin[T any](p *T) { pinned[unsafe.Pointer(p)] = struct{}{} }
+
+// Unpin releases a value previously retained by [Pin].
+// It is safe to call Unpin with a pointer that was not pinned.
+func Unpin[T any](p *T) { delete(pinned, unsafe.Pointer(p)) }
+
+// pinned holds pointers retained by [Pin].
+// WebAssembly is single-threaded, so it is not protected by a mutex.
+var pinned = make(map[unsafe.Pointer]struct{})
+
 // Appease vet, see https://github.com/golang/go/issues/58625
 func unsafePointer(p uintptr) unsafe.Pointer {
 	return *(*unsafe.Pointer)(unsafe.Pointer(&p))
@@ -81,3 +81,17 @@ type Integers interface {
 type CoreIntegers interface {
 	uint32 | uint64
 }
+
+func TestPin(t *testing.T) {
+	s := "hello"
+	p := &s
+	Pin(p)
+	if len(pinned) != 1 {
+		t.Errorf("len(pinned): got %d, expected 1", len(pinned))
+	}
+	Unpin(p)
+	Unpin(p)
+	if len(pinned) != 0 {
+		t.Errorf("len(pinned): got %d, expected 0", len(pinned))
+	}
+}
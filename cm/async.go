@@ -0,0 +1,393 @@
+package cm
+
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+// ErrDropped is returned when reading from or writing to a [Stream] or [Future]
+// whose other end was dropped, or when closing an [Owned] whose handle was already dropped or released.
+var ErrDropped = errors.New("cm: handle dropped")
+
+// Waitable represents a Component Model [waitable] handle: a [Subtask],
+// or the readable or writable end of a [Stream] or [Future].
+//
+// [waitable]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Async.md#waiting
+type Waitable uint32
+
+// Join represents the Canonical ABI [waitable.join] function.
+// It adds w to [WaitableSet] set, removing it from any previous set.
+// Joining set 0 removes w from its current set.
+//
+// [waitable.join]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-waitablejoin
+func (w Waitable) Join(set WaitableSet) {
+	wasmimport_waitableJoin(w, set)
+}
+
+// WaitableSet represents a Component Model [waitable set], which is used
+// to wait for events on one or more [Waitable] handles.
+//
+// [waitable set]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Async.md#waiting
+type WaitableSet uint32
+
+// NewWaitableSet represents the Canonical ABI [waitable-set.new] function.
+//
+// [waitable-set.new]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-waitable-setnew
+func NewWaitableSet() WaitableSet {
+	return wasmimport_waitableSetNew()
+}
+
+// Wait represents the Canonical ABI [waitable-set.wait] function.
+// It blocks until an event is delivered to a [Waitable] in set.
+// Note: this blocks the entire component instance, including all goroutines.
+// Use [Await] to block only the calling goroutine.
+//
+// [waitable-set.wait]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-waitable-setwait
+func (set WaitableSet) Wait() Event {
+	var payload [2]uint32
+	code := wasmimport_waitableSetWait(set, unsafe.Pointer(&payload))
+	return Event{Code: EventCode(code), Waitable: Waitable(payload[0]), Payload: payload[1]}
+}
+
+// Poll represents the Canonical ABI [waitable-set.poll] function.
+// It returns an [Event] with [EventNone] if no event is pending.
+//
+// [waitable-set.poll]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-waitable-setpoll
+func (set WaitableSet) Poll() Event {
+	var payload [2]uint32
+	code := wasmimport_waitableSetPoll(set, unsafe.Pointer(&payload))
+	return Event{Code: EventCode(code), Waitable: Waitable(payload[0]), Payload: payload[1]}
+}
+
+// Drop represents the Canonical ABI [waitable-set.drop] function.
+//
+// [waitable-set.drop]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-waitable-setdrop
+func (set WaitableSet) Drop() {
+	wasmimport_waitableSetDrop(set)
+}
+
+// EventCode represents the kind of an [Event] delivered to a [Waitable].
+type EventCode uint32
+
+const (
+	EventNone          EventCode = 0
+	EventSubtask       EventCode = 1
+	EventStreamRead    EventCode = 2
+	EventStreamWrite   EventCode = 3
+	EventFutureRead    EventCode = 4
+	EventFutureWrite   EventCode = 5
+	EventTaskCancelled EventCode = 6
+)
+
+// Event represents an event delivered to a [Waitable] in a [WaitableSet].
+// The meaning of Payload depends on Code: for [EventSubtask], it is a [SubtaskState];
+// for stream and future events, it is a packed copy result.
+type Event struct {
+	Code     EventCode
+	Waitable Waitable
+	Payload  uint32
+}
+
+// Subtask represents a Component Model [subtask], an in-progress call to an async-lowered import.
+//
+// [subtask]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Async.md#subtask-and-supertask
+type Subtask uint32
+
+// Drop represents the Canonical ABI [subtask.drop] function.
+//
+// [subtask.drop]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-subtaskdrop
+func (s Subtask) Drop() {
+	wasmimport_subtaskDrop(s)
+}
+
+// Cancel represents the Canonical ABI [subtask.cancel] function.
+// It blocks until the subtask is cancelled or returns, and returns its final state.
+//
+// [subtask.cancel]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-subtaskcancel
+func (s Subtask) Cancel() SubtaskState {
+	return SubtaskState(wasmimport_subtaskCancel(s))
+}
+
+// SubtaskState represents the state of a [Subtask].
+type SubtaskState uint32
+
+const (
+	SubtaskStarting                SubtaskState = 0
+	SubtaskStarted                 SubtaskState = 1
+	SubtaskReturned                SubtaskState = 2
+	SubtaskCancelledBeforeStarted  SubtaskState = 3
+	SubtaskCancelledBeforeReturned SubtaskState = 4
+)
+
+// Done returns true if the subtask has returned or was cancelled.
+func (state SubtaskState) Done() bool {
+	return state >= SubtaskReturned
+}
+
+// UnpackSubtaskStatus unpacks the status returned from an async-lowered import
+// into a [SubtaskState] and a [Subtask] handle. If the import returned synchronously,
+// the returned [Subtask] will be 0.
+func UnpackSubtaskStatus(status uint32) (SubtaskState, Subtask) {
+	return SubtaskState(status & 0xf), Subtask(status >> 4)
+}
+
+// CallbackCode represents the value returned from an async-lifted export or its callback
+// in the Canonical ABI [callback] calling convention.
+//
+// [callback]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#canon-lift
+type CallbackCode uint32
+
+const (
+	CallbackExit  CallbackCode = 0
+	CallbackYield CallbackCode = 1
+	CallbackWait  CallbackCode = 2
+	CallbackPoll  CallbackCode = 3
+)
+
+// PackCallbackCode packs [CallbackCode] code and [WaitableSet] set into
+// the value returned from an async-lifted export or its callback.
+func PackCallbackCode(code CallbackCode, set WaitableSet) uint32 {
+	return uint32(code) | uint32(set)<<4
+}
+
+// Yield represents the Canonical ABI [yield] function.
+// It allows the host to schedule other tasks, without blocking on an event.
+//
+// [yield]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-yield
+func Yield() {
+	wasmimport_yield()
+}
+
+// Await blocks the calling goroutine until an [Event] is delivered to [Waitable] w.
+// Other goroutines continue to run while the calling goroutine is blocked.
+//
+// If called from the implementation of an async-lifted export (see [StartTask]),
+// events are delivered when the host calls [TaskCallback]. Otherwise, the calling goroutine
+// blocks the component instance in [WaitableSet.Wait] after all other goroutines have settled.
+func Await(w Waitable) Event {
+	return loop.await(w)
+}
+
+// AwaitSubtask blocks the calling goroutine until the subtask identified by status,
+// as returned by an async-lowered import, has returned. It drops the subtask before returning.
+func AwaitSubtask(status uint32) SubtaskState {
+	state, subtask := UnpackSubtaskStatus(status)
+	for !state.Done() {
+		e := Await(Waitable(subtask))
+		if e.Code == EventSubtask {
+			state = SubtaskState(e.Payload)
+		}
+	}
+	if subtask != 0 {
+		subtask.Drop()
+	}
+	return state
+}
+
+// StartTask runs f in a new goroutine as the implementation of an async-lifted export.
+// Function f must call the corresponding task.return function before it returns,
+// unless [Task.Return] reports that the task was cancelled.
+// StartTask runs all goroutines until they are blocked, and returns a packed [CallbackCode]
+// which should be returned from the exported function.
+func StartTask(f func(t *Task)) uint32 {
+	return loop.start(f)
+}
+
+// Task represents an in-progress call to an async-lifted export, started by [StartTask].
+type Task struct {
+	cancelled chan struct{}
+	resolved  bool // task.return or task.cancel was called
+	done      bool // the goroutine running the task returned
+}
+
+// Cancelled returns a channel that is closed when the caller cancels task t.
+// A cancelled task should stop work and return as soon as possible.
+func (t *Task) Cancelled() <-chan struct{} {
+	return t.cancelled
+}
+
+// Return reports whether task t may call its task.return function, and records that it was called.
+// It returns false if the cancellation of t was already acknowledged with the Canonical ABI
+// [task.cancel] function, which happens if t has not returned when the host delivers
+// [EventTaskCancelled] and all goroutines are blocked.
+//
+// [task.cancel]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-taskcancel
+func (t *Task) Return() bool {
+	if t.resolved {
+		return false
+	}
+	t.resolved = true
+	return true
+}
+
+func (t *Task) isCancelled() bool {
+	select {
+	case <-t.cancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// TaskCallback handles an event delivered by the host to the callback of an async-lifted export.
+// It returns a packed [CallbackCode] which should be returned from the callback function.
+func TaskCallback(event, waitable, payload uint32) uint32 {
+	return loop.callback(Event{Code: EventCode(event), Waitable: Waitable(waitable), Payload: payload})
+}
+
+// loop is the event loop for this component instance.
+// WebAssembly is single-threaded, so it is not protected by a mutex.
+var loop eventLoop
+
+// eventLoop dispatches events from a single [WaitableSet] to goroutines
+// blocked in [Await], and tracks tasks started by [StartTask].
+type eventLoop struct {
+	set      WaitableSet
+	waiters  map[Waitable][]chan Event
+	tasks    map[uint32]*Task
+	nextTask uint32
+	driving  bool   // true while running goroutines on behalf of the host
+	progress uint64 // incremented whenever a goroutine makes observable progress
+}
+
+// Canonical ABI functions called by the event loop, which tests replace
+// to run the event loop without WebAssembly.
+var (
+	waitableJoin = Waitable.Join
+	contextGet   = wasmimport_contextGet
+	contextSet   = wasmimport_contextSet
+	taskCancel   = wasmimport_taskCancel
+)
+
+func (l *eventLoop) await(w Waitable) Event {
+	if l.set == 0 {
+		l.set = NewWaitableSet()
+	}
+	if l.waiters == nil {
+		l.waiters = make(map[Waitable][]chan Event)
+	}
+	ch := make(chan Event, 1)
+	if len(l.waiters[w]) == 0 {
+		waitableJoin(w, l.set)
+	}
+	l.waiters[w] = append(l.waiters[w], ch)
+	l.progress++
+
+	var e Event
+	if l.driving {
+		// The host will deliver events via TaskCallback.
+		e = <-ch
+	} else {
+		e = l.block(ch)
+	}
+	if len(l.waiters[w]) == 0 {
+		waitableJoin(w, 0)
+	}
+	l.progress++
+	return e
+}
+
+// block blocks the component instance until an event is received on ch,
+// dispatching events for other goroutines along the way.
+func (l *eventLoop) block(ch chan Event) Event {
+	for {
+		select {
+		case e := <-ch:
+			return e
+		default:
+		}
+		l.settle()
+		select {
+		case e := <-ch:
+			return e
+		default:
+		}
+		l.dispatch(l.set.Wait())
+	}
+}
+
+// dispatch delivers [Event] e to each goroutine waiting on its [Waitable], if any.
+func (l *eventLoop) dispatch(e Event) {
+	waiters, ok := l.waiters[e.Waitable]
+	if !ok {
+		return
+	}
+	delete(l.waiters, e.Waitable)
+	for _, ch := range waiters {
+		ch <- e
+	}
+	l.progress++
+}
+
+// settle runs other goroutines until no further progress is made.
+func (l *eventLoop) settle() {
+	for {
+		p := l.progress
+		runtime.Gosched()
+		if l.progress == p {
+			return
+		}
+	}
+}
+
+func (l *eventLoop) start(f func(t *Task)) uint32 {
+	if l.tasks == nil {
+		l.tasks = make(map[uint32]*Task)
+	}
+	l.nextTask++
+	id := l.nextTask
+	t := &Task{cancelled: make(chan struct{})}
+	l.tasks[id] = t
+	contextSet(id)
+	go func() {
+		defer func() {
+			t.done = true
+			l.progress++
+		}()
+		f(t)
+	}()
+	return l.drive(id)
+}
+
+func (l *eventLoop) callback(e Event) uint32 {
+	id := contextGet()
+	switch e.Code {
+	case EventNone:
+	case EventTaskCancelled:
+		// The waitable of a cancellation event is 0, so it is delivered to the task instead.
+		if t := l.tasks[id]; t != nil && !t.isCancelled() {
+			close(t.cancelled)
+			l.progress++
+		}
+	default:
+		l.dispatch(e)
+	}
+	return l.drive(id)
+}
+
+// drive runs all goroutines until they are blocked, then returns
+// the packed [CallbackCode] for the task identified by id.
+func (l *eventLoop) drive(id uint32) uint32 {
+	l.driving = true
+	l.settle()
+	l.driving = false
+	t := l.tasks[id]
+	if t != nil && t.isCancelled() && !t.resolved {
+		// The task returned or is blocked after it was cancelled, without returning results,
+		// so acknowledge the cancellation. Its goroutine may continue to run until it returns.
+		t.resolved = true
+		taskCancel()
+		delete(l.tasks, id)
+		return PackCallbackCode(CallbackExit, 0)
+	}
+	if t == nil || t.done {
+		delete(l.tasks, id)
+		return PackCallbackCode(CallbackExit, 0)
+	}
+	if len(l.waiters) == 0 {
+		// Goroutines are blocked on something other than a waitable.
+		return PackCallbackCode(CallbackYield, 0)
+	}
+	return PackCallbackCode(CallbackWait, l.set)
+}
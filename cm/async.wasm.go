@@ -0,0 +1,53 @@
+//go:build wasm
+
+package cm
+
+import "unsafe"
+
+//go:wasmimport canon waitable.join
+//go:noescape
+func wasmimport_waitableJoin(w Waitable, set WaitableSet)
+
+//go:wasmimport canon waitable-set.new
+//go:noescape
+func wasmimport_waitableSetNew() WaitableSet
+
+// payload uses unsafe.Pointer for compatibility with go1.23 and lower.
+//
+//go:wasmimport canon waitable-set.wait
+//go:noescape
+func wasmimport_waitableSetWait(set WaitableSet, payload unsafe.Pointer) uint32
+
+// payload uses unsafe.Pointer for compatibility with go1.23 and lower.
+//
+//go:wasmimport canon waitable-set.poll
+//go:noescape
+func wasmimport_waitableSetPoll(set WaitableSet, payload unsafe.Pointer) uint32
+
+//go:wasmimport canon waitable-set.drop
+//go:noescape
+func wasmimport_waitableSetDrop(set WaitableSet)
+
+//go:wasmimport canon subtask.drop
+//go:noescape
+func wasmimport_subtaskDrop(s Subtask)
+
+//go:wasmimport canon subtask.cancel
+//go:noescape
+func wasmimport_subtaskCancel(s Subtask) uint32
+
+//go:wasmimport canon yield
+//go:noescape
+func wasmimport_yield()
+
+//go:wasmimport canon context.get
+//go:noescape
+func wasmimport_contextGet() uint32
+
+//go:wasmimport canon context.set
+//go:noescape
+func wasmimport_contextSet(v uint32)
+
+//go:wasmimport canon task.cancel
+//go:noescape
+func wasmimport_taskCancel()
@@ -0,0 +1,170 @@
+//go:build !race
+
+package cm
+
+import (
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+// testEventLoop replaces the event loop for this component instance with one that runs
+// without WebAssembly, returning it and a function
+// that returns the number of calls to task.cancel. The event loop is not synchronized,
+// because WebAssembly is single-threaded, so it runs with GOMAXPROCS(1), which the race
+// detector does not account for.
+func testEventLoop(t *testing.T) (*eventLoop, func() int) {
+	procs := runtime.GOMAXPROCS(1)
+	t.Cleanup(func() { runtime.GOMAXPROCS(procs) })
+	var context uint32
+	var cancels int
+	join, get, set, cancel := waitableJoin, contextGet, contextSet, taskCancel
+	t.Cleanup(func() { waitableJoin, contextGet, contextSet, taskCancel = join, get, set, cancel })
+	waitableJoin = func(w Waitable, set WaitableSet) {}
+	contextGet = func() uint32 { return context }
+	contextSet = func(v uint32) { context = v }
+	taskCancel = func() { cancels++ }
+	saved := loop
+	loop = eventLoop{set: 1}
+	t.Cleanup(func() { loop = saved })
+	return &loop, func() int { return cancels }
+}
+
+func TestEventLoopWaiters(t *testing.T) {
+	l, _ := testEventLoop(t)
+	var got [2]Event
+	code := l.start(func(*Task) {
+		done := make(chan struct{})
+		go func() {
+			got[1] = l.await(5)
+			close(done)
+		}()
+		got[0] = l.await(5)
+		<-done
+	})
+	if want := PackCallbackCode(CallbackWait, 1); code != want {
+		t.Fatalf("start: got %#x, expected %#x", code, want)
+	}
+	if n := len(l.waiters[5]); n != 2 {
+		t.Fatalf("await: %d waiters, expected 2", n)
+	}
+
+	// Each goroutine waiting on a waitable receives its event.
+	e := Event{Code: EventStreamRead, Waitable: 5, Payload: 7}
+	if code, want := l.callback(e), PackCallbackCode(CallbackExit, 0); code != want {
+		t.Errorf("callback: got %#x, expected %#x", code, want)
+	}
+	if got[0] != e || got[1] != e {
+		t.Errorf("await: got %v, expected %v for both waiters", got, e)
+	}
+	if len(l.waiters) != 0 || len(l.tasks) != 0 {
+		t.Errorf("callback: %d waiters and %d tasks remain, expected 0", len(l.waiters), len(l.tasks))
+	}
+}
+
+func TestEventLoopCancel(t *testing.T) {
+	tests := []struct {
+		name    string
+		f       func(l *eventLoop, t *Task) // must block until cancelled
+		cancels int                         // expected calls to task.cancel
+	}{
+		{
+			name:    "stop",
+			f:       func(l *eventLoop, t *Task) { <-t.Cancelled() },
+			cancels: 1,
+		},
+		{
+			name: "return",
+			f: func(l *eventLoop, t *Task) {
+				<-t.Cancelled()
+				if !t.Return() {
+					panic("Return: false before task.cancel")
+				}
+			},
+		},
+		{
+			name: "blocked",
+			f: func(l *eventLoop, t *Task) {
+				l.await(5)
+				if t.Return() {
+					panic("Return: true after task.cancel")
+				}
+			},
+			cancels: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, cancels := testEventLoop(t)
+			var task *Task
+			code := l.start(func(t *Task) {
+				task = t
+				tt.f(l, t)
+			})
+			if code&0xf == uint32(CallbackExit) {
+				t.Fatalf("start: got %#x, expected task to block", code)
+			}
+			code = l.callback(Event{Code: EventTaskCancelled})
+			if want := PackCallbackCode(CallbackExit, 0); code != want {
+				t.Errorf("callback: got %#x, expected %#x", code, want)
+			}
+			if got := cancels(); got != tt.cancels {
+				t.Errorf("task.cancel: called %d times, expected %d", got, tt.cancels)
+			}
+			if !task.resolved {
+				t.Errorf("Task: not resolved after cancellation")
+			}
+			if len(l.tasks) != 0 {
+				t.Errorf("callback: %d tasks remain, expected 0", len(l.tasks))
+			}
+			if len(l.waiters) != 0 {
+				// Deliver the event the cancelled task is blocked on, to let its goroutine return.
+				l.dispatch(Event{Code: EventStreamRead, Waitable: 5})
+				l.settle()
+			}
+			if !task.done {
+				t.Errorf("Task: goroutine did not return")
+			}
+		})
+	}
+}
+
+func TestFutureReadBlocked(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  CopyResult
+		err     error
+		dropped bool
+	}{
+		{"completed", CopyCompleted, nil, true},
+		{"cancelled", CopyCancelled, ErrDropped, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := testEventLoop(t)
+			isolateHandleVTables(t)
+			var dropped bool
+			vt := &FutureVTable{
+				New:          func() uint64 { return 4<<32 | 3 },
+				Read:         func(handle uint32, buf unsafe.Pointer) uint32 { return copyBlocked },
+				DropReadable: func(handle uint32) { dropped = true },
+			}
+			_, f := NewFuture[string](vt)
+			var err error
+			l.start(func(*Task) { _, err = f.Read() })
+			if dropped {
+				t.Fatalf("Read: readable end was dropped while the read is blocked")
+			}
+			l.callback(Event{Code: EventFutureRead, Waitable: 3, Payload: uint32(tt.result)})
+			if err != tt.err {
+				t.Errorf("Read: got error %v, expected %v", err, tt.err)
+			}
+			if dropped != tt.dropped {
+				t.Errorf("Read: readable end dropped: %t, expected %t", dropped, tt.dropped)
+			}
+			if _, ok := handleVTables[3]; ok == tt.dropped {
+				t.Errorf("Read: readable end bound: %t, expected %t", ok, !tt.dropped)
+			}
+		})
+	}
+}
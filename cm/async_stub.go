@@ -0,0 +1,32 @@
+//go:build !wasm
+
+package cm
+
+import "unsafe"
+
+// The functions in this file allow this package to be built and tested without WebAssembly.
+// The Canonical ABI async functions are only available in a Component Model host.
+
+func wasmimport_waitableJoin(w Waitable, set WaitableSet) { panic(errNotWasm) }
+
+func wasmimport_waitableSetNew() WaitableSet { panic(errNotWasm) }
+
+func wasmimport_waitableSetWait(set WaitableSet, payload unsafe.Pointer) uint32 { panic(errNotWasm) }
+
+func wasmimport_waitableSetPoll(set WaitableSet, payload unsafe.Pointer) uint32 { panic(errNotWasm) }
+
+func wasmimport_waitableSetDrop(set WaitableSet) { panic(errNotWasm) }
+
+func wasmimport_subtaskDrop(s Subtask) { panic(errNotWasm) }
+
+func wasmimport_subtaskCancel(s Subtask) uint32 { panic(errNotWasm) }
+
+func wasmimport_yield() { panic(errNotWasm) }
+
+func wasmimport_contextGet() uint32 { panic(errNotWasm) }
+
+func wasmimport_contextSet(v uint32) { panic(errNotWasm) }
+
+func wasmimport_taskCancel() { panic(errNotWasm) }
+
+const errNotWasm = "cm: Component Model async functions require WebAssembly"
@@ -0,0 +1,193 @@
+package cm
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"unsafe"
+)
+
+func TestUnpackSubtaskStatus(t *testing.T) {
+	tests := []struct {
+		status  uint32
+		state   SubtaskState
+		subtask Subtask
+	}{
+		{0, SubtaskStarting, 0},
+		{uint32(SubtaskReturned), SubtaskReturned, 0},
+		{1<<4 | uint32(SubtaskStarted), SubtaskStarted, 1},
+		{0xfffffff<<4 | uint32(SubtaskStarting), SubtaskStarting, 0xfffffff},
+	}
+	for _, tt := range tests {
+		state, subtask := UnpackSubtaskStatus(tt.status)
+		if state != tt.state || subtask != tt.subtask {
+			t.Errorf("UnpackSubtaskStatus(%#x): got (%d, %d), expected (%d, %d)", tt.status, state, subtask, tt.state, tt.subtask)
+		}
+	}
+}
+
+func TestPackCallbackCode(t *testing.T) {
+	if got, want := PackCallbackCode(CallbackExit, 0), uint32(0); got != want {
+		t.Errorf("PackCallbackCode(CallbackExit, 0): got %#x, expected %#x", got, want)
+	}
+	if got, want := PackCallbackCode(CallbackWait, 3), uint32(0x32); got != want {
+		t.Errorf("PackCallbackCode(CallbackWait, 3): got %#x, expected %#x", got, want)
+	}
+}
+
+// testStream is a single-buffered stream for testing without a Component Model host.
+type testStream struct {
+	buf     []uint32
+	dropped bool
+}
+
+func (s *testStream) vtable() *StreamVTable {
+	return &StreamVTable{
+		New: func() uint64 { return 2<<32 | 1 },
+		Read: func(handle uint32, buf unsafe.Pointer, n uint32) uint32 {
+			if len(s.buf) == 0 && s.dropped {
+				return uint32(CopyDropped)
+			}
+			count := copy(unsafe.Slice((*uint32)(buf), n), s.buf)
+			s.buf = s.buf[count:]
+			return uint32(count)<<4 | uint32(CopyCompleted)
+		},
+		Write: func(handle uint32, buf unsafe.Pointer, n uint32) uint32 {
+			s.buf = append(s.buf, unsafe.Slice((*uint32)(buf), n)...)
+			return n<<4 | uint32(CopyCompleted)
+		},
+		DropWritable: func(handle uint32) { s.dropped = true },
+		DropReadable: func(handle uint32) {},
+	}
+}
+
+// isolateHandleVTables replaces the package-global handle vtable map with an empty map
+// for the duration of test t, so the bound handles observed by t are only its own.
+func isolateHandleVTables(t *testing.T) {
+	saved := handleVTables
+	handleVTables = make(map[uint32]any)
+	t.Cleanup(func() { handleVTables = saved })
+}
+
+func TestStream(t *testing.T) {
+	isolateHandleVTables(t)
+	var ts testStream
+	w, r := NewStream[uint32](ts.vtable())
+	if w != 2 || r.stream != 1 {
+		t.Fatalf("NewStream: got (%d, %d), expected (2, 1)", w, r.stream)
+	}
+
+	n, err := w.Write([]uint32{1, 2, 3})
+	if n != 3 || err != nil {
+		t.Errorf("Write: got (%d, %v), expected (3, nil)", n, err)
+	}
+	w.Drop()
+
+	buf := make([]uint32, 2)
+	n, err = r.Read(buf)
+	if n != 2 || err != nil || buf[0] != 1 || buf[1] != 2 {
+		t.Errorf("Read: got (%d, %v, %v), expected (2, nil, [1 2])", n, err, buf)
+	}
+	n, err = r.Read(buf)
+	if n != 1 || err != nil || buf[0] != 3 {
+		t.Errorf("Read: got (%d, %v, %v), expected (1, nil, [3 ...])", n, err, buf[:n])
+	}
+	n, err = r.Read(buf)
+	if n != 0 || err != io.EOF {
+		t.Errorf("Read: got (%d, %v), expected (0, io.EOF)", n, err)
+	}
+	r.Drop()
+	if _, ok := handleVTables[1]; ok {
+		t.Errorf("Drop: readable end is still bound")
+	}
+	if len(handleVTables) != 0 {
+		t.Errorf("Drop: %d handles still bound, expected 0", len(handleVTables))
+	}
+}
+
+func TestStreamVTables(t *testing.T) {
+	isolateHandleVTables(t)
+	// Streams of the same value type from different import modules use different vtables.
+	var a, b testStream
+	va, vb := a.vtable(), b.vtable()
+	va.New = func() uint64 { return 2<<32 | 1 }
+	vb.New = func() uint64 { return 4<<32 | 3 }
+	wa, _ := NewStream[uint32](va)
+	wb, _ := NewStream[uint32](vb)
+
+	wa.Write([]uint32{1})
+	wb.Write([]uint32{2, 3})
+	if len(a.buf) != 1 || len(b.buf) != 2 {
+		t.Errorf("Write: got %v and %v, expected [1] and [2 3]", a.buf, b.buf)
+	}
+
+	var s Stream[uint32]
+	s.stream = 5
+	BindStream(s, vb)
+	buf := make([]uint32, 2)
+	if n, _ := s.Read(buf); n != 2 || buf[0] != 2 {
+		t.Errorf("Read: got %v, expected [2 3]", buf[:n])
+	}
+}
+
+func TestFuture(t *testing.T) {
+	isolateHandleVTables(t)
+	var value string
+	var written, readDropped bool
+	vt := &FutureVTable{
+		New: func() uint64 { return 4<<32 | 3 },
+		Read: func(handle uint32, buf unsafe.Pointer) uint32 {
+			if !written {
+				return uint32(CopyDropped)
+			}
+			*(*string)(buf) = value
+			return uint32(CopyCompleted)
+		},
+		Write: func(handle uint32, buf unsafe.Pointer) uint32 {
+			value = *(*string)(buf)
+			written = true
+			return uint32(CopyCompleted)
+		},
+		DropReadable: func(handle uint32) { readDropped = true },
+		DropWritable: func(handle uint32) {},
+	}
+
+	w, f := NewFuture[string](vt)
+	if err := w.Write("hello"); err != nil {
+		t.Errorf("Write: %v", err)
+	}
+	v, err := f.Read()
+	if v != "hello" || err != nil {
+		t.Errorf("Read: got (%q, %v), expected (\"hello\", nil)", v, err)
+	}
+	if !readDropped {
+		t.Errorf("Read: readable end was not dropped")
+	}
+
+	written, readDropped = false, false
+	_, f = NewFuture[string](vt)
+	_, err = f.Read()
+	if !errors.Is(err, ErrDropped) {
+		t.Errorf("Read: got error %v, expected %v", err, ErrDropped)
+	}
+	if readDropped {
+		t.Errorf("Read: readable end was dropped after an incomplete read")
+	}
+	f.Drop()
+	if !readDropped {
+		t.Errorf("Drop: readable end was not dropped")
+	}
+	if _, ok := handleVTables[3]; ok {
+		t.Errorf("Drop: readable end is still bound")
+	}
+}
+
+func TestStreamNotBound(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Read: expected panic for unbound stream handle")
+		}
+	}()
+	var s Stream[uint32]
+	s.Read(nil)
+}
@@ -3,6 +3,37 @@
 // The types in this package (such as [List], [Option], [Result], and [Variant]) are designed to match the memory layout
 // of [Component Model] types as specified in the [Canonical ABI].
 //
+// # Memory Safety
+//
+// The cases of a [Variant] or [Result] share memory by default, which requires that every case
+// stores pointers at the same offsets. Otherwise the Go garbage collector may find a non-pointer value
+// where it expects a pointer. A Variant whose cases cannot share memory uses a Shape type
+// that implements [SplitShape], and a Result uses [SplitResult], to store each case separately.
+// Split storage does not match the Canonical ABI memory layout, so wit-bindgen-go generates functions
+// to convert these values when they cross the component boundary.
+//
+// # JSON
+//
+// Types in this package and types generated by wit-bindgen-go implement [encoding/json.Marshaler]
+// and [encoding/json.Unmarshaler] with a canonical JSON representation of Component Model values:
+//
+//   - bool, integer, and floating-point types: a JSON boolean or number.
+//   - char: a JSON number representing the Unicode code point.
+//   - string: a JSON string.
+//   - list<T>: a JSON array. A list<u8> is a JSON array of numbers, not a base64 string.
+//   - tuple<T0, T1, ...>: a JSON array with one element per field.
+//   - record: a JSON object with a key for each field name.
+//   - enum: a JSON string with the case name.
+//   - flags: a JSON array of the names of the flags that are set.
+//   - variant: a JSON object with a single key for the case name. The value is the case payload,
+//     or null if the case has no associated type. A JSON string with the case name is also accepted when unmarshaling.
+//   - option<T>: null for none, or the JSON representation of T for some.
+//     If T is itself an option, some is a JSON array with a single element, the JSON representation of T,
+//     so an option<option<u32>> is null for none, [null] for some(none), and [1] for some(some(1)).
+//   - result<T, E>: a JSON object with a single key, "ok" or "err". The value is the payload,
+//     or null if the result case has no associated type.
+//   - resource handles, stream, future, and error-context: a JSON number.
+//
 // [Component Model]: https://component-model.bytecodealliance.org/introduction.html
 // [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#alignment
 package cm
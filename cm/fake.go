@@ -0,0 +1,38 @@
+package cm
+
+// FakeHandles is an in-memory table of fake resource handles of type Handle, each with a value of type T.
+// It is used by the fakes generated by wit-bindgen-go for builds on targets other than WebAssembly,
+// such as with go test, to allocate and drop resource handles without a component runtime.
+// The zero value is an empty table ready to use. A FakeHandles is not safe for concurrent use.
+//
+// Handles are allocated sequentially, starting at 1, and are not reused.
+type FakeHandles[Handle ~uint32, T any] struct {
+	last   Handle
+	values map[Handle]T
+}
+
+// New allocates a new handle for value v.
+func (t *FakeHandles[Handle, T]) New(v T) Handle {
+	if t.values == nil {
+		t.values = make(map[Handle]T)
+	}
+	t.last++
+	t.values[t.last] = v
+	return t.last
+}
+
+// Get returns the value for handle h, and true if h is a valid handle.
+func (t *FakeHandles[Handle, T]) Get(h Handle) (v T, ok bool) {
+	v, ok = t.values[h]
+	return v, ok
+}
+
+// Drop removes handle h from the table.
+func (t *FakeHandles[Handle, T]) Drop(h Handle) {
+	delete(t.values, h)
+}
+
+// Len returns the number of handles in the table, which can be used to detect leaked handles.
+func (t *FakeHandles[Handle, T]) Len() int {
+	return len(t.values)
+}
@@ -0,0 +1,27 @@
+package cm
+
+import "testing"
+
+func TestFakeHandles(t *testing.T) {
+	type handle uint32
+	var table FakeHandles[handle, string]
+
+	a := table.New("a")
+	b := table.New("b")
+	if a != 1 || b != 2 {
+		t.Fatalf("New: handles %d and %d, expected 1 and 2", a, b)
+	}
+	if v, ok := table.Get(b); !ok || v != "b" {
+		t.Errorf("Get(%d): %q, %t, expected %q, true", b, v, ok, "b")
+	}
+	table.Drop(a)
+	if _, ok := table.Get(a); ok {
+		t.Errorf("Get(%d): ok after Drop", a)
+	}
+	if got, want := table.Len(), 1; got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+	if c := table.New("c"); c != 3 {
+		t.Errorf("New: handle %d, expected 3", c)
+	}
+}
@@ -1,6 +1,8 @@
 package cm
 
-// Future represents the Component Model [future] type.
+import "unsafe"
+
+// Future represents the readable end of a Component Model [future] type.
 // A future is a special case of stream. In non-error cases,
 // a future delivers exactly one value before being automatically closed.
 //
@@ -12,4 +14,121 @@ type Future[T any] struct {
 
 type future[T any] uint32
 
-// TODO: implement methods on type future
+// Read reads the value of future f, blocking the calling goroutine until it is available.
+// The readable end of the future is dropped after the value is read.
+// If the writable end of the future was dropped before writing a value, or the read was cancelled,
+// Read returns [ErrDropped], and the readable end must be dropped with Drop.
+func (f future[T]) Read() (T, error) {
+	vt := futureVTable(uint32(f))
+	var v T
+	result := vt.Read(uint32(f), unsafe.Pointer(&v))
+	if result == copyBlocked {
+		result = Await(Waitable(f)).Payload
+	}
+	if code, _ := unpackCopyResult(result); code != CopyCompleted {
+		return v, ErrDropped
+	}
+	vt.DropReadable(uint32(f))
+	delete(handleVTables, uint32(f))
+	return v, nil
+}
+
+// CancelRead represents the Canonical ABI [future.cancel-read] function.
+//
+// [future.cancel-read]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturecancel-readwrite
+func (f future[T]) CancelRead() {
+	futureVTable(uint32(f)).CancelRead(uint32(f))
+}
+
+// Drop represents the Canonical ABI [future.drop-readable] function.
+//
+// [future.drop-readable]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturedrop-readablewritable
+func (f future[T]) Drop() {
+	futureVTable(uint32(f)).DropReadable(uint32(f))
+	delete(handleVTables, uint32(f))
+}
+
+// FutureWriter represents the writable end of a Component Model [future] type.
+// The writable end of a future cannot be passed to another component.
+//
+// [future]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Explainer.md#asynchronous-value-types
+type FutureWriter[T any] uint32
+
+// Write writes value v to the future, blocking the calling goroutine until it is read.
+// The writable end of the future is dropped after the value is written.
+// If the readable end of the future was dropped, Write returns [ErrDropped].
+func (w FutureWriter[T]) Write(v T) error {
+	vt := futureVTable(uint32(w))
+	result := vt.Write(uint32(w), unsafe.Pointer(&v))
+	if result == copyBlocked {
+		result = Await(Waitable(w)).Payload
+	}
+	vt.DropWritable(uint32(w))
+	delete(handleVTables, uint32(w))
+	if code, _ := unpackCopyResult(result); code != CopyCompleted {
+		return ErrDropped
+	}
+	return nil
+}
+
+// CancelWrite represents the Canonical ABI [future.cancel-write] function.
+//
+// [future.cancel-write]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturecancel-readwrite
+func (w FutureWriter[T]) CancelWrite() {
+	futureVTable(uint32(w)).CancelWrite(uint32(w))
+}
+
+// Drop represents the Canonical ABI [future.drop-writable] function.
+//
+// [future.drop-writable]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturedrop-readablewritable
+func (w FutureWriter[T]) Drop() {
+	futureVTable(uint32(w)).DropWritable(uint32(w))
+	delete(handleVTables, uint32(w))
+}
+
+// NewFuture represents the Canonical ABI [future.new] function.
+// It returns the writable and readable ends of a new future with a value of type T,
+// using the type-specific Canonical ABI functions in vt.
+// Generated bindings declare a constructor that calls NewFuture for each future type
+// passed to an imported function or returned from an exported function.
+//
+// [future.new]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturenew
+func NewFuture[T any](vt *FutureVTable) (FutureWriter[T], Future[T]) {
+	if vt.New == nil {
+		panic("cm: future intrinsics are not available on this target")
+	}
+	w, r := unpackEnds(vt.New())
+	handleVTables[w] = vt
+	handleVTables[r] = vt
+	var f Future[T]
+	f.future = future[T](r)
+	return FutureWriter[T](w), f
+}
+
+// BindFuture binds the readable end f of a future received from another component to vt.
+// It is called by generated bindings, and should not be called directly.
+func BindFuture[T any](f Future[T], vt *FutureVTable) {
+	handleVTables[uint32(f.future)] = vt
+}
+
+// FutureVTable represents the Canonical ABI functions for a future with a specific value type.
+// In the Component Model, these functions are imported separately for each future type
+// in the type of each function, so generated bindings declare a FutureVTable for each
+// import module and future type, and bind each future handle to its FutureVTable.
+type FutureVTable struct {
+	New          func() uint64
+	Read         func(handle uint32, buf unsafe.Pointer) uint32
+	Write        func(handle uint32, buf unsafe.Pointer) uint32
+	CancelRead   func(handle uint32) uint32
+	CancelWrite  func(handle uint32) uint32
+	DropReadable func(handle uint32)
+	DropWritable func(handle uint32)
+}
+
+func futureVTable(handle uint32) *FutureVTable {
+	vt, ok := handleVTables[handle].(*FutureVTable)
+	if !ok {
+		panic("cm: future handle is not bound to a FutureVTable")
+	}
+	return vt
+}
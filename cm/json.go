@@ -0,0 +1,160 @@
+package cm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/bits"
+	"unsafe"
+)
+
+// MarshalVariantJSON returns the canonical JSON representation of a variant with case tag
+// and associated data. Argument names contains the name of each variant case.
+// If data is nil, the case value is null.
+func MarshalVariantJSON[Tag Discriminant](names []string, tag Tag, data any) ([]byte, error) {
+	if int(tag) >= len(names) {
+		return nil, errNoMatchingCase
+	}
+	return marshalCaseJSON(names[tag], data)
+}
+
+// UnmarshalVariantJSON unmarshals the canonical JSON representation of a variant,
+// returning the index of the variant case in names and the JSON value of the case.
+// If data is null, it returns tag -1.
+func UnmarshalVariantJSON(names []string, data []byte) (tag int, value json.RawMessage, err error) {
+	var name string
+	name, value, err = unmarshalCaseJSON(data)
+	if err != nil || name == "" {
+		return -1, nil, err
+	}
+	for i := range names {
+		if names[i] == name {
+			return i, value, nil
+		}
+	}
+	return -1, nil, errNoMatchingCase
+}
+
+// MarshalFlagsJSON returns the canonical JSON representation of flags f,
+// a JSON array of the names of the flags set in f. Argument names contains the name of each flag.
+func MarshalFlagsJSON[T ~uint8 | ~uint16 | ~uint32 | ~uint64](names []string, f T) ([]byte, error) {
+	set := make([]string, 0, bits.OnesCount64(uint64(f)))
+	for i := range names {
+		if f&(1<<i) != 0 {
+			set = append(set, names[i])
+		}
+	}
+	return json.Marshal(set)
+}
+
+// UnmarshalFlagsJSON unmarshals the canonical JSON representation of flags into f.
+// Argument names contains the name of each flag.
+func UnmarshalFlagsJSON[T ~uint8 | ~uint16 | ~uint32 | ~uint64](names []string, f *T, data []byte) error {
+	if bytes.Equal(data, nullLiteral) {
+		return nil
+	}
+	var set []string
+	err := json.Unmarshal(data, &set)
+	if err != nil {
+		return err
+	}
+	var v T
+outer:
+	for _, s := range set {
+		for i := range names {
+			if names[i] == s {
+				v |= 1 << i
+				continue outer
+			}
+		}
+		return errNoMatchingCase
+	}
+	*f = v
+	return nil
+}
+
+// marshalCaseJSON returns a JSON object with a single key name and value v.
+// Zero-sized values are represented as null.
+func marshalCaseJSON[T any](name string, v T) ([]byte, error) {
+	key, err := json.Marshal(name)
+	if err != nil {
+		return nil, err
+	}
+	value := nullLiteral
+	if unsafe.Sizeof(v) != 0 {
+		value, err = json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var b bytes.Buffer
+	b.WriteByte('{')
+	b.Write(key)
+	b.WriteByte(':')
+	b.Write(value)
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// unmarshalCaseJSON unmarshals a JSON object with a single key, or a JSON string,
+// returning the key or string and the JSON value. If data is null, it returns an empty name.
+func unmarshalCaseJSON(data []byte) (name string, value json.RawMessage, err error) {
+	if bytes.Equal(data, nullLiteral) {
+		return "", nil, nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		err = json.Unmarshal(data, &name)
+		return name, nullLiteral, err
+	}
+	var m map[string]json.RawMessage
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(m) != 1 {
+		return "", nil, errCaseObject
+	}
+	for name, value = range m {
+	}
+	if name == "" {
+		return "", nil, errEmpty
+	}
+	return name, value, nil
+}
+
+// unmarshalCaseValueJSON unmarshals JSON value into v.
+// Zero-sized values ignore value.
+func unmarshalCaseValueJSON[T any](value json.RawMessage, v *T) error {
+	if unsafe.Sizeof(*v) == 0 {
+		return nil
+	}
+	return json.Unmarshal(value, v)
+}
+
+// unmarshalTupleJSON unmarshals a JSON array into fields.
+func unmarshalTupleJSON(data []byte, fields ...any) error {
+	if bytes.Equal(data, nullLiteral) {
+		return nil
+	}
+	var values []json.RawMessage
+	err := json.Unmarshal(data, &values)
+	if err != nil {
+		return err
+	}
+	if len(values) != len(fields) {
+		return errTupleLength
+	}
+	for i := range values {
+		err = json.Unmarshal(values[i], fields[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	errCaseObject   = errors.New("expected JSON object with a single key")
+	errTupleLength  = errors.New("wrong number of tuple fields")
+	errNestedOption = errors.New("expected JSON array with a single element for nested option")
+)
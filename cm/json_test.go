@@ -0,0 +1,189 @@
+package cm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		json string
+	}{
+		{"option<u32>(none)", None[uint32](), `null`},
+		{"option<u32>(some)", Some[uint32](7), `7`},
+		{"option<string>(some)", Some("hello"), `"hello"`},
+		{"option<list<u8>>(some)", Some(ToList([]uint8{1, 2})), `[1,2]`},
+		{"option<option<u32>>(none)", None[Option[uint32]](), `null`},
+		{"option<option<u32>>(some(none))", Some(None[uint32]()), `[null]`},
+		{"option<option<u32>>(some(some))", Some(Some[uint32](1)), `[1]`},
+		{"option<option<option<u32>>>(some(some(none)))", Some(Some(None[uint32]())), `[[null]]`},
+		{"result<string, u8>(ok)", OK[Result[string, string, uint8]]("ok"), `{"ok":"ok"}`},
+		{"result<string, u8>(err)", Err[Result[string, string, uint8]](uint8(3)), `{"err":3}`},
+		{"result<_, u32>(ok)", OK[Result[uint32, struct{}, uint32]](struct{}{}), `{"ok":null}`},
+		{"result<_, u32>(err)", Err[Result[uint32, struct{}, uint32]](uint32(5)), `{"err":5}`},
+		{"result(ok)", BoolResult(ResultOK), `{"ok":null}`},
+		{"result(err)", BoolResult(ResultErr), `{"err":null}`},
+		{"tuple<u8, string>", Tuple[uint8, string]{F0: 1, F1: "a"}, `[1,"a"]`},
+		{"tuple<bool, option<u32>, string>", Tuple3[bool, Option[uint32], string]{F0: true, F1: Some[uint32](2), F2: "b"}, `[true,2,"b"]`},
+		{"record", struct {
+			A Option[string] `json:"a"`
+			B Result[string, string, bool]
+		}{Some("x"), Err[Result[string, string, bool]](true)}, `{"a":"x","B":{"err":true}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("json.Marshal(%v): %s, expected %s", tt.v, got, tt.json)
+			}
+			into := reflect.New(reflect.TypeOf(tt.v))
+			err = json.Unmarshal([]byte(tt.json), into.Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err = json.Marshal(into.Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("json.Unmarshal(%s): round-trip %s", tt.json, got)
+			}
+		})
+	}
+}
+
+func TestJSONNestedOption(t *testing.T) {
+	for _, v := range []Option[Option[uint32]]{
+		None[Option[uint32]](),
+		Some(None[uint32]()),
+		Some(Some[uint32](0)),
+		Some(Some[uint32](1)),
+	} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got Option[Option[uint32]]
+		err = json.Unmarshal(data, &got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v {
+			t.Errorf("json.Unmarshal(%s): %v, expected %v", data, got, v)
+		}
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		into any
+		json string
+	}{
+		{"result with unknown case", &Result[string, string, uint8]{}, `{"error":1}`},
+		{"result with two keys", &Result[string, string, uint8]{}, `{"ok":"a","err":1}`},
+		{"bool result with unknown case", new(BoolResult), `{"okay":null}`},
+		{"tuple with too few fields", &Tuple[uint8, string]{}, `[1]`},
+		{"tuple with wrong field type", &Tuple[uint8, string]{}, `["a","b"]`},
+		{"option with wrong type", &Option[uint8]{}, `"a"`},
+		{"nested option without array", &Option[Option[uint8]]{}, `1`},
+		{"nested option with empty array", &Option[Option[uint8]]{}, `[]`},
+		{"nested option with two elements", &Option[Option[uint8]]{}, `[1,2]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(tt.json), tt.into)
+			if err == nil {
+				t.Errorf("json.Unmarshal(%s): expected error", tt.json)
+			}
+		})
+	}
+}
+
+func TestVariantJSON(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	tests := []struct {
+		tag  uint8
+		data any
+		json string
+	}{
+		{0, nil, `{"a":null}`},
+		{1, "hello", `{"b":"hello"}`},
+		{2, Some[uint32](3), `{"c":3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			got, err := MarshalVariantJSON(names, tt.tag, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("MarshalVariantJSON: %s, expected %s", got, tt.json)
+			}
+			tag, _, err := UnmarshalVariantJSON(names, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tag != int(tt.tag) {
+				t.Errorf("UnmarshalVariantJSON(%s): tag %d, expected %d", got, tag, tt.tag)
+			}
+		})
+	}
+
+	tag, _, err := UnmarshalVariantJSON(names, []byte(`"b"`))
+	if err != nil || tag != 1 {
+		t.Errorf(`UnmarshalVariantJSON("b"): %d, %v, expected 1, nil`, tag, err)
+	}
+	tag, _, err = UnmarshalVariantJSON(names, []byte(`null`))
+	if err != nil || tag != -1 {
+		t.Errorf(`UnmarshalVariantJSON(null): %d, %v, expected -1, nil`, tag, err)
+	}
+	_, _, err = UnmarshalVariantJSON(names, []byte(`{"d":1}`))
+	if err == nil {
+		t.Errorf(`UnmarshalVariantJSON({"d":1}): expected error`)
+	}
+}
+
+func TestFlagsJSON(t *testing.T) {
+	names := []string{"read", "write", "exec"}
+	tests := []struct {
+		f    uint8
+		json string
+	}{
+		{0, `[]`},
+		{1, `["read"]`},
+		{5, `["read","exec"]`},
+		{7, `["read","write","exec"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			got, err := MarshalFlagsJSON(names, tt.f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.json {
+				t.Errorf("MarshalFlagsJSON(%d): %s, expected %s", tt.f, got, tt.json)
+			}
+			var f uint8
+			err = UnmarshalFlagsJSON(names, &f, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if f != tt.f {
+				t.Errorf("UnmarshalFlagsJSON(%s): %d, expected %d", got, f, tt.f)
+			}
+		})
+	}
+
+	var f uint8
+	err := UnmarshalFlagsJSON(names, &f, []byte(`["delete"]`))
+	if err == nil {
+		t.Errorf(`UnmarshalFlagsJSON(["delete"]): expected error`)
+	}
+}
@@ -1,5 +1,10 @@
 package cm
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // Option represents a Component Model [option<T>] type.
 //
 // [option<T>]: https://component-model.bytecodealliance.org/design/wit.html#options
@@ -57,3 +62,58 @@ func (o option[T]) Value() T {
 	}
 	return o.some
 }
+
+// MarshalJSON implements [json.Marshaler], returning null for the none case,
+// or the JSON representation of T for the some case. If T is an option type,
+// the JSON representation of T is wrapped in a JSON array with a single element,
+// so the some case with a none value is distinguishable from the none case.
+func (o option[T]) MarshalJSON() ([]byte, error) {
+	if !o.isSome {
+		return nullLiteral, nil
+	}
+	if o.nested() {
+		return json.Marshal([1]T{o.some})
+	}
+	return json.Marshal(o.some)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling null into the none case,
+// or any other JSON value into the some case. If T is an option type,
+// the some case must be a JSON array with a single element.
+func (o *option[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, nullLiteral) {
+		*o = option[T]{}
+		return nil
+	}
+	var some T
+	if o.nested() {
+		var values []json.RawMessage
+		err := json.Unmarshal(data, &values)
+		if err != nil {
+			return err
+		}
+		if len(values) != 1 {
+			return errNestedOption
+		}
+		data = values[0]
+	}
+	err := json.Unmarshal(data, &some)
+	if err != nil {
+		return err
+	}
+	*o = option[T]{isSome: true, some: some}
+	return nil
+}
+
+// nested reports whether T is an option type.
+func (o *option[T]) nested() bool {
+	_, ok := any(&o.some).(anyOption)
+	return ok
+}
+
+// anyOption is implemented by a pointer to an option type.
+type anyOption interface {
+	isOption()
+}
+
+func (o *option[T]) isOption() {}
@@ -0,0 +1,95 @@
+package cm
+
+import "runtime"
+
+// Droppable is a type constraint for an imported resource handle type
+// with a ResourceDrop method, such as those generated by wit-bindgen-go.
+type Droppable interface {
+	~uint32
+	ResourceDrop()
+}
+
+// Owned wraps an owned handle to an imported resource. It implements [io.Closer]:
+// Close drops the handle and poisons the wrapper, so the handle cannot be used
+// or dropped again. Create an Owned with [NewOwned].
+//
+// By default, a handle that is never closed is leaked. Call [Owned.AutoDrop] to drop the handle
+// when the Owned becomes unreachable. If the program is built with the cm_debug build tag,
+// an Owned that becomes unreachable without being closed is reported to stderr,
+// with the stack of the call to NewOwned.
+//
+// An Owned is not safe for concurrent use.
+type Owned[T Droppable] struct {
+	handle   T
+	dropped  bool
+	autoDrop bool
+	stack    []byte // allocation stack, only recorded with the cm_debug build tag
+}
+
+// NewOwned returns an [Owned] that wraps owned resource handle.
+func NewOwned[T Droppable](handle T) *Owned[T] {
+	o := &Owned[T]{handle: handle}
+	if ownedDebug {
+		o.stack = ownedStack()
+		runtime.SetFinalizer(o, finalizeOwned[T])
+	}
+	return o
+}
+
+// Handle returns the resource handle wrapped by o.
+// It panics if the handle was dropped or released.
+func (o *Owned[T]) Handle() T {
+	if o.dropped {
+		panic(ErrDropped)
+	}
+	return o.handle
+}
+
+// Close drops the resource handle wrapped by o.
+// It returns [ErrDropped] if the handle was already dropped or released.
+func (o *Owned[T]) Close() error {
+	if o.dropped {
+		return ErrDropped
+	}
+	o.dropped = true
+	runtime.SetFinalizer(o, nil)
+	o.handle.ResourceDrop()
+	return nil
+}
+
+// Release returns the resource handle wrapped by o without dropping it, transferring
+// ownership to the caller, for example to pass it to a function that takes an owned handle.
+// It panics if the handle was dropped or released.
+func (o *Owned[T]) Release() T {
+	handle := o.Handle()
+	o.dropped = true
+	runtime.SetFinalizer(o, nil)
+	return handle
+}
+
+// AutoDrop registers a finalizer that drops the resource handle wrapped by o
+// if o becomes unreachable without being closed or released. It returns o.
+// Because the finalizer runs at an unspecified time after o becomes unreachable,
+// callers should prefer to call [Owned.Close] explicitly.
+func (o *Owned[T]) AutoDrop() *Owned[T] {
+	if !o.dropped {
+		o.autoDrop = true
+		if !ownedDebug { // already set by NewOwned
+			runtime.SetFinalizer(o, finalizeOwned[T])
+		}
+	}
+	return o
+}
+
+func finalizeOwned[T Droppable](o *Owned[T]) {
+	if o.dropped {
+		return
+	}
+	if ownedDebug {
+		reportLeak(uint32(o.handle), o.stack)
+	}
+	if o.autoDrop {
+		o.dropped = true
+		o.handle.ResourceDrop()
+	}
+}
@@ -0,0 +1,20 @@
+//go:build cm_debug
+
+package cm
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+)
+
+// ownedDebug is true if the cm_debug build tag is set, which reports leaked [Owned] handles.
+const ownedDebug = true
+
+func ownedStack() []byte {
+	return debug.Stack()
+}
+
+func reportLeak(handle uint32, stack []byte) {
+	fmt.Fprintf(os.Stderr, "cm: leaked resource handle %d, allocated at:\n%s\n", handle, stack)
+}
@@ -0,0 +1,10 @@
+//go:build !cm_debug
+
+package cm
+
+// ownedDebug is true if the cm_debug build tag is set, which reports leaked [Owned] handles.
+const ownedDebug = false
+
+func ownedStack() []byte { return nil }
+
+func reportLeak(handle uint32, stack []byte) {}
@@ -0,0 +1,68 @@
+package cm
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testDrops [4]atomic.Int32
+
+type testHandle uint32
+
+func (h testHandle) ResourceDrop() { testDrops[h].Add(1) }
+
+func TestOwned(t *testing.T) {
+	testDrops[1].Store(0)
+	o := NewOwned(testHandle(1))
+	if got, want := o.Handle(), testHandle(1); got != want {
+		t.Errorf("Handle(): %d, expected %d", got, want)
+	}
+	if err := o.Close(); err != nil {
+		t.Errorf("Close(): %v", err)
+	}
+	if err := o.Close(); !errors.Is(err, ErrDropped) {
+		t.Errorf("Close(): %v, expected %v after Close", err, ErrDropped)
+	}
+	if got, want := testDrops[1].Load(), int32(1); got != want {
+		t.Errorf("ResourceDrop called %d times, expected %d", got, want)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != ErrDropped {
+				t.Errorf("Handle(): recovered %v, expected panic with %v after Close", r, ErrDropped)
+			}
+		}()
+		o.Handle()
+	}()
+}
+
+func TestOwnedRelease(t *testing.T) {
+	testDrops[2].Store(0)
+	o := NewOwned(testHandle(2))
+	if got, want := o.Release(), testHandle(2); got != want {
+		t.Errorf("Release(): %d, expected %d", got, want)
+	}
+	if err := o.Close(); !errors.Is(err, ErrDropped) {
+		t.Errorf("Close(): %v, expected %v after Release", err, ErrDropped)
+	}
+	if got := testDrops[2].Load(); got != 0 {
+		t.Errorf("ResourceDrop called %d times, expected 0", got)
+	}
+}
+
+func TestOwnedAutoDrop(t *testing.T) {
+	testDrops[3].Store(0)
+	func() {
+		NewOwned(testHandle(3)).AutoDrop()
+	}()
+	for i := 0; i < 100 && testDrops[3].Load() == 0; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	if got, want := testDrops[3].Load(), int32(1); got != want {
+		t.Errorf("ResourceDrop called %d times, expected %d", got, want)
+	}
+}
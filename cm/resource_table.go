@@ -0,0 +1,98 @@
+package cm
+
+import (
+	"errors"
+	"sync"
+)
+
+// ResourceTable maps the [Rep] of an exported resource to its Go value of type T.
+// It is used by generated bindings to look up the Go value for a resource
+// in exported methods, and to release it when the resource is destroyed.
+// The zero value is an empty table ready to use. A ResourceTable is safe for concurrent use.
+//
+// Reps are allocated starting at 1, so the zero Rep is never valid.
+// The rep of a value released by [ResourceTable.Drop] may be reused by a later call to [ResourceTable.New].
+type ResourceTable[T any] struct {
+	mu     sync.Mutex
+	values []resourceEntry[T]
+	free   []Rep
+	len    int
+}
+
+type resourceEntry[T any] struct {
+	value T
+	ok    bool
+}
+
+// New stores v in t, returning a new [Rep] that identifies it.
+// Pass the returned rep to the generated resource-new function to create a resource handle.
+func (t *ResourceTable[T]) New(v T) Rep {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var rep Rep
+	if n := len(t.free); n > 0 {
+		rep = t.free[n-1]
+		t.free = t.free[:n-1]
+	} else {
+		t.values = append(t.values, resourceEntry[T]{})
+		rep = Rep(len(t.values))
+	}
+	t.values[rep-1] = resourceEntry[T]{value: v, ok: true}
+	t.len++
+	return rep
+}
+
+// Get returns the value in t for rep, and whether rep is valid.
+func (t *ResourceTable[T]) Get(rep Rep) (v T, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e := t.entry(rep); e != nil {
+		return e.value, true
+	}
+	return v, false
+}
+
+// Value returns the value in t for rep.
+// It panics if rep is not valid, which indicates a resource was used after it was destroyed.
+func (t *ResourceTable[T]) Value(rep Rep) T {
+	v, ok := t.Get(rep)
+	if !ok {
+		panic(errInvalidRep)
+	}
+	return v
+}
+
+// Drop removes rep from t, returning its value.
+// It panics if rep is not valid, which indicates a resource was destroyed more than once.
+func (t *ResourceTable[T]) Drop(rep Rep) T {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e := t.entry(rep)
+	if e == nil {
+		panic(errInvalidRep)
+	}
+	v := e.value
+	*e = resourceEntry[T]{}
+	t.free = append(t.free, rep)
+	t.len--
+	return v
+}
+
+// Len returns the number of values in t.
+// A count that grows without bound indicates that resources are not being destroyed.
+func (t *ResourceTable[T]) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.len
+}
+
+// entry returns the entry for rep, or nil if rep is not valid.
+// The caller must hold t.mu.
+func (t *ResourceTable[T]) entry(rep Rep) *resourceEntry[T] {
+	if rep == 0 || int(rep) > len(t.values) || !t.values[rep-1].ok {
+		return nil
+	}
+	return &t.values[rep-1]
+}
+
+var errInvalidRep = errors.New("invalid resource rep")
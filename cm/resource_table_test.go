@@ -0,0 +1,85 @@
+package cm
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestResourceTable(t *testing.T) {
+	type value struct{ n int }
+	var table ResourceTable[*value]
+
+	a := table.New(&value{1})
+	b := table.New(&value{2})
+	if a == 0 || b == 0 || a == b {
+		t.Fatalf("New: reps %d and %d, expected distinct non-zero reps", a, b)
+	}
+	if got, want := table.Len(), 2; got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+	if got := table.Value(b); got.n != 2 {
+		t.Errorf("Value(%d): %d, expected 2", b, got.n)
+	}
+	if got := table.Drop(a); got.n != 1 {
+		t.Errorf("Drop(%d): %d, expected 1", a, got.n)
+	}
+	if _, ok := table.Get(a); ok {
+		t.Errorf("Get(%d): ok after Drop", a)
+	}
+	if _, ok := table.Get(0); ok {
+		t.Errorf("Get(0): ok, expected invalid rep")
+	}
+	if c := table.New(&value{3}); c != a {
+		t.Errorf("New: rep %d, expected reused rep %d", c, a)
+	}
+	if got, want := table.Len(), 2; got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+}
+
+func TestResourceTablePanics(t *testing.T) {
+	var table ResourceTable[string]
+	rep := table.New("a")
+	table.Drop(rep)
+
+	tests := []struct {
+		name string
+		f    func()
+	}{
+		{"Value after Drop", func() { table.Value(rep) }},
+		{"Drop after Drop", func() { table.Drop(rep) }},
+		{"Value of zero rep", func() { table.Value(0) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", tt.name)
+				}
+			}()
+			tt.f()
+		})
+	}
+}
+
+func TestResourceTableConcurrent(t *testing.T) {
+	var table ResourceTable[int]
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rep := table.New(j)
+				if got := table.Value(rep); got != j {
+					t.Errorf("Value(%d): %d, expected %d", rep, got, j)
+				}
+				table.Drop(rep)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := table.Len(); got != 0 {
+		t.Errorf("Len(): %d, expected 0", got)
+	}
+}
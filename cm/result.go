@@ -1,6 +1,9 @@
 package cm
 
-import "unsafe"
+import (
+	"errors"
+	"unsafe"
+)
 
 const (
 	// ResultOK represents the OK case of a result.
@@ -16,6 +19,7 @@ type BoolResult bool
 
 // Result represents a result sized to hold the Shape type.
 // The size of the Shape type must be greater than or equal to the size of OK and Err types.
+// If OK and Err cannot share storage, use [SplitResult] as the Shape type.
 // For results with two zero-length types, use [BoolResult].
 type Result[Shape, OK, Err any] struct {
 	_ HostLayout
@@ -58,7 +62,7 @@ func (r *result[Shape, OK, Err]) OK() *OK {
 	if r.isErr {
 		return nil
 	}
-	return (*OK)(unsafe.Pointer(&r.data))
+	return r.okData()
 }
 
 // Err returns a non-nil *Err pointer if r represents the error case.
@@ -68,7 +72,7 @@ func (r *result[Shape, OK, Err]) Err() *Err {
 	if !r.isErr {
 		return nil
 	}
-	return (*Err)(unsafe.Pointer(&r.data))
+	return r.errData()
 }
 
 // Result returns (OK, zero value of Err, false) if r represents the OK case,
@@ -76,9 +80,89 @@ func (r *result[Shape, OK, Err]) Err() *Err {
 // This does not have a pointer receiver, so it can be chained.
 func (r result[Shape, OK, Err]) Result() (ok OK, err Err, isErr bool) {
 	if r.isErr {
-		return ok, *(*Err)(unsafe.Pointer(&r.data)), true
+		return ok, *r.errData(), true
+	}
+	return *r.okData(), err, false
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON object with a single key, "ok" or "err".
+// The value is null if the result case has no associated type.
+func (r result[Shape, OK, Err]) MarshalJSON() ([]byte, error) {
+	if r.isErr {
+		return marshalCaseJSON(resultErrJSON, *r.errData())
+	}
+	return marshalCaseJSON(resultOKJSON, *r.okData())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON object with a single key, "ok" or "err".
+func (r *result[Shape, OK, Err]) UnmarshalJSON(data []byte) error {
+	name, value, err := unmarshalCaseJSON(data)
+	if err != nil || name == "" {
+		return err
+	}
+	switch name {
+	case resultOKJSON:
+		var ok OK
+		err = unmarshalCaseValueJSON(value, &ok)
+		if err != nil {
+			return err
+		}
+		*r = result[Shape, OK, Err]{}
+		*r.okData() = ok
+	case resultErrJSON:
+		var e Err
+		err = unmarshalCaseValueJSON(value, &e)
+		if err != nil {
+			return err
+		}
+		*r = result[Shape, OK, Err]{isErr: ResultErr}
+		*r.errData() = e
+	default:
+		return errNoMatchingCase
+	}
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON object with a single key, "ok" or "err",
+// with a null value.
+func (r BoolResult) MarshalJSON() ([]byte, error) {
+	if r == BoolResult(ResultErr) {
+		return marshalCaseJSON(resultErrJSON, struct{}{})
 	}
-	return *(*OK)(unsafe.Pointer(&r.data)), err, false
+	return marshalCaseJSON(resultOKJSON, struct{}{})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON object with a single key, "ok" or "err".
+func (r *BoolResult) UnmarshalJSON(data []byte) error {
+	name, _, err := unmarshalCaseJSON(data)
+	if err != nil || name == "" {
+		return err
+	}
+	switch name {
+	case resultOKJSON:
+		*r = BoolResult(ResultOK)
+	case resultErrJSON:
+		*r = BoolResult(ResultErr)
+	default:
+		return errNoMatchingCase
+	}
+	return nil
+}
+
+const (
+	resultOKJSON  = "ok"
+	resultErrJSON = "err"
+)
+
+// okData returns a pointer to the storage for the OK value of r.
+func (r *result[Shape, OK, Err]) okData() *OK {
+	return (*OK)(unsafe.Pointer(&r.data))
+}
+
+// errData returns a pointer to the storage for the Err value of r,
+// which follows the OK value if Shape is a [SplitResult].
+func (r *result[Shape, OK, Err]) errData() *Err {
+	return (*Err)(unsafe.Add(unsafe.Pointer(&r.data), errOffset[Shape, OK, Err]()))
 }
 
 // This function is sized so it can be inlined and optimized away.
@@ -87,8 +171,9 @@ func (r *result[Shape, OK, Err]) validate() {
 	var ok OK
 	var err Err
 
-	// Check if size of Shape is greater than both OK and Err
-	if unsafe.Sizeof(shape) > unsafe.Sizeof(ok) && unsafe.Sizeof(shape) > unsafe.Sizeof(err) {
+	// Check if size of Shape is greater than both OK and Err, but Shape is not a SplitResult
+	if unsafe.Sizeof(shape) > unsafe.Sizeof(ok) && unsafe.Sizeof(shape) > unsafe.Sizeof(err) &&
+		!isSplitResult[Shape, OK, Err]() {
 		panic("result: size of data type > OK and Err types")
 	}
 
@@ -114,7 +199,7 @@ func OK[R AnyResult[Shape, OK, Err], Shape, OK, Err any](ok OK) R {
 	var r Result[Shape, OK, Err]
 	r.validate()
 	r.isErr = ResultOK
-	*((*OK)(unsafe.Pointer(&r.data))) = ok
+	*r.okData() = ok
 	return R(r)
 }
 
@@ -124,6 +209,98 @@ func Err[R AnyResult[Shape, OK, Err], Shape, OK, Err any](err Err) R {
 	var r Result[Shape, OK, Err]
 	r.validate()
 	r.isErr = ResultErr
-	*((*Err)(unsafe.Pointer(&r.data))) = err
+	*r.errData() = err
 	return R(r)
 }
+
+// ResultError represents the error case of a Component Model result as a Go error.
+// It is returned by [ResultValue] and generated functions that return a Go error.
+type ResultError[Err any] struct {
+	Err Err
+}
+
+// Error implements the [error] interface.
+// If Err implements [error] or [fmt.Stringer], or is a string, its value is returned.
+func (e *ResultError[Err]) Error() string {
+	switch v := any(e.Err).(type) {
+	case error:
+		return v.Error()
+	case interface{ String() string }:
+		return v.String()
+	case string:
+		return v
+	}
+	return "cm: error result"
+}
+
+// Unwrap returns e.Err if it implements the [error] interface, otherwise nil.
+func (e *ResultError[Err]) Unwrap() error {
+	if err, ok := any(e.Err).(error); ok {
+		return err
+	}
+	return nil
+}
+
+// ResultValue converts [Result] r into a Go-style (value, error) pair.
+// If r represents the error case, the returned error is a *[ResultError] wrapping the error value.
+func ResultValue[R AnyResult[Shape, T, E], Shape, T, E any](r R) (T, error) {
+	ok, err, isErr := Result[Shape, T, E](r).Result()
+	if isErr {
+		return ok, &ResultError[E]{Err: err}
+	}
+	return ok, nil
+}
+
+// ErrResultConversion is returned by [ResultFrom] when an error cannot be converted
+// into the error type of a result.
+var ErrResultConversion = errors.New("cm: cannot convert error into result error type")
+
+// ResultFrom converts a Go-style (value, error) pair into a result of type R.
+// Pass Result[Shape, OK, Err] or a named result type as the first type argument.
+//
+// If err is nil, the OK result is returned. Otherwise the error value is taken from
+// a *[ResultError] in the error chain of err, or from a value of type Err in the error chain
+// of err if Err implements [error]. If Err is a string, the error message is used.
+// If err cannot be converted into a value of type Err, ResultFrom returns an error result
+// with the zero value of Err, and [ErrResultConversion]. Use [ResultFromFunc] to convert
+// other errors into a value of type Err.
+func ResultFrom[R AnyResult[Shape, T, E], Shape, T, E any](ok T, err error) (R, error) {
+	if err == nil {
+		return OK[R](ok), nil
+	}
+	if e, converted := resultErr[E](err); converted {
+		return Err[R](e), nil
+	}
+	var e E
+	if s, isString := any(&e).(*string); isString {
+		*s = err.Error()
+		return Err[R](e), nil
+	}
+	return Err[R](e), ErrResultConversion
+}
+
+// ResultFromFunc converts a Go-style (value, error) pair into a result of type R,
+// like [ResultFrom], calling mapErr to convert err into a value of type Err
+// if err does not wrap a *[ResultError] or a value of type Err.
+func ResultFromFunc[R AnyResult[Shape, T, E], Shape, T, E any](ok T, err error, mapErr func(error) E) R {
+	if err == nil {
+		return OK[R](ok)
+	}
+	if e, converted := resultErr[E](err); converted {
+		return Err[R](e)
+	}
+	return Err[R](mapErr(err))
+}
+
+// resultErr returns the error value of type E from a *[ResultError] in the error chain of err,
+// or from a value of type E in the error chain of err if E implements [error].
+func resultErr[E any](err error) (e E, converted bool) {
+	var re *ResultError[E]
+	if errors.As(err, &re) {
+		return re.Err, true
+	}
+	if _, isError := any(e).(error); isError && errors.As(err, &e) {
+		return e, true
+	}
+	return e, false
+}
@@ -1,6 +1,7 @@
 package cm
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"testing"
@@ -61,6 +62,89 @@ func TestResultResult(t *testing.T) {
 	}
 }
 
+type testErrorCode uint8
+
+func (e testErrorCode) String() string { return "error code " + string('0'+rune(e)) }
+
+type testError struct{ msg string }
+
+func (e testError) Error() string { return e.msg }
+
+func TestResultValue(t *testing.T) {
+	v, err := ResultValue(OK[Result[string, string, testErrorCode]]("hello"))
+	if v != "hello" || err != nil {
+		t.Errorf("ResultValue(): (%q, %v), expected (\"hello\", nil)", v, err)
+	}
+
+	type named Result[string, string, testErrorCode]
+	v, err = ResultValue(Err[named](testErrorCode(7)))
+	if v != "" || err == nil {
+		t.Fatalf("ResultValue(): (%q, %v), expected (\"\", non-nil)", v, err)
+	}
+	var re *ResultError[testErrorCode]
+	if !errors.As(err, &re) || re.Err != 7 {
+		t.Errorf("ResultValue(): err = %#v, expected *ResultError wrapping 7", err)
+	}
+	if got, want := err.Error(), "error code 7"; got != want {
+		t.Errorf("Error(): %q, expected %q", got, want)
+	}
+	if errors.Unwrap(err) != nil {
+		t.Errorf("Unwrap(): expected nil")
+	}
+
+	_, err = ResultValue(Err[Result[testError, struct{}, testError]](testError{"boom"}))
+	var te testError
+	if !errors.As(err, &te) || te.msg != "boom" {
+		t.Errorf("errors.As(%v): expected testError", err)
+	}
+}
+
+func TestResultFrom(t *testing.T) {
+	r, err := ResultFrom[Result[string, string, testErrorCode]]("hello", nil)
+	if ok := r.OK(); ok == nil || *ok != "hello" || err != nil {
+		t.Errorf("ResultFrom(\"hello\", nil): expected OK result")
+	}
+
+	r, err = ResultFrom[Result[string, string, testErrorCode]]("", fmt.Errorf("wrapped: %w", &ResultError[testErrorCode]{Err: 3}))
+	if e := r.Err(); e == nil || *e != 3 || err != nil {
+		t.Errorf("ResultFrom(*ResultError): expected error result 3")
+	}
+
+	r2, err := ResultFrom[Result[testError, struct{}, testError]](struct{}{}, fmt.Errorf("wrapped: %w", testError{"boom"}))
+	if e := r2.Err(); e == nil || e.msg != "boom" || err != nil {
+		t.Errorf("ResultFrom(testError): expected error result \"boom\"")
+	}
+
+	r3, err := ResultFrom[Result[string, uint32, string]](0, errors.New("message"))
+	if e := r3.Err(); e == nil || *e != "message" || err != nil {
+		t.Errorf("ResultFrom(error): expected error result \"message\"")
+	}
+
+	r, err = ResultFrom[Result[string, string, testErrorCode]]("", errors.New("message"))
+	if e := r.Err(); e == nil || *e != 0 || !errors.Is(err, ErrResultConversion) {
+		t.Errorf("ResultFrom(unconvertible): got (%v, %v), expected error result 0 and %v", r, err, ErrResultConversion)
+	}
+}
+
+func TestResultFromFunc(t *testing.T) {
+	mapErr := func(err error) testErrorCode { return 7 }
+
+	r := ResultFromFunc[Result[string, string, testErrorCode]]("hello", nil, mapErr)
+	if ok := r.OK(); ok == nil || *ok != "hello" {
+		t.Errorf("ResultFromFunc(\"hello\", nil): expected OK result")
+	}
+
+	r = ResultFromFunc[Result[string, string, testErrorCode]]("", &ResultError[testErrorCode]{Err: 3}, mapErr)
+	if e := r.Err(); e == nil || *e != 3 {
+		t.Errorf("ResultFromFunc(*ResultError): expected error result 3")
+	}
+
+	r = ResultFromFunc[Result[string, string, testErrorCode]]("", errors.New("message"), mapErr)
+	if e := r.Err(); e == nil || *e != 7 {
+		t.Errorf("ResultFromFunc(error): expected error result 7")
+	}
+}
+
 func TestResultLayout(t *testing.T) {
 	// 8 on 64-bit, 4 on 32-bit
 	ptrSize := unsafe.Sizeof(uintptr(0))
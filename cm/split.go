@@ -0,0 +1,75 @@
+package cm
+
+import "unsafe"
+
+// SplitShape is implemented by a pointer to the Shape type of a [Variant]
+// that stores each case in separate memory, rather than overlapping all cases in the same memory.
+// Use [NewSplit] and [SplitCase] to create and access a variant with split storage.
+//
+// A variant or result whose cases mix pointer and non-pointer values cannot overlap them
+// without the Go garbage collector seeing a non-pointer value where it expects a pointer,
+// or missing a pointer stored where it expects a non-pointer value.
+// Split storage does not match the [Canonical ABI] memory layout, so generated bindings
+// convert values with split storage to and from the Canonical ABI layout when they cross
+// the boundary between the host and guest.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+type SplitShape interface {
+	// CaseOffset returns the offset in bytes of the storage for case tag
+	// from the start of the shape.
+	CaseOffset(tag uint32) uintptr
+}
+
+// SplitShapeOf is a type constraint for a pointer to Shape that implements [SplitShape].
+type SplitShapeOf[Shape any] interface {
+	*Shape
+	SplitShape
+}
+
+// NewSplit returns a [Variant] with split storage of type Shape, with tag and a value of type T.
+// It is equivalent to [New] for a variant whose *Shape implements [SplitShape].
+func NewSplit[V AnyVariant[Tag, Shape, Align], PShape SplitShapeOf[Shape], Tag Discriminant, Shape, Align any, T any](tag Tag, data T) V {
+	validateVariant[Tag, Shape, Align, T]()
+	var v variant[Tag, Shape, Align]
+	v.tag = tag
+	*(*T)(unsafe.Add(unsafe.Pointer(&v.data), PShape(&v.data).CaseOffset(uint32(tag)))) = data
+	return *(*V)(unsafe.Pointer(&v))
+}
+
+// SplitCase returns a non-nil *T if the [Variant] with split storage of type Shape is equal to tag,
+// otherwise it returns nil. It is equivalent to [Case] for a variant whose *Shape implements [SplitShape].
+func SplitCase[T any, V AnyVariant[Tag, Shape, Align], PShape SplitShapeOf[Shape], Tag Discriminant, Shape, Align any](v *V, tag Tag) *T {
+	validateVariant[Tag, Shape, Align, T]()
+	v2 := (*variant[Tag, Shape, Align])(unsafe.Pointer(v))
+	if v2.tag == tag {
+		return (*T)(unsafe.Add(unsafe.Pointer(&v2.data), PShape(&v2.data).CaseOffset(uint32(tag))))
+	}
+	return nil
+}
+
+// SplitResult is the Shape type of a [Result] that stores OK and Err in separate fields.
+// Use it as the Shape type argument for a Result whose OK and Err types cannot share memory,
+// for example Result[SplitResult[string, uint64], string, uint64].
+type SplitResult[OK, Err any] struct {
+	_   HostLayout
+	ok  OK
+	err Err
+}
+
+// isSplitResult reports whether Shape is [SplitResult][OK, Err].
+// The layout of a [Result] is determined by the type of Shape, not its size,
+// so a Shape of the same size that is not a SplitResult is never treated as one.
+func isSplitResult[Shape, OK, Err any]() bool {
+	_, split := any((*Shape)(nil)).(*SplitResult[OK, Err])
+	return split
+}
+
+// errOffset returns the offset in bytes of the Err value in the Shape type of a [Result].
+// A [SplitResult] stores Err after OK. Otherwise OK and Err share storage.
+func errOffset[Shape, OK, Err any]() uintptr {
+	if isSplitResult[Shape, OK, Err]() {
+		var s SplitResult[OK, Err]
+		return unsafe.Offsetof(s.err)
+	}
+	return 0
+}
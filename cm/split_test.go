@@ -0,0 +1,239 @@
+package cm
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// splitVariant represents variant { s(string), u(u64), p(list<u8>), a(tuple<u32, u64>), b(tuple<u32, string, u32>), none }.
+type splitVariant Variant[uint8, splitVariantShape, uint64]
+
+type splitVariantShape struct {
+	_ HostLayout
+	s string
+	u uint64
+	p List[uint8]
+	a splitTuple64
+	b splitTupleString
+}
+
+// splitTuple64 and splitTupleString can share memory on targets with 32-bit pointers,
+// where the u64 in splitTuple64 overlaps the string length and u32 in splitTupleString.
+// On targets with 64-bit pointers, the u64 overlaps the string pointer.
+type (
+	splitTuple64     = Tuple[uint32, uint64]
+	splitTupleString = Tuple3[uint32, string, uint32]
+)
+
+func (s *splitVariantShape) CaseOffset(tag uint32) uintptr {
+	switch tag {
+	case 0:
+		return unsafe.Offsetof(s.s)
+	case 1:
+		return unsafe.Offsetof(s.u)
+	case 2:
+		return unsafe.Offsetof(s.p)
+	case 3:
+		return unsafe.Offsetof(s.a)
+	case 4:
+		return unsafe.Offsetof(s.b)
+	}
+	return 0
+}
+
+type splitResult = Result[SplitResult[string, uint64], string, uint64]
+
+func TestSplitResult(t *testing.T) {
+	r := OK[splitResult]("hello")
+	if got, want := *r.OK(), "hello"; got != want {
+		t.Errorf("OK(): %q, expected %q", got, want)
+	}
+	if r.Err() != nil {
+		t.Errorf("Err(): non-nil, expected nil")
+	}
+
+	r = Err[splitResult](uint64(42))
+	if got, want := *r.Err(), uint64(42); got != want {
+		t.Errorf("Err(): %d, expected %d", got, want)
+	}
+	_, err, isErr := r.Result()
+	if !isErr || err != 42 {
+		t.Errorf("Result(): %d, %t, expected 42, true", err, isErr)
+	}
+
+	// Cases must not share storage
+	var s SplitResult[string, uint64]
+	if got, want := errOffset[SplitResult[string, uint64], string, uint64](), unsafe.Offsetof(s.err); got != want {
+		t.Errorf("errOffset: %d, expected %d", got, want)
+	}
+	if got := errOffset[string, string, struct{}](); got != 0 {
+		t.Errorf("errOffset for shared storage: %d, expected 0", got)
+	}
+}
+
+// sameSizeShape has the same size as SplitResult[string, uint64], but is not a SplitResult.
+type sameSizeShape struct {
+	_ HostLayout
+	s string
+	u uint64
+}
+
+func TestSplitResultSameSize(t *testing.T) {
+	if got, want := unsafe.Sizeof(sameSizeShape{}), unsafe.Sizeof(SplitResult[string, uint64]{}); got != want {
+		t.Fatalf("size of sameSizeShape: %d, expected %d", got, want)
+	}
+	if got := errOffset[sameSizeShape, string, uint64](); got != 0 {
+		t.Errorf("errOffset: %d, expected 0", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("OK did not panic for a Shape that is not a SplitResult")
+		}
+	}()
+	OK[Result[sameSizeShape, string, uint64]]("hello")
+}
+
+func TestSplitVariant(t *testing.T) {
+	v := NewSplit[splitVariant](0, "hello")
+	if got := SplitCase[string](&v, 0); got == nil || *got != "hello" {
+		t.Errorf("SplitCase[string](0): %v, expected hello", got)
+	}
+	if got := SplitCase[uint64](&v, 1); got != nil {
+		t.Errorf("SplitCase[uint64](1): %v, expected nil", got)
+	}
+
+	v = NewSplit[splitVariant](1, uint64(7))
+	if got := SplitCase[uint64](&v, 1); got == nil || *got != 7 {
+		t.Errorf("SplitCase[uint64](1): %v, expected 7", got)
+	}
+	// The string storage must be untouched by the u64 case.
+	shape := (*variant[uint8, splitVariantShape, uint64])(unsafe.Pointer(&v)).data
+	if shape.s != "" {
+		t.Errorf("string storage: %q, expected empty", shape.s)
+	}
+}
+
+func TestSplitVariant64(t *testing.T) {
+	var a splitTuple64
+	var b splitTupleString
+	if unsafe.Sizeof(uintptr(0)) == 8 && unsafe.Offsetof(a.F1) != unsafe.Offsetof(b.F1) {
+		t.Errorf("u64 offset %d, expected string pointer offset %d", unsafe.Offsetof(a.F1), unsafe.Offsetof(b.F1))
+	}
+
+	v := NewSplit[splitVariant](4, splitTupleString{F0: 1, F1: "hello", F2: 2})
+	if got := SplitCase[splitTupleString](&v, 4); got == nil || got.F1 != "hello" {
+		t.Errorf("SplitCase[splitTupleString](4): %v, expected hello", got)
+	}
+	v = NewSplit[splitVariant](3, splitTuple64{F0: 1, F1: 42})
+	if got := SplitCase[splitTuple64](&v, 3); got == nil || got.F1 != 42 {
+		t.Errorf("SplitCase[splitTuple64](3): %v, expected 42", got)
+	}
+	// The string storage must be untouched by the tuple<u32, u64> case.
+	shape := (*variant[uint8, splitVariantShape, uint64])(unsafe.Pointer(&v)).data
+	if shape.b.F1 != "" {
+		t.Errorf("string storage: %q, expected empty", shape.b.F1)
+	}
+}
+
+// TestSplitGC stores values that resemble invalid or stale pointers alongside pointer values
+// in variants and results with split storage, and forces garbage collections and stack copies.
+// The Go runtime crashes if it finds an invalid pointer in memory it expects to hold a pointer.
+func TestSplitGC(t *testing.T) {
+	if !strings.Contains(os.Getenv("GODEBUG"), "invalidptr=1") {
+		// GODEBUG is read at startup, so rerun this test in a subprocess.
+		if runtime.GOOS == "wasip1" || runtime.GOOS == "js" {
+			t.Skip("cannot run subprocess on " + runtime.GOOS)
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			t.Skip(err)
+		}
+		godebug := "invalidptr=1"
+		if s := os.Getenv("GODEBUG"); s != "" {
+			godebug += "," + s
+		}
+		cmd := exec.Command(exe, "-test.run=^"+t.Name()+"$", "-test.count=1")
+		cmd.Env = append(os.Environ(), "GODEBUG="+godebug)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return
+	}
+
+	const n = 1000
+	results := make([]splitResult, n)
+	variants := make([]splitVariant, n)
+	for i := range n {
+		// Values that would be invalid pointers: small integers and addresses of unreachable objects.
+		bad := uint64(i%4095 + 1)
+		if i%2 == 1 {
+			bad = uint64(uintptr(unsafe.Pointer(new([64]byte))))
+		}
+		switch i % 5 {
+		case 0:
+			results[i] = OK[splitResult](strconv.Itoa(i))
+			variants[i] = NewSplit[splitVariant](0, strconv.Itoa(i))
+		case 1:
+			results[i] = Err[splitResult](bad)
+			variants[i] = NewSplit[splitVariant](1, bad)
+		case 2:
+			results[i] = Err[splitResult](bad)
+			variants[i] = NewSplit[splitVariant](2, ToList([]uint8(strconv.Itoa(i))))
+		case 3:
+			results[i] = Err[splitResult](bad)
+			variants[i] = NewSplit[splitVariant](3, splitTuple64{F0: uint32(i), F1: bad})
+		case 4:
+			results[i] = OK[splitResult](strconv.Itoa(i))
+			variants[i] = NewSplit[splitVariant](4, splitTupleString{F0: uint32(i), F1: strconv.Itoa(i)})
+		}
+	}
+
+	for range 10 {
+		runtime.GC()
+		splitStack(64, results[1], variants[1])
+	}
+
+	for i := range n {
+		want := strconv.Itoa(i)
+		switch i % 5 {
+		case 0:
+			if got := *results[i].OK(); got != want {
+				t.Fatalf("results[%d]: %q, expected %q", i, got, want)
+			}
+			if got := *SplitCase[string](&variants[i], 0); got != want {
+				t.Fatalf("variants[%d]: %q, expected %q", i, got, want)
+			}
+		case 2:
+			if got := string(SplitCase[List[uint8]](&variants[i], 2).Slice()); got != want {
+				t.Fatalf("variants[%d]: %q, expected %q", i, got, want)
+			}
+		case 4:
+			if got := *results[i].OK(); got != want {
+				t.Fatalf("results[%d]: %q, expected %q", i, got, want)
+			}
+			if got := SplitCase[splitTupleString](&variants[i], 4).F1; got != want {
+				t.Fatalf("variants[%d]: %q, expected %q", i, got, want)
+			}
+		}
+	}
+}
+
+// splitStack grows the goroutine stack with r and v on it, forcing the stack to be copied,
+// then forces a garbage collection.
+//
+//go:noinline
+func splitStack(depth int, r splitResult, v splitVariant) {
+	var pad [256]byte
+	if depth == 0 {
+		runtime.GC()
+		return
+	}
+	splitStack(depth-1, r, v)
+	runtime.KeepAlive(pad)
+}
@@ -1,8 +1,12 @@
 package cm
 
-// Stream represents the Component Model [stream] type.
-// A stream is a special case of stream. In non-error cases,
-// a stream delivers exactly one value before being automatically closed.
+import (
+	"io"
+	"unsafe"
+)
+
+// Stream represents the readable end of a Component Model [stream] type.
+// A stream asynchronously delivers zero or more values of type T before being closed.
 //
 // [stream]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Explainer.md#asynchronous-value-types
 type Stream[T any] struct {
@@ -12,4 +16,158 @@ type Stream[T any] struct {
 
 type stream[T any] uint32
 
-// TODO: implement methods on type stream
+// Read reads up to len(buf) values from stream s into buf, blocking the calling goroutine
+// until at least one value is available. It returns the number of values read.
+// If the writable end of the stream was dropped, Read returns [io.EOF].
+func (s stream[T]) Read(buf []T) (int, error) {
+	vt := streamVTable(uint32(s))
+	result := vt.Read(uint32(s), unsafe.Pointer(unsafe.SliceData(buf)), uint32(len(buf)))
+	if result == copyBlocked {
+		result = Await(Waitable(s)).Payload
+	}
+	code, n := unpackCopyResult(result)
+	if code == CopyDropped {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// CancelRead represents the Canonical ABI [stream.cancel-read] function.
+// It cancels an in-progress read, and returns the number of values read before cancellation.
+//
+// [stream.cancel-read]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturecancel-readwrite
+func (s stream[T]) CancelRead() int {
+	_, n := unpackCopyResult(streamVTable(uint32(s)).CancelRead(uint32(s)))
+	return n
+}
+
+// Drop represents the Canonical ABI [stream.drop-readable] function.
+//
+// [stream.drop-readable]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturedrop-readablewritable
+func (s stream[T]) Drop() {
+	streamVTable(uint32(s)).DropReadable(uint32(s))
+	delete(handleVTables, uint32(s))
+}
+
+// StreamWriter represents the writable end of a Component Model [stream] type.
+// The writable end of a stream cannot be passed to another component.
+//
+// [stream]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Explainer.md#asynchronous-value-types
+type StreamWriter[T any] uint32
+
+// Write writes the values in buf to the stream, blocking the calling goroutine
+// until they are all written. It returns the number of values written.
+// If the readable end of the stream was dropped, Write returns [ErrDropped].
+func (w StreamWriter[T]) Write(buf []T) (int, error) {
+	vt := streamVTable(uint32(w))
+	var n int
+	for n < len(buf) {
+		result := vt.Write(uint32(w), unsafe.Pointer(unsafe.SliceData(buf[n:])), uint32(len(buf)-n))
+		if result == copyBlocked {
+			result = Await(Waitable(w)).Payload
+		}
+		code, count := unpackCopyResult(result)
+		n += count
+		if code == CopyDropped {
+			return n, ErrDropped
+		}
+	}
+	return n, nil
+}
+
+// CancelWrite represents the Canonical ABI [stream.cancel-write] function.
+// It cancels an in-progress write, and returns the number of values written before cancellation.
+//
+// [stream.cancel-write]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturecancel-readwrite
+func (w StreamWriter[T]) CancelWrite() int {
+	_, n := unpackCopyResult(streamVTable(uint32(w)).CancelWrite(uint32(w)))
+	return n
+}
+
+// Drop represents the Canonical ABI [stream.drop-writable] function.
+// Dropping the writable end closes the stream.
+//
+// [stream.drop-writable]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturedrop-readablewritable
+func (w StreamWriter[T]) Drop() {
+	streamVTable(uint32(w)).DropWritable(uint32(w))
+	delete(handleVTables, uint32(w))
+}
+
+// NewStream represents the Canonical ABI [stream.new] function.
+// It returns the writable and readable ends of a new stream with values of type T,
+// using the type-specific Canonical ABI functions in vt.
+// Generated bindings declare a constructor that calls NewStream for each stream type
+// passed to an imported function or returned from an exported function.
+//
+// [stream.new]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-streamfuturenew
+func NewStream[T any](vt *StreamVTable) (StreamWriter[T], Stream[T]) {
+	if vt.New == nil {
+		panic("cm: stream intrinsics are not available on this target")
+	}
+	w, r := unpackEnds(vt.New())
+	handleVTables[w] = vt
+	handleVTables[r] = vt
+	var s Stream[T]
+	s.stream = stream[T](r)
+	return StreamWriter[T](w), s
+}
+
+// BindStream binds the readable end s of a stream received from another component to vt.
+// It is called by generated bindings, and should not be called directly.
+func BindStream[T any](s Stream[T], vt *StreamVTable) {
+	handleVTables[uint32(s.stream)] = vt
+}
+
+// StreamVTable represents the Canonical ABI functions for a stream with a specific value type.
+// In the Component Model, these functions are imported separately for each stream type
+// in the type of each function, so generated bindings declare a StreamVTable for each
+// import module and stream type, and bind each stream handle to its StreamVTable.
+type StreamVTable struct {
+	New          func() uint64
+	Read         func(handle uint32, buf unsafe.Pointer, n uint32) uint32
+	Write        func(handle uint32, buf unsafe.Pointer, n uint32) uint32
+	CancelRead   func(handle uint32) uint32
+	CancelWrite  func(handle uint32) uint32
+	DropReadable func(handle uint32)
+	DropWritable func(handle uint32)
+}
+
+func streamVTable(handle uint32) *StreamVTable {
+	vt, ok := handleVTables[handle].(*StreamVTable)
+	if !ok {
+		panic("cm: stream handle is not bound to a StreamVTable")
+	}
+	return vt
+}
+
+// handleVTables maps each live stream or future handle to its *[StreamVTable] or *[FutureVTable].
+// Handles share a single table in a component instance, so a handle is bound to one vtable at a time.
+// Entries for handles transferred to another component are replaced when the handle is reused.
+var handleVTables = make(map[uint32]any)
+
+// CopyResult represents the result of a read from or write to a [Stream] or [Future].
+type CopyResult uint32
+
+const (
+	// CopyCompleted indicates the read or write completed.
+	CopyCompleted CopyResult = 0
+
+	// CopyDropped indicates the other end was dropped.
+	CopyDropped CopyResult = 1
+
+	// CopyCancelled indicates the read or write was cancelled.
+	CopyCancelled CopyResult = 2
+)
+
+// copyBlocked is returned from a read or write that has not yet completed.
+const copyBlocked = 0xffff_ffff
+
+// unpackCopyResult unpacks a packed copy result into a [CopyResult] and a count of values.
+func unpackCopyResult(v uint32) (CopyResult, int) {
+	return CopyResult(v & 0xf), int(v >> 4)
+}
+
+// unpackEnds unpacks the writable and readable ends returned from stream.new or future.new.
+func unpackEnds(v uint64) (writable, readable uint32) {
+	return uint32(v >> 32), uint32(v)
+}
@@ -1,5 +1,7 @@
 package cm
 
+import "encoding/json"
+
 // Tuple represents a [Component Model tuple] with 2 fields.
 //
 // [Component Model tuple]: https://component-model.bytecodealliance.org/design/wit.html#tuples
@@ -240,6 +242,156 @@ type Tuple16[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T1
 	F15 T15
 }
 
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple[T0, T1]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple[T0, T1]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple3[T0, T1, T2]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple3[T0, T1, T2]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple4[T0, T1, T2, T3]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple4[T0, T1, T2, T3]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple5[T0, T1, T2, T3, T4]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple5[T0, T1, T2, T3, T4]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple6[T0, T1, T2, T3, T4, T5]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple6[T0, T1, T2, T3, T4, T5]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple7[T0, T1, T2, T3, T4, T5, T6]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple7[T0, T1, T2, T3, T4, T5, T6]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple8[T0, T1, T2, T3, T4, T5, T6, T7]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple8[T0, T1, T2, T3, T4, T5, T6, T7]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple9[T0, T1, T2, T3, T4, T5, T6, T7, T8]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple9[T0, T1, T2, T3, T4, T5, T6, T7, T8]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7, &t.F8)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple10[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple10[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7, &t.F8, &t.F9)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple11[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple11[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7, &t.F8, &t.F9, &t.F10)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple12[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple12[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7, &t.F8, &t.F9, &t.F10, &t.F11)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple13[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11, t.F12})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple13[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7, &t.F8, &t.F9, &t.F10, &t.F11, &t.F12)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple14[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11, t.F12, t.F13})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple14[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7, &t.F8, &t.F9, &t.F10, &t.F11, &t.F12, &t.F13)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple15[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11, t.F12, t.F13, t.F14})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple15[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7, &t.F8, &t.F9, &t.F10, &t.F11, &t.F12, &t.F13, &t.F14)
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the tuple fields.
+func (t Tuple16[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T15]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.F0, t.F1, t.F2, t.F3, t.F4, t.F5, t.F6, t.F7, t.F8, t.F9, t.F10, t.F11, t.F12, t.F13, t.F14, t.F15})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array into the tuple fields.
+func (t *Tuple16[T0, T1, T2, T3, T4, T5, T6, T7, T8, T9, T10, T11, T12, T13, T14, T15]) UnmarshalJSON(data []byte) error {
+	return unmarshalTupleJSON(data, &t.F0, &t.F1, &t.F2, &t.F3, &t.F4, &t.F5, &t.F6, &t.F7, &t.F8, &t.F9, &t.F10, &t.F11, &t.F12, &t.F13, &t.F14, &t.F15)
+}
+
 // MaxTuple specifies the maximum number of fields in a Tuple* type, currently [Tuple16].
 // See https://github.com/WebAssembly/component-model/issues/373 for more information.
 const MaxTuple = 16
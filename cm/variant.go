@@ -10,7 +10,8 @@ type Discriminant interface {
 
 // Variant represents a loosely-typed Component Model variant.
 // Shape and Align must be non-zero sized types. To create a variant with no associated
-// types, use an enum.
+// types, use an enum. All cases share the storage of Shape, unless *Shape implements [SplitShape],
+// which stores each case separately in Shape. Use [NewSplit] and [SplitCase] for split storage.
 type Variant[Tag Discriminant, Shape, Align any] struct {
 	_ HostLayout
 	variant[Tag, Shape, Align]
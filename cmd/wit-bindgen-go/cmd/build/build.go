@@ -0,0 +1,305 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/wasm"
+	"go.bytecodealliance.org/internal/wasmtools"
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/wit"
+)
+
+// Command is the CLI command for build.
+var Command = &cli.Command{
+	Name:      "build",
+	Usage:     "builds a Go package into a WebAssembly component",
+	ArgsUsage: "[package directory]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "wit",
+			Value:    "wit",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "path to the WIT file, directory, or JSON that defines the component world",
+		},
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world of the component (default: the only world)",
+		},
+		&cli.StringFlag{
+			Name:     "output",
+			Aliases:  []string{"o"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "output file (default: the package directory name with a .wasm extension)",
+		},
+		&cli.StringFlag{
+			Name:     "toolchain",
+			Value:    "go",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "Go toolchain to build with: go or tinygo",
+		},
+		&cli.StringFlag{
+			Name:     "target",
+			Value:    "wasip1",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "build target: wasip1 or wasip2 (tinygo only)",
+		},
+		&cli.StringFlag{
+			Name:     "buildmode",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "go build -buildmode, e.g. c-shared for a reactor (go toolchain only)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "adapter",
+			Usage: "core module adapter as [name=]path, where name defaults to wasi_snapshot_preview1",
+		},
+	},
+	Action: action,
+}
+
+// preview1 is the name of the core module imported by wasip1 modules.
+const preview1 = "wasi_snapshot_preview1"
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	pkg, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	if pkg == "-" {
+		pkg = "."
+	}
+
+	res, err := witcli.LoadWIT(ctx, cmd.String("wit"), nil, cmd.Bool("force-wit"))
+	if err != nil {
+		return err
+	}
+	w, err := findWorld(res, cmd.String("world"))
+	if err != nil {
+		return err
+	}
+	worldID := w.Package.Name
+	worldID.Extension = w.Name
+
+	adapters, err := loadAdapters(cmd.StringSlice("adapter"))
+	if err != nil {
+		return err
+	}
+
+	out := cmd.String("output")
+	if out == "" {
+		abs, err := filepath.Abs(pkg)
+		if err != nil {
+			return err
+		}
+		out = filepath.Base(abs) + ".wasm"
+	}
+
+	tmp, err := os.MkdirTemp("", "wit-bindgen-go-build-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	witPath, err := filepath.Abs(cmd.String("wit"))
+	if err != nil {
+		return err
+	}
+	core := filepath.Join(tmp, "core.wasm")
+	build, err := buildCommand(ctx, cmd, pkg, core, witPath, worldID.String())
+	if err != nil {
+		return err
+	}
+	build.Stdout = cmd.Writer
+	build.Stderr = cmd.ErrWriter
+	if build.Stderr == nil {
+		build.Stderr = os.Stderr
+	}
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("%s: %w", strings.Join(build.Args, " "), err)
+	}
+
+	module, err := os.ReadFile(core)
+	if err != nil {
+		return err
+	}
+	b, err := wasm.Decode(module)
+	if err != nil {
+		return err
+	}
+
+	// TinyGo may create a component directly when targeting wasip2.
+	if b.Component {
+		return os.WriteFile(out, module, 0o644)
+	}
+
+	imports, err := b.Imports()
+	if err != nil {
+		return err
+	}
+	if adapters[preview1] == nil && slices.ContainsFunc(imports, func(imp wasm.Import) bool { return imp.Module == preview1 }) {
+		return fmt.Errorf("module imports %s: use --adapter to provide a WASI Preview 1 adapter module", preview1)
+	}
+
+	component, err := newComponent(ctx, b, res, w, adapters)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, component, 0o644)
+}
+
+// newComponent embeds a component-type custom section that encodes world w into core module b,
+// then creates a component from it with the core module adapters.
+func newComponent(ctx context.Context, b *wasm.Binary, res *wit.Resolve, w *wit.World, adapters map[string][]byte) ([]byte, error) {
+	worldID := w.Package.Name
+	worldID.Extension = w.Name
+	if wit.HasAsync(w) {
+		return nil, fmt.Errorf("world %s: cannot embed component type: wasm-tools does not yet support async functions", worldID.String())
+	}
+
+	wasmTools, err := wasmtools.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer wasmTools.Close(ctx)
+
+	witText := res.WIT(wit.Filter(w, nil), "")
+	componentType, err := wasmTools.ComponentEmbed(ctx, witText, worldID.String())
+	if err != nil {
+		return nil, err
+	}
+	sections := make([]wasm.Section, 0, len(b.Sections)+1)
+	for _, s := range b.Sections {
+		sections = append(sections, s)
+	}
+	sections = append(sections, &wasm.CustomSection{
+		Name:     "component-type:" + worldID.String(),
+		Contents: componentType,
+	})
+	var buf bytes.Buffer
+	err = wasm.Write(&buf, sections)
+	if err != nil {
+		return nil, err
+	}
+
+	return wasmTools.ComponentNew(ctx, buf.Bytes(), adapters)
+}
+
+// buildCommand returns the command to build the Go package in directory pkg into core module out.
+func buildCommand(ctx context.Context, cmd *cli.Command, pkg, out, witPath, world string) (*exec.Cmd, error) {
+	target := cmd.String("target")
+	switch cmd.String("toolchain") {
+	case "go":
+		if target != "wasip1" {
+			return nil, fmt.Errorf("target %s is not supported by the go toolchain", target)
+		}
+		args := []string{"build", "-o", out}
+		if mode := cmd.String("buildmode"); mode != "" {
+			args = append(args, "-buildmode="+mode)
+		}
+		build := exec.CommandContext(ctx, "go", append(args, ".")...)
+		build.Dir = pkg
+		build.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+		return build, nil
+
+	case "tinygo":
+		if cmd.String("buildmode") != "" {
+			return nil, errors.New("--buildmode is not supported by the tinygo toolchain")
+		}
+		args := []string{"build", "-target=" + target, "-o", out}
+		if target == "wasip2" {
+			args = append(args, "-wit-package", witPath, "-wit-world", world)
+		}
+		build := exec.CommandContext(ctx, "tinygo", append(args, ".")...)
+		build.Dir = pkg
+		return build, nil
+	}
+	return nil, fmt.Errorf("unknown toolchain %s", cmd.String("toolchain"))
+}
+
+// loadAdapters loads adapter modules from [name=]path arguments.
+func loadAdapters(args []string) (map[string][]byte, error) {
+	adapters := make(map[string][]byte)
+	for _, arg := range args {
+		name, path, ok := strings.Cut(arg, "=")
+		if !ok {
+			name, path = preview1, arg
+		}
+		if adapters[name] != nil {
+			return nil, fmt.Errorf("duplicate adapter for %s", name)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		adapters[name] = data
+	}
+	return adapters, nil
+}
+
+// findWorld returns the world in r that matches pattern. If pattern is empty,
+// it returns the only world in the main package of r.
+func findWorld(r *wit.Resolve, pattern string) (*wit.World, error) {
+	if pattern == "" {
+		pkg, err := mainPackage(r)
+		if err != nil {
+			return nil, err
+		}
+		if n := pkg.Worlds.Len(); n != 1 {
+			return nil, fmt.Errorf("found %d worlds in package %s, use --world to select one", n, pkg.Name.String())
+		}
+		var w *wit.World
+		pkg.Worlds.All()(func(_ string, world *wit.World) bool {
+			w = world
+			return false
+		})
+		return w, nil
+	}
+	for _, w := range r.Worlds {
+		if w.Match(pattern) {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("world %s not found", pattern)
+}
+
+// mainPackage returns the main package of r, which no other package in r depends on.
+// Packages loaded from the deps directory of a WIT directory are dependencies of the main package.
+func mainPackage(r *wit.Resolve) (*wit.Package, error) {
+	var main []*wit.Package
+	for _, p := range r.Packages {
+		isDep := slices.ContainsFunc(r.Packages, func(q *wit.Package) bool {
+			return q != p && wit.DependsOn(q, p)
+		})
+		if !isDep {
+			main = append(main, p)
+		}
+	}
+	if len(main) != 1 {
+		names := make([]string, len(main))
+		for i, p := range main {
+			names[i] = p.Name.String()
+		}
+		return nil, fmt.Errorf("found %d packages that are not dependencies of another package (%s), use --world to select a world", len(main), strings.Join(names, ", "))
+	}
+	return main[0], nil
+}
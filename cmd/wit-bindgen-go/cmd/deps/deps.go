@@ -0,0 +1,62 @@
+package deps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/internal/witdeps"
+)
+
+// Command is the CLI command for deps.
+var Command = &cli.Command{
+	Name:  "deps",
+	Usage: "fetches the WIT package dependencies of a WIT directory into its deps directory",
+	Description: `Dependencies are resolved by scanning the WIT files in the directory for references to other packages.
+The source of each package is read from ` + witdeps.ManifestFile + ` in the WIT directory, which maps package names
+to OCI references or local paths, e.g.:
+
+	{
+	  "wasi:io": "ghcr.io/webassembly/wasi/io",
+	  "wasi:clocks@0.2.0": "../vendor/clocks"
+	}
+
+Each package is written to a WIT file in the deps directory, and the digest of its source is recorded in ` + witdeps.LockFile + `.`,
+	ArgsUsage: "[WIT directory]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "update",
+			Usage: "ignore the lockfile and resolve each dependency from the manifest again",
+		},
+		&cli.BoolFlag{
+			Name:  "plain-http",
+			Usage: "connect to OCI registries over HTTP rather than HTTPS",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	dir, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	if dir == "-" {
+		dir = "wit"
+	}
+
+	lock, err := witdeps.Sync(ctx, dir,
+		witdeps.Logger(witcli.Logger(cmd.Bool("verbose"), cmd.Bool("debug"))),
+		witdeps.Update(cmd.Bool("update")),
+		witdeps.PlainHTTP(cmd.Bool("plain-http")),
+	)
+	if err != nil {
+		return err
+	}
+	for _, p := range lock.Packages {
+		fmt.Fprintf(cmd.Writer, "%s\t%s\n", p.Name, p.Resolved)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/wit"
+)
+
+// Command is the CLI command for diff.
+var Command = &cli.Command{
+	Name:      "diff",
+	Usage:     "compares two versions of WIT packages and checks their version numbers",
+	ArgsUsage: "<old> <new>",
+	Action:    action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 2 {
+		return fmt.Errorf("found %d path arguments, expecting 2", cmd.Args().Len())
+	}
+
+	old, err := witcli.LoadWIT(ctx, cmd.Args().Get(0), nil, cmd.Bool("force-wit"))
+	if err != nil {
+		return err
+	}
+
+	new, err := witcli.LoadWIT(ctx, cmd.Args().Get(1), nil, cmd.Bool("force-wit"))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, d := range wit.Diff(old, new) {
+		fmt.Fprintf(cmd.Writer, "package %s: %s\n", d.Name(), d.Kind())
+		for _, c := range d.Changes {
+			fmt.Fprintf(cmd.Writer, "\t%s\n", c)
+		}
+		if err := d.CheckVersion(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
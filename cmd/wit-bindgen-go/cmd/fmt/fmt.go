@@ -0,0 +1,126 @@
+package fmt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/diff"
+	"go.bytecodealliance.org/wit/format"
+)
+
+// Command is the CLI command for fmt.
+var Command = &cli.Command{
+	Name:  "fmt",
+	Usage: "reformats WIT source files in canonical style",
+	Description: `Each WIT file named on the command line is rewritten in place. Directories are searched
+recursively for files with a .wit extension, skipping deps directories of vendored dependencies.
+With no arguments, WIT source is read from standard input and the result is written to standard output.`,
+	ArgsUsage: "[path...]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "l",
+			Usage: "list files whose formatting differs, rather than rewriting them",
+		},
+		&cli.BoolFlag{
+			Name:  "d",
+			Usage: "print diffs of formatting changes, rather than rewriting files",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	args := cmd.Args().Slice()
+	if len(args) == 0 {
+		src, err := io.ReadAll(cmd.Reader)
+		if err != nil {
+			return err
+		}
+		return formatFile(cmd, "<standard input>", src, 0)
+	}
+
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			err = processFile(cmd, arg)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != arg && d.Name() == "deps" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if filepath.Ext(path) != ".wit" {
+				return nil
+			}
+			return processFile(cmd, path)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func processFile(cmd *cli.Command, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return formatFile(cmd, path, src, info.Mode().Perm())
+}
+
+// formatFile formats WIT source src read from path. If perm is 0, src was read
+// from standard input, and the result is written to standard output.
+func formatFile(cmd *cli.Command, path string, src []byte, perm fs.FileMode) error {
+	out, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("%s:%w", path, err)
+	}
+
+	list, showDiff := cmd.Bool("l"), cmd.Bool("d")
+	if !list && !showDiff {
+		if perm == 0 {
+			_, err = cmd.Writer.Write(out)
+			return err
+		}
+		if bytes.Equal(src, out) {
+			return nil
+		}
+		return os.WriteFile(path, out, perm)
+	}
+
+	if bytes.Equal(src, out) {
+		return nil
+	}
+	if list {
+		fmt.Fprintln(cmd.Writer, path)
+	}
+	if showDiff {
+		fmt.Fprintf(cmd.Writer, "diff %s.orig %s\n", path, path)
+		fmt.Fprint(cmd.Writer, diff.Unified(path+".orig", path, string(src), string(out)))
+	}
+	return nil
+}
@@ -1,15 +1,20 @@
 package generate
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/urfave/cli/v3"
 
 	"go.bytecodealliance.org/internal/codec"
+	"go.bytecodealliance.org/internal/diff"
 	"go.bytecodealliance.org/internal/go/gen"
 	"go.bytecodealliance.org/internal/witcli"
 	"go.bytecodealliance.org/wit/bindgen"
@@ -21,14 +26,39 @@ var Command = &cli.Command{
 	Name:    "generate",
 	Aliases: []string{"go"},
 	Usage:   "generate Go bindings from from WIT (WebAssembly Interface Types)",
+	Description: `If no path argument is specified and a ` + projectFiles[0] + ` or ` + projectFiles[2] + ` project configuration
+file is found in the current directory, every job in the file is run, e.g.:
+
+	jobs:
+	  - path: wit
+	    world: example:app/app
+	    out: internal
+	    idiomatic-errors: true
+	    packages:
+	      wasi:io/streams: example.com/app/internal/streams
+
+Job fields have the same names as the flags of this command. Paths are relative to the configuration file.
+A job can generate multiple worlds with a list of glob patterns in worlds, or with all-worlds.`,
 	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:      "config",
+			Aliases:   []string{"c"},
+			TakesFile: true,
+			OnlyOnce:  true,
+			Config:    cli.StringConfig{TrimSpace: true},
+			Usage:     "project configuration file with generation jobs, otherwise " + projectFiles[0] + " or " + projectFiles[2] + " in the current directory",
+		},
 		&cli.StringFlag{
 			Name:     "world",
 			Aliases:  []string{"w"},
 			Value:    "",
 			OnlyOnce: true,
 			Config:   cli.StringConfig{TrimSpace: true},
-			Usage:    "WIT world to generate, otherwise generate the first world",
+			Usage:    "WIT world to generate, otherwise generate the first world, or with --all-worlds, a glob pattern that selects worlds",
+		},
+		&cli.BoolFlag{
+			Name:  "all-worlds",
+			Usage: "generate every WIT world into one tree of Go packages, sharing interface packages",
 		},
 		&cli.StringFlag{
 			Name:      "out",
@@ -62,10 +92,42 @@ var Command = &cli.Command{
 			Name:  "generate-wit",
 			Usage: "generate a WIT file for each generated Go package corresponding to each WIT world or interface",
 		},
+		&cli.BoolFlag{
+			Name:  "idiomatic-errors",
+			Usage: "generate additional functions that return a Go error for functions that return a WIT result",
+		},
+		&cli.BoolFlag{
+			Name:  "export-interfaces",
+			Usage: "generate Go interfaces and a SetExports function for exports instead of an Exports struct",
+		},
+		&cli.BoolFlag{
+			Name:  "resource-tables",
+			Usage: "store the Go values of exported resources in a cm.ResourceTable and call methods on them",
+		},
+		&cli.BoolFlag{
+			Name:  "owned-resources",
+			Usage: "generate an Owned method for imported resources that wraps an owned handle in a cm.Owned",
+		},
+		&cli.BoolFlag{
+			Name:  "fakes",
+			Usage: "generate a Fake struct with hooks for imported functions, for builds on targets other than WebAssembly",
+		},
+		&cli.BoolFlag{
+			Name:  "host",
+			Usage: "generate host-side bindings for the wazero runtime instead of guest bindings",
+		},
 		&cli.BoolFlag{
 			Name:  "dry-run",
 			Usage: "do not write files; print to stdout",
 		},
+		&cli.BoolFlag{
+			Name:  "check",
+			Usage: "do not write files; print a diff of files that differ from the generated files, and fail if any differ",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "overwrite or remove previously generated files, even if they were modified",
+		},
 	},
 	Action: action,
 }
@@ -74,41 +136,155 @@ var Command = &cli.Command{
 type config struct {
 	logger      logging.Logger
 	dryRun      bool
+	check       bool
+	force       bool
 	out         string
 	outPerm     os.FileMode
 	pkgRoot     string
 	world       string
+	allWorlds   bool
+	worlds      []string
+	packages    map[string]string
 	cm          string
 	versioned   bool
 	generateWIT bool
+	idiomatic   bool
+	interfaces  bool
+	tables      bool
+	owned       bool
+	fakes       bool
+	host        bool
 	forceWIT    bool
 	path        string
 }
 
 func action(ctx context.Context, cmd *cli.Command) error {
+	file := cmd.String("config")
+	if file == "" && cmd.Args().Len() == 0 {
+		var err error
+		file, err = findProject(".")
+		if err != nil {
+			return err
+		}
+	}
+	if file != "" {
+		return generateProject(ctx, cmd, file)
+	}
+
 	cfg, err := parseFlags(ctx, cmd)
 	if err != nil {
 		return err
 	}
+	return generate(ctx, cmd, cfg)
+}
 
+// jobFlags are the flags that are specified for each job in a project configuration file.
+var jobFlags = []string{
+	"world",
+	"all-worlds",
+	"out",
+	"package-root",
+	"cm",
+	"versioned",
+	"generate-wit",
+	"idiomatic-errors",
+	"export-interfaces",
+	"resource-tables",
+	"owned-resources",
+	"fakes",
+	"host",
+}
+
+func generateProject(ctx context.Context, cmd *cli.Command, file string) error {
+	if cmd.Args().Len() > 0 {
+		return fmt.Errorf("path argument cannot be used with project configuration file %s", file)
+	}
+	for _, name := range jobFlags {
+		if cmd.IsSet(name) {
+			return fmt.Errorf("flag --%s cannot be used with project configuration file %s", name, file)
+		}
+	}
+
+	p, err := loadProject(file)
+	if err != nil {
+		return err
+	}
+	logger := witcli.Logger(cmd.Bool("verbose"), cmd.Bool("debug"))
+	logger.Infof("Project: %s\n", file)
+
+	dir := filepath.Dir(file)
+	outs := make(map[string]string)
+	for i := range p.Jobs {
+		j := &p.Jobs[i]
+		out := filepath.Clean(j.outDir(dir))
+		if other, ok := outs[out]; ok {
+			return fmt.Errorf("jobs %s and %s have the same output directory %s", other, j.name(i), out)
+		}
+		outs[out] = j.name(i)
+	}
+
+	// With --check, every job is checked before reporting out-of-date jobs.
+	var outOfDate []string
+	for i := range p.Jobs {
+		j := &p.Jobs[i]
+		logger.Infof("Job: %s\n", j.name(i))
+		cfg, err := jobConfig(cmd, logger, dir, j)
+		if err == nil {
+			err = generate(ctx, cmd, cfg)
+		}
+		if errors.Is(err, errOutOfDate) {
+			outOfDate = append(outOfDate, j.name(i))
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("job %s: %w", j.name(i), err)
+		}
+	}
+	if len(outOfDate) > 0 {
+		return fmt.Errorf("%w: job(s) %s", errOutOfDate, strings.Join(outOfDate, ", "))
+	}
+	return nil
+}
+
+func generate(ctx context.Context, cmd *cli.Command, cfg *config) error {
 	res, err := witcli.LoadWIT(ctx, cfg.path, cmd.Reader, cfg.forceWIT)
 	if err != nil {
 		return err
 	}
 
-	packages, err := bindgen.Go(res,
+	opts := []bindgen.Option{
 		bindgen.GeneratedBy(cmd.Root().Name),
 		bindgen.Logger(cfg.logger),
 		bindgen.PackageRoot(cfg.pkgRoot),
-		bindgen.World(cfg.world),
+		bindgen.PackagePaths(cfg.packages),
 		bindgen.CMPackage(cfg.cm),
 		bindgen.Versioned(cfg.versioned),
 		bindgen.WIT(cfg.generateWIT),
-	)
+		bindgen.IdiomaticErrors(cfg.idiomatic),
+		bindgen.ExportInterfaces(cfg.interfaces),
+		bindgen.ResourceTables(cfg.tables),
+		bindgen.OwnedResources(cfg.owned),
+		bindgen.Fakes(cfg.fakes),
+		bindgen.Host(cfg.host),
+	}
+	if cfg.allWorlds {
+		patterns := cfg.worlds
+		if cfg.world != "" {
+			patterns = append(patterns, cfg.world)
+		}
+		opts = append(opts, bindgen.AllWorlds(patterns...))
+	} else {
+		opts = append(opts, bindgen.World(cfg.world))
+	}
+
+	packages, err := bindgen.Go(res, opts...)
 	if err != nil {
 		return err
 	}
 
+	if cfg.check {
+		return checkGoPackages(ctx, cmd, cfg, packages)
+	}
 	return writeGoPackages(ctx, cmd, cfg, packages)
 }
 
@@ -117,44 +293,129 @@ func parseFlags(_ context.Context, cmd *cli.Command) (*config, error) {
 	dryRun := cmd.Bool("dry-run")
 	out := cmd.String("out")
 
-	info, err := witcli.FindOrCreateDir(out)
+	outPerm, pkgRoot, err := outputDir(logger, out, cmd.String("package-root"), cmd.IsSet("package-root"), !cmd.Bool("check"))
 	if err != nil {
 		return nil, err
 	}
-	logger.Infof("Output dir: %s\n", out)
-	outPerm := info.Mode().Perm()
 
-	pkgRoot := cmd.String("package-root")
-	if !cmd.IsSet("package-root") {
-		pkgRoot, err = gen.PackagePath(out)
-		if err != nil {
-			return nil, err
-		}
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return nil, err
 	}
-	logger.Infof("Package root: %s\n", pkgRoot)
 
-	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	return &config{
+		logger:      logger,
+		dryRun:      dryRun,
+		check:       cmd.Bool("check"),
+		force:       cmd.Bool("force"),
+		out:         out,
+		outPerm:     outPerm,
+		pkgRoot:     pkgRoot,
+		world:       cmd.String("world"),
+		allWorlds:   cmd.Bool("all-worlds"),
+		cm:          cmd.String("cm"),
+		versioned:   cmd.Bool("versioned"),
+		generateWIT: cmd.Bool("generate-wit"),
+		idiomatic:   cmd.Bool("idiomatic-errors"),
+		interfaces:  cmd.Bool("export-interfaces"),
+		tables:      cmd.Bool("resource-tables"),
+		owned:       cmd.Bool("owned-resources"),
+		fakes:       cmd.Bool("fakes"),
+		host:        cmd.Bool("host"),
+		forceWIT:    cmd.Bool("force-wit"),
+		path:        path,
+	}, nil
+}
+
+// jobConfig returns the configuration for job j in a project configuration file in dir.
+func jobConfig(cmd *cli.Command, logger logging.Logger, dir string, j *job) (*config, error) {
+	out := j.outDir(dir)
+	outPerm, pkgRoot, err := outputDir(logger, out, j.PackageRoot, j.PackageRoot != "", !cmd.Bool("check"))
 	if err != nil {
 		return nil, err
 	}
+	for name, pkgPath := range j.Packages {
+		if pkgRoot != "" && !withinRoot(pkgPath, pkgRoot) {
+			return nil, fmt.Errorf("Go package %s for %s is not within package root %s", pkgPath, name, pkgRoot)
+		}
+	}
 
 	return &config{
-		logger,
-		dryRun,
-		out,
-		outPerm,
-		pkgRoot,
-		cmd.String("world"),
-		cmd.String("cm"),
-		cmd.Bool("versioned"),
-		cmd.Bool("generate-wit"),
-		cmd.Bool("force-wit"),
-		path,
+		logger:      logger,
+		dryRun:      cmd.Bool("dry-run"),
+		check:       cmd.Bool("check"),
+		force:       cmd.Bool("force"),
+		out:         out,
+		outPerm:     outPerm,
+		pkgRoot:     pkgRoot,
+		world:       j.World,
+		allWorlds:   j.AllWorlds || len(j.Worlds) > 0,
+		worlds:      j.Worlds,
+		packages:    j.Packages,
+		cm:          j.CM,
+		versioned:   j.Versioned,
+		generateWIT: j.GenerateWIT,
+		idiomatic:   j.IdiomaticErrors,
+		interfaces:  j.ExportInterfaces,
+		tables:      j.ResourceTables,
+		owned:       j.OwnedResources,
+		fakes:       j.Fakes,
+		host:        j.Host,
+		forceWIT:    cmd.Bool("force-wit"),
+		path:        resolveInput(dir, j.Path),
 	}, nil
 }
 
-func writeGoPackages(_ context.Context, cmd *cli.Command, cfg *config, packages []*gen.Package) error {
+// outputDir finds or creates output directory out, returning its permissions and the Go package root.
+// If setRoot is false, the package root is derived from the Go module that contains out.
+// If create is false, out is not created, and the package root is derived from its nearest existing parent.
+func outputDir(logger logging.Logger, out, pkgRoot string, setRoot, create bool) (os.FileMode, string, error) {
+	var perm os.FileMode = 0o755
+	dir, subdir := out, ""
+	if create {
+		info, err := witcli.FindOrCreateDir(out)
+		if err != nil {
+			return 0, "", err
+		}
+		perm = info.Mode().Perm()
+	} else {
+		for {
+			_, err := os.Stat(dir)
+			if err == nil {
+				break
+			}
+			parent := filepath.Dir(dir)
+			if !errors.Is(err, fs.ErrNotExist) || parent == dir {
+				return 0, "", err
+			}
+			subdir = path.Join(filepath.Base(dir), subdir)
+			dir = parent
+		}
+	}
+	logger.Infof("Output dir: %s\n", out)
+
+	if !setRoot {
+		root, err := gen.PackagePath(dir)
+		if err != nil {
+			return 0, "", err
+		}
+		pkgRoot = path.Join(root, subdir)
+	}
+	logger.Infof("Package root: %s\n", pkgRoot)
+	return perm, pkgRoot, nil
+}
+
+// outputFile is a file generated in the output directory.
+type outputFile struct {
+	// rel is the slash-separated path of the file, relative to the output directory.
+	rel     string
+	content []byte
+}
+
+// outputFiles returns the files in packages, sorted by package.
+func outputFiles(cfg *config, packages []*gen.Package) ([]outputFile, error) {
 	cfg.logger.Infof("Generated %d Go package(s)\n", len(packages))
+	var files []outputFile
 	for _, pkg := range packages {
 		if !pkg.HasContent() {
 			cfg.logger.Debugf("Skipped empty package: %s\n", pkg.Path)
@@ -164,38 +425,165 @@ func writeGoPackages(_ context.Context, cmd *cli.Command, cfg *config, packages
 
 		for _, filename := range codec.SortedKeys(pkg.Files) {
 			file := pkg.Files[filename]
-			dir := filepath.Join(cfg.out, strings.TrimPrefix(file.Package.Path, cfg.pkgRoot))
-			path := filepath.Join(dir, file.Name)
+			rel := strings.TrimPrefix(path.Join(strings.TrimPrefix(file.Package.Path, cfg.pkgRoot), file.Name), "/")
 
 			if !file.HasContent() {
-				cfg.logger.Debugf("\tSkipping empty file: %s\n", path)
+				cfg.logger.Debugf("\tSkipping empty file: %s\n", rel)
 				continue
 			}
 
-			if err := os.MkdirAll(dir, cfg.outPerm); err != nil {
-				return err
-			}
-
 			content, err := file.Bytes()
 			if err != nil {
 				if content == nil {
-					return err
+					return nil, err
 				}
 				cfg.logger.Errorf("\tError formatting file: %v\n", err)
 			} else {
-				cfg.logger.Infof("\t%s\n", path)
+				cfg.logger.Infof("\t%s\n", filepath.Join(cfg.out, filepath.FromSlash(rel)))
 			}
+			files = append(files, outputFile{rel, content})
+		}
+	}
+	return files, nil
+}
 
-			if cfg.dryRun {
-				fmt.Fprintln(cmd.Writer, string(content))
-				fmt.Fprintln(cmd.Writer)
-				continue
-			}
+// outputManifest returns the generation manifest for files.
+func outputManifest(files []outputFile) *manifest {
+	m := &manifest{Files: make(map[string]string, len(files))}
+	for _, f := range files {
+		m.Files[f.rel] = fileDigest(f.content)
+	}
+	return m
+}
 
-			if err := os.WriteFile(path, content, cfg.outPerm); err != nil {
+// writeGoPackages writes the files in packages to the output directory, and records them
+// in the generation manifest. Files recorded in the previous manifest that are no longer
+// generated are removed. Files that were modified since they were generated are not
+// overwritten or removed unless cfg.force is set.
+func writeGoPackages(_ context.Context, cmd *cli.Command, cfg *config, packages []*gen.Package) error {
+	files, err := outputFiles(cfg, packages)
+	if err != nil {
+		return err
+	}
+	if cfg.dryRun {
+		for _, f := range files {
+			fmt.Fprintln(cmd.Writer, string(f.content))
+			fmt.Fprintln(cmd.Writer)
+		}
+		return nil
+	}
+
+	prev, err := readManifest(cfg.out)
+	if err != nil {
+		return err
+	}
+	next := outputManifest(files)
+
+	// Check for modified files before writing or removing any files.
+	if !cfg.force {
+		for _, rel := range codec.SortedKeys(prev.Files) {
+			path := filepath.Join(cfg.out, filepath.FromSlash(rel))
+			mod, err := modified(path, prev.Files[rel])
+			if err != nil {
 				return err
 			}
+			if mod {
+				return fmt.Errorf("%s was modified since it was generated; revert or remove it, or use --force to overwrite it", path)
+			}
+		}
+	}
+
+	for _, f := range files {
+		path := filepath.Join(cfg.out, filepath.FromSlash(f.rel))
+		if err := os.MkdirAll(filepath.Dir(path), cfg.outPerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, f.content, cfg.outPerm); err != nil {
+			return err
+		}
+	}
+
+	// Remove stale files that were previously generated.
+	for _, rel := range codec.SortedKeys(prev.Files) {
+		if _, ok := next.Files[rel]; ok {
+			continue
+		}
+		path := filepath.Join(cfg.out, filepath.FromSlash(rel))
+		cfg.logger.Infof("Removing stale file: %s\n", path)
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
 		}
+		removeEmptyDirs(filepath.Clean(cfg.out), filepath.Dir(path))
+	}
+
+	return next.write(cfg.out, cfg.outPerm)
+}
+
+// errOutOfDate is returned by [checkGoPackages] if the files in the output directory
+// differ from the generated files.
+var errOutOfDate = errors.New("generated files are out of date")
+
+// checkGoPackages compares the files in packages and the generation manifest with the files
+// in the output directory, printing a unified diff for each file that would be changed,
+// added, or removed by generating. It does not write any files.
+func checkGoPackages(_ context.Context, cmd *cli.Command, cfg *config, packages []*gen.Package) error {
+	files, err := outputFiles(cfg, packages)
+	if err != nil {
+		return err
+	}
+	prev, err := readManifest(cfg.out)
+	if err != nil {
+		return err
+	}
+	next := outputManifest(files)
+	data, err := next.encode()
+	if err != nil {
+		return err
+	}
+	files = append(files, outputFile{manifestFile, data})
+
+	var count int
+	compare := func(rel string, content []byte, generated bool) error {
+		path := filepath.Join(cfg.out, filepath.FromSlash(rel))
+		old, err := os.ReadFile(path)
+		exists := err == nil
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		oldName, newName := path, path
+		switch {
+		case !exists && !generated:
+			return nil
+		case !exists:
+			oldName = "/dev/null"
+		case !generated:
+			newName = "/dev/null"
+		}
+		if exists && generated && bytes.Equal(old, content) {
+			return nil
+		}
+		count++
+		fmt.Fprint(cmd.Writer, diff.Unified(oldName, newName, string(old), string(content)))
+		return nil
+	}
+
+	for _, f := range files {
+		if err := compare(f.rel, f.content, true); err != nil {
+			return err
+		}
+	}
+	for _, rel := range codec.SortedKeys(prev.Files) {
+		if _, ok := next.Files[rel]; ok {
+			continue
+		}
+		if err := compare(rel, nil, false); err != nil {
+			return err
+		}
+	}
+
+	if count > 0 {
+		return fmt.Errorf("%w: %d file(s) differ in %s", errOutOfDate, count, cfg.out)
 	}
 	return nil
 }
@@ -0,0 +1,114 @@
+package generate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// manifestFile is the name of the generation manifest written to the output directory.
+const manifestFile = ".wit-bindgen-go.json"
+
+// manifest records the files generated in an output directory.
+type manifest struct {
+	// Files maps the slash-separated path of each generated file,
+	// relative to the output directory, to the SHA-256 digest of its content.
+	Files map[string]string `json:"files"`
+}
+
+// readManifest reads the generation manifest in output directory out.
+// If the manifest does not exist, it returns an empty manifest.
+func readManifest(out string) (*manifest, error) {
+	m := &manifest{Files: make(map[string]string)}
+	path := filepath.Join(out, manifestFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, m)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]string)
+	}
+	for rel := range m.Files {
+		if !generatedPath(rel) {
+			return nil, fmt.Errorf("%s: invalid generated file path %q", path, rel)
+		}
+	}
+	return m, nil
+}
+
+// generatedSuffixes are the file name suffixes of generated files.
+var generatedSuffixes = []string{".go", ".s", ".wit"}
+
+// generatedPath reports whether rel is a valid path of a generated file:
+// a slash-separated local path, relative to the output directory, with a generated file suffix.
+// Paths read from a manifest are checked before files are removed, so a hostile or corrupted
+// manifest cannot remove files outside the output directory.
+func generatedPath(rel string) bool {
+	if rel == "" || strings.Contains(rel, "\\") || path.Clean(rel) != rel || !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return false
+	}
+	return slices.ContainsFunc(generatedSuffixes, func(suffix string) bool {
+		return strings.HasSuffix(rel, suffix)
+	})
+}
+
+// encode returns the JSON encoding of the generation manifest.
+func (m *manifest) encode() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// write writes the generation manifest to output directory out.
+func (m *manifest) write(out string, perm os.FileMode) error {
+	data, err := m.encode()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(out, manifestFile), data, perm)
+}
+
+// modified reports whether the file at path was modified since it was generated with digest.
+// A file that does not exist is not modified.
+func modified(path, digest string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return fileDigest(data) != digest, nil
+}
+
+// removeEmptyDirs removes dir and its parent directories while they are empty, stopping at root.
+func removeEmptyDirs(root, dir string) {
+	for dir != root && len(dir) > len(root) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func fileDigest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
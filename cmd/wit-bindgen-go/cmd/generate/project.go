@@ -0,0 +1,171 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"go.bytecodealliance.org/internal/oci"
+)
+
+// projectFiles are the names of the project configuration files
+// found in the current directory, in order of preference.
+var projectFiles = []string{
+	"wit-bindgen-go.yaml",
+	"wit-bindgen-go.yml",
+	"wit-bindgen-go.toml",
+}
+
+// project is a project configuration file, which declares one or more generation jobs.
+type project struct {
+	Jobs []job `yaml:"jobs" toml:"jobs"`
+}
+
+// job is a single generation job in a project configuration file.
+// Paths are relative to the directory of the configuration file.
+type job struct {
+	// Name optionally identifies the job in log messages.
+	Name string `yaml:"name" toml:"name"`
+
+	// Path is a WIT file or directory, a JSON file, or an OCI reference.
+	Path string `yaml:"path" toml:"path"`
+
+	// World is the WIT world to generate.
+	World string `yaml:"world" toml:"world"`
+
+	// Worlds are glob patterns that select the WIT worlds to generate into one tree of Go packages.
+	Worlds []string `yaml:"worlds" toml:"worlds"`
+
+	// AllWorlds generates every WIT world into one tree of Go packages.
+	AllWorlds bool `yaml:"all-worlds" toml:"all-worlds"`
+
+	// Out is the output directory. Default: the directory of the configuration file.
+	Out string `yaml:"out" toml:"out"`
+
+	// PackageRoot is the Go package root. Default: the Go package path of Out.
+	PackageRoot string `yaml:"package-root" toml:"package-root"`
+
+	// CM is the import path for the Component Model utility package.
+	CM string `yaml:"cm" toml:"cm"`
+
+	// Packages maps WIT interface or world names to Go package paths.
+	Packages map[string]string `yaml:"packages" toml:"packages"`
+
+	Versioned        bool `yaml:"versioned" toml:"versioned"`
+	GenerateWIT      bool `yaml:"generate-wit" toml:"generate-wit"`
+	IdiomaticErrors  bool `yaml:"idiomatic-errors" toml:"idiomatic-errors"`
+	ExportInterfaces bool `yaml:"export-interfaces" toml:"export-interfaces"`
+	ResourceTables   bool `yaml:"resource-tables" toml:"resource-tables"`
+	OwnedResources   bool `yaml:"owned-resources" toml:"owned-resources"`
+	Fakes            bool `yaml:"fakes" toml:"fakes"`
+	Host             bool `yaml:"host" toml:"host"`
+}
+
+// findProject returns the path of the project configuration file in dir, or "" if not found.
+func findProject(dir string) (string, error) {
+	for _, name := range projectFiles {
+		path := filepath.Join(dir, name)
+		_, err := os.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// loadProject reads and validates the project configuration file at path.
+// Files with a .toml extension are decoded as TOML, otherwise as YAML.
+func loadProject(path string) (*project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p project
+	if filepath.Ext(path) == ".toml" {
+		md, err := toml.Decode(string(data), &p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if keys := md.Undecoded(); len(keys) > 0 {
+			return nil, fmt.Errorf("%s: unknown field %q", path, keys[0].String())
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err := dec.Decode(&p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if len(p.Jobs) == 0 {
+		return nil, fmt.Errorf("%s: no jobs", path)
+	}
+	for i := range p.Jobs {
+		err := p.Jobs[i].validate()
+		if err != nil {
+			return nil, fmt.Errorf("%s: job %s: %w", path, p.Jobs[i].name(i), err)
+		}
+	}
+	return &p, nil
+}
+
+func (j *job) validate() error {
+	switch {
+	case j.Path == "":
+		return errors.New("missing path")
+	case j.World != "" && (j.AllWorlds || len(j.Worlds) > 0):
+		return errors.New("world cannot be used with worlds or all-worlds")
+	}
+	return nil
+}
+
+// name returns the name of the job at index i, for log and error messages.
+func (j *job) name(i int) string {
+	if j.Name != "" {
+		return j.Name
+	}
+	return fmt.Sprintf("%d", i+1)
+}
+
+// outDir returns the output directory of the job in a project configuration file in dir.
+func (j *job) outDir(dir string) string {
+	if j.Out == "" {
+		return dir
+	}
+	return resolve(dir, j.Out)
+}
+
+// resolve returns path relative to dir, unless path is absolute.
+func resolve(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, filepath.FromSlash(path))
+}
+
+// resolveInput returns input path relative to dir, unless path is absolute
+// or an OCI reference that does not exist as a local path.
+func resolveInput(dir, path string) string {
+	local := resolve(dir, path)
+	if _, err := os.Stat(local); err != nil && oci.IsOCIPath(path) {
+		return path
+	}
+	return local
+}
+
+// withinRoot reports whether Go package path pkgPath is pkgRoot or a subpackage of it.
+func withinRoot(pkgPath, pkgRoot string) bool {
+	return pkgPath == pkgRoot || strings.HasPrefix(pkgPath, pkgRoot+"/")
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/wit/graph"
+)
+
+// Command is the CLI command for graph.
+var Command = &cli.Command{
+	Name:  "graph",
+	Usage: "prints the dependency graph of WIT packages, interfaces, types, and functions",
+	Description: `The graph contains each package, world, interface, type, and function, with edges for
+containment, world imports and exports, and the types used by each type and function.
+Interfaces that use types from other interfaces depend on those interfaces.
+Imported and exported interfaces and functions are highlighted.`,
+	ArgsUsage: "[<path to WIT file or directory>]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "format",
+			Aliases:  []string{"f"},
+			Value:    "dot",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "output format: dot, mermaid, or json",
+		},
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "limit the graph to a world and its dependencies",
+		},
+		&cli.StringFlag{
+			Name:     "detail",
+			Value:    "function",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "level of detail: interface, type, or function",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	var level graph.Level
+	switch detail := cmd.String("detail"); detail {
+	case "interface":
+		level = graph.InterfaceLevel
+	case "type":
+		level = graph.TypeLevel
+	case "function":
+		level = graph.FunctionLevel
+	default:
+		return fmt.Errorf("unknown detail level %q", detail)
+	}
+
+	var encode func(*graph.Graph) error
+	switch format := cmd.String("format"); format {
+	case "dot":
+		encode = func(g *graph.Graph) error { return graph.EncodeDOT(cmd.Writer, g) }
+	case "mermaid":
+		encode = func(g *graph.Graph) error { return graph.EncodeMermaid(cmd.Writer, g) }
+	case "json":
+		encode = func(g *graph.Graph) error { return graph.EncodeJSON(cmd.Writer, g) }
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	res, err := witcli.LoadWIT(ctx, path, cmd.Reader, cmd.Bool("force-wit"))
+	if err != nil {
+		return err
+	}
+
+	opts := []graph.Option{graph.Detail(level)}
+	if world := cmd.String("world"); world != "" {
+		opts = append(opts, graph.World(world))
+	}
+	g, err := graph.Build(res, opts...)
+	if err != nil {
+		return err
+	}
+	return encode(g)
+}
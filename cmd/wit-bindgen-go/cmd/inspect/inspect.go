@@ -0,0 +1,110 @@
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/wasm"
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/wit"
+)
+
+// Command is the CLI command for inspect.
+var Command = &cli.Command{
+	Name:      "inspect",
+	Usage:     "prints the imports, exports, and WIT of a WebAssembly core module or component",
+	ArgsUsage: "<path>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "no-wit",
+			Usage: "do not print WIT, only imports and exports",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if path == "-" {
+		data, err = io.ReadAll(cmd.Reader)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	b, err := wasm.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	imports, err := b.Imports()
+	if err != nil {
+		return err
+	}
+	exports, err := b.Exports()
+	if err != nil {
+		return err
+	}
+
+	w := cmd.Writer
+	if b.Component {
+		fmt.Fprintln(w, ";; component")
+	} else {
+		fmt.Fprintln(w, ";; core module")
+	}
+	for _, imp := range imports {
+		if b.Component {
+			fmt.Fprintf(w, "(import %q (%s))\n", imp.Name, imp.Kind)
+		} else {
+			fmt.Fprintf(w, "(import %q %q (%s))\n", imp.Module, imp.Name, imp.Kind)
+		}
+	}
+	for _, exp := range exports {
+		fmt.Fprintf(w, "(export %q (%s))\n", exp.Name, exp.Kind)
+	}
+
+	if cmd.Bool("no-wit") {
+		return nil
+	}
+
+	// A component is decoded directly. A core module may contain
+	// component-type custom sections, each of which encodes a WIT world.
+	if b.Component {
+		res, err := wit.DecodeWIT(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n%s", res.WIT(nil, ""))
+		return nil
+	}
+
+	sections, err := b.CustomSections()
+	if err != nil {
+		return err
+	}
+	for _, s := range sections {
+		if !strings.HasPrefix(s.Name, "component-type") {
+			continue
+		}
+		res, err := wit.DecodeWIT(bytes.NewReader(s.Contents))
+		if err != nil {
+			return fmt.Errorf("custom section %s: %w", s.Name, err)
+		}
+		fmt.Fprintf(w, "\n// custom section %s\n%s", s.Name, res.WIT(nil, ""))
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/wit/lint"
+)
+
+// Command is the CLI command for lint.
+var Command = &cli.Command{
+	Name:  "lint",
+	Usage: "reports style and portability problems in WIT packages",
+	Description: `The level of each rule can be changed with --rule name=level, where level is error, warning, note, or off.
+The command fails if any problem is reported at the error level. Rules:
+
+` + ruleDocs(),
+	ArgsUsage: "[<path to WIT file or directory>]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "format",
+			Aliases:  []string{"f"},
+			Value:    "text",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "output format: text, json, or sarif",
+		},
+		&cli.StringSliceFlag{
+			Name:    "rule",
+			Aliases: []string{"r"},
+			Usage:   "set the level of a rule as name=level",
+		},
+		&cli.StringSliceFlag{
+			Name:    "package",
+			Aliases: []string{"p"},
+			Usage:   "lint only WIT packages matching a glob pattern, e.g. example:*",
+		},
+	},
+	Action: action,
+}
+
+func ruleDocs() string {
+	var b strings.Builder
+	for _, r := range lint.Rules() {
+		fmt.Fprintf(&b, "\t%-18s %s (default %s)\n", r.Name, r.Doc, r.Level)
+	}
+	return b.String()
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	levels := make(map[string]lint.Level)
+	for _, rule := range cmd.StringSlice("rule") {
+		name, value, ok := strings.Cut(rule, "=")
+		if !ok {
+			return fmt.Errorf("invalid rule %q: expected name=level", rule)
+		}
+		level, err := lint.ParseLevel(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		levels[strings.TrimSpace(name)] = level
+	}
+
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+	res, err := witcli.LoadWIT(ctx, path, cmd.Reader, cmd.Bool("force-wit"))
+	if err != nil {
+		return err
+	}
+
+	diags, err := lint.Lint(res, lint.Levels(levels), lint.Packages(cmd.StringSlice("package")...))
+	if err != nil {
+		return err
+	}
+
+	switch format := cmd.String("format"); format {
+	case "text":
+		for _, d := range diags {
+			fmt.Fprintln(cmd.Writer, d.String())
+		}
+	case "json":
+		err = lint.EncodeJSON(cmd.Writer, diags)
+	case "sarif":
+		err = lint.EncodeSARIF(cmd.Writer, diags)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	var errs int
+	for _, d := range diags {
+		if d.Level == lint.Error {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return fmt.Errorf("lint found %d error(s)", errs)
+	}
+	return nil
+}
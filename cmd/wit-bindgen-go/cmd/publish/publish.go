@@ -0,0 +1,129 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/oci"
+	"go.bytecodealliance.org/internal/wasm"
+	"go.bytecodealliance.org/internal/wasmtools"
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/wit"
+)
+
+// Command is the CLI command for publish.
+var Command = &cli.Command{
+	Name:      "publish",
+	Usage:     "publishes a WIT package or component to an OCI registry",
+	ArgsUsage: "<ref>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "wit",
+			Value:    "wit",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "path to the WIT file, directory, or JSON to publish, or a .wasm WIT package or component",
+		},
+		&cli.StringFlag{
+			Name:     "source",
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "source URL, e.g. a git repository, recorded in the org.opencontainers.image.source annotation",
+		},
+		&cli.BoolFlag{
+			Name:  "plain-http",
+			Usage: "connect to the registry over HTTP rather than HTTPS",
+		},
+	},
+	Action: action,
+}
+
+// OCI annotations set on published artifacts.
+const (
+	annotationVersion     = "org.opencontainers.image.version"
+	annotationDescription = "org.opencontainers.image.description"
+	annotationSource      = "org.opencontainers.image.source"
+)
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 1 {
+		return fmt.Errorf("found %d ref arguments, expecting 1", cmd.Args().Len())
+	}
+	ref := cmd.Args().First()
+	if !oci.IsOCIPath(ref) {
+		return fmt.Errorf("invalid OCI ref: %s", ref)
+	}
+
+	data, err := loadWasm(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	res, err := wit.DecodeWIT(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if len(res.Packages) == 0 {
+		return errors.New("no WIT packages found")
+	}
+	// The main package of a binary-encoded WIT package or component is last.
+	pkg := res.Packages[len(res.Packages)-1]
+
+	annotations := make(map[string]string)
+	if pkg.Name.Version != nil {
+		annotations[annotationVersion] = pkg.Name.Version.String()
+	}
+	if pkg.Docs.Contents != "" {
+		annotations[annotationDescription] = pkg.Docs.Contents
+	}
+	if source := cmd.String("source"); source != "" {
+		annotations[annotationSource] = source
+	}
+
+	digest, err := oci.PushWIT(ctx, ref, data, annotations, oci.PlainHTTP(cmd.Bool("plain-http")))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.Writer, "Published %s@%s\n", ref, digest)
+	return nil
+}
+
+// loadWasm returns the binary-encoded WIT package or component to publish.
+// WIT text or JSON is encoded as a WIT package with wasm-tools.
+func loadWasm(ctx context.Context, cmd *cli.Command) ([]byte, error) {
+	path := cmd.String("wit")
+	if strings.HasSuffix(path, ".wasm") {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		b, err := wasm.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+		if !b.Component {
+			return nil, fmt.Errorf("%s is a core module: a WIT package or component is required", path)
+		}
+		return data, nil
+	}
+
+	res, err := witcli.LoadWIT(ctx, path, nil, cmd.Bool("force-wit"))
+	if err != nil {
+		return nil, err
+	}
+
+	wasmTools, err := wasmtools.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer wasmTools.Close(ctx)
+
+	return wasmTools.ComponentWIT(ctx, res.WIT(nil, ""))
+}
@@ -7,7 +7,15 @@ import (
 
 	"github.com/urfave/cli/v3"
 
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/build"
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/deps"
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/diff"
+	witfmt "go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/fmt"
 	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/generate"
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/graph"
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/inspect"
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/lint"
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/publish"
 	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/wit"
 	"go.bytecodealliance.org/internal/module"
 )
@@ -25,7 +33,15 @@ var Command = &cli.Command{
 	Usage: "inspect or manipulate WebAssembly Interface Types for Go",
 	Commands: []*cli.Command{
 		generate.Command,
+		build.Command,
+		publish.Command,
+		deps.Command,
 		wit.Command,
+		witfmt.Command,
+		lint.Command,
+		graph.Command,
+		diff.Command,
+		inspect.Command,
 		version,
 	},
 	Flags: []cli.Flag{
@@ -3,10 +3,46 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"testing/fstest"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/inspect"
+	"go.bytecodealliance.org/internal/wasm"
+	"go.bytecodealliance.org/internal/wasmtools"
 )
 
+func TestMain(m *testing.M) {
+	if os.Getenv("WIT_BINDGEN_GO_TEST_MAIN") != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain runs wit-bindgen-go with args in a new process in dir, returning its combined output.
+// Flags of the package-level Command retain their values across calls to Run, so commands
+// that are run more than once with different flags are run in a new process.
+func runMain(t *testing.T, dir string, args ...string) (string, error) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "WIT_BINDGEN_GO_TEST_MAIN=1")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
 // TestSimpleGenVerbosity ensures that a basic generation case honors the verbose flag
 func TestSimpleGenVerbosity(t *testing.T) {
 	inWIT := `package tests:test;`
@@ -52,3 +88,620 @@ func TestSimpleGenVerbosity(t *testing.T) {
 		t.Errorf("no output was written to stderr when --verbose was used")
 	}
 }
+
+func TestInspect(t *testing.T) {
+	var stdout bytes.Buffer
+	inspect.Command.Writer = &stdout
+
+	err := Command.Run(context.Background(), []string{"wit-bindgen-go", "inspect", "../../internal/wasm/testdata/module.wasm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`(import "cm32p2|my:pkg/foo@0.1" "f" (func))`,
+		`(export "cm32p2_memory" (memory))`,
+		"f: func(a: string) -> u32;",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestBuildRequiresAdapter(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.22\n",
+		"main.go":       "package main\n\nfunc main() {}\n",
+		"wit/world.wit": "package example:app;\n\nworld app {\n\texport run: func();\n}\n",
+	})
+
+	err := Command.Run(context.Background(), []string{"wit-bindgen-go", "build", "--wit", filepath.Join(dir, "wit"), "-o", filepath.Join(dir, "app.wasm"), dir})
+	if err == nil || !strings.Contains(err.Error(), "--adapter") {
+		t.Errorf("build: %v, expected error requesting --adapter", err)
+	}
+}
+
+func TestBuildWorld(t *testing.T) {
+	const logWIT = "package example:log;\n\ninterface logger {\n\tlog: func(msg: string);\n}\n\nworld imports {\n\timport logger;\n}\n"
+	tests := []struct {
+		name  string
+		world string
+		wit   string
+		want  string
+	}{
+		{"main package with deps", "", "world app {\n\timport example:log/logger;\n}\n", "--adapter"},
+		{"two worlds", "", "world app {\n\timport example:log/logger;\n}\n\nworld other {}\n", "found 2 worlds in package example:app, use --world"},
+		{"select world", "other", "world app {\n\timport example:log/logger;\n}\n\nworld other {}\n", "--adapter"},
+		{"select dependency world", "example:log/imports", "world app {\n\timport example:log/logger;\n}\n", "--adapter"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"go.mod":           "module example.com/app\n\ngo 1.22\n",
+				"main.go":          "package main\n\nfunc main() {}\n",
+				"wit/world.wit":    "package example:app;\n\n" + tt.wit,
+				"wit/deps/log.wit": logWIT,
+			})
+			args := []string{"build", "--wit", "wit", "-o", "app.wasm"}
+			if tt.world != "" {
+				args = append(args, "--world", tt.world)
+			}
+			out, err := runMain(t, dir, append(args, ".")...)
+			if err == nil || !strings.Contains(out, tt.want) {
+				t.Errorf("build: %v, expected output containing %q:\n%s", err, tt.want, out)
+			}
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("skipping test: go command not found")
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.22\n",
+		"main.go":       "package main\n\nfunc main() {}\n",
+		"wit/world.wit": "package example:app;\n\nworld app {\n\timport log: func(msg: string);\n}\n",
+	})
+	writeTestAdapter(t, dir, filepath.Join(dir, "adapter.wasm"))
+
+	out, err := runMain(t, dir, "build", "--wit", "wit", "--adapter", "adapter.wasm", "-o", "app.wasm", ".")
+	if err != nil {
+		t.Fatalf("build: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "app.wasm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := wasm.Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Component {
+		t.Errorf("build: output is a core module, expected a component")
+	}
+}
+
+// writeTestAdapter writes a WASI Preview 1 adapter module to path that exports a stub for each
+// wasi_snapshot_preview1 function imported by the Go package in dir when built for wasip1.
+// The stubs trap if called, so the adapter is only suitable for creating a component.
+func writeTestAdapter(t *testing.T, dir, path string) {
+	t.Helper()
+	ctx := context.Background()
+	core := filepath.Join(t.TempDir(), "core.wasm")
+	build := exec.Command("go", "build", "-o", core, ".")
+	build.Dir = dir
+	build.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(core)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := wazero.NewRuntime(ctx)
+	defer r.Close(ctx)
+	m, err := r.CompileModule(ctx, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wat strings.Builder
+	wat.WriteString("(module\n")
+	for _, f := range m.ImportedFunctions() {
+		module, name, _ := f.Import()
+		if module != "wasi_snapshot_preview1" {
+			continue
+		}
+		fmt.Fprintf(&wat, "\t(func (export %q)", name)
+		for _, p := range f.ParamTypes() {
+			fmt.Fprintf(&wat, " (param %s)", api.ValueTypeName(p))
+		}
+		for _, res := range f.ResultTypes() {
+			fmt.Fprintf(&wat, " (result %s)", api.ValueTypeName(res))
+		}
+		wat.WriteString(" unreachable)\n")
+	}
+	wat.WriteString(")\n")
+
+	w, err := wasmtools.New(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close(ctx)
+	var stdout, stderr bytes.Buffer
+	err = w.Run(ctx, nil, &stdout, &stderr, map[string]fs.FS{
+		"": fstest.MapFS{
+			"adapter.wit": &fstest.MapFile{Data: []byte("package test:adapter;\n\nworld adapter {}\n")},
+			"adapter.wat": &fstest.MapFile{Data: []byte(wat.String())},
+		},
+	}, "component", "embed", "adapter.wit", "adapter.wat")
+	if err != nil {
+		t.Fatalf("wasm-tools: %v: %s", err, stderr.Bytes())
+	}
+	if err := os.WriteFile(path, stdout.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const projectWIT = `package example:app@0.1.0;
+
+interface types {
+	record point { x: s32, y: s32 }
+}
+
+world app {
+	import types;
+	export run: func();
+}
+`
+
+func TestGenerateProject(t *testing.T) {
+	// Skip test on incompatible platforms
+	if runtime.Compiler == "tinygo" || strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"wit-bindgen-go.yaml", `jobs:
+  - name: guest
+    path: wit
+    world: app
+    out: internal
+    packages:
+      example:app/types: example.com/app/internal/apptypes
+  - path: wit
+    worlds: ["example:app/*"]
+    out: versioned
+    versioned: true
+`},
+		{"wit-bindgen-go.toml", `[[jobs]]
+name = "guest"
+path = "wit"
+world = "app"
+out = "internal"
+
+[jobs.packages]
+"example:app/types@0.1.0" = "example.com/app/internal/apptypes"
+
+[[jobs]]
+path = "wit"
+all-worlds = true
+out = "versioned"
+versioned = true
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"go.mod":      "module example.com/app\n\ngo 1.22\n",
+				"wit/app.wit": projectWIT,
+				tt.name:       tt.config,
+			})
+
+			// Run with --config from another directory, then from the project directory.
+			for _, args := range [][]string{{"generate", "--config", filepath.Join(dir, tt.name)}, {"generate"}} {
+				wd := t.TempDir()
+				if len(args) == 1 {
+					wd = dir
+				}
+				out, err := runMain(t, wd, args...)
+				if err != nil {
+					t.Fatalf("%v: %v\n%s", args, err, out)
+				}
+				for _, name := range []string{
+					"internal/apptypes/apptypes.wit.go",
+					"internal/example/app/app/app.wit.go",
+					"versioned/example/app/v0.1.0/types/types.wit.go",
+					"versioned/example/app/v0.1.0/app/app.wit.go",
+				} {
+					path := filepath.Join(dir, filepath.FromSlash(name))
+					if _, err := os.Stat(path); err != nil {
+						t.Error(err)
+					}
+					os.Remove(path)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateProjectErrors(t *testing.T) {
+	// Skip test on incompatible platforms
+	if runtime.Compiler == "tinygo" || strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	tests := []struct {
+		name   string
+		config string
+		args   []string
+		want   string
+	}{
+		{"wit-bindgen-go.yaml", "jobs:\n  - path: wit\n    wrold: app\n", nil, "field wrold not found"},
+		{"wit-bindgen-go.toml", "[[jobs]]\npath = \"wit\"\nwrold = \"app\"\n", nil, `unknown field "jobs.wrold"`},
+		{"wit-bindgen-go.yaml", "jobs: []\n", nil, "no jobs"},
+		{"wit-bindgen-go.yaml", "jobs:\n  - world: app\n", nil, "job 1: missing path"},
+		{"wit-bindgen-go.yaml", "jobs:\n  - path: wit\n    world: app\n    all-worlds: true\n", nil, "world cannot be used with worlds or all-worlds"},
+		{"wit-bindgen-go.yaml", "jobs:\n  - path: wit\n    packages:\n      example:app/types: example.com/other\n", nil, "not within package root"},
+		{"wit-bindgen-go.yaml", "jobs:\n  - path: wit\n", []string{"--world", "app"}, "flag --world cannot be used"},
+		{"wit-bindgen-go.yaml", "jobs:\n  - path: wit\n    out: gen\n  - path: wit\n    out: ./gen/\n", nil, "jobs 1 and 2 have the same output directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"go.mod":      "module example.com/app\n\ngo 1.22\n",
+				"wit/app.wit": projectWIT,
+				tt.name:       tt.config,
+			})
+			out, err := runMain(t, dir, append([]string{"generate"}, tt.args...)...)
+			if err == nil || !strings.Contains(out, tt.want) {
+				t.Errorf("generate: %v, expected error containing %q:\n%s", err, tt.want, out)
+			}
+		})
+	}
+}
+
+func TestGenerateManifest(t *testing.T) {
+	// Skip test on incompatible platforms
+	if runtime.Compiler == "tinygo" || strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":      "module example.com/app\n\ngo 1.22\n",
+		"wit/app.wit": projectWIT,
+		"gen/doc.go":  "package gen\n",
+	})
+	generate := func(args ...string) (string, error) {
+		t.Helper()
+		return runMain(t, dir, append([]string{"generate", "-o", "gen"}, append(args, "wit")...)...)
+	}
+	if out, err := generate(); err != nil {
+		t.Fatalf("generate: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "gen", ".wit-bindgen-go.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := filepath.Join(dir, "gen", "example", "app", "types", "types.wit.go")
+	if !strings.Contains(string(data), `"example/app/types/types.wit.go": "sha256:`) {
+		t.Errorf("manifest does not contain types.wit.go:\n%s", data)
+	}
+
+	// Files that are no longer generated are removed, with their empty directories.
+	writeFiles(t, dir, map[string]string{"wit/app.wit": strings.ReplaceAll(projectWIT, "types", "shapes")})
+	if out, err := generate(); err != nil {
+		t.Fatalf("generate: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Dir(types)); !os.IsNotExist(err) {
+		t.Errorf("stale package not removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "gen", "doc.go")); err != nil {
+		t.Errorf("file not generated was removed: %v", err)
+	}
+
+	// Modified files are not overwritten or removed, unless forced.
+	shapes := filepath.Join(dir, "gen", "example", "app", "shapes", "shapes.wit.go")
+	if err := os.WriteFile(shapes, []byte("package shapes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{"wit/app.wit": projectWIT})
+	out, err := generate()
+	if err == nil || !strings.Contains(out, "shapes.wit.go was modified since it was generated") {
+		t.Errorf("generate: %v, expected error for modified file:\n%s", err, out)
+	}
+	if _, err := os.Stat(shapes); err != nil {
+		t.Errorf("modified file removed: %v", err)
+	}
+	if out, err := generate("--force"); err != nil {
+		t.Fatalf("generate --force: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(shapes); !os.IsNotExist(err) {
+		t.Errorf("modified file not removed with --force: %v", err)
+	}
+	if _, err := os.Stat(types); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerateHostileManifest(t *testing.T) {
+	// Skip test on incompatible platforms
+	if runtime.Compiler == "tinygo" || strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	dir := t.TempDir()
+	victim := filepath.Join(dir, "victim.go")
+	for _, rel := range []string{
+		"../victim.go",
+		"example/../../victim.go",
+		filepath.ToSlash(victim),
+		"victim.txt",
+	} {
+		t.Run(rel, func(t *testing.T) {
+			writeFiles(t, dir, map[string]string{
+				"go.mod":                   "module example.com/app\n\ngo 1.22\n",
+				"wit/app.wit":              projectWIT,
+				"victim.go":                "package app\n",
+				"gen/victim.txt":           "victim\n",
+				"gen/.wit-bindgen-go.json": `{"files": {"` + rel + `": "sha256:00"}}`,
+			})
+			out, err := runMain(t, dir, "generate", "--force", "-o", "gen", "wit")
+			if err == nil || !strings.Contains(out, "invalid generated file path") {
+				t.Errorf("generate: %v, expected error for invalid manifest path:\n%s", err, out)
+			}
+			for _, path := range []string{victim, filepath.Join(dir, "gen", "victim.txt")} {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("file not in manifest removed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateCheck(t *testing.T) {
+	// Skip test on incompatible platforms
+	if runtime.Compiler == "tinygo" || strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":      "module example.com/app\n\ngo 1.22\n",
+		"wit/app.wit": projectWIT,
+	})
+	check := func(out string, want ...string) {
+		t.Helper()
+		output, err := runMain(t, dir, "generate", "--check", "-o", out, "wit")
+		if len(want) == 0 {
+			if err != nil || output != "" {
+				t.Errorf("generate --check: %v, expected no differences:\n%s", err, output)
+			}
+			return
+		}
+		if err == nil {
+			t.Errorf("generate --check: nil error, expected error")
+		}
+		for _, w := range append(want, "error: generated files are out of date") {
+			if !strings.Contains(output, w) {
+				t.Errorf("generate --check: output does not contain %q:\n%s", w, output)
+			}
+		}
+	}
+
+	// Nothing is written when checking a missing output directory.
+	check("gen", "--- /dev/null\n+++ gen/example/app/types/types.wit.go\n", "+++ gen/.wit-bindgen-go.json\n")
+	if _, err := os.Stat(filepath.Join(dir, "gen")); !os.IsNotExist(err) {
+		t.Errorf("output directory created: %v", err)
+	}
+
+	if out, err := runMain(t, dir, "generate", "-o", "gen", "wit"); err != nil {
+		t.Fatalf("generate: %v\n%s", err, out)
+	}
+	check("gen")
+
+	// Changed, added, and removed files.
+	types := filepath.Join(dir, "gen", "example", "app", "types", "types.wit.go")
+	data, err := os.ReadFile(types)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := filepath.Join(dir, "gen", "example", "app", "app", "app.wit.go")
+	if err := os.WriteFile(app, []byte("package app // edited\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{"wit/app.wit": strings.ReplaceAll(projectWIT, "types", "shapes")})
+	check("gen",
+		"--- gen/example/app/app/app.wit.go\n+++ gen/example/app/app/app.wit.go\n",
+		"--- /dev/null\n+++ gen/example/app/shapes/shapes.wit.go\n",
+		"--- gen/example/app/types/types.wit.go\n+++ /dev/null\n",
+		"--- gen/.wit-bindgen-go.json\n+++ gen/.wit-bindgen-go.json\n",
+		"-package app // edited\n",
+	)
+	if got, err := os.ReadFile(types); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("generate --check modified %s: %v", types, err)
+	}
+}
+
+func TestFmt(t *testing.T) {
+	// Skip test on incompatible platforms
+	if runtime.Compiler == "tinygo" || strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	const src = "package example:app;\ninterface types{f:func();}\n"
+	const want = "package example:app;\n\ninterface types {\n\tf: func();\n}\n"
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"wit/app.wit":         src,
+		"wit/deps/x/x.wit":    src,
+		"wit/formatted.wit":   want,
+		"wit/notes/readme.md": src,
+	})
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// Listing and diffing do not rewrite files.
+	out, err := runMain(t, dir, "fmt", "-l", "wit")
+	if err != nil || out != filepath.Join("wit", "app.wit")+"\n" {
+		t.Errorf("fmt -l: %v, output %q", err, out)
+	}
+	out, err = runMain(t, dir, "fmt", "-d", "wit")
+	if err != nil || !strings.Contains(out, "-interface types{f:func();}\n+\n+interface types {\n") {
+		t.Errorf("fmt -d: %v, output:\n%s", err, out)
+	}
+	if got := read("wit/app.wit"); got != src {
+		t.Errorf("fmt -l -d rewrote app.wit:\n%s", got)
+	}
+
+	out, err = runMain(t, dir, "fmt", "wit")
+	if err != nil || out != "" {
+		t.Errorf("fmt: %v, output:\n%s", err, out)
+	}
+	if got := read("wit/app.wit"); got != want {
+		t.Errorf("fmt: app.wit:\n%s\nexpected:\n%s", got, want)
+	}
+	if got := read("wit/deps/x/x.wit"); got != src {
+		t.Errorf("fmt rewrote a file in the deps directory:\n%s", got)
+	}
+
+	writeFiles(t, dir, map[string]string{"bad.wit": "interface types {\n"})
+	out, err = runMain(t, dir, "fmt", "bad.wit")
+	if err == nil || !strings.Contains(out, "bad.wit:2:1: expected `}`, found end of file") {
+		t.Errorf("fmt bad.wit: %v, output:\n%s", err, out)
+	}
+}
+
+func TestLint(t *testing.T) {
+	// Skip test on incompatible platforms
+	if runtime.Compiler == "tinygo" || strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"wit/app.wit": `package example:app;
+
+interface types {
+	record names { http-url: u32, http-u-r-l: u32 }
+	get: func() -> names;
+}
+`,
+	})
+
+	out, err := runMain(t, dir, "lint", "wit")
+	if err == nil || !strings.Contains(out, "error: example:app/types#names: http-url and http-u-r-l have the same Go name HTTPURL (go-name-collision)\n") ||
+		!strings.Contains(out, "note: example:app/types#get: function get has no doc comment (missing-docs)\n") ||
+		!strings.Contains(out, "error: lint found 1 error(s)") {
+		t.Errorf("lint: %v, output:\n%s", err, out)
+	}
+
+	out, err = runMain(t, dir, "lint", "--rule", "go-name-collision=warning", "--rule", "missing-docs=off", "--format", "json", "wit")
+	if err != nil {
+		t.Fatalf("lint --format json: %v\n%s", err, out)
+	}
+	var diags []struct {
+		Rule  string `json:"rule"`
+		Level string `json:"level"`
+		Path  string `json:"path"`
+	}
+	if err := json.Unmarshal([]byte(out), &diags); err != nil {
+		t.Fatalf("lint --format json: %v\n%s", err, out)
+	}
+	if len(diags) != 1 || diags[0].Rule != "go-name-collision" || diags[0].Level != "warning" || diags[0].Path != "example:app/types#names" {
+		t.Errorf("lint --format json: %+v", diags)
+	}
+
+	out, err = runMain(t, dir, "lint", "--rule", "no-such-rule=off", "wit")
+	if err == nil || !strings.Contains(out, `unknown lint rule "no-such-rule"`) {
+		t.Errorf("lint --rule no-such-rule=off: %v, output:\n%s", err, out)
+	}
+}
+
+func TestGraph(t *testing.T) {
+	// Skip test on incompatible platforms
+	if runtime.Compiler == "tinygo" || strings.Contains(runtime.GOARCH, "wasm") {
+		return
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"wit/app.wit": `package example:app;
+
+interface types {
+	record point { x: s32, y: s32 }
+}
+
+interface api {
+	use types.{point};
+	get: func() -> point;
+}
+
+world app {
+	import api;
+	export run: func();
+}
+`,
+	})
+
+	out, err := runMain(t, dir, "graph", "wit")
+	if err != nil || !strings.HasPrefix(out, "digraph wit {\n") ||
+		!strings.Contains(out, `"example:app/api" -> "example:app/types";`) ||
+		!strings.Contains(out, `"example:app/app" -> "example:app/app export run" [label="exports"];`) {
+		t.Errorf("graph: %v, output:\n%s", err, out)
+	}
+
+	out, err = runMain(t, dir, "graph", "--format", "mermaid", "--detail", "interface", "wit")
+	if err != nil || !strings.HasPrefix(out, "flowchart LR\n") || strings.Contains(out, "point") {
+		t.Errorf("graph --format mermaid: %v, output:\n%s", err, out)
+	}
+
+	out, err = runMain(t, dir, "graph", "--format", "json", "--world", "app", "wit")
+	if err != nil {
+		t.Fatalf("graph --format json: %v\n%s", err, out)
+	}
+	var g struct {
+		Nodes []struct {
+			ID       string `json:"id"`
+			Imported bool   `json:"imported"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal([]byte(out), &g); err != nil {
+		t.Fatalf("graph --format json: %v\n%s", err, out)
+	}
+	var imported []string
+	for _, n := range g.Nodes {
+		if n.Imported {
+			imported = append(imported, n.ID)
+		}
+	}
+	if want := []string{"example:app/types", "example:app/api"}; !slices.Equal(imported, want) {
+		t.Errorf("graph --format json: imported %v, expected %v", imported, want)
+	}
+
+	out, err = runMain(t, dir, "graph", "--world", "missing", "wit")
+	if err == nil || !strings.Contains(out, "world missing not found") {
+		t.Errorf("graph --world missing: %v, output:\n%s", err, out)
+	}
+}
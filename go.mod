@@ -3,7 +3,10 @@ module go.bytecodealliance.org
 go 1.22.0
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/coreos/go-semver v0.3.1
+	github.com/olareg/olareg v0.1.1
+	github.com/opencontainers/go-digest v1.0.0
 	github.com/regclient/regclient v0.8.2
 	github.com/sergi/go-diff v1.3.1
 	github.com/tetratelabs/wazero v1.8.2
@@ -11,12 +14,12 @@ require (
 	go.bytecodealliance.org/cm v0.1.0
 	golang.org/x/mod v0.23.0
 	golang.org/x/tools v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/docker/libtrust v0.0.0-20160708172513-aabc10ec26b7 // indirect
 	github.com/klauspost/compress v1.17.11 // indirect
-	github.com/opencontainers/go-digest v1.0.0 // indirect
 	github.com/sirupsen/logrus v1.9.3 // indirect
 	github.com/ulikunitz/xz v0.5.12 // indirect
 	golang.org/x/sync v0.11.0 // indirect
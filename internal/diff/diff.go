@@ -0,0 +1,212 @@
+// Package diff computes line-based differences between texts in unified diff format.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around each change.
+const context = 3
+
+// maxEdits is the maximum number of line edits searched for before falling back
+// to replacing every line that differs between the common prefix and suffix.
+const maxEdits = 2000
+
+type op byte
+
+const (
+	opEqual  op = ' '
+	opDelete op = '-'
+	opInsert op = '+'
+)
+
+type edit struct {
+	op   op
+	line string
+}
+
+// Unified returns a unified diff of old and new text, with oldName and newName in the file headers.
+// It returns "" if old and new are equal.
+func Unified(oldName, newName, old, new string) string {
+	if old == new {
+		return ""
+	}
+	edits := lineEdits(lines(old), lines(new))
+
+	// Count lines of old and new text before each edit.
+	oldLines := make([]int, len(edits)+1)
+	newLines := make([]int, len(edits)+1)
+	for i, e := range edits {
+		oldLines[i+1], newLines[i+1] = oldLines[i], newLines[i]
+		if e.op != opInsert {
+			oldLines[i+1]++
+		}
+		if e.op != opDelete {
+			newLines[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(edits); {
+		for i < len(edits) && edits[i].op == opEqual {
+			i++
+		}
+		if i == len(edits) {
+			break
+		}
+
+		// Extend the hunk over changes separated by few enough unchanged lines.
+		start := max(i-context, 0)
+		end := i
+		for {
+			for end < len(edits) && edits[end].op != opEqual {
+				end++
+			}
+			next := end
+			for next < len(edits) && edits[next].op == opEqual {
+				next++
+			}
+			if next == len(edits) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		stop := min(end+context, len(edits))
+
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(oldLines[start], oldLines[stop]-oldLines[start]),
+			hunkRange(newLines[start], newLines[stop]-newLines[start]))
+		for _, e := range edits[start:stop] {
+			b.WriteByte(byte(e.op))
+			b.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = stop
+	}
+	return b.String()
+}
+
+// hunkRange formats the range of count lines after line before in a hunk header.
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// lines splits s into lines, each with its trailing newline, if any.
+func lines(s string) []string {
+	var lines []string
+	for s != "" {
+		i := strings.IndexByte(s, '\n') + 1
+		if i == 0 {
+			i = len(s)
+		}
+		lines = append(lines, s[:i])
+		s = s[i:]
+	}
+	return lines
+}
+
+// lineEdits returns the edits that transform lines a into lines b.
+func lineEdits(a, b []string) []edit {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []edit
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{opEqual, line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{opEqual, line})
+	}
+	return edits
+}
+
+// myers returns the shortest edits that transform lines a into lines b, using the
+// Myers difference algorithm. If more than maxEdits edits are required, it returns
+// edits that delete every line in a and insert every line in b.
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset)
+			}
+		}
+	}
+
+	edits := make([]edit, 0, n+m)
+	for _, line := range a {
+		edits = append(edits, edit{opDelete, line})
+	}
+	for _, line := range b {
+		edits = append(edits, edit{opInsert, line})
+	}
+	return edits
+}
+
+// backtrack follows trace, the state of the Myers algorithm before each step, back from the end of a and b.
+func backtrack(a, b []string, trace [][]int, offset int) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{opEqual, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, edit{opInsert, b[y-1]})
+			} else {
+				edits = append(edits, edit{opDelete, a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
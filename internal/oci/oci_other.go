@@ -5,13 +5,29 @@ package oci
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 
+	"github.com/opencontainers/go-digest"
 	"github.com/regclient/regclient"
+	"github.com/regclient/regclient/config"
+	"github.com/regclient/regclient/types/descriptor"
 	"github.com/regclient/regclient/types/manifest"
+	"github.com/regclient/regclient/types/mediatype"
+	v1 "github.com/regclient/regclient/types/oci/v1"
 	"github.com/regclient/regclient/types/ref"
+
+	"go.bytecodealliance.org/internal/wasm"
+)
+
+// Media types defined by the [Wasm OCI Artifact] layout.
+//
+// [Wasm OCI Artifact]: https://tag-runtime.cncf.io/wgs/wasm/deliverables/wasm-oci-artifact/
+const (
+	MediaTypeWasm       = "application/wasm"
+	MediaTypeWasmConfig = "application/vnd.wasm.config.v0+json"
 )
 
 // IsOCIPath checks if a given path is an OCI path
@@ -28,13 +44,14 @@ func IsOCIPath(path string) bool {
 // It invokes "regclient" APIs to pull the artifact and then
 // processes it with `wasm-tools`.
 // The output is returned as raw bytes.
-func PullWIT(ctx context.Context, path string) ([]byte, error) {
+// The artifact's application/wasm layer is used, or its only layer if it has no application/wasm layer.
+func PullWIT(ctx context.Context, path string, opts ...Option) ([]byte, error) {
 	r, err := ref.New(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ref: %v", err)
 	}
 
-	rc := regclient.New()
+	rc := newClient(r, opts...)
 	defer rc.Close(ctx, r)
 
 	m, err := rc.ManifestGet(ctx, r)
@@ -55,13 +72,25 @@ func PullWIT(ctx context.Context, path string) ([]byte, error) {
 		return nil, fmt.Errorf("no layers found in the artifact")
 	}
 
-	layer := layers[0] // assuming the WIT file is the first layer
+	var layer *descriptor.Descriptor
+	for i := range layers {
+		if layers[i].MediaType == MediaTypeWasm {
+			layer = &layers[i]
+			break
+		}
+	}
+	if layer == nil && len(layers) == 1 {
+		layer = &layers[0] // artifacts pushed by other tools may use another media type
+	}
+	if layer == nil {
+		return nil, fmt.Errorf("no %s layer found in the artifact", MediaTypeWasm)
+	}
 
 	if err = layer.Digest.Validate(); err != nil {
 		return nil, fmt.Errorf("layer contains invalid digest: %s: %v", string(layer.Digest), err)
 	}
 
-	rdr, err := rc.BlobGet(ctx, r, layer)
+	rdr, err := rc.BlobGet(ctx, r, *layer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch blob: %v", err)
 	}
@@ -75,3 +104,147 @@ func PullWIT(ctx context.Context, path string) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// ManifestDigest returns the digest of the manifest of the OCI artifact at path.
+// A path with a digest, such as example.com/wasi/io:0.2.0@sha256:..., can be passed to [PullWIT]
+// to pull the same artifact even if the tag is later updated.
+func ManifestDigest(ctx context.Context, path string, opts ...Option) (string, error) {
+	r, err := ref.New(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse ref: %v", err)
+	}
+
+	rc := newClient(r, opts...)
+	defer rc.Close(ctx, r)
+
+	m, err := rc.ManifestHead(ctx, r, regclient.WithManifestRequireDigest())
+	if err != nil {
+		return "", fmt.Errorf("failed to get manifest: %v", err)
+	}
+	return m.GetDescriptor().Digest.String(), nil
+}
+
+// PushWIT pushes a binary-encoded WIT package or component to the OCI path
+// as a Wasm OCI artifact with a single application/wasm layer.
+// The manifest is annotated with annotations, which may be nil.
+// It returns the digest of the pushed manifest.
+func PushWIT(ctx context.Context, path string, data []byte, annotations map[string]string, opts ...Option) (string, error) {
+	r, err := ref.New(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse ref: %v", err)
+	}
+
+	b, err := wasm.Decode(data)
+	if err != nil {
+		return "", err
+	}
+	if !b.Component {
+		return "", fmt.Errorf("cannot push a core module: a WIT package or component is required")
+	}
+	cc, err := newComponentConfig(b)
+	if err != nil {
+		return "", err
+	}
+
+	layer := descriptor.Descriptor{
+		MediaType: MediaTypeWasm,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	configData, err := json.Marshal(&wasmConfig{
+		Architecture: "wasm",
+		OS:           "wasip2",
+		LayerDigests: []string{layer.Digest.String()},
+		Component:    cc,
+	})
+	if err != nil {
+		return "", err
+	}
+	configDesc := descriptor.Descriptor{
+		MediaType: MediaTypeWasmConfig,
+		Digest:    digest.FromBytes(configData),
+		Size:      int64(len(configData)),
+	}
+
+	m, err := manifest.New(manifest.WithOrig(v1.Manifest{
+		Versioned:   v1.ManifestSchemaVersion,
+		MediaType:   mediatype.OCI1Manifest,
+		Config:      configDesc,
+		Layers:      []descriptor.Descriptor{layer},
+		Annotations: annotations,
+	}))
+	if err != nil {
+		return "", fmt.Errorf("failed to create manifest: %v", err)
+	}
+
+	rc := newClient(r, opts...)
+	defer rc.Close(ctx, r)
+
+	_, err = rc.BlobPut(ctx, r, configDesc, bytes.NewReader(configData))
+	if err != nil {
+		return "", fmt.Errorf("failed to push config: %v", err)
+	}
+	_, err = rc.BlobPut(ctx, r, layer, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to push layer: %v", err)
+	}
+	err = rc.ManifestPut(ctx, r, m)
+	if err != nil {
+		return "", fmt.Errorf("failed to push manifest: %v", err)
+	}
+	return m.GetDescriptor().Digest.String(), nil
+}
+
+// newClient returns a regclient that uses Docker credentials, including credential helpers,
+// to connect to the registry of r.
+func newClient(r ref.Ref, opts ...Option) *regclient.RegClient {
+	var o options
+	o.apply(opts...)
+	rcOpts := []regclient.Opt{
+		regclient.WithDockerCerts(),
+		regclient.WithDockerCreds(),
+	}
+	if o.plainHTTP {
+		rcOpts = append(rcOpts, regclient.WithConfigHost(config.Host{
+			Name: r.Registry,
+			TLS:  config.TLSDisabled,
+		}))
+	}
+	return regclient.New(rcOpts...)
+}
+
+// wasmConfig is the config object of a Wasm OCI artifact.
+type wasmConfig struct {
+	Architecture string           `json:"architecture"`
+	OS           string           `json:"os"`
+	LayerDigests []string         `json:"layerDigests"`
+	Component    *componentConfig `json:"component,omitempty"`
+}
+
+// componentConfig describes the imports and exports of a component.
+type componentConfig struct {
+	Imports []string `json:"imports"`
+	Exports []string `json:"exports"`
+}
+
+func newComponentConfig(b *wasm.Binary) (*componentConfig, error) {
+	imports, err := b.Imports()
+	if err != nil {
+		return nil, err
+	}
+	exports, err := b.Exports()
+	if err != nil {
+		return nil, err
+	}
+	cc := &componentConfig{
+		Imports: make([]string, 0, len(imports)),
+		Exports: make([]string, 0, len(exports)),
+	}
+	for _, imp := range imports {
+		cc.Imports = append(cc.Imports, imp.Name)
+	}
+	for _, exp := range exports {
+		cc.Exports = append(cc.Exports, exp.Name)
+	}
+	return cc, nil
+}
@@ -0,0 +1,282 @@
+//go:build !wasip1 && !wasip2 && !tinygo
+
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/olareg/olareg"
+	oconfig "github.com/olareg/olareg/config"
+	"github.com/opencontainers/go-digest"
+	"github.com/regclient/regclient"
+	"github.com/regclient/regclient/config"
+	"github.com/regclient/regclient/types/descriptor"
+	"github.com/regclient/regclient/types/manifest"
+	"github.com/regclient/regclient/types/mediatype"
+	v1 "github.com/regclient/regclient/types/oci/v1"
+	"github.com/regclient/regclient/types/ref"
+)
+
+// newRegistry starts an in-memory OCI registry, returning its host.
+// If handler is non-nil, it wraps the registry handler.
+func newRegistry(t *testing.T, wrap func(http.Handler) http.Handler) string {
+	t.Helper()
+	var h http.Handler = olareg.New(oconfig.Config{
+		Storage: oconfig.ConfigStorage{StoreType: oconfig.StoreMem},
+	})
+	if wrap != nil {
+		h = wrap(h)
+	}
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ignore any Docker credentials on the host.
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	return u.Host
+}
+
+func readComponent(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("../wasm/testdata/component.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestPushPullWIT(t *testing.T) {
+	ctx := context.Background()
+	host := newRegistry(t, nil)
+	path := host + "/example/component:0.1.0"
+	data := readComponent(t)
+	annotations := map[string]string{
+		"org.opencontainers.image.version":     "0.1.0",
+		"org.opencontainers.image.description": "An example component.",
+	}
+
+	d, err := PushWIT(ctx, path, data, annotations, PlainHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := PullWIT(ctx, path, PlainHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("PullWIT: got %d bytes, expected %d bytes", len(got), len(data))
+	}
+
+	// Check the manifest and config.
+	r, err := ref.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := regclient.New(regclient.WithConfigHost(config.Host{Name: host, TLS: config.TLSDisabled}))
+	m, err := rc.ManifestGet(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetDescriptor().Digest.String(); got != d {
+		t.Errorf("manifest digest: %s, expected %s", got, d)
+	}
+	om, ok := m.GetOrig().(v1.Manifest)
+	if !ok {
+		t.Fatalf("manifest: %T, expected v1.Manifest", m.GetOrig())
+	}
+	if !reflect.DeepEqual(om.Annotations, annotations) {
+		t.Errorf("annotations: %v, expected %v", om.Annotations, annotations)
+	}
+	if om.Config.MediaType != MediaTypeWasmConfig {
+		t.Errorf("config media type: %s, expected %s", om.Config.MediaType, MediaTypeWasmConfig)
+	}
+	rdr, err := rc.BlobGet(ctx, r, om.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdr.Close()
+	var c wasmConfig
+	if err := json.NewDecoder(rdr).Decode(&c); err != nil {
+		t.Fatal(err)
+	}
+	want := wasmConfig{
+		Architecture: "wasm",
+		OS:           "wasip2",
+		LayerDigests: []string{digest.FromBytes(data).String()},
+		Component: &componentConfig{
+			Imports: []string{"my:pkg/foo@0.1.0"},
+			Exports: []string{"g"},
+		},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("config: %+v, expected %+v", c, want)
+	}
+}
+
+func TestPushWITCoreModule(t *testing.T) {
+	data, err := os.ReadFile("../wasm/testdata/module.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = PushWIT(context.Background(), "localhost:5000/example/module", data, nil, PlainHTTP(true))
+	if err == nil {
+		t.Error("PushWIT: nil error, expected error")
+	}
+}
+
+// pushLayers pushes an artifact with an empty config and a layer for each blob with the media type at the same index.
+func pushLayers(t *testing.T, host, path string, mediaTypes []string, blobs [][]byte) {
+	t.Helper()
+	ctx := context.Background()
+	r, err := ref.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := regclient.New(regclient.WithConfigHost(config.Host{Name: host, TLS: config.TLSDisabled}))
+	mediaTypes = append([]string{mediatype.OCI1Empty}, mediaTypes...)
+	blobs = append([][]byte{[]byte("{}")}, blobs...)
+	var descs []descriptor.Descriptor
+	for i, mt := range mediaTypes {
+		d, err := rc.BlobPut(ctx, r, descriptor.Descriptor{MediaType: mt}, bytes.NewReader(blobs[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.MediaType = mt
+		descs = append(descs, d)
+	}
+	m, err := manifest.New(manifest.WithOrig(v1.Manifest{
+		Versioned: v1.ManifestSchemaVersion,
+		MediaType: mediatype.OCI1Manifest,
+		Config:    descs[0],
+		Layers:    descs[1:],
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rc.ManifestPut(ctx, r, m); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPullWITLayer(t *testing.T) {
+	ctx := context.Background()
+	host := newRegistry(t, nil)
+	data := readComponent(t)
+
+	tests := []struct {
+		name       string
+		mediaTypes []string
+		blobs      [][]byte
+		wantErr    bool
+	}{
+		// The first layer is not the application/wasm layer.
+		{"wasm-layer", []string{"text/plain", MediaTypeWasm}, [][]byte{[]byte("README"), data}, false},
+		// The only layer is used even if it has another media type.
+		{"single-layer", []string{"application/octet-stream"}, [][]byte{data}, false},
+		{"no-wasm-layer", []string{"text/plain", "application/octet-stream"}, [][]byte{[]byte("README"), data}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := host + "/example/" + tt.name
+			pushLayers(t, host, path, tt.mediaTypes, tt.blobs)
+			got, err := PullWIT(ctx, path, PlainHTTP(true))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("PullWIT: expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("PullWIT: got %q, expected the component layer", got)
+			}
+		})
+	}
+}
+
+func TestPushWITCredentialHelper(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper is a shell script")
+	}
+	const user, pass = "user", "secret"
+	host := newRegistry(t, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if u, p, ok := req.BasicAuth(); !ok || u != user || p != pass {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			h.ServeHTTP(w, req)
+		})
+	})
+	path := host + "/example/component"
+	data := readComponent(t)
+
+	// Without credentials, the push is rejected.
+	_, err := PushWIT(context.Background(), path, data, nil, PlainHTTP(true))
+	if err == nil {
+		t.Fatal("PushWIT: nil error, expected error without credentials")
+	}
+
+	// Configure a Docker credential helper for host.
+	dir := t.TempDir()
+	helper := "#!/bin/sh\necho '{\"Username\":\"" + user + "\",\"Secret\":\"" + pass + "\"}'\n"
+	err = os.WriteFile(filepath.Join(dir, "docker-credential-test"), []byte(helper), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dockerConfig := `{"credHelpers":{"` + host + `":"test"}}`
+	err = os.WriteFile(filepath.Join(dir, "config.json"), []byte(dockerConfig), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", dir)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	_, err = PushWIT(context.Background(), path, data, nil, PlainHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := PullWIT(context.Background(), path, PlainHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("PullWIT: got %d bytes, expected %d bytes", len(got), len(data))
+	}
+}
+
+func TestManifestDigest(t *testing.T) {
+	ctx := context.Background()
+	host := newRegistry(t, nil)
+	path := host + "/example/component:0.1.0"
+	d, err := PushWIT(ctx, path, readComponent(t), nil, PlainHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ManifestDigest(ctx, path, PlainHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != d {
+		t.Errorf("ManifestDigest: %s, expected %s", got, d)
+	}
+	if _, err := PullWIT(ctx, path+"@"+got, PlainHTTP(true)); err != nil {
+		t.Errorf("PullWIT by digest: %v", err)
+	}
+}
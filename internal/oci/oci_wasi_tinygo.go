@@ -11,6 +11,14 @@ func IsOCIPath(path string) bool {
 	return false
 }
 
-func PullWIT(ctx context.Context, path string) ([]byte, error) {
+func PullWIT(ctx context.Context, path string, opts ...Option) ([]byte, error) {
 	return nil, errors.New("OCI not supported on WASI or TinyGo")
 }
+
+func PushWIT(ctx context.Context, path string, data []byte, annotations map[string]string, opts ...Option) (string, error) {
+	return "", errors.New("OCI not supported on WASI or TinyGo")
+}
+
+func ManifestDigest(ctx context.Context, path string, opts ...Option) (string, error) {
+	return "", errors.New("OCI not supported on WASI or TinyGo")
+}
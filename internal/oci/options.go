@@ -0,0 +1,30 @@
+package oci
+
+// Option represents a single configuration option for [PullWIT] or [PushWIT].
+type Option interface {
+	applyOption(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) applyOption(opts *options) {
+	f(opts)
+}
+
+type options struct {
+	plainHTTP bool
+}
+
+func (opts *options) apply(o ...Option) {
+	for _, o := range o {
+		o.applyOption(opts)
+	}
+}
+
+// PlainHTTP returns an [Option] that connects to the registry over HTTP rather than HTTPS.
+// This is useful for local registries.
+func PlainHTTP(plainHTTP bool) Option {
+	return optionFunc(func(opts *options) {
+		opts.plainHTTP = plainHTTP
+	})
+}
@@ -0,0 +1,473 @@
+package wasm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.bytecodealliance.org/internal/wasm/sleb128"
+	"go.bytecodealliance.org/internal/wasm/uleb128"
+)
+
+// ComponentVersion is the version and layer of a binary [WebAssembly component].
+//
+// [WebAssembly component]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Binary.md#component-definitions
+const ComponentVersion = "\x0d\x00\x01\x00"
+
+// Section IDs of a binary [WebAssembly component] that differ from those of a core module.
+//
+// [WebAssembly component]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Binary.md#component-definitions
+const (
+	SectionComponentImport SectionID = 10
+	SectionComponentExport SectionID = 11
+)
+
+// Binary represents a decoded WebAssembly core module or component.
+type Binary struct {
+	// Component is true if the binary is a component rather than a core module.
+	Component bool
+
+	// Sections are the top-level sections of the binary, in order.
+	Sections []*RawSection
+}
+
+// RawSection represents an undecoded section of a WebAssembly binary.
+// It implements the [Section] interface.
+type RawSection struct {
+	ID       SectionID
+	Contents []byte
+}
+
+// SectionID implements the [Section] interface.
+func (s *RawSection) SectionID() SectionID {
+	return s.ID
+}
+
+// SectionContents implements the [Section] interface.
+func (s *RawSection) SectionContents() ([]byte, error) {
+	return s.Contents, nil
+}
+
+// Read reads a binary WebAssembly core module or component from r.
+// Section contents are not decoded.
+func Read(r io.Reader) (*Binary, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// Decode decodes a binary WebAssembly core module or component from data.
+// Section contents are not decoded, and refer to data.
+func Decode(data []byte) (*Binary, error) {
+	if len(data) < 8 || string(data[:4]) != Magic {
+		return nil, errors.New("not a WebAssembly binary")
+	}
+	b := &Binary{}
+	switch string(data[4:8]) {
+	case Version1:
+	case ComponentVersion:
+		b.Component = true
+	default:
+		return nil, fmt.Errorf("unsupported WebAssembly version % x", data[4:8])
+	}
+	br := bytes.NewReader(data[8:])
+	for br.Len() > 0 {
+		id, err := br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		size, _, err := uleb128.Read(br)
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %w", id, err)
+		}
+		if size > uint64(br.Len()) {
+			return nil, fmt.Errorf("section %d: size %d exceeds remaining %d bytes", id, size, br.Len())
+		}
+		offset := len(data) - br.Len()
+		b.Sections = append(b.Sections, &RawSection{
+			ID:       SectionID(id),
+			Contents: data[offset : offset+int(size)],
+		})
+		br.Seek(int64(size), io.SeekCurrent)
+	}
+	return b, nil
+}
+
+// CustomSections returns the custom sections in b, in order.
+// Custom sections in nested modules or components are not included.
+func (b *Binary) CustomSections() ([]*CustomSection, error) {
+	var sections []*CustomSection
+	for _, s := range b.Sections {
+		if s.ID != SectionCustom {
+			continue
+		}
+		r := bytes.NewReader(s.Contents)
+		name, err := ReadString(r)
+		if err != nil {
+			return nil, fmt.Errorf("custom section: %w", err)
+		}
+		sections = append(sections, &CustomSection{
+			Name:     name,
+			Contents: s.Contents[len(s.Contents)-r.Len():],
+		})
+	}
+	return sections, nil
+}
+
+// ExternKind represents the kind of an import or export.
+type ExternKind uint8
+
+const (
+	ExternFunc ExternKind = iota
+	ExternTable
+	ExternMemory
+	ExternGlobal
+	ExternTag
+	ExternModule
+	ExternValue
+	ExternType
+	ExternComponent
+	ExternInstance
+)
+
+// String implements the [fmt.Stringer] interface.
+func (k ExternKind) String() string {
+	switch k {
+	case ExternFunc:
+		return "func"
+	case ExternTable:
+		return "table"
+	case ExternMemory:
+		return "memory"
+	case ExternGlobal:
+		return "global"
+	case ExternTag:
+		return "tag"
+	case ExternModule:
+		return "module"
+	case ExternValue:
+		return "value"
+	case ExternType:
+		return "type"
+	case ExternComponent:
+		return "component"
+	case ExternInstance:
+		return "instance"
+	}
+	return fmt.Sprintf("ExternKind(%d)", k)
+}
+
+// Import represents an import of a core module or component.
+// Module is empty for component imports.
+type Import struct {
+	Module string
+	Name   string
+	Kind   ExternKind
+}
+
+// Export represents an export of a core module or component.
+type Export struct {
+	Name string
+	Kind ExternKind
+}
+
+// Imports decodes and returns the imports of b.
+func (b *Binary) Imports() ([]Import, error) {
+	var imports []Import
+	for _, s := range b.Sections {
+		var err error
+		switch {
+		case b.Component && s.ID == SectionComponentImport:
+			imports, err = readVec(imports, s.Contents, readComponentImport)
+		case !b.Component && s.ID == SectionImport:
+			imports, err = readVec(imports, s.Contents, readImport)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("import section: %w", err)
+		}
+	}
+	return imports, nil
+}
+
+// Exports decodes and returns the exports of b.
+func (b *Binary) Exports() ([]Export, error) {
+	var exports []Export
+	for _, s := range b.Sections {
+		var err error
+		switch {
+		case b.Component && s.ID == SectionComponentExport:
+			exports, err = readVec(exports, s.Contents, readComponentExport)
+		case !b.Component && s.ID == SectionExport:
+			exports, err = readVec(exports, s.Contents, readExport)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("export section: %w", err)
+		}
+	}
+	return exports, nil
+}
+
+// ReadString reads a string from r encoded as a [LEB128] length followed by the string bytes.
+//
+// [LEB128]: https://en.wikipedia.org/wiki/LEB128
+func ReadString(r *bytes.Reader) (string, error) {
+	n, _, err := uleb128.Read(r)
+	if err != nil {
+		return "", err
+	}
+	if n > uint64(r.Len()) {
+		return "", io.ErrUnexpectedEOF
+	}
+	buf := make([]byte, n)
+	_, err = io.ReadFull(r, buf)
+	return string(buf), err
+}
+
+// readVec reads a vector of elements from data with read, appending them to s.
+func readVec[T any](s []T, data []byte, read func(*bytes.Reader) (T, error)) ([]T, error) {
+	r := bytes.NewReader(data)
+	n, _, err := uleb128.Read(r)
+	if err != nil {
+		return nil, err
+	}
+	for range n {
+		v, err := read(r)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, v)
+	}
+	return s, nil
+}
+
+// readImport reads a [core module import].
+//
+// [core module import]: https://webassembly.github.io/spec/core/binary/modules.html#import-section
+func readImport(r *bytes.Reader) (imp Import, err error) {
+	imp.Module, err = ReadString(r)
+	if err != nil {
+		return imp, err
+	}
+	imp.Name, err = ReadString(r)
+	if err != nil {
+		return imp, err
+	}
+	kind, err := r.ReadByte()
+	if err != nil {
+		return imp, err
+	}
+	switch kind {
+	case 0x00: // func: typeidx
+		imp.Kind = ExternFunc
+		_, _, err = uleb128.Read(r)
+	case 0x01: // table: reftype limits
+		imp.Kind = ExternTable
+		if err = skipValType(r); err == nil {
+			err = skipLimits(r)
+		}
+	case 0x02: // memory: limits
+		imp.Kind = ExternMemory
+		err = skipLimits(r)
+	case 0x03: // global: valtype mut
+		imp.Kind = ExternGlobal
+		if err = skipValType(r); err == nil {
+			_, err = r.ReadByte()
+		}
+	case 0x04: // tag: attribute typeidx
+		imp.Kind = ExternTag
+		if _, err = r.ReadByte(); err == nil {
+			_, _, err = uleb128.Read(r)
+		}
+	default:
+		return imp, fmt.Errorf("unknown import kind %#x for %s.%s", kind, imp.Module, imp.Name)
+	}
+	return imp, err
+}
+
+// readExport reads a [core module export].
+//
+// [core module export]: https://webassembly.github.io/spec/core/binary/modules.html#export-section
+func readExport(r *bytes.Reader) (exp Export, err error) {
+	exp.Name, err = ReadString(r)
+	if err != nil {
+		return exp, err
+	}
+	kind, err := r.ReadByte()
+	if err != nil {
+		return exp, err
+	}
+	switch kind {
+	case 0x00:
+		exp.Kind = ExternFunc
+	case 0x01:
+		exp.Kind = ExternTable
+	case 0x02:
+		exp.Kind = ExternMemory
+	case 0x03:
+		exp.Kind = ExternGlobal
+	case 0x04:
+		exp.Kind = ExternTag
+	default:
+		return exp, fmt.Errorf("unknown export kind %#x for %s", kind, exp.Name)
+	}
+	_, _, err = uleb128.Read(r)
+	return exp, err
+}
+
+func skipLimits(r *bytes.Reader) error {
+	flags, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if _, _, err = uleb128.Read(r); err != nil {
+		return err
+	}
+	if flags&0x01 != 0 {
+		_, _, err = uleb128.Read(r)
+	}
+	return err
+}
+
+// skipValType skips a value type or reference type, including a heap type
+// for the reference types in the GC proposal.
+func skipValType(r *bytes.Reader) error {
+	b, err := r.ReadByte()
+	if err != nil {
+		return err
+	}
+	if b == 0x63 || b == 0x64 { // (ref null ht) or (ref ht)
+		_, _, err = sleb128.Read(r)
+	}
+	return err
+}
+
+// readComponentImport reads a [component import].
+//
+// [component import]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Binary.md#import-and-export-definitions
+func readComponentImport(r *bytes.Reader) (imp Import, err error) {
+	imp.Name, err = readExternName(r)
+	if err != nil {
+		return imp, err
+	}
+	imp.Kind, err = readExternDesc(r)
+	return imp, err
+}
+
+// readComponentExport reads a [component export].
+//
+// [component export]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Binary.md#import-and-export-definitions
+func readComponentExport(r *bytes.Reader) (exp Export, err error) {
+	exp.Name, err = readExternName(r)
+	if err != nil {
+		return exp, err
+	}
+	// sortidx
+	sort, err := r.ReadByte()
+	if err != nil {
+		return exp, err
+	}
+	if sort == 0x00 {
+		if _, err = r.ReadByte(); err != nil { // core:sort
+			return exp, err
+		}
+	}
+	if _, _, err = uleb128.Read(r); err != nil {
+		return exp, err
+	}
+	// optional externdesc
+	present, err := r.ReadByte()
+	if err != nil {
+		return exp, err
+	}
+	if present == 0x01 {
+		exp.Kind, err = readExternDesc(r)
+		return exp, err
+	}
+	exp.Kind, err = sortKind(sort)
+	return exp, err
+}
+
+// readExternName reads an importname' or exportname', ignoring an optional version suffix.
+func readExternName(r *bytes.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	name, err := ReadString(r)
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 0x00:
+	case 0x01:
+		if _, err = ReadString(r); err != nil { // version suffix
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unknown extern name encoding %#x", b)
+	}
+	return name, nil
+}
+
+// readExternDesc reads an externdesc, returning its kind.
+func readExternDesc(r *bytes.Reader) (ExternKind, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	var kind ExternKind
+	switch b {
+	case 0x00: // 0x11 core:typeidx
+		kind = ExternModule
+		if _, err = r.ReadByte(); err != nil {
+			return 0, err
+		}
+		_, _, err = uleb128.Read(r)
+	case 0x01:
+		kind = ExternFunc
+		_, _, err = uleb128.Read(r)
+	case 0x02: // valuebound
+		kind = ExternValue
+		if _, err = r.ReadByte(); err != nil {
+			return 0, err
+		}
+		_, _, err = sleb128.Read(r)
+	case 0x03: // typebound
+		kind = ExternType
+		var bound byte
+		if bound, err = r.ReadByte(); err == nil && bound == 0x00 {
+			_, _, err = uleb128.Read(r)
+		}
+	case 0x04:
+		kind = ExternComponent
+		_, _, err = uleb128.Read(r)
+	case 0x05:
+		kind = ExternInstance
+		_, _, err = uleb128.Read(r)
+	default:
+		return 0, fmt.Errorf("unknown externdesc %#x", b)
+	}
+	return kind, err
+}
+
+// sortKind returns the [ExternKind] for a component sort.
+func sortKind(sort byte) (ExternKind, error) {
+	switch sort {
+	case 0x00:
+		return ExternModule, nil
+	case 0x01:
+		return ExternFunc, nil
+	case 0x02:
+		return ExternValue, nil
+	case 0x03:
+		return ExternType, nil
+	case 0x04:
+		return ExternComponent, nil
+	case 0x05:
+		return ExternInstance, nil
+	}
+	return 0, fmt.Errorf("unknown sort %#x", sort)
+}
@@ -0,0 +1,129 @@
+package wasm
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReadWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, []Section{
+		&LinkingSection{},
+		&CustomSection{Name: "component-type:example", Contents: []byte("contents")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Component {
+		t.Errorf("Component: true, expected false")
+	}
+	sections, err := b.CustomSections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*CustomSection{
+		{Name: "linking", Contents: []byte{2}},
+		{Name: "component-type:example", Contents: []byte("contents")},
+	}
+	if !reflect.DeepEqual(sections, want) {
+		t.Errorf("CustomSections: %+v, expected %+v", sections, want)
+	}
+}
+
+func TestReadModule(t *testing.T) {
+	b := readFile(t, "testdata/module.wasm")
+	if b.Component {
+		t.Errorf("Component: true, expected false")
+	}
+	imports, err := b.Imports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantImports := []Import{{Module: "cm32p2|my:pkg/foo@0.1", Name: "f", Kind: ExternFunc}}
+	if !reflect.DeepEqual(imports, wantImports) {
+		t.Errorf("Imports: %v, expected %v", imports, wantImports)
+	}
+	exports, err := b.Exports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantExports := []Export{
+		{Name: "cm32p2||g", Kind: ExternFunc},
+		{Name: "cm32p2||g_post", Kind: ExternFunc},
+		{Name: "cm32p2_memory", Kind: ExternMemory},
+		{Name: "cm32p2_realloc", Kind: ExternFunc},
+		{Name: "cm32p2_initialize", Kind: ExternFunc},
+	}
+	if !reflect.DeepEqual(exports, wantExports) {
+		t.Errorf("Exports: %v, expected %v", exports, wantExports)
+	}
+	sections, err := b.CustomSections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 1 || sections[0].Name != "component-type" {
+		t.Errorf("CustomSections: %d sections, expected component-type", len(sections))
+	}
+}
+
+func TestReadComponent(t *testing.T) {
+	b := readFile(t, "testdata/component.wasm")
+	if !b.Component {
+		t.Errorf("Component: false, expected true")
+	}
+	imports, err := b.Imports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantImports := []Import{{Name: "my:pkg/foo@0.1.0", Kind: ExternInstance}}
+	if !reflect.DeepEqual(imports, wantImports) {
+		t.Errorf("Imports: %v, expected %v", imports, wantImports)
+	}
+	exports, err := b.Exports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantExports := []Export{{Name: "g", Kind: ExternFunc}}
+	if !reflect.DeepEqual(exports, wantExports) {
+		t.Errorf("Exports: %v, expected %v", exports, wantExports)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"not wasm", "hello, world"},
+		{"unknown version", Magic + "\x02\x00\x00\x00"},
+		{"truncated section", Magic + Version1 + "\x00\x10name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.data))
+			if err == nil {
+				t.Errorf("Decode: nil error, expected error")
+			}
+		})
+	}
+}
+
+func readFile(t *testing.T, path string) *Binary {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
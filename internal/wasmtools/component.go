@@ -0,0 +1,70 @@
+package wasmtools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"testing/fstest"
+)
+
+// ComponentEmbed encodes a world in WIT text witData with wasm-tools, returning the contents
+// of a component-type custom section. If world is empty, the only world in witData is encoded.
+func (w *Instance) ComponentEmbed(ctx context.Context, witData string, world string) ([]byte, error) {
+	filename := "component.wit"
+	args := []string{"component", "embed", "--only-custom", filename}
+	if world != "" {
+		args = append(args, "--world", world)
+	}
+	fsMap := map[string]fs.FS{
+		"": fstest.MapFS{
+			filename: &fstest.MapFile{Data: []byte(witData)},
+		},
+	}
+	return w.run(ctx, fsMap, args...)
+}
+
+// ComponentWIT encodes the main package in WIT text witData as a WebAssembly binary with wasm-tools.
+func (w *Instance) ComponentWIT(ctx context.Context, witData string) ([]byte, error) {
+	filename := "component.wit"
+	fsMap := map[string]fs.FS{
+		"": fstest.MapFS{
+			filename: &fstest.MapFile{Data: []byte(witData)},
+		},
+	}
+	return w.run(ctx, fsMap, "component", "wit", "--wasm", filename)
+}
+
+// ComponentNew creates a component from core WebAssembly module with wasm-tools.
+// The module must contain one or more component-type custom sections.
+// Argument adapters maps the names of imported core modules, such as wasi_snapshot_preview1,
+// to the contents of an adapter module that implements them.
+func (w *Instance) ComponentNew(ctx context.Context, module []byte, adapters map[string][]byte) ([]byte, error) {
+	files := fstest.MapFS{
+		"module.wasm": &fstest.MapFile{Data: module},
+	}
+	args := []string{"component", "new", "module.wasm"}
+	i := 0
+	for name, adapter := range adapters {
+		filename := "adapter" + strconv.Itoa(i) + ".wasm"
+		files[filename] = &fstest.MapFile{Data: adapter}
+		args = append(args, "--adapt", name+"="+filename)
+		i++
+	}
+	return w.run(ctx, map[string]fs.FS{"": files}, args...)
+}
+
+// run runs wasm-tools with args, returning its output. Errors written by wasm-tools are included in the returned error.
+func (w *Instance) run(ctx context.Context, fsMap map[string]fs.FS, args ...string) ([]byte, error) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err := w.Run(ctx, nil, stdout, stderr, fsMap, args...)
+	if err != nil {
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("wasm-tools: %s", bytes.TrimSpace(stderr.Bytes()))
+		}
+		return nil, fmt.Errorf("wasm-tools: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
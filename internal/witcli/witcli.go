@@ -17,8 +17,8 @@ import (
 // from the buffer.
 // If path == "" or "-", then it reads from stdin.
 // If the resolved path doesn’t end in ".json", it will attempt to load
-// WIT indirectly by processing the input through wasm-tools.
-// If forceWIT is true, it will always process input through wasm-tools.
+// WIT text or a binary-encoded WIT package.
+// If forceWIT is true, it will always load input as WIT rather than JSON.
 func LoadWIT(ctx context.Context, path string, r io.Reader, forceWIT bool) (*wit.Resolve, error) {
 	if oci.IsOCIPath(path) {
 		fmt.Fprintf(os.Stderr, "Fetching OCI artifact %s\n", path)
@@ -0,0 +1,50 @@
+package witdeps
+
+import (
+	"go.bytecodealliance.org/wit/logging"
+)
+
+// Option represents a single configuration option for [Sync].
+type Option interface {
+	applyOption(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) applyOption(opts *options) {
+	f(opts)
+}
+
+type options struct {
+	logger    logging.Logger
+	update    bool
+	plainHTTP bool
+}
+
+func (opts *options) apply(o ...Option) {
+	for _, o := range o {
+		o.applyOption(opts)
+	}
+}
+
+// Logger returns an [Option] that specifies a [logging.Logger].
+func Logger(logger logging.Logger) Option {
+	return optionFunc(func(opts *options) {
+		opts.logger = logger
+	})
+}
+
+// Update returns an [Option] that ignores the lockfile, resolving each
+// dependency from the manifest again.
+func Update(update bool) Option {
+	return optionFunc(func(opts *options) {
+		opts.update = update
+	})
+}
+
+// PlainHTTP returns an [Option] that connects to OCI registries over HTTP rather than HTTPS.
+func PlainHTTP(plainHTTP bool) Option {
+	return optionFunc(func(opts *options) {
+		opts.plainHTTP = plainHTTP
+	})
+}
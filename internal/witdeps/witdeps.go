@@ -0,0 +1,404 @@
+// Package witdeps resolves the WIT package dependencies of a WIT directory,
+// fetching them from OCI registries or local paths into its deps directory.
+package witdeps
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+
+	"go.bytecodealliance.org/internal/oci"
+	"go.bytecodealliance.org/wit"
+	"go.bytecodealliance.org/wit/logging"
+)
+
+const (
+	// ManifestFile is the name of the dependency manifest in a WIT directory.
+	ManifestFile = "deps.json"
+
+	// LockFile is the name of the lockfile in a WIT directory.
+	LockFile = "deps.lock"
+
+	// DepsDir is the name of the directory in a WIT directory where dependencies are written.
+	DepsDir = "deps"
+)
+
+// Manifest maps WIT package names to the source of each package.
+// A name without a version, such as "wasi:io", matches any version of the package.
+// A source is either an OCI reference or a local path relative to the WIT directory.
+// An OCI reference without a tag or digest is tagged with the version of the package.
+type Manifest map[string]string
+
+// source returns the manifest source for package id.
+// A name with a version is preferred over a name without a version.
+func (m Manifest) source(id wit.Ident) (string, bool) {
+	if id.Version != nil {
+		if source, ok := m[id.String()]; ok {
+			return source, true
+		}
+	}
+	id.Version = nil
+	source, ok := m[id.String()]
+	return source, ok
+}
+
+// Lock records the resolved dependencies of a WIT directory.
+type Lock struct {
+	Packages []LockedPackage `json:"packages"`
+}
+
+// LockedPackage records a single resolved WIT package.
+type LockedPackage struct {
+	// Name is the WIT package name, such as "wasi:io@0.2.0".
+	Name string `json:"name"`
+
+	// Source is the source of the package in the manifest.
+	Source string `json:"source"`
+
+	// Resolved is the OCI reference with the manifest digest, or the local path, the package was fetched from.
+	Resolved string `json:"resolved"`
+
+	// Path is the slash-separated path of the WIT file written for the package, relative to the WIT directory.
+	Path string `json:"path"`
+
+	// Digest is the SHA-256 digest of the package source: the application/wasm layer of an OCI artifact,
+	// or the WIT files at a local path. A package fetched again from Resolved must match Digest.
+	Digest string `json:"digest"`
+
+	// FileDigest is the SHA-256 digest of the WIT file at Path. The WIT file is a cache of the package
+	// rendered from its source, and is rendered again if it is missing or does not match FileDigest.
+	FileDigest string `json:"file_digest"`
+}
+
+// find returns the locked package for package id from source, or nil if not found.
+func (l *Lock) find(id wit.Ident, source string) *LockedPackage {
+	for i := range l.Packages {
+		p := &l.Packages[i]
+		if p.Source != source {
+			continue
+		}
+		name, err := wit.ParseIdent(p.Name)
+		if err == nil && matches(id, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Sync resolves the transitive WIT package dependencies of the WIT files in dir,
+// which are found by scanning their use statements, imports, exports, and includes.
+// Each dependency is fetched from the source in the manifest, and written to a WIT file
+// in the deps directory. The lockfile is updated with the digest of each dependency.
+//
+// Dependencies recorded in the lockfile are fetched by digest, and are not fetched
+// again if their WIT file is unchanged, so a WIT directory can be loaded offline.
+func Sync(ctx context.Context, dir string, opts ...Option) (*Lock, error) {
+	var o options
+	o.apply(opts...)
+	if o.logger == nil {
+		o.logger = logging.DiscardLogger()
+	}
+
+	var manifest Manifest
+	err := readJSON(filepath.Join(dir, ManifestFile), &manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev Lock
+	err = readJSON(filepath.Join(dir, LockFile), &prev)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	var locked Lock
+	if !o.update {
+		locked = prev
+	}
+
+	queue, err := wit.ForeignPackages(dir)
+	if err != nil {
+		return nil, err
+	}
+	usedBy := make(map[string]string)
+
+	var lock Lock
+	var resolved []wit.Ident
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if slices.ContainsFunc(resolved, func(name wit.Ident) bool { return matches(id, name) }) {
+			continue
+		}
+
+		source, ok := manifest.source(id)
+		if !ok {
+			if parent, ok := usedBy[id.String()]; ok {
+				return nil, fmt.Errorf("package %s used by %s not found in %s", id.String(), parent, ManifestFile)
+			}
+			return nil, fmt.Errorf("package %s not found in %s", id.String(), ManifestFile)
+		}
+
+		var p *LockedPackage
+		if lp := locked.find(id, source); lp != nil {
+			p, err = syncLocked(ctx, dir, lp, &o)
+		} else {
+			p, err = fetch(ctx, dir, id, source, &o)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", id.String(), err)
+		}
+		name, err := wit.ParseIdent(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, name)
+		lock.Packages = append(lock.Packages, *p)
+
+		deps, err := wit.ForeignPackages(filepath.Join(dir, filepath.FromSlash(p.Path)))
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			if _, ok := usedBy[dep.String()]; !ok {
+				usedBy[dep.String()] = p.Name
+			}
+		}
+		queue = append(queue, deps...)
+	}
+
+	// Remove WIT files of dependencies that are no longer used.
+	for _, p := range prev.Packages {
+		if !slices.ContainsFunc(lock.Packages, func(lp LockedPackage) bool { return lp.Path == p.Path }) {
+			err := os.Remove(filepath.Join(dir, filepath.FromSlash(p.Path)))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	slices.SortFunc(lock.Packages, func(a, b LockedPackage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	err = writeJSON(filepath.Join(dir, LockFile), &lock)
+	if err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// syncLocked ensures the WIT file for locked package lp is present in dir and matches its file digest.
+// If the file is missing or modified, the package is fetched from the resolved source, which must match
+// the locked digest, and the file is rendered again.
+func syncLocked(ctx context.Context, dir string, lp *LockedPackage, o *options) (*LockedPackage, error) {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(lp.Path)))
+	if err == nil && digest(data) == lp.FileDigest {
+		o.logger.Debugf("Using locked %s from %s\n", lp.Name, lp.Path)
+		return lp, nil
+	}
+
+	name, err := wit.ParseIdent(lp.Name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := fetchResolved(ctx, dir, name, lp.Source, lp.Resolved, o)
+	if err != nil {
+		return nil, err
+	}
+	if p.Digest != lp.Digest {
+		return nil, fmt.Errorf("digest %s of %s does not match %s in %s", p.Digest, lp.Resolved, lp.Digest, LockFile)
+	}
+	return p, nil
+}
+
+// fetch fetches package id from source, resolving an OCI reference to its manifest digest.
+func fetch(ctx context.Context, dir string, id wit.Ident, source string, o *options) (*LockedPackage, error) {
+	resolved := source
+	if localPath(dir, source) == "" {
+		if !oci.IsOCIPath(source) {
+			return nil, fmt.Errorf("source %s is neither a local path nor an OCI reference", source)
+		}
+		resolved = ociRef(source, id.Version)
+		if !strings.Contains(resolved, "@") {
+			d, err := oci.ManifestDigest(ctx, resolved, oci.PlainHTTP(o.plainHTTP))
+			if err != nil {
+				return nil, err
+			}
+			resolved += "@" + d
+		}
+	}
+	return fetchResolved(ctx, dir, id, source, resolved, o)
+}
+
+// fetchResolved fetches package id from a resolved source, and writes it as a WIT file in the deps directory.
+func fetchResolved(ctx context.Context, dir string, id wit.Ident, source, resolved string, o *options) (*LockedPackage, error) {
+	var res *wit.Resolve
+	var sourceDigest string
+	if path := localPath(dir, resolved); path != "" {
+		o.logger.Infof("Loading %s from %s\n", id.String(), path)
+		var err error
+		sourceDigest, err = localDigest(path)
+		if err != nil {
+			return nil, err
+		}
+		res, err = wit.LoadWIT(path)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		o.logger.Infof("Fetching %s from %s\n", id.String(), resolved)
+		data, err := oci.PullWIT(ctx, resolved, oci.PlainHTTP(o.plainHTTP))
+		if err != nil {
+			return nil, err
+		}
+		sourceDigest = digest(data)
+		res, err = wit.DecodeWIT(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var pkg *wit.Package
+	for _, p := range res.Packages {
+		if matches(id, p.Name) {
+			pkg = p
+		}
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("package %s not found in %s", id.String(), resolved)
+	}
+
+	data := []byte(pkg.WIT(nil, ""))
+	path := DepsDir + "/" + fileName(pkg.Name)
+	filename := filepath.Join(dir, filepath.FromSlash(path))
+	err := os.MkdirAll(filepath.Dir(filename), 0o755)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(filename, data, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &LockedPackage{
+		Name:       pkg.Name.String(),
+		Source:     source,
+		Resolved:   resolved,
+		Path:       path,
+		Digest:     sourceDigest,
+		FileDigest: digest(data),
+	}, nil
+}
+
+// localPath returns the path of source relative to dir, or "" if source is not a local path.
+func localPath(dir, source string) string {
+	path := source
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// ociRef returns OCI reference ref tagged with version if ref has no tag or digest.
+func ociRef(ref string, version *semver.Version) string {
+	if version == nil {
+		return ref
+	}
+	name := ref[strings.LastIndex(ref, "/")+1:]
+	if strings.ContainsAny(name, ":@") {
+		return ref
+	}
+	return ref + ":" + version.String()
+}
+
+// matches reports whether package name matches package id.
+// An id without a version matches any version.
+func matches(id, name wit.Ident) bool {
+	if id.Namespace != name.Namespace || id.Package != name.Package {
+		return false
+	}
+	return id.Version == nil || (name.Version != nil && id.Version.Equal(*name.Version))
+}
+
+// fileName returns the name of the WIT file for a package, e.g. wasi-io-0.2.0.wit.
+func fileName(name wit.Ident) string {
+	s := name.Namespace + "-" + name.Package
+	if name.Version != nil {
+		s += "-" + name.Version.String()
+	}
+	return s + ".wit"
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// localDigest returns the digest of the WIT file at path, or of the WIT files in directory path
+// and its subdirectories, which are hashed in lexical order with their slash-separated relative paths.
+func localDigest(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return digest(data), nil
+	}
+	h := sha256.New()
+	err = filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(name) != ".wit" {
+			return err
+		}
+		rel, err := filepath.Rel(path, name)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(data))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,240 @@
+package witdeps
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/olareg/olareg"
+	oconfig "github.com/olareg/olareg/config"
+
+	"go.bytecodealliance.org/internal/oci"
+	"go.bytecodealliance.org/internal/wasmtools"
+	"go.bytecodealliance.org/wit"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func lockedNames(lock *Lock) []string {
+	var names []string
+	for _, p := range lock.Packages {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+const (
+	appWIT = `package example:app;
+
+world app {
+	import wasi:io/streams@0.2.0;
+	import wasi:clocks/wall-clock;
+}
+`
+	ioWIT = `package wasi:io@0.2.0;
+
+interface streams {
+	use wasi:poll/poll@0.2.0.{pollable};
+	resource output-stream {
+		subscribe: func() -> pollable;
+	}
+}
+`
+	pollWIT = `package wasi:poll@0.2.0;
+
+interface poll {
+	resource pollable;
+}
+`
+	clocksWIT = `package wasi:clocks@0.2.0;
+
+interface wall-clock {
+	now: func() -> u64;
+}
+`
+)
+
+func TestSyncLocal(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	dir := filepath.Join(root, "wit")
+	writeFiles(t, root, map[string]string{
+		"wit/app.wit":          appWIT,
+		"wit/deps.json":        `{"wasi:io@0.2.0": "../src/io", "wasi:poll": "../src/poll.wit", "wasi:clocks": "../src/clocks.wit"}`,
+		"src/io/streams.wit":   ioWIT,
+		"src/io/deps/poll.wit": pollWIT,
+		"src/poll.wit":         pollWIT,
+		"src/clocks.wit":       clocksWIT,
+	})
+
+	lock, err := Sync(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"wasi:clocks@0.2.0", "wasi:io@0.2.0", "wasi:poll@0.2.0"}
+	if got := lockedNames(lock); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sync: locked %v, expected %v", got, want)
+	}
+	if got, want := lock.Packages[1].Path, "deps/wasi-io-0.2.0.wit"; got != want {
+		t.Errorf("Path: %s, expected %s", got, want)
+	}
+	if got, want := lock.Packages[0].Digest, digest([]byte(clocksWIT)); got != want {
+		t.Errorf("Digest: %s, expected digest of source %s", got, want)
+	}
+	res, err := wit.LoadWIT(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(res.Packages), 4; got != want {
+		t.Errorf("LoadWIT: %d packages, expected %d", got, want)
+	}
+
+	// A modified dependency is rendered again from its unchanged source.
+	writeFiles(t, dir, map[string]string{"deps/wasi-clocks-0.2.0.wit": clocksWIT + "\n"})
+	lock2, err := Sync(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lock2, lock) {
+		t.Errorf("Sync: lock %+v, expected %+v", lock2, lock)
+	}
+
+	// Dependencies in the lockfile are not loaded again.
+	if err := os.RemoveAll(filepath.Join(root, "src")); err != nil {
+		t.Fatal(err)
+	}
+	lock2, err = Sync(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lock2, lock) {
+		t.Errorf("Sync: lock %+v, expected %+v", lock2, lock)
+	}
+
+	// A modified dependency is loaded again, which fails without its source.
+	writeFiles(t, dir, map[string]string{"deps/wasi-clocks-0.2.0.wit": clocksWIT + "\n"})
+	if _, err = Sync(ctx, dir); err == nil {
+		t.Error("Sync: nil error, expected error for modified dependency without source")
+	}
+
+	// Dependencies that are no longer used are removed.
+	writeFiles(t, dir, map[string]string{
+		"app.wit":                    "package example:app;\n\nworld app {\n\timport wasi:clocks/wall-clock;\n}\n",
+		"deps/wasi-clocks-0.2.0.wit": clocksWIT,
+	})
+	lock, err = Sync(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := lockedNames(lock), []string{"wasi:clocks@0.2.0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sync: locked %v, expected %v", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "deps", "wasi-io-0.2.0.wit")); !os.IsNotExist(err) {
+		t.Errorf("unused dependency not removed: %v", err)
+	}
+}
+
+func TestSyncMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app.wit":              appWIT,
+		"deps.json":            `{"wasi:io": "src/io", "wasi:clocks": "src/clocks.wit"}`,
+		"src/io/streams.wit":   ioWIT,
+		"src/io/deps/poll.wit": pollWIT,
+		"src/clocks.wit":       clocksWIT,
+	})
+	_, err := Sync(context.Background(), dir)
+	want := "package wasi:poll@0.2.0 used by wasi:io@0.2.0 not found in deps.json"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Sync: %v, expected error %q", err, want)
+	}
+}
+
+func TestSyncOCI(t *testing.T) {
+	ctx := context.Background()
+	reg := olareg.New(oconfig.Config{
+		Storage: oconfig.ConfigStorage{StoreType: oconfig.StoreMem},
+	})
+	ts := httptest.NewServer(reg)
+	t.Cleanup(ts.Close)
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	wasmTools, err := wasmtools.New(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wasmTools.Close(ctx)
+	publish := func(witText string) []byte {
+		t.Helper()
+		data, err := wasmTools.ComponentWIT(ctx, witText)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = oci.PushWIT(ctx, u.Host+"/wasi/clocks:0.2.0", data, nil, oci.PlainHTTP(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	data := publish(clocksWIT)
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app.wit":   "package example:app;\n\nworld app {\n\timport wasi:clocks/wall-clock@0.2.0;\n}\n",
+		"deps.json": `{"wasi:clocks": "` + u.Host + `/wasi/clocks"}`,
+	})
+	lock, err := Sync(ctx, dir, PlainHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := lock.Packages[0]
+	if !strings.HasPrefix(p.Resolved, u.Host+"/wasi/clocks:0.2.0@sha256:") {
+		t.Errorf("Resolved: %s, expected tag 0.2.0 and digest", p.Resolved)
+	}
+	if got, want := p.Digest, digest(data); got != want {
+		t.Errorf("Digest: %s, expected digest of layer %s", got, want)
+	}
+	if _, err := wit.LoadWIT(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Update the tag. The locked digest is fetched, unless updating.
+	publish(strings.Replace(clocksWIT, "now:", "/// Returns the time.\n\tnow:", 1))
+	if err := os.Remove(filepath.Join(dir, filepath.FromSlash(p.Path))); err != nil {
+		t.Fatal(err)
+	}
+	lock2, err := Sync(ctx, dir, PlainHTTP(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(lock2, lock) {
+		t.Errorf("Sync: lock %+v, expected %+v", lock2, lock)
+	}
+	lock3, err := Sync(ctx, dir, PlainHTTP(true), Update(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock3.Packages[0].Resolved == p.Resolved || lock3.Packages[0].Digest == p.Digest {
+		t.Errorf("Sync with Update: lock %+v not updated", lock3)
+	}
+}
@@ -0,0 +1,281 @@
+// Package witlex implements a lexer for WIT source, shared by the WIT parser
+// in package wit and the WIT formatter in package wit/format.
+package witlex
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pos represents a line and column position in a WIT source file.
+type Pos struct {
+	File string
+	Line int // 1-based
+	Col  int // 1-based
+}
+
+// String implements [fmt.Stringer], returning pos in file:line:column format.
+func (pos Pos) String() string {
+	file := pos.File
+	if file == "" {
+		file = "<input>"
+	}
+	return fmt.Sprintf("%s:%d:%d", file, pos.Line, pos.Col)
+}
+
+// Error is a lexical error at a position in WIT source.
+type Error struct {
+	Pos Pos
+	Msg string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+// Kind represents the kind of a lexical token in WIT source.
+type Kind int
+
+const (
+	EOF Kind = iota
+	Ident
+	Int
+	Version // a SemVer version following @ or =
+	Punct
+)
+
+// Comment is a line or block comment, including its // or /* */ delimiters.
+type Comment struct {
+	Text     string
+	Newlines int // number of newlines between the previous token or comment and this comment
+}
+
+// IsLine returns true if c is a line comment.
+func (c *Comment) IsLine() bool {
+	return strings.HasPrefix(c.Text, "//")
+}
+
+// Token represents a single lexical token in WIT source, with the comments that precede it.
+type Token struct {
+	Kind     Kind
+	Text     string // identifier (without leading %), integer, version, or punctuation
+	Escaped  bool   // true if an identifier was escaped with %
+	Comments []Comment
+	Newlines int // number of newlines between the previous token or comment and this token
+	Pos      Pos
+}
+
+// Is returns true if t is punctuation or an unescaped identifier or keyword equal to s.
+func (t *Token) Is(s string) bool {
+	return (t.Kind == Punct || (t.Kind == Ident && !t.Escaped)) && t.Text == s
+}
+
+// Raw returns the source text of t, including the leading % of an escaped identifier.
+func (t *Token) Raw() string {
+	if t.Escaped {
+		return "%" + t.Text
+	}
+	return t.Text
+}
+
+// String implements [fmt.Stringer].
+func (t Token) String() string {
+	if t.Kind == EOF {
+		return "end of file"
+	}
+	return "`" + t.Raw() + "`"
+}
+
+// Lexer splits WIT source into tokens.
+// Comments are attached to the following token.
+type Lexer struct {
+	src  string
+	file string
+	off  int
+	line int
+	col  int
+	prev Token
+}
+
+// New returns a [Lexer] for src, which was read from file.
+func New(file, src string) *Lexer {
+	return &Lexer{src: src, file: file, line: 1, col: 1}
+}
+
+// File returns the file name of the source of l.
+func (l *Lexer) File() string {
+	return l.file
+}
+
+func (l *Lexer) pos() Pos {
+	return Pos{File: l.file, Line: l.line, Col: l.col}
+}
+
+func (l *Lexer) errorf(pos Pos, format string, args ...any) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// peekc returns the byte at offset n from the current position, or -1 at EOF.
+func (l *Lexer) peekc(n int) rune {
+	if l.off+n >= len(l.src) {
+		return -1
+	}
+	return rune(l.src[l.off+n])
+}
+
+// advance advances the lexer by n bytes, tracking lines and columns.
+func (l *Lexer) advance(n int) {
+	for i := 0; i < n && l.off < len(l.src); i++ {
+		if l.src[l.off] == '\n' {
+			l.line++
+			l.col = 1
+		} else if l.src[l.off] < utf8.RuneSelf || utf8.RuneStart(l.src[l.off]) {
+			l.col++
+		}
+		l.off++
+	}
+}
+
+// skip skips whitespace and comments, returning the comments encountered
+// and the number of newlines following the last comment.
+func (l *Lexer) skip() ([]Comment, int, error) {
+	var comments []Comment
+	newlines := 0
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		switch {
+		case c == '\n':
+			newlines++
+			l.advance(1)
+		case c == ' ' || c == '\t' || c == '\r':
+			l.advance(1)
+		case c == '/' && l.peekc(1) == '/':
+			end := strings.IndexByte(l.src[l.off:], '\n')
+			if end < 0 {
+				end = len(l.src) - l.off
+			}
+			comments = append(comments, Comment{Text: l.src[l.off : l.off+end], Newlines: newlines})
+			newlines = 0
+			l.advance(end)
+		case c == '/' && l.peekc(1) == '*':
+			start := l.pos()
+			depth := 0
+			i := l.off
+			for {
+				if i+1 >= len(l.src) {
+					return nil, 0, l.errorf(start, "unterminated block comment")
+				}
+				if l.src[i] == '/' && l.src[i+1] == '*' {
+					depth++
+					i += 2
+					continue
+				}
+				if l.src[i] == '*' && l.src[i+1] == '/' {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+					continue
+				}
+				i++
+			}
+			comments = append(comments, Comment{Text: l.src[l.off:i], Newlines: newlines})
+			newlines = 0
+			l.advance(i - l.off)
+		default:
+			return comments, newlines, nil
+		}
+	}
+	return comments, newlines, nil
+}
+
+// Next returns the next token. A number following @ or = is scanned as a [Version].
+func (l *Lexer) Next() (Token, error) {
+	t, err := l.next()
+	l.prev = t
+	return t, err
+}
+
+func (l *Lexer) next() (Token, error) {
+	comments, newlines, err := l.skip()
+	if err != nil {
+		return Token{}, err
+	}
+	t := Token{Comments: comments, Newlines: newlines, Pos: l.pos()}
+	if l.off >= len(l.src) {
+		t.Kind = EOF
+		return t, nil
+	}
+	c := l.src[l.off]
+	switch {
+	case c == '%' || isIdentStart(c):
+		if c == '%' {
+			t.Escaped = true
+			l.advance(1)
+			if l.off >= len(l.src) || !isIdentStart(l.src[l.off]) {
+				return t, l.errorf(t.Pos, "expected identifier after %%")
+			}
+		}
+		start := l.off
+		for l.off < len(l.src) && isIdentChar(l.src[l.off]) {
+			l.advance(1)
+		}
+		t.Kind = Ident
+		t.Text = l.src[start:l.off]
+		return t, nil
+	case c >= '0' && c <= '9' && (l.prev.Is("@") || l.prev.Is("=")):
+		t.Kind = Version
+		t.Text = l.version()
+		return t, nil
+	case c >= '0' && c <= '9':
+		start := l.off
+		for l.off < len(l.src) && l.src[l.off] >= '0' && l.src[l.off] <= '9' {
+			l.advance(1)
+		}
+		t.Kind = Int
+		t.Text = l.src[start:l.off]
+		return t, nil
+	case c == '-' && l.peekc(1) == '>':
+		t.Kind = Punct
+		t.Text = "->"
+		l.advance(2)
+		return t, nil
+	case strings.IndexByte("{}()<>,;:.=*/@_", c) >= 0:
+		t.Kind = Punct
+		t.Text = string(c)
+		l.advance(1)
+		return t, nil
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.off:])
+	return t, l.errorf(t.Pos, "unexpected character %q", r)
+}
+
+// version scans a raw [SemVer] version string starting at the current position.
+// It stops before a trailing ".{", which is used in use statements.
+//
+// [SemVer]: https://semver.org/
+func (l *Lexer) version() string {
+	start := l.off
+	for l.off < len(l.src) {
+		c := l.src[l.off]
+		if c == '.' && (l.peekc(1) == '{' || l.peekc(1) < 0) {
+			break
+		}
+		if !isIdentChar(c) && c != '.' && c != '+' {
+			break
+		}
+		l.advance(1)
+	}
+	return l.src[start:l.off]
+}
+
+func isIdentStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9') || c == '-'
+}
@@ -0,0 +1,75 @@
+package witlex
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLexer(t *testing.T) {
+	src := "// a\n\n/* b */ package %foo:bar@0.2.0-rc.1;\nuse x:y/z@1.0.0.{t};\n@since(version = 0.1.0)\ntype l = list<u8, 4>; -> /**/"
+	l := New("", src)
+	var got []string
+	var tokens []Token
+	for {
+		tok, err := l.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens = append(tokens, tok)
+		if tok.Kind == EOF {
+			break
+		}
+		got = append(got, tok.Raw())
+	}
+	wantText := []string{
+		"package", "%foo", ":", "bar", "@", "0.2.0-rc.1", ";",
+		"use", "x", ":", "y", "/", "z", "@", "1.0.0", ".", "{", "t", "}", ";",
+		"@", "since", "(", "version", "=", "0.1.0", ")",
+		"type", "l", "=", "list", "<", "u8", ",", "4", ">", ";", "->",
+	}
+	if !slices.Equal(got, wantText) {
+		t.Errorf("Next: got %q, expected %q", got, wantText)
+	}
+
+	first := tokens[0]
+	if first.Pos.Line != 3 || first.Pos.Col != 9 || len(first.Comments) != 2 ||
+		first.Comments[0] != (Comment{Text: "// a"}) || first.Comments[1] != (Comment{Text: "/* b */", Newlines: 2}) {
+		t.Errorf("first token: %+v", first)
+	}
+	for i, want := range map[int]Kind{1: Ident, 5: Version, 14: Version, 25: Version, 34: Int} {
+		if tokens[i].Kind != want {
+			t.Errorf("token %d %s: kind %d, expected %d", i, tokens[i], tokens[i].Kind, want)
+		}
+	}
+	if !tokens[1].Escaped || tokens[1].Text != "foo" {
+		t.Errorf("token 1: %+v, expected escaped identifier foo", tokens[1])
+	}
+	if eof := tokens[len(tokens)-1]; len(eof.Comments) != 1 || eof.Comments[0].Text != "/**/" {
+		t.Errorf("EOF: %+v, expected trailing comment", eof)
+	}
+}
+
+func TestLexerErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"/* unterminated", "<input>:1:1: unterminated block comment"},
+		{"foo\n  $", "<input>:2:3: unexpected character '$'"},
+		{"% foo", "<input>:1:1: expected identifier after %"},
+	}
+	for _, tt := range tests {
+		l := New("", tt.src)
+		var err error
+		for err == nil {
+			var tok Token
+			tok, err = l.Next()
+			if tok.Kind == EOF && err == nil {
+				break
+			}
+		}
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Next(%q): %v, expected %q", tt.src, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+//go:build !wasm
+
+package fake
+
+import (
+	"testing"
+
+	wallclock "tests/generated/wasi/clocks/v0.2.0/wall-clock"
+	"tests/generated/wasi/filesystem/v0.2.0/preopens"
+	"tests/generated/wasi/filesystem/v0.2.0/types"
+
+	"go.bytecodealliance.org/cm"
+)
+
+func TestFakeFunction(t *testing.T) {
+	want := wallclock.DateTime{Seconds: 1234567890, Nanoseconds: 42}
+	wallclock.Fake.Now = func() wallclock.DateTime { return want }
+	defer func() { wallclock.Fake.Now = nil }()
+
+	got := wallclock.Now()
+	if got != want {
+		t.Errorf("Now(): %v, expected %v", got, want)
+	}
+}
+
+func TestFakeNil(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Resolution(): expected panic")
+		}
+	}()
+	wallclock.Resolution()
+}
+
+func TestFakeResource(t *testing.T) {
+	const path = "/tmp"
+	fake := &types.Fake.Descriptor
+	d := fake.Handles.New(path)
+	preopens.Fake.GetDirectories = func() cm.List[cm.Tuple[types.Descriptor, string]] {
+		return cm.ToList([]cm.Tuple[types.Descriptor, string]{{F0: d, F1: path}})
+	}
+	type getTypeResult = cm.Result[types.DescriptorType, types.DescriptorType, types.ErrorCode]
+	fake.GetType = func(self types.Descriptor) getTypeResult {
+		if _, ok := fake.Handles.Get(self); !ok {
+			return cm.Err[getTypeResult](types.ErrorCodeBadDescriptor)
+		}
+		return cm.OK[getTypeResult](types.DescriptorTypeDirectory)
+	}
+	defer func() {
+		preopens.Fake.GetDirectories = nil
+		fake.GetType = nil
+	}()
+
+	dirs := preopens.GetDirectories().Slice()
+	if len(dirs) != 1 {
+		t.Fatalf("GetDirectories(): %d directories, expected 1", len(dirs))
+	}
+	desc := dirs[0].F0
+	if got, want := dirs[0].F1, path; got != want {
+		t.Errorf("GetDirectories(): path %q, expected %q", got, want)
+	}
+	result := desc.GetType()
+	if got, want := *result.OK(), types.DescriptorTypeDirectory; got != want {
+		t.Errorf("GetType(): %v, expected %v", got, want)
+	}
+
+	desc.ResourceDrop()
+	if n := fake.Handles.Len(); n != 0 {
+		t.Errorf("Handles.Len(): %d, expected 0 after ResourceDrop", n)
+	}
+	result = desc.GetType()
+	if got, want := *result.Err(), types.ErrorCodeBadDescriptor; got != want {
+		t.Errorf("GetType() after ResourceDrop: %v, expected %v", got, want)
+	}
+}
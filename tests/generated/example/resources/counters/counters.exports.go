@@ -0,0 +1,49 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+package counters
+
+// exports represents the caller-defined exports from "example:resources/counters".
+var exports struct {
+	// Counter represents the caller-defined exports for resource "example:resources/counters#counter".
+	Counter struct {
+		// Destructor represents the caller-defined, exported destructor for resource "counter".
+		//
+		// Resource destructor.
+		//
+		Destructor func(self CounterValue)
+
+		// Constructor represents the caller-defined, exported constructor for resource "counter".
+		//
+		//	constructor(start: u32)
+		Constructor func(start uint32) (result Counter)
+	}
+}
+
+// Interface represents the caller-defined exports from "example:resources/counters".
+// Register an implementation with [SetExports].
+type Interface interface {
+	// Counter returns the caller-defined exports for resource "example:resources/counters#counter".
+	Counter() CounterInterface
+}
+
+// CounterInterface represents the caller-defined exports for resource "example:resources/counters#counter".
+type CounterInterface interface {
+	// Destructor represents the caller-defined, exported destructor for resource "counter".
+	//
+	// Resource destructor.
+	//
+	Destructor(self CounterValue)
+
+	// Constructor represents the caller-defined, exported constructor for resource "counter".
+	//
+	//	constructor(start: u32)
+	Constructor(start uint32) (result Counter)
+}
+
+// SetExports sets the caller-defined implementation of the exports from "example:resources/counters".
+// It must be called before any exported function is called, such as from an init function.
+func SetExports(impl Interface) {
+	counter := impl.Counter()
+	exports.Counter.Destructor = counter.Destructor
+	exports.Counter.Constructor = counter.Constructor
+}
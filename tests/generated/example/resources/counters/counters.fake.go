@@ -0,0 +1,81 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package counters
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "example:resources/counters"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// Counter contains the hooks for exported resource "example:resources/counters#counter".
+	Counter struct {
+		// Handles is an in-memory table of fake handles for resource "counter".
+		// The default ResourceNew, ResourceRep, and ResourceDrop hooks use Handles to map handles to reps.
+		Handles cm.FakeHandles[Counter, cm.Rep]
+
+		// ResourceNew represents the imported resource-new for resource "counter".
+		//
+		// Creates a new resource handle.
+		ResourceNew func(rep cm.Rep) (result Counter)
+
+		// ResourceRep represents the imported resource-rep for resource "counter".
+		//
+		// Returns the underlying resource representation.
+		ResourceRep func(self Counter) (result cm.Rep)
+
+		// ResourceDrop represents the imported resource-drop for resource "counter".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self Counter)
+	}
+}
+
+func wasmimport_CounterResourceNew(rep0 uint32) (result0 uint32) {
+	rep := cm.Reinterpret[cm.Rep]((uint32)(rep0))
+	if Fake.Counter.ResourceNew == nil {
+		panic("Counter.ResourceNew: no fake for [export]example:resources/counters [resource-new]counter")
+	}
+	result := Fake.Counter.ResourceNew(rep)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
+
+func wasmimport_CounterResourceRep(self0 uint32) (result0 uint32) {
+	self := cm.Reinterpret[Counter]((uint32)(self0))
+	if Fake.Counter.ResourceRep == nil {
+		panic("Counter.ResourceRep: no fake for [export]example:resources/counters [resource-rep]counter")
+	}
+	result := Fake.Counter.ResourceRep(self)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
+
+func wasmimport_CounterResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[Counter]((uint32)(self0))
+	if Fake.Counter.ResourceDrop == nil {
+		panic("Counter.ResourceDrop: no fake for [export]example:resources/counters [resource-drop]counter")
+	}
+	Fake.Counter.ResourceDrop(self)
+	return
+}
+
+func init() {
+	Fake.Counter.ResourceNew = func(rep cm.Rep) (result Counter) {
+		return Fake.Counter.Handles.New(rep)
+	}
+	Fake.Counter.ResourceRep = func(self Counter) (result cm.Rep) {
+		rep, _ := Fake.Counter.Handles.Get(self)
+		return rep
+	}
+	Fake.Counter.ResourceDrop = func(self Counter) {
+		if rep, ok := Fake.Counter.Handles.Get(self); ok {
+			Fake.Counter.Handles.Drop(self)
+			exports.Counter.Destructor(CounterTable.Drop(rep))
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build wasm
+
+package counters
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// This file contains wasmimport and wasmexport declarations for "example:resources".
+
+//go:wasmimport [export]example:resources/counters [resource-new]counter
+//go:noescape
+func wasmimport_CounterResourceNew(rep0 uint32) (result0 uint32)
+
+//go:wasmimport [export]example:resources/counters [resource-rep]counter
+//go:noescape
+func wasmimport_CounterResourceRep(self0 uint32) (result0 uint32)
+
+//go:wasmimport [export]example:resources/counters [resource-drop]counter
+//go:noescape
+func wasmimport_CounterResourceDrop(self0 uint32)
+
+//go:wasmexport example:resources/counters#[dtor]counter
+//export example:resources/counters#[dtor]counter
+func wasmexport_CounterDestructor(self0 uint32) {
+	self := cm.Reinterpret[cm.Rep]((uint32)(self0))
+	exports.Counter.Destructor(CounterTable.Drop(self))
+	return
+}
+
+//go:wasmexport example:resources/counters#[constructor]counter
+//export example:resources/counters#[constructor]counter
+func wasmexport_Constructor(start0 uint32) (result0 uint32) {
+	start := (uint32)((uint32)(start0))
+	result := exports.Counter.Constructor(start)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
+
+//go:wasmexport example:resources/counters#[method]counter.add
+//export example:resources/counters#[method]counter.add
+func wasmexport_CounterAdd(self0 uint32, n0 uint32) (result0 uint32) {
+	self := cm.Reinterpret[cm.Rep]((uint32)(self0))
+	n := (uint32)((uint32)(n0))
+	result := CounterTable.Value(self).Add(n)
+	result0 = (uint32)(result)
+	return
+}
@@ -0,0 +1,68 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package counters represents the exported interface "example:resources/counters".
+package counters
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Counter represents the exported resource "example:resources/counters#counter".
+//
+//	resource counter
+type Counter cm.Resource
+
+// CounterResourceNew represents the imported resource-new for resource "counter".
+//
+// Creates a new resource handle.
+//
+//go:nosplit
+func CounterResourceNew(rep cm.Rep) (result Counter) {
+	rep0 := cm.Reinterpret[uint32](rep)
+	result0 := wasmimport_CounterResourceNew((uint32)(rep0))
+	result = cm.Reinterpret[Counter]((uint32)(result0))
+	return
+}
+
+// ResourceRep represents the imported resource-rep for resource "counter".
+//
+// Returns the underlying resource representation.
+//
+//go:nosplit
+func (self Counter) ResourceRep() (result cm.Rep) {
+	self0 := cm.Reinterpret[uint32](self)
+	result0 := wasmimport_CounterResourceRep((uint32)(self0))
+	result = cm.Reinterpret[cm.Rep]((uint32)(result0))
+	return
+}
+
+// ResourceDrop represents the imported resource-drop for resource "counter".
+//
+// Drops a resource handle.
+//
+//go:nosplit
+func (self Counter) ResourceDrop() {
+	self0 := cm.Reinterpret[uint32](self)
+	wasmimport_CounterResourceDrop((uint32)(self0))
+	return
+}
+
+func init() {
+	exports.Counter.Destructor = func(self CounterValue) {}
+}
+
+// CounterValue represents the Go value of exported resource "example:resources/counters#counter".
+// The methods of resource "counter" are called on the value stored in [CounterTable]
+// for the rep of the resource handle. For example, a Go type T implements
+// CounterValue with methods on *T.
+type CounterValue interface {
+	// Add represents the caller-defined, exported method "add".
+	//
+	//	add: func(n: u32) -> u32
+	Add(n uint32) (result uint32)
+}
+
+// CounterTable holds the Go values of exported resource "example:resources/counters#counter", indexed by rep.
+// Create a resource handle for a value with CounterResourceNew(CounterTable.New(v)).
+// The value is released before the caller-defined destructor is called.
+var CounterTable cm.ResourceTable[CounterValue]
@@ -0,0 +1,70 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package handles
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "example:resources/handles"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// File contains the hooks for imported resource "example:resources/handles#file".
+	File struct {
+		// Handles is an in-memory table of fake handles for resource "file".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[File, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "file".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self File)
+
+		// Constructor represents the imported constructor for resource "file".
+		//
+		//	constructor(name: string)
+		Constructor func(name string) (result File)
+
+		// Name represents the imported method "name".
+		//
+		//	name: func() -> string
+		Name func(self File) (result string)
+	}
+}
+
+func wasmimport_FileResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[File]((uint32)(self0))
+	if Fake.File.ResourceDrop == nil {
+		panic("File.ResourceDrop: no fake for example:resources/handles [resource-drop]file")
+	}
+	Fake.File.ResourceDrop(self)
+	return
+}
+
+func wasmimport_NewFile(name0 *uint8, name1 uint32) (result0 uint32) {
+	name := cm.LiftString[string]((*uint8)(name0), (uint32)(name1))
+	if Fake.File.Constructor == nil {
+		panic("File.Constructor: no fake for example:resources/handles [constructor]file")
+	}
+	result := Fake.File.Constructor(name)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
+
+func wasmimport_FileName(self0 uint32, result *string) {
+	self := cm.Reinterpret[File]((uint32)(self0))
+	if Fake.File.Name == nil {
+		panic("File.Name: no fake for example:resources/handles [method]file.name")
+	}
+	*result = Fake.File.Name(self)
+	return
+}
+
+func init() {
+	Fake.File.ResourceDrop = func(self File) {
+		Fake.File.Handles.Drop(self)
+	}
+}
@@ -0,0 +1,19 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build wasm
+
+package handles
+
+// This file contains wasmimport and wasmexport declarations for "example:resources".
+
+//go:wasmimport example:resources/handles [resource-drop]file
+//go:noescape
+func wasmimport_FileResourceDrop(self0 uint32)
+
+//go:wasmimport example:resources/handles [constructor]file
+//go:noescape
+func wasmimport_NewFile(name0 *uint8, name1 uint32) (result0 uint32)
+
+//go:wasmimport example:resources/handles [method]file.name
+//go:noescape
+func wasmimport_FileName(self0 uint32, result *string)
@@ -0,0 +1,54 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+// Package handles represents the imported interface "example:resources/handles".
+package handles
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// File represents the imported resource "example:resources/handles#file".
+//
+//	resource file
+type File cm.Resource
+
+// ResourceDrop represents the imported resource-drop for resource "file".
+//
+// Drops a resource handle.
+//
+//go:nosplit
+func (self File) ResourceDrop() {
+	self0 := cm.Reinterpret[uint32](self)
+	wasmimport_FileResourceDrop((uint32)(self0))
+	return
+}
+
+// Owned wraps owned handle self to resource "file" in a [cm.Owned].
+// Its Close method drops the handle, after which the handle cannot be used or dropped again.
+// Call AutoDrop to drop the handle if it becomes unreachable without being closed.
+func (self File) Owned() *cm.Owned[File] {
+	return cm.NewOwned(self)
+}
+
+// NewFile represents the imported constructor for resource "file".
+//
+//	constructor(name: string)
+//
+//go:nosplit
+func NewFile(name string) (result File) {
+	name0, name1 := cm.LowerString(name)
+	result0 := wasmimport_NewFile((*uint8)(name0), (uint32)(name1))
+	result = cm.Reinterpret[File]((uint32)(result0))
+	return
+}
+
+// Name represents the imported method "name".
+//
+//	name: func() -> string
+//
+//go:nosplit
+func (self File) Name() (result string) {
+	self0 := cm.Reinterpret[uint32](self)
+	wasmimport_FileName((uint32)(self0), &result)
+	return
+}
@@ -0,0 +1,67 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package environment
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/environment@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetEnvironment represents the imported function "get-environment".
+	//
+	// Get the POSIX-style environment variables.
+	//
+	// Each environment variable is provided as a pair of string variable names
+	// and string value.
+	//
+	// Morally, these are a value import, but until value imports are available
+	// in the component model, this import function should return the same
+	// values each time it is called.
+	//
+	//	get-environment: func() -> list<tuple<string, string>>
+	GetEnvironment func() (result cm.List[[2]string])
+
+	// GetArguments represents the imported function "get-arguments".
+	//
+	// Get the POSIX-style arguments to the program.
+	//
+	//	get-arguments: func() -> list<string>
+	GetArguments func() (result cm.List[string])
+
+	// InitialCWD represents the imported function "initial-cwd".
+	//
+	// Return a path that programs should use as their initial current working
+	// directory, interpreting `.` as shorthand for this.
+	//
+	//	initial-cwd: func() -> option<string>
+	InitialCWD func() (result cm.Option[string])
+}
+
+func wasmimport_GetEnvironment(result *cm.List[[2]string]) {
+	if Fake.GetEnvironment == nil {
+		panic("GetEnvironment: no fake for wasi:cli/environment@0.2.0 get-environment")
+	}
+	*result = Fake.GetEnvironment()
+	return
+}
+
+func wasmimport_GetArguments(result *cm.List[string]) {
+	if Fake.GetArguments == nil {
+		panic("GetArguments: no fake for wasi:cli/environment@0.2.0 get-arguments")
+	}
+	*result = Fake.GetArguments()
+	return
+}
+
+func wasmimport_InitialCWD(result *cm.Option[string]) {
+	if Fake.InitialCWD == nil {
+		panic("InitialCWD: no fake for wasi:cli/environment@0.2.0 initial-cwd")
+	}
+	*result = Fake.InitialCWD()
+	return
+}
@@ -0,0 +1,30 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package exit
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/exit@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// Exit represents the imported function "exit".
+	//
+	// Exit the current instance and any linked instances.
+	//
+	//	exit: func(status: result)
+	Exit func(status cm.BoolResult)
+}
+
+func wasmimport_Exit(status0 uint32) {
+	status := (cm.BoolResult)((bool)(cm.U32ToBool((uint32)(status0))))
+	if Fake.Exit == nil {
+		panic("Exit: no fake for wasi:cli/exit@0.2.0 exit")
+	}
+	Fake.Exit(status)
+	return
+}
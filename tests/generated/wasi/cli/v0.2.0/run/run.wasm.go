@@ -1,5 +1,7 @@
 // Code generated by wit-bindgen-go. DO NOT EDIT.
 
+//go:build wasm
+
 package run
 
 import (
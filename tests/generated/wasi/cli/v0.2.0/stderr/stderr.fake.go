@@ -0,0 +1,28 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package stderr
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/stderr@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetStderr represents the imported function "get-stderr".
+	//
+	//	get-stderr: func() -> output-stream
+	GetStderr func() (result OutputStream)
+}
+
+func wasmimport_GetStderr() (result0 uint32) {
+	if Fake.GetStderr == nil {
+		panic("GetStderr: no fake for wasi:cli/stderr@0.2.0 get-stderr")
+	}
+	result := Fake.GetStderr()
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
@@ -1,5 +1,7 @@
 // Code generated by wit-bindgen-go. DO NOT EDIT.
 
+//go:build wasm
+
 package stderr
 
 // This file contains wasmimport and wasmexport declarations for "wasi:cli@0.2.0".
@@ -0,0 +1,28 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package stdin
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/stdin@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetStdin represents the imported function "get-stdin".
+	//
+	//	get-stdin: func() -> input-stream
+	GetStdin func() (result InputStream)
+}
+
+func wasmimport_GetStdin() (result0 uint32) {
+	if Fake.GetStdin == nil {
+		panic("GetStdin: no fake for wasi:cli/stdin@0.2.0 get-stdin")
+	}
+	result := Fake.GetStdin()
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
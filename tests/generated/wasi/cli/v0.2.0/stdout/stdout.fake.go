@@ -0,0 +1,28 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package stdout
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/stdout@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetStdout represents the imported function "get-stdout".
+	//
+	//	get-stdout: func() -> output-stream
+	GetStdout func() (result OutputStream)
+}
+
+func wasmimport_GetStdout() (result0 uint32) {
+	if Fake.GetStdout == nil {
+		panic("GetStdout: no fake for wasi:cli/stdout@0.2.0 get-stdout")
+	}
+	result := Fake.GetStdout()
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
@@ -0,0 +1,41 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package terminalinput
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/terminal-input@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// TerminalInput contains the hooks for imported resource "wasi:cli/terminal-input@0.2.0#terminal-input".
+	TerminalInput struct {
+		// Handles is an in-memory table of fake handles for resource "terminal-input".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[TerminalInput, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "terminal-input".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self TerminalInput)
+	}
+}
+
+func wasmimport_TerminalInputResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[TerminalInput]((uint32)(self0))
+	if Fake.TerminalInput.ResourceDrop == nil {
+		panic("TerminalInput.ResourceDrop: no fake for wasi:cli/terminal-input@0.2.0 [resource-drop]terminal-input")
+	}
+	Fake.TerminalInput.ResourceDrop(self)
+	return
+}
+
+func init() {
+	Fake.TerminalInput.ResourceDrop = func(self TerminalInput) {
+		Fake.TerminalInput.Handles.Drop(self)
+	}
+}
@@ -0,0 +1,41 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package terminaloutput
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/terminal-output@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// TerminalOutput contains the hooks for imported resource "wasi:cli/terminal-output@0.2.0#terminal-output".
+	TerminalOutput struct {
+		// Handles is an in-memory table of fake handles for resource "terminal-output".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[TerminalOutput, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "terminal-output".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self TerminalOutput)
+	}
+}
+
+func wasmimport_TerminalOutputResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[TerminalOutput]((uint32)(self0))
+	if Fake.TerminalOutput.ResourceDrop == nil {
+		panic("TerminalOutput.ResourceDrop: no fake for wasi:cli/terminal-output@0.2.0 [resource-drop]terminal-output")
+	}
+	Fake.TerminalOutput.ResourceDrop(self)
+	return
+}
+
+func init() {
+	Fake.TerminalOutput.ResourceDrop = func(self TerminalOutput) {
+		Fake.TerminalOutput.Handles.Drop(self)
+	}
+}
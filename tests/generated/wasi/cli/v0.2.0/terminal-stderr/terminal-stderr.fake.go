@@ -0,0 +1,30 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package terminalstderr
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/terminal-stderr@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetTerminalStderr represents the imported function "get-terminal-stderr".
+	//
+	// If stderr is connected to a terminal, return a `terminal-output` handle
+	// allowing further interaction with it.
+	//
+	//	get-terminal-stderr: func() -> option<terminal-output>
+	GetTerminalStderr func() (result cm.Option[TerminalOutput])
+}
+
+func wasmimport_GetTerminalStderr(result *cm.Option[TerminalOutput]) {
+	if Fake.GetTerminalStderr == nil {
+		panic("GetTerminalStderr: no fake for wasi:cli/terminal-stderr@0.2.0 get-terminal-stderr")
+	}
+	*result = Fake.GetTerminalStderr()
+	return
+}
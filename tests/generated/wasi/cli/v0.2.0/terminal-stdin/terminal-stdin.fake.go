@@ -0,0 +1,30 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package terminalstdin
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/terminal-stdin@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetTerminalStdin represents the imported function "get-terminal-stdin".
+	//
+	// If stdin is connected to a terminal, return a `terminal-input` handle
+	// allowing further interaction with it.
+	//
+	//	get-terminal-stdin: func() -> option<terminal-input>
+	GetTerminalStdin func() (result cm.Option[TerminalInput])
+}
+
+func wasmimport_GetTerminalStdin(result *cm.Option[TerminalInput]) {
+	if Fake.GetTerminalStdin == nil {
+		panic("GetTerminalStdin: no fake for wasi:cli/terminal-stdin@0.2.0 get-terminal-stdin")
+	}
+	*result = Fake.GetTerminalStdin()
+	return
+}
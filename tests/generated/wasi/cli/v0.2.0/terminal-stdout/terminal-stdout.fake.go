@@ -0,0 +1,30 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package terminalstdout
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:cli/terminal-stdout@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetTerminalStdout represents the imported function "get-terminal-stdout".
+	//
+	// If stdout is connected to a terminal, return a `terminal-output` handle
+	// allowing further interaction with it.
+	//
+	//	get-terminal-stdout: func() -> option<terminal-output>
+	GetTerminalStdout func() (result cm.Option[TerminalOutput])
+}
+
+func wasmimport_GetTerminalStdout(result *cm.Option[TerminalOutput]) {
+	if Fake.GetTerminalStdout == nil {
+		panic("GetTerminalStdout: no fake for wasi:cli/terminal-stdout@0.2.0 get-terminal-stdout")
+	}
+	*result = Fake.GetTerminalStdout()
+	return
+}
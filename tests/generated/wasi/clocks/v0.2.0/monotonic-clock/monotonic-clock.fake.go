@@ -0,0 +1,87 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package monotonicclock
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:clocks/monotonic-clock@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// Now represents the imported function "now".
+	//
+	// Read the current value of the clock.
+	//
+	// The clock is monotonic, therefore calling this function repeatedly will
+	// produce a sequence of non-decreasing values.
+	//
+	//	now: func() -> instant
+	Now func() (result Instant)
+
+	// Resolution represents the imported function "resolution".
+	//
+	// Query the resolution of the clock. Returns the duration of time
+	// corresponding to a clock tick.
+	//
+	//	resolution: func() -> duration
+	Resolution func() (result Duration)
+
+	// SubscribeInstant represents the imported function "subscribe-instant".
+	//
+	// Create a `pollable` which will resolve once the specified instant
+	// occured.
+	//
+	//	subscribe-instant: func(when: instant) -> pollable
+	SubscribeInstant func(when Instant) (result Pollable)
+
+	// SubscribeDuration represents the imported function "subscribe-duration".
+	//
+	// Create a `pollable` which will resolve once the given duration has
+	// elapsed, starting at the time at which this function was called.
+	// occured.
+	//
+	//	subscribe-duration: func(when: duration) -> pollable
+	SubscribeDuration func(when Duration) (result Pollable)
+}
+
+func wasmimport_Now() (result0 uint64) {
+	if Fake.Now == nil {
+		panic("Now: no fake for wasi:clocks/monotonic-clock@0.2.0 now")
+	}
+	result := Fake.Now()
+	result0 = (uint64)(result)
+	return
+}
+
+func wasmimport_Resolution() (result0 uint64) {
+	if Fake.Resolution == nil {
+		panic("Resolution: no fake for wasi:clocks/monotonic-clock@0.2.0 resolution")
+	}
+	result := Fake.Resolution()
+	result0 = (uint64)(result)
+	return
+}
+
+func wasmimport_SubscribeInstant(when0 uint64) (result0 uint32) {
+	when := (Instant)((uint64)(when0))
+	if Fake.SubscribeInstant == nil {
+		panic("SubscribeInstant: no fake for wasi:clocks/monotonic-clock@0.2.0 subscribe-instant")
+	}
+	result := Fake.SubscribeInstant(when)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
+
+func wasmimport_SubscribeDuration(when0 uint64) (result0 uint32) {
+	when := (Duration)((uint64)(when0))
+	if Fake.SubscribeDuration == nil {
+		panic("SubscribeDuration: no fake for wasi:clocks/monotonic-clock@0.2.0 subscribe-duration")
+	}
+	result := Fake.SubscribeDuration(when)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
@@ -1,5 +1,7 @@
 // Code generated by wit-bindgen-go. DO NOT EDIT.
 
+//go:build wasm
+
 package monotonicclock
 
 // This file contains wasmimport and wasmexport declarations for "wasi:clocks@0.2.0".
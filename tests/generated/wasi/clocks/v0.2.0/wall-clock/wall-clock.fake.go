@@ -0,0 +1,54 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package wallclock
+
+// Fake contains hooks for the functions imported from "wasi:clocks/wall-clock@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// Now represents the imported function "now".
+	//
+	// Read the current value of the clock.
+	//
+	// This clock is not monotonic, therefore calling this function repeatedly
+	// will not necessarily produce a sequence of non-decreasing values.
+	//
+	// The returned timestamps represent the number of seconds since
+	// 1970-01-01T00:00:00Z, also known as [POSIX's Seconds Since the Epoch],
+	// also known as [Unix Time].
+	//
+	// The nanoseconds field of the output is always less than 1000000000.
+	//
+	// [POSIX's Seconds Since the Epoch]: https://pubs.opengroup.org/onlinepubs/9699919799/xrat/V4_xbd_chap04.html#tag_21_04_16
+	// [Unix Time]: https://en.wikipedia.org/wiki/Unix_time
+	//
+	//	now: func() -> datetime
+	Now func() (result DateTime)
+
+	// Resolution represents the imported function "resolution".
+	//
+	// Query the resolution of the clock.
+	//
+	// The nanoseconds field of the output is always less than 1000000000.
+	//
+	//	resolution: func() -> datetime
+	Resolution func() (result DateTime)
+}
+
+func wasmimport_Now(result *DateTime) {
+	if Fake.Now == nil {
+		panic("Now: no fake for wasi:clocks/wall-clock@0.2.0 now")
+	}
+	*result = Fake.Now()
+	return
+}
+
+func wasmimport_Resolution(result *DateTime) {
+	if Fake.Resolution == nil {
+		panic("Resolution: no fake for wasi:clocks/wall-clock@0.2.0 resolution")
+	}
+	*result = Fake.Resolution()
+	return
+}
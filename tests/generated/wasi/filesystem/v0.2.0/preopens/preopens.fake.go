@@ -0,0 +1,29 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package preopens
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:filesystem/preopens@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetDirectories represents the imported function "get-directories".
+	//
+	// Return the set of preopened directories, and their path.
+	//
+	//	get-directories: func() -> list<tuple<descriptor, string>>
+	GetDirectories func() (result cm.List[cm.Tuple[Descriptor, string]])
+}
+
+func wasmimport_GetDirectories(result *cm.List[cm.Tuple[Descriptor, string]]) {
+	if Fake.GetDirectories == nil {
+		panic("GetDirectories: no fake for wasi:filesystem/preopens@0.2.0 get-directories")
+	}
+	*result = Fake.GetDirectories()
+	return
+}
@@ -4,6 +4,7 @@ package types
 
 import (
 	"go.bytecodealliance.org/cm"
+	"strconv"
 	wallclock "tests/generated/wasi/clocks/v0.2.0/wall-clock"
 	"unsafe"
 )
@@ -14,10 +15,44 @@ type MetadataHashValueShape struct {
 	shape [unsafe.Sizeof(MetadataHashValue{})]byte
 }
 
-// TupleListU8BoolShape is used for storage in variant or result types.
-type TupleListU8BoolShape struct {
+// abi_ResultTupleListU8BoolErrorCodeShape is used for storage in type result<tuple<list<u8>, bool>, error-code> with the Canonical ABI memory layout.
+type abi_ResultTupleListU8BoolErrorCodeShape struct {
 	_     cm.HostLayout
-	shape [unsafe.Sizeof(cm.Tuple[cm.List[uint8], bool]{})]byte
+	shape [max(unsafe.Sizeof(*new(cm.Tuple[cm.List[uint8], bool])), unsafe.Sizeof(*new(ErrorCode)))]byte
+}
+
+func fromABI_ResultTupleListU8BoolErrorCode(v cm.Result[abi_ResultTupleListU8BoolErrorCodeShape, cm.Tuple[cm.List[uint8], bool], ErrorCode]) (out cm.Result[cm.SplitResult[cm.Tuple[cm.List[uint8], bool], ErrorCode], cm.Tuple[cm.List[uint8], bool], ErrorCode]) {
+	if ok := v.OK(); ok != nil {
+		return cm.OK[cm.Result[cm.SplitResult[cm.Tuple[cm.List[uint8], bool], ErrorCode], cm.Tuple[cm.List[uint8], bool], ErrorCode]](*ok)
+	}
+	return cm.Err[cm.Result[cm.SplitResult[cm.Tuple[cm.List[uint8], bool], ErrorCode], cm.Tuple[cm.List[uint8], bool], ErrorCode]](*v.Err())
+}
+
+func toABI_ResultTupleListU8BoolErrorCode(v cm.Result[cm.SplitResult[cm.Tuple[cm.List[uint8], bool], ErrorCode], cm.Tuple[cm.List[uint8], bool], ErrorCode]) (out cm.Result[abi_ResultTupleListU8BoolErrorCodeShape, cm.Tuple[cm.List[uint8], bool], ErrorCode]) {
+	if ok := v.OK(); ok != nil {
+		return cm.OK[cm.Result[abi_ResultTupleListU8BoolErrorCodeShape, cm.Tuple[cm.List[uint8], bool], ErrorCode]](*ok)
+	}
+	return cm.Err[cm.Result[abi_ResultTupleListU8BoolErrorCodeShape, cm.Tuple[cm.List[uint8], bool], ErrorCode]](*v.Err())
+}
+
+// abi_ResultStringErrorCodeShape is used for storage in type result<string, error-code> with the Canonical ABI memory layout.
+type abi_ResultStringErrorCodeShape struct {
+	_     cm.HostLayout
+	shape [max(unsafe.Sizeof(*new(string)), unsafe.Sizeof(*new(ErrorCode)))]byte
+}
+
+func fromABI_ResultStringErrorCode(v cm.Result[abi_ResultStringErrorCodeShape, string, ErrorCode]) (out cm.Result[cm.SplitResult[string, ErrorCode], string, ErrorCode]) {
+	if ok := v.OK(); ok != nil {
+		return cm.OK[cm.Result[cm.SplitResult[string, ErrorCode], string, ErrorCode]](*ok)
+	}
+	return cm.Err[cm.Result[cm.SplitResult[string, ErrorCode], string, ErrorCode]](*v.Err())
+}
+
+func toABI_ResultStringErrorCode(v cm.Result[cm.SplitResult[string, ErrorCode], string, ErrorCode]) (out cm.Result[abi_ResultStringErrorCodeShape, string, ErrorCode]) {
+	if ok := v.OK(); ok != nil {
+		return cm.OK[cm.Result[abi_ResultStringErrorCodeShape, string, ErrorCode]](*ok)
+	}
+	return cm.Err[cm.Result[abi_ResultStringErrorCodeShape, string, ErrorCode]](*v.Err())
 }
 
 func lower_DateTime(v wallclock.DateTime) (f0 uint64, f1 uint32) {
@@ -37,14 +72,26 @@ func lower_NewTimestamp(v NewTimestamp) (f0 uint32, f1 uint64, f2 uint32) {
 	return
 }
 
+func lift_DateTime(f0 uint64, f1 uint32) (v wallclock.DateTime) {
+	v.Seconds = (uint64)(f0)
+	v.Nanoseconds = (uint32)(f1)
+	return
+}
+
+func lift_NewTimestamp(f0 uint32, f1 uint64, f2 uint32) (v NewTimestamp) {
+	switch f0 {
+	case 0:
+		return cm.New[NewTimestamp](0, struct{}{})
+	case 1:
+		return cm.New[NewTimestamp](1, struct{}{})
+	case 2:
+		return cm.New[NewTimestamp](2, lift_DateTime((uint64)(f1), (uint32)(f2)))
+	}
+	panic("lift variant: unknown case: " + strconv.Itoa(int(f0)))
+}
+
 // DescriptorStatShape is used for storage in variant or result types.
 type DescriptorStatShape struct {
 	_     cm.HostLayout
 	shape [unsafe.Sizeof(DescriptorStat{})]byte
 }
-
-// OptionDirectoryEntryShape is used for storage in variant or result types.
-type OptionDirectoryEntryShape struct {
-	_     cm.HostLayout
-	shape [unsafe.Sizeof(cm.Option[DirectoryEntry]{})]byte
-}
@@ -0,0 +1,749 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package types
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:filesystem/types@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// Descriptor contains the hooks for imported resource "wasi:filesystem/types@0.2.0#descriptor".
+	Descriptor struct {
+		// Handles is an in-memory table of fake handles for resource "descriptor".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[Descriptor, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "descriptor".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self Descriptor)
+
+		// Advise represents the imported method "advise".
+		//
+		// Provide file advisory information on a descriptor.
+		//
+		// This is similar to `posix_fadvise` in POSIX.
+		//
+		//	advise: func(offset: filesize, length: filesize, advice: advice) -> result<_, error-code>
+		Advise func(self Descriptor, offset FileSize, length FileSize, advice Advice) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// AppendViaStream represents the imported method "append-via-stream".
+		//
+		// Return a stream for appending to a file, if available.
+		//
+		// May fail with an error-code describing why the file cannot be appended.
+		//
+		// Note: This allows using `write-stream`, which is similar to `write` with
+		// `O_APPEND` in in POSIX.
+		//
+		//	append-via-stream: func() -> result<output-stream, error-code>
+		AppendViaStream func(self Descriptor) (result cm.Result[OutputStream, OutputStream, ErrorCode])
+
+		// CreateDirectoryAt represents the imported method "create-directory-at".
+		//
+		// Create a directory.
+		//
+		// Note: This is similar to `mkdirat` in POSIX.
+		//
+		//	create-directory-at: func(path: string) -> result<_, error-code>
+		CreateDirectoryAt func(self Descriptor, path string) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// GetFlags represents the imported method "get-flags".
+		//
+		// Get flags associated with a descriptor.
+		//
+		// Note: This returns similar flags to `fcntl(fd, F_GETFL)` in POSIX.
+		//
+		// Note: This returns the value that was the `fs_flags` value returned
+		// from `fdstat_get` in earlier versions of WASI.
+		//
+		//	get-flags: func() -> result<descriptor-flags, error-code>
+		GetFlags func(self Descriptor) (result cm.Result[DescriptorFlags, DescriptorFlags, ErrorCode])
+
+		// GetType represents the imported method "get-type".
+		//
+		// Get the dynamic type of a descriptor.
+		//
+		// Note: This returns the same value as the `type` field of the `fd-stat`
+		// returned by `stat`, `stat-at` and similar.
+		//
+		// Note: This returns similar flags to the `st_mode & S_IFMT` value provided
+		// by `fstat` in POSIX.
+		//
+		// Note: This returns the value that was the `fs_filetype` value returned
+		// from `fdstat_get` in earlier versions of WASI.
+		//
+		//	get-type: func() -> result<descriptor-type, error-code>
+		GetType func(self Descriptor) (result cm.Result[DescriptorType, DescriptorType, ErrorCode])
+
+		// IsSameObject represents the imported method "is-same-object".
+		//
+		// Test whether two descriptors refer to the same filesystem object.
+		//
+		// In POSIX, this corresponds to testing whether the two descriptors have the
+		// same device (`st_dev`) and inode (`st_ino` or `d_ino`) numbers.
+		// wasi-filesystem does not expose device and inode numbers, so this function
+		// may be used instead.
+		//
+		//	is-same-object: func(other: borrow<descriptor>) -> bool
+		IsSameObject func(self Descriptor, other Descriptor) (result bool)
+
+		// LinkAt represents the imported method "link-at".
+		//
+		// Create a hard link.
+		//
+		// Note: This is similar to `linkat` in POSIX.
+		//
+		//	link-at: func(old-path-flags: path-flags, old-path: string, new-descriptor: borrow<descriptor>,
+		//	new-path: string) -> result<_, error-code>
+		LinkAt func(self Descriptor, oldPathFlags PathFlags, oldPath string, newDescriptor Descriptor, newPath string) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// MetadataHash represents the imported method "metadata-hash".
+		//
+		// Return a hash of the metadata associated with a filesystem object referred
+		// to by a descriptor.
+		//
+		// This returns a hash of the last-modification timestamp and file size, and
+		// may also include the inode number, device number, birth timestamp, and
+		// other metadata fields that may change when the file is modified or
+		// replaced. It may also include a secret value chosen by the
+		// implementation and not otherwise exposed.
+		//
+		// Implementations are encourated to provide the following properties:
+		//
+		// - If the file is not modified or replaced, the computed hash value should
+		// usually not change.
+		// - If the object is modified or replaced, the computed hash value should
+		// usually change.
+		// - The inputs to the hash should not be easily computable from the
+		// computed hash.
+		//
+		// However, none of these is required.
+		//
+		//	metadata-hash: func() -> result<metadata-hash-value, error-code>
+		MetadataHash func(self Descriptor) (result cm.Result[MetadataHashValueShape, MetadataHashValue, ErrorCode])
+
+		// MetadataHashAt represents the imported method "metadata-hash-at".
+		//
+		// Return a hash of the metadata associated with a filesystem object referred
+		// to by a directory descriptor and a relative path.
+		//
+		// This performs the same hash computation as `metadata-hash`.
+		//
+		//	metadata-hash-at: func(path-flags: path-flags, path: string) -> result<metadata-hash-value,
+		//	error-code>
+		MetadataHashAt func(self Descriptor, pathFlags PathFlags, path string) (result cm.Result[MetadataHashValueShape, MetadataHashValue, ErrorCode])
+
+		// OpenAt represents the imported method "open-at".
+		//
+		// Open a file or directory.
+		//
+		// The returned descriptor is not guaranteed to be the lowest-numbered
+		// descriptor not currently open/ it is randomized to prevent applications
+		// from depending on making assumptions about indexes, since this is
+		// error-prone in multi-threaded contexts. The returned descriptor is
+		// guaranteed to be less than 2**31.
+		//
+		// If `flags` contains `descriptor-flags::mutate-directory`, and the base
+		// descriptor doesn't have `descriptor-flags::mutate-directory` set,
+		// `open-at` fails with `error-code::read-only`.
+		//
+		// If `flags` contains `write` or `mutate-directory`, or `open-flags`
+		// contains `truncate` or `create`, and the base descriptor doesn't have
+		// `descriptor-flags::mutate-directory` set, `open-at` fails with
+		// `error-code::read-only`.
+		//
+		// Note: This is similar to `openat` in POSIX.
+		//
+		//	open-at: func(path-flags: path-flags, path: string, open-flags: open-flags, %flags:
+		//	descriptor-flags) -> result<descriptor, error-code>
+		OpenAt func(self Descriptor, pathFlags PathFlags, path string, openFlags OpenFlags, flags DescriptorFlags) (result cm.Result[Descriptor, Descriptor, ErrorCode])
+
+		// Read represents the imported method "read".
+		//
+		// Read from a descriptor, without using and updating the descriptor's offset.
+		//
+		// This function returns a list of bytes containing the data that was
+		// read, along with a bool which, when true, indicates that the end of the
+		// file was reached. The returned list will contain up to `length` bytes; it
+		// may return fewer than requested, if the end of the file is reached or
+		// if the I/O operation is interrupted.
+		//
+		// In the future, this may change to return a `stream<u8, error-code>`.
+		//
+		// Note: This is similar to `pread` in POSIX.
+		//
+		//	read: func(length: filesize, offset: filesize) -> result<tuple<list<u8>, bool>,
+		//	error-code>
+		Read func(self Descriptor, length FileSize, offset FileSize) (result cm.Result[cm.SplitResult[cm.Tuple[cm.List[uint8], bool], ErrorCode], cm.Tuple[cm.List[uint8], bool], ErrorCode])
+
+		// ReadDirectory represents the imported method "read-directory".
+		//
+		// Read directory entries from a directory.
+		//
+		// On filesystems where directories contain entries referring to themselves
+		// and their parents, often named `.` and `..` respectively, these entries
+		// are omitted.
+		//
+		// This always returns a new stream which starts at the beginning of the
+		// directory. Multiple streams may be active on the same directory, and they
+		// do not interfere with each other.
+		//
+		//	read-directory: func() -> result<directory-entry-stream, error-code>
+		ReadDirectory func(self Descriptor) (result cm.Result[DirectoryEntryStream, DirectoryEntryStream, ErrorCode])
+
+		// ReadViaStream represents the imported method "read-via-stream".
+		//
+		// Return a stream for reading from a file, if available.
+		//
+		// May fail with an error-code describing why the file cannot be read.
+		//
+		// Multiple read, write, and append streams may be active on the same open
+		// file and they do not interfere with each other.
+		//
+		// Note: This allows using `read-stream`, which is similar to `read` in POSIX.
+		//
+		//	read-via-stream: func(offset: filesize) -> result<input-stream, error-code>
+		ReadViaStream func(self Descriptor, offset FileSize) (result cm.Result[InputStream, InputStream, ErrorCode])
+
+		// ReadLinkAt represents the imported method "readlink-at".
+		//
+		// Read the contents of a symbolic link.
+		//
+		// If the contents contain an absolute or rooted path in the underlying
+		// filesystem, this function fails with `error-code::not-permitted`.
+		//
+		// Note: This is similar to `readlinkat` in POSIX.
+		//
+		//	readlink-at: func(path: string) -> result<string, error-code>
+		ReadLinkAt func(self Descriptor, path string) (result cm.Result[cm.SplitResult[string, ErrorCode], string, ErrorCode])
+
+		// RemoveDirectoryAt represents the imported method "remove-directory-at".
+		//
+		// Remove a directory.
+		//
+		// Return `error-code::not-empty` if the directory is not empty.
+		//
+		// Note: This is similar to `unlinkat(fd, path, AT_REMOVEDIR)` in POSIX.
+		//
+		//	remove-directory-at: func(path: string) -> result<_, error-code>
+		RemoveDirectoryAt func(self Descriptor, path string) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// RenameAt represents the imported method "rename-at".
+		//
+		// Rename a filesystem object.
+		//
+		// Note: This is similar to `renameat` in POSIX.
+		//
+		//	rename-at: func(old-path: string, new-descriptor: borrow<descriptor>, new-path:
+		//	string) -> result<_, error-code>
+		RenameAt func(self Descriptor, oldPath string, newDescriptor Descriptor, newPath string) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// SetSize represents the imported method "set-size".
+		//
+		// Adjust the size of an open file. If this increases the file's size, the
+		// extra bytes are filled with zeros.
+		//
+		// Note: This was called `fd_filestat_set_size` in earlier versions of WASI.
+		//
+		//	set-size: func(size: filesize) -> result<_, error-code>
+		SetSize func(self Descriptor, size FileSize) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// SetTimes represents the imported method "set-times".
+		//
+		// Adjust the timestamps of an open file or directory.
+		//
+		// Note: This is similar to `futimens` in POSIX.
+		//
+		// Note: This was called `fd_filestat_set_times` in earlier versions of WASI.
+		//
+		//	set-times: func(data-access-timestamp: new-timestamp, data-modification-timestamp:
+		//	new-timestamp) -> result<_, error-code>
+		SetTimes func(self Descriptor, dataAccessTimestamp NewTimestamp, dataModificationTimestamp NewTimestamp) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// SetTimesAt represents the imported method "set-times-at".
+		//
+		// Adjust the timestamps of a file or directory.
+		//
+		// Note: This is similar to `utimensat` in POSIX.
+		//
+		// Note: This was called `path_filestat_set_times` in earlier versions of
+		// WASI.
+		//
+		//	set-times-at: func(path-flags: path-flags, path: string, data-access-timestamp:
+		//	new-timestamp, data-modification-timestamp: new-timestamp) -> result<_, error-code>
+		SetTimesAt func(self Descriptor, pathFlags PathFlags, path string, dataAccessTimestamp NewTimestamp, dataModificationTimestamp NewTimestamp) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// Stat represents the imported method "stat".
+		//
+		// Return the attributes of an open file or directory.
+		//
+		// Note: This is similar to `fstat` in POSIX, except that it does not return
+		// device and inode information. For testing whether two descriptors refer to
+		// the same underlying filesystem object, use `is-same-object`. To obtain
+		// additional data that can be used do determine whether a file has been
+		// modified, use `metadata-hash`.
+		//
+		// Note: This was called `fd_filestat_get` in earlier versions of WASI.
+		//
+		//	stat: func() -> result<descriptor-stat, error-code>
+		Stat func(self Descriptor) (result cm.Result[DescriptorStatShape, DescriptorStat, ErrorCode])
+
+		// StatAt represents the imported method "stat-at".
+		//
+		// Return the attributes of a file or directory.
+		//
+		// Note: This is similar to `fstatat` in POSIX, except that it does not
+		// return device and inode information. See the `stat` description for a
+		// discussion of alternatives.
+		//
+		// Note: This was called `path_filestat_get` in earlier versions of WASI.
+		//
+		//	stat-at: func(path-flags: path-flags, path: string) -> result<descriptor-stat,
+		//	error-code>
+		StatAt func(self Descriptor, pathFlags PathFlags, path string) (result cm.Result[DescriptorStatShape, DescriptorStat, ErrorCode])
+
+		// SymlinkAt represents the imported method "symlink-at".
+		//
+		// Create a symbolic link (also known as a "symlink").
+		//
+		// If `old-path` starts with `/`, the function fails with
+		// `error-code::not-permitted`.
+		//
+		// Note: This is similar to `symlinkat` in POSIX.
+		//
+		//	symlink-at: func(old-path: string, new-path: string) -> result<_, error-code>
+		SymlinkAt func(self Descriptor, oldPath string, newPath string) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// Sync represents the imported method "sync".
+		//
+		// Synchronize the data and metadata of a file to disk.
+		//
+		// This function succeeds with no effect if the file descriptor is not
+		// opened for writing.
+		//
+		// Note: This is similar to `fsync` in POSIX.
+		//
+		//	sync: func() -> result<_, error-code>
+		Sync func(self Descriptor) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// SyncData represents the imported method "sync-data".
+		//
+		// Synchronize the data of a file to disk.
+		//
+		// This function succeeds with no effect if the file descriptor is not
+		// opened for writing.
+		//
+		// Note: This is similar to `fdatasync` in POSIX.
+		//
+		//	sync-data: func() -> result<_, error-code>
+		SyncData func(self Descriptor) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// UnlinkFileAt represents the imported method "unlink-file-at".
+		//
+		// Unlink a filesystem object that is not a directory.
+		//
+		// Return `error-code::is-directory` if the path refers to a directory.
+		// Note: This is similar to `unlinkat(fd, path, 0)` in POSIX.
+		//
+		//	unlink-file-at: func(path: string) -> result<_, error-code>
+		UnlinkFileAt func(self Descriptor, path string) (result cm.Result[ErrorCode, struct{}, ErrorCode])
+
+		// Write represents the imported method "write".
+		//
+		// Write to a descriptor, without using and updating the descriptor's offset.
+		//
+		// It is valid to write past the end of a file; the file is extended to the
+		// extent of the write, with bytes between the previous end and the start of
+		// the write set to zero.
+		//
+		// In the future, this may change to take a `stream<u8, error-code>`.
+		//
+		// Note: This is similar to `pwrite` in POSIX.
+		//
+		//	write: func(buffer: list<u8>, offset: filesize) -> result<filesize, error-code>
+		Write func(self Descriptor, buffer cm.List[uint8], offset FileSize) (result cm.Result[uint64, FileSize, ErrorCode])
+
+		// WriteViaStream represents the imported method "write-via-stream".
+		//
+		// Return a stream for writing to a file, if available.
+		//
+		// May fail with an error-code describing why the file cannot be written.
+		//
+		// Note: This allows using `write-stream`, which is similar to `write` in
+		// POSIX.
+		//
+		//	write-via-stream: func(offset: filesize) -> result<output-stream, error-code>
+		WriteViaStream func(self Descriptor, offset FileSize) (result cm.Result[OutputStream, OutputStream, ErrorCode])
+	}
+
+	// DirectoryEntryStream contains the hooks for imported resource "wasi:filesystem/types@0.2.0#directory-entry-stream".
+	DirectoryEntryStream struct {
+		// Handles is an in-memory table of fake handles for resource "directory-entry-stream".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[DirectoryEntryStream, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "directory-entry-stream".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self DirectoryEntryStream)
+
+		// ReadDirectoryEntry represents the imported method "read-directory-entry".
+		//
+		// Read a single directory entry from a `directory-entry-stream`.
+		//
+		//	read-directory-entry: func() -> result<option<directory-entry>, error-code>
+		ReadDirectoryEntry func(self DirectoryEntryStream) (result cm.Result[cm.Option[DirectoryEntry], cm.Option[DirectoryEntry], ErrorCode])
+	}
+
+	// FilesystemErrorCode represents the imported function "filesystem-error-code".
+	//
+	// Attempts to extract a filesystem-related `error-code` from the stream
+	// `error` provided.
+	//
+	// Stream operations which return `stream-error::last-operation-failed`
+	// have a payload with more information about the operation that failed.
+	// This payload can be passed through to this function to see if there's
+	// filesystem-related information about the error to return.
+	//
+	// Note that this function is fallible because not all stream-related
+	// errors are filesystem-related errors.
+	//
+	//	filesystem-error-code: func(err: borrow<error>) -> option<error-code>
+	FilesystemErrorCode func(err Error) (result cm.Option[ErrorCode])
+}
+
+func wasmimport_DescriptorResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.ResourceDrop == nil {
+		panic("Descriptor.ResourceDrop: no fake for wasi:filesystem/types@0.2.0 [resource-drop]descriptor")
+	}
+	Fake.Descriptor.ResourceDrop(self)
+	return
+}
+
+func wasmimport_DescriptorAdvise(self0 uint32, offset0 uint64, length0 uint64, advice0 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	offset := (FileSize)((uint64)(offset0))
+	length := (FileSize)((uint64)(length0))
+	advice := (Advice)((uint32)(advice0))
+	if Fake.Descriptor.Advise == nil {
+		panic("Descriptor.Advise: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.advise")
+	}
+	*result = Fake.Descriptor.Advise(self, offset, length, advice)
+	return
+}
+
+func wasmimport_DescriptorAppendViaStream(self0 uint32, result *cm.Result[OutputStream, OutputStream, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.AppendViaStream == nil {
+		panic("Descriptor.AppendViaStream: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.append-via-stream")
+	}
+	*result = Fake.Descriptor.AppendViaStream(self)
+	return
+}
+
+func wasmimport_DescriptorCreateDirectoryAt(self0 uint32, path0 *uint8, path1 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	path := cm.LiftString[string]((*uint8)(path0), (uint32)(path1))
+	if Fake.Descriptor.CreateDirectoryAt == nil {
+		panic("Descriptor.CreateDirectoryAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.create-directory-at")
+	}
+	*result = Fake.Descriptor.CreateDirectoryAt(self, path)
+	return
+}
+
+func wasmimport_DescriptorGetFlags(self0 uint32, result *cm.Result[DescriptorFlags, DescriptorFlags, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.GetFlags == nil {
+		panic("Descriptor.GetFlags: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.get-flags")
+	}
+	*result = Fake.Descriptor.GetFlags(self)
+	return
+}
+
+func wasmimport_DescriptorGetType(self0 uint32, result *cm.Result[DescriptorType, DescriptorType, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.GetType == nil {
+		panic("Descriptor.GetType: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.get-type")
+	}
+	*result = Fake.Descriptor.GetType(self)
+	return
+}
+
+func wasmimport_DescriptorIsSameObject(self0 uint32, other0 uint32) (result0 uint32) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	other := cm.Reinterpret[Descriptor]((uint32)(other0))
+	if Fake.Descriptor.IsSameObject == nil {
+		panic("Descriptor.IsSameObject: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.is-same-object")
+	}
+	result := Fake.Descriptor.IsSameObject(self, other)
+	result0 = (uint32)(cm.BoolToU32(result))
+	return
+}
+
+func wasmimport_DescriptorLinkAt(self0 uint32, oldPathFlags0 uint32, oldPath0 *uint8, oldPath1 uint32, newDescriptor0 uint32, newPath0 *uint8, newPath1 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	oldPathFlags := (PathFlags)((uint32)(oldPathFlags0))
+	oldPath := cm.LiftString[string]((*uint8)(oldPath0), (uint32)(oldPath1))
+	newDescriptor := cm.Reinterpret[Descriptor]((uint32)(newDescriptor0))
+	newPath := cm.LiftString[string]((*uint8)(newPath0), (uint32)(newPath1))
+	if Fake.Descriptor.LinkAt == nil {
+		panic("Descriptor.LinkAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.link-at")
+	}
+	*result = Fake.Descriptor.LinkAt(self, oldPathFlags, oldPath, newDescriptor, newPath)
+	return
+}
+
+func wasmimport_DescriptorMetadataHash(self0 uint32, result *cm.Result[MetadataHashValueShape, MetadataHashValue, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.MetadataHash == nil {
+		panic("Descriptor.MetadataHash: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.metadata-hash")
+	}
+	*result = Fake.Descriptor.MetadataHash(self)
+	return
+}
+
+func wasmimport_DescriptorMetadataHashAt(self0 uint32, pathFlags0 uint32, path0 *uint8, path1 uint32, result *cm.Result[MetadataHashValueShape, MetadataHashValue, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	pathFlags := (PathFlags)((uint32)(pathFlags0))
+	path := cm.LiftString[string]((*uint8)(path0), (uint32)(path1))
+	if Fake.Descriptor.MetadataHashAt == nil {
+		panic("Descriptor.MetadataHashAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.metadata-hash-at")
+	}
+	*result = Fake.Descriptor.MetadataHashAt(self, pathFlags, path)
+	return
+}
+
+func wasmimport_DescriptorOpenAt(self0 uint32, pathFlags0 uint32, path0 *uint8, path1 uint32, openFlags0 uint32, flags0 uint32, result *cm.Result[Descriptor, Descriptor, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	pathFlags := (PathFlags)((uint32)(pathFlags0))
+	path := cm.LiftString[string]((*uint8)(path0), (uint32)(path1))
+	openFlags := (OpenFlags)((uint32)(openFlags0))
+	flags := (DescriptorFlags)((uint32)(flags0))
+	if Fake.Descriptor.OpenAt == nil {
+		panic("Descriptor.OpenAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.open-at")
+	}
+	*result = Fake.Descriptor.OpenAt(self, pathFlags, path, openFlags, flags)
+	return
+}
+
+func wasmimport_DescriptorRead(self0 uint32, length0 uint64, offset0 uint64, result *cm.Result[abi_ResultTupleListU8BoolErrorCodeShape, cm.Tuple[cm.List[uint8], bool], ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	length := (FileSize)((uint64)(length0))
+	offset := (FileSize)((uint64)(offset0))
+	if Fake.Descriptor.Read == nil {
+		panic("Descriptor.Read: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.read")
+	}
+	*result = toABI_ResultTupleListU8BoolErrorCode(Fake.Descriptor.Read(self, length, offset))
+	return
+}
+
+func wasmimport_DescriptorReadDirectory(self0 uint32, result *cm.Result[DirectoryEntryStream, DirectoryEntryStream, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.ReadDirectory == nil {
+		panic("Descriptor.ReadDirectory: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.read-directory")
+	}
+	*result = Fake.Descriptor.ReadDirectory(self)
+	return
+}
+
+func wasmimport_DescriptorReadViaStream(self0 uint32, offset0 uint64, result *cm.Result[InputStream, InputStream, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	offset := (FileSize)((uint64)(offset0))
+	if Fake.Descriptor.ReadViaStream == nil {
+		panic("Descriptor.ReadViaStream: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.read-via-stream")
+	}
+	*result = Fake.Descriptor.ReadViaStream(self, offset)
+	return
+}
+
+func wasmimport_DescriptorReadLinkAt(self0 uint32, path0 *uint8, path1 uint32, result *cm.Result[abi_ResultStringErrorCodeShape, string, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	path := cm.LiftString[string]((*uint8)(path0), (uint32)(path1))
+	if Fake.Descriptor.ReadLinkAt == nil {
+		panic("Descriptor.ReadLinkAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.readlink-at")
+	}
+	*result = toABI_ResultStringErrorCode(Fake.Descriptor.ReadLinkAt(self, path))
+	return
+}
+
+func wasmimport_DescriptorRemoveDirectoryAt(self0 uint32, path0 *uint8, path1 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	path := cm.LiftString[string]((*uint8)(path0), (uint32)(path1))
+	if Fake.Descriptor.RemoveDirectoryAt == nil {
+		panic("Descriptor.RemoveDirectoryAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.remove-directory-at")
+	}
+	*result = Fake.Descriptor.RemoveDirectoryAt(self, path)
+	return
+}
+
+func wasmimport_DescriptorRenameAt(self0 uint32, oldPath0 *uint8, oldPath1 uint32, newDescriptor0 uint32, newPath0 *uint8, newPath1 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	oldPath := cm.LiftString[string]((*uint8)(oldPath0), (uint32)(oldPath1))
+	newDescriptor := cm.Reinterpret[Descriptor]((uint32)(newDescriptor0))
+	newPath := cm.LiftString[string]((*uint8)(newPath0), (uint32)(newPath1))
+	if Fake.Descriptor.RenameAt == nil {
+		panic("Descriptor.RenameAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.rename-at")
+	}
+	*result = Fake.Descriptor.RenameAt(self, oldPath, newDescriptor, newPath)
+	return
+}
+
+func wasmimport_DescriptorSetSize(self0 uint32, size0 uint64, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	size := (FileSize)((uint64)(size0))
+	if Fake.Descriptor.SetSize == nil {
+		panic("Descriptor.SetSize: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.set-size")
+	}
+	*result = Fake.Descriptor.SetSize(self, size)
+	return
+}
+
+func wasmimport_DescriptorSetTimes(self0 uint32, dataAccessTimestamp0 uint32, dataAccessTimestamp1 uint64, dataAccessTimestamp2 uint32, dataModificationTimestamp0 uint32, dataModificationTimestamp1 uint64, dataModificationTimestamp2 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	dataAccessTimestamp := lift_NewTimestamp((uint32)(dataAccessTimestamp0), (uint64)(dataAccessTimestamp1), (uint32)(dataAccessTimestamp2))
+	dataModificationTimestamp := lift_NewTimestamp((uint32)(dataModificationTimestamp0), (uint64)(dataModificationTimestamp1), (uint32)(dataModificationTimestamp2))
+	if Fake.Descriptor.SetTimes == nil {
+		panic("Descriptor.SetTimes: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.set-times")
+	}
+	*result = Fake.Descriptor.SetTimes(self, dataAccessTimestamp, dataModificationTimestamp)
+	return
+}
+
+func wasmimport_DescriptorSetTimesAt(self0 uint32, pathFlags0 uint32, path0 *uint8, path1 uint32, dataAccessTimestamp0 uint32, dataAccessTimestamp1 uint64, dataAccessTimestamp2 uint32, dataModificationTimestamp0 uint32, dataModificationTimestamp1 uint64, dataModificationTimestamp2 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	pathFlags := (PathFlags)((uint32)(pathFlags0))
+	path := cm.LiftString[string]((*uint8)(path0), (uint32)(path1))
+	dataAccessTimestamp := lift_NewTimestamp((uint32)(dataAccessTimestamp0), (uint64)(dataAccessTimestamp1), (uint32)(dataAccessTimestamp2))
+	dataModificationTimestamp := lift_NewTimestamp((uint32)(dataModificationTimestamp0), (uint64)(dataModificationTimestamp1), (uint32)(dataModificationTimestamp2))
+	if Fake.Descriptor.SetTimesAt == nil {
+		panic("Descriptor.SetTimesAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.set-times-at")
+	}
+	*result = Fake.Descriptor.SetTimesAt(self, pathFlags, path, dataAccessTimestamp, dataModificationTimestamp)
+	return
+}
+
+func wasmimport_DescriptorStat(self0 uint32, result *cm.Result[DescriptorStatShape, DescriptorStat, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.Stat == nil {
+		panic("Descriptor.Stat: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.stat")
+	}
+	*result = Fake.Descriptor.Stat(self)
+	return
+}
+
+func wasmimport_DescriptorStatAt(self0 uint32, pathFlags0 uint32, path0 *uint8, path1 uint32, result *cm.Result[DescriptorStatShape, DescriptorStat, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	pathFlags := (PathFlags)((uint32)(pathFlags0))
+	path := cm.LiftString[string]((*uint8)(path0), (uint32)(path1))
+	if Fake.Descriptor.StatAt == nil {
+		panic("Descriptor.StatAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.stat-at")
+	}
+	*result = Fake.Descriptor.StatAt(self, pathFlags, path)
+	return
+}
+
+func wasmimport_DescriptorSymlinkAt(self0 uint32, oldPath0 *uint8, oldPath1 uint32, newPath0 *uint8, newPath1 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	oldPath := cm.LiftString[string]((*uint8)(oldPath0), (uint32)(oldPath1))
+	newPath := cm.LiftString[string]((*uint8)(newPath0), (uint32)(newPath1))
+	if Fake.Descriptor.SymlinkAt == nil {
+		panic("Descriptor.SymlinkAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.symlink-at")
+	}
+	*result = Fake.Descriptor.SymlinkAt(self, oldPath, newPath)
+	return
+}
+
+func wasmimport_DescriptorSync(self0 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.Sync == nil {
+		panic("Descriptor.Sync: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.sync")
+	}
+	*result = Fake.Descriptor.Sync(self)
+	return
+}
+
+func wasmimport_DescriptorSyncData(self0 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	if Fake.Descriptor.SyncData == nil {
+		panic("Descriptor.SyncData: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.sync-data")
+	}
+	*result = Fake.Descriptor.SyncData(self)
+	return
+}
+
+func wasmimport_DescriptorUnlinkFileAt(self0 uint32, path0 *uint8, path1 uint32, result *cm.Result[ErrorCode, struct{}, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	path := cm.LiftString[string]((*uint8)(path0), (uint32)(path1))
+	if Fake.Descriptor.UnlinkFileAt == nil {
+		panic("Descriptor.UnlinkFileAt: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.unlink-file-at")
+	}
+	*result = Fake.Descriptor.UnlinkFileAt(self, path)
+	return
+}
+
+func wasmimport_DescriptorWrite(self0 uint32, buffer0 *uint8, buffer1 uint32, offset0 uint64, result *cm.Result[uint64, FileSize, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	buffer := cm.LiftList[cm.List[uint8]]((*uint8)(buffer0), (uint32)(buffer1))
+	offset := (FileSize)((uint64)(offset0))
+	if Fake.Descriptor.Write == nil {
+		panic("Descriptor.Write: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.write")
+	}
+	*result = Fake.Descriptor.Write(self, buffer, offset)
+	return
+}
+
+func wasmimport_DescriptorWriteViaStream(self0 uint32, offset0 uint64, result *cm.Result[OutputStream, OutputStream, ErrorCode]) {
+	self := cm.Reinterpret[Descriptor]((uint32)(self0))
+	offset := (FileSize)((uint64)(offset0))
+	if Fake.Descriptor.WriteViaStream == nil {
+		panic("Descriptor.WriteViaStream: no fake for wasi:filesystem/types@0.2.0 [method]descriptor.write-via-stream")
+	}
+	*result = Fake.Descriptor.WriteViaStream(self, offset)
+	return
+}
+
+func init() {
+	Fake.Descriptor.ResourceDrop = func(self Descriptor) {
+		Fake.Descriptor.Handles.Drop(self)
+	}
+}
+
+func wasmimport_DirectoryEntryStreamResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[DirectoryEntryStream]((uint32)(self0))
+	if Fake.DirectoryEntryStream.ResourceDrop == nil {
+		panic("DirectoryEntryStream.ResourceDrop: no fake for wasi:filesystem/types@0.2.0 [resource-drop]directory-entry-stream")
+	}
+	Fake.DirectoryEntryStream.ResourceDrop(self)
+	return
+}
+
+func wasmimport_DirectoryEntryStreamReadDirectoryEntry(self0 uint32, result *cm.Result[cm.Option[DirectoryEntry], cm.Option[DirectoryEntry], ErrorCode]) {
+	self := cm.Reinterpret[DirectoryEntryStream]((uint32)(self0))
+	if Fake.DirectoryEntryStream.ReadDirectoryEntry == nil {
+		panic("DirectoryEntryStream.ReadDirectoryEntry: no fake for wasi:filesystem/types@0.2.0 [method]directory-entry-stream.read-directory-entry")
+	}
+	*result = Fake.DirectoryEntryStream.ReadDirectoryEntry(self)
+	return
+}
+
+func init() {
+	Fake.DirectoryEntryStream.ResourceDrop = func(self DirectoryEntryStream) {
+		Fake.DirectoryEntryStream.Handles.Drop(self)
+	}
+}
+
+func wasmimport_FilesystemErrorCode(err0 uint32, result *cm.Option[ErrorCode]) {
+	err := cm.Reinterpret[Error]((uint32)(err0))
+	if Fake.FilesystemErrorCode == nil {
+		panic("FilesystemErrorCode: no fake for wasi:filesystem/types@0.2.0 filesystem-error-code")
+	}
+	*result = Fake.FilesystemErrorCode(err)
+	return
+}
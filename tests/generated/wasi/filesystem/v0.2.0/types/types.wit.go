@@ -29,6 +29,7 @@
 package types
 
 import (
+	"encoding/json"
 	"go.bytecodealliance.org/cm"
 	wallclock "tests/generated/wasi/clocks/v0.2.0/wall-clock"
 	"tests/generated/wasi/io/v0.2.0/streams"
@@ -196,6 +197,27 @@ const (
 	DescriptorFlagsMutateDirectory
 )
 
+var _DescriptorFlagsStrings = [6]string{
+	"read",
+	"write",
+	"file-integrity-sync",
+	"data-integrity-sync",
+	"requested-write-sync",
+	"mutate-directory",
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the names of
+// the flags set in f.
+func (f DescriptorFlags) MarshalJSON() ([]byte, error) {
+	return cm.MarshalFlagsJSON(_DescriptorFlagsStrings[:], f)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array of flag
+// names.
+func (f *DescriptorFlags) UnmarshalJSON(data []byte) error {
+	return cm.UnmarshalFlagsJSON(_DescriptorFlagsStrings[:], f, data)
+}
+
 // PathFlags represents the flags "wasi:filesystem/types@0.2.0#path-flags".
 //
 // Flags determining the method of how paths are resolved.
@@ -211,6 +233,22 @@ const (
 	PathFlagsSymlinkFollow PathFlags = 1 << iota
 )
 
+var _PathFlagsStrings = [1]string{
+	"symlink-follow",
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the names of
+// the flags set in f.
+func (f PathFlags) MarshalJSON() ([]byte, error) {
+	return cm.MarshalFlagsJSON(_PathFlagsStrings[:], f)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array of flag
+// names.
+func (f *PathFlags) UnmarshalJSON(data []byte) error {
+	return cm.UnmarshalFlagsJSON(_PathFlagsStrings[:], f, data)
+}
+
 // OpenFlags represents the flags "wasi:filesystem/types@0.2.0#open-flags".
 //
 // Open flags used by `open-at`.
@@ -237,6 +275,25 @@ const (
 	OpenFlagsTruncate
 )
 
+var _OpenFlagsStrings = [4]string{
+	"create",
+	"directory",
+	"exclusive",
+	"truncate",
+}
+
+// MarshalJSON implements [json.Marshaler], returning a JSON array of the names of
+// the flags set in f.
+func (f OpenFlags) MarshalJSON() ([]byte, error) {
+	return cm.MarshalFlagsJSON(_OpenFlagsStrings[:], f)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array of flag
+// names.
+func (f *OpenFlags) UnmarshalJSON(data []byte) error {
+	return cm.UnmarshalFlagsJSON(_OpenFlagsStrings[:], f, data)
+}
+
 // LinkCount represents the u64 "wasi:filesystem/types@0.2.0#link-count".
 //
 // Number of hard links to an inode.
@@ -350,6 +407,37 @@ func (v NewTimestamp) String() string {
 	return _NewTimestampStrings[v.Tag()]
 }
 
+// MarshalJSON implements [json.Marshaler], returning a JSON object with a single
+// key for the variant case name.
+func (v NewTimestamp) MarshalJSON() ([]byte, error) {
+	var data any
+	switch v.Tag() {
+	case 2:
+		data = v.Timestamp()
+	}
+	return cm.MarshalVariantJSON(_NewTimestampStrings[:], v.Tag(), data)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON object with a
+// single key for the variant case name.
+func (v *NewTimestamp) UnmarshalJSON(data []byte) error {
+	tag, value, err := cm.UnmarshalVariantJSON(_NewTimestampStrings[:], data)
+	if err != nil {
+		return err
+	}
+	switch tag {
+	case 0:
+		*v = NewTimestampNoChange()
+	case 1:
+		*v = NewTimestampNow()
+	case 2:
+		var payload DateTime
+		err = json.Unmarshal(value, &payload)
+		*v = NewTimestampTimestamp(payload)
+	}
+	return err
+}
+
 // DirectoryEntry represents the record "wasi:filesystem/types@0.2.0#directory-entry".
 //
 // A directory entry.
@@ -927,11 +1015,13 @@ func (self Descriptor) OpenAt(pathFlags PathFlags, path string, openFlags OpenFl
 //	error-code>
 //
 //go:nosplit
-func (self Descriptor) Read(length FileSize, offset FileSize) (result cm.Result[TupleListU8BoolShape, cm.Tuple[cm.List[uint8], bool], ErrorCode]) {
+func (self Descriptor) Read(length FileSize, offset FileSize) (result cm.Result[cm.SplitResult[cm.Tuple[cm.List[uint8], bool], ErrorCode], cm.Tuple[cm.List[uint8], bool], ErrorCode]) {
 	self0 := cm.Reinterpret[uint32](self)
 	length0 := (uint64)(length)
 	offset0 := (uint64)(offset)
-	wasmimport_DescriptorRead((uint32)(self0), (uint64)(length0), (uint64)(offset0), &result)
+	var result_ cm.Result[abi_ResultTupleListU8BoolErrorCodeShape, cm.Tuple[cm.List[uint8], bool], ErrorCode]
+	wasmimport_DescriptorRead((uint32)(self0), (uint64)(length0), (uint64)(offset0), &result_)
+	result = fromABI_ResultTupleListU8BoolErrorCode(result_)
 	return
 }
 
@@ -989,10 +1079,12 @@ func (self Descriptor) ReadViaStream(offset FileSize) (result cm.Result[InputStr
 //	readlink-at: func(path: string) -> result<string, error-code>
 //
 //go:nosplit
-func (self Descriptor) ReadLinkAt(path string) (result cm.Result[string, string, ErrorCode]) {
+func (self Descriptor) ReadLinkAt(path string) (result cm.Result[cm.SplitResult[string, ErrorCode], string, ErrorCode]) {
 	self0 := cm.Reinterpret[uint32](self)
 	path0, path1 := cm.LowerString(path)
-	wasmimport_DescriptorReadLinkAt((uint32)(self0), (*uint8)(path0), (uint32)(path1), &result)
+	var result_ cm.Result[abi_ResultStringErrorCodeShape, string, ErrorCode]
+	wasmimport_DescriptorReadLinkAt((uint32)(self0), (*uint8)(path0), (uint32)(path1), &result_)
+	result = fromABI_ResultStringErrorCode(result_)
 	return
 }
 
@@ -1276,7 +1368,7 @@ func (self DirectoryEntryStream) ResourceDrop() {
 //	read-directory-entry: func() -> result<option<directory-entry>, error-code>
 //
 //go:nosplit
-func (self DirectoryEntryStream) ReadDirectoryEntry() (result cm.Result[OptionDirectoryEntryShape, cm.Option[DirectoryEntry], ErrorCode]) {
+func (self DirectoryEntryStream) ReadDirectoryEntry() (result cm.Result[cm.Option[DirectoryEntry], cm.Option[DirectoryEntry], ErrorCode]) {
 	self0 := cm.Reinterpret[uint32](self)
 	wasmimport_DirectoryEntryStreamReadDirectoryEntry((uint32)(self0), &result)
 	return
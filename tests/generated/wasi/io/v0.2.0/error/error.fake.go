@@ -0,0 +1,63 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package ioerror
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:io/error@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// Error contains the hooks for imported resource "wasi:io/error@0.2.0#error".
+	Error struct {
+		// Handles is an in-memory table of fake handles for resource "error".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[Error, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "error".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self Error)
+
+		// ToDebugString represents the imported method "to-debug-string".
+		//
+		// Returns a string that is suitable to assist humans in debugging
+		// this error.
+		//
+		// WARNING: The returned string should not be consumed mechanically!
+		// It may change across platforms, hosts, or other implementation
+		// details. Parsing this string is a major platform-compatibility
+		// hazard.
+		//
+		//	to-debug-string: func() -> string
+		ToDebugString func(self Error) (result string)
+	}
+}
+
+func wasmimport_ErrorResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[Error]((uint32)(self0))
+	if Fake.Error.ResourceDrop == nil {
+		panic("Error.ResourceDrop: no fake for wasi:io/error@0.2.0 [resource-drop]error")
+	}
+	Fake.Error.ResourceDrop(self)
+	return
+}
+
+func wasmimport_ErrorToDebugString(self0 uint32, result *string) {
+	self := cm.Reinterpret[Error]((uint32)(self0))
+	if Fake.Error.ToDebugString == nil {
+		panic("Error.ToDebugString: no fake for wasi:io/error@0.2.0 [method]error.to-debug-string")
+	}
+	*result = Fake.Error.ToDebugString(self)
+	return
+}
+
+func init() {
+	Fake.Error.ResourceDrop = func(self Error) {
+		Fake.Error.Handles.Drop(self)
+	}
+}
@@ -0,0 +1,113 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package poll
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:io/poll@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// Pollable contains the hooks for imported resource "wasi:io/poll@0.2.0#pollable".
+	Pollable struct {
+		// Handles is an in-memory table of fake handles for resource "pollable".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[Pollable, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "pollable".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self Pollable)
+
+		// Block represents the imported method "block".
+		//
+		// `block` returns immediately if the pollable is ready, and otherwise
+		// blocks until ready.
+		//
+		// This function is equivalent to calling `poll.poll` on a list
+		// containing only this pollable.
+		//
+		//	block: func()
+		Block func(self Pollable)
+
+		// Ready represents the imported method "ready".
+		//
+		// Return the readiness of a pollable. This function never blocks.
+		//
+		// Returns `true` when the pollable is ready, and `false` otherwise.
+		//
+		//	ready: func() -> bool
+		Ready func(self Pollable) (result bool)
+	}
+
+	// Poll represents the imported function "poll".
+	//
+	// Poll for completion on a set of pollables.
+	//
+	// This function takes a list of pollables, which identify I/O sources of
+	// interest, and waits until one or more of the events is ready for I/O.
+	//
+	// The result `list<u32>` contains one or more indices of handles in the
+	// argument list that is ready for I/O.
+	//
+	// If the list contains more elements than can be indexed with a `u32`
+	// value, this function traps.
+	//
+	// A timeout can be implemented by adding a pollable from the
+	// wasi-clocks API to the list.
+	//
+	// This function does not return a `result`; polling in itself does not
+	// do any I/O so it doesn't fail. If any of the I/O sources identified by
+	// the pollables has an error, it is indicated by marking the source as
+	// being reaedy for I/O.
+	//
+	//	poll: func(in: list<borrow<pollable>>) -> list<u32>
+	Poll func(in cm.List[Pollable]) (result cm.List[uint32])
+}
+
+func wasmimport_PollableResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[Pollable]((uint32)(self0))
+	if Fake.Pollable.ResourceDrop == nil {
+		panic("Pollable.ResourceDrop: no fake for wasi:io/poll@0.2.0 [resource-drop]pollable")
+	}
+	Fake.Pollable.ResourceDrop(self)
+	return
+}
+
+func wasmimport_PollableBlock(self0 uint32) {
+	self := cm.Reinterpret[Pollable]((uint32)(self0))
+	if Fake.Pollable.Block == nil {
+		panic("Pollable.Block: no fake for wasi:io/poll@0.2.0 [method]pollable.block")
+	}
+	Fake.Pollable.Block(self)
+	return
+}
+
+func wasmimport_PollableReady(self0 uint32) (result0 uint32) {
+	self := cm.Reinterpret[Pollable]((uint32)(self0))
+	if Fake.Pollable.Ready == nil {
+		panic("Pollable.Ready: no fake for wasi:io/poll@0.2.0 [method]pollable.ready")
+	}
+	result := Fake.Pollable.Ready(self)
+	result0 = (uint32)(cm.BoolToU32(result))
+	return
+}
+
+func init() {
+	Fake.Pollable.ResourceDrop = func(self Pollable) {
+		Fake.Pollable.Handles.Drop(self)
+	}
+}
+
+func wasmimport_Poll(in0 *Pollable, in1 uint32, result *cm.List[uint32]) {
+	in := cm.LiftList[cm.List[Pollable]]((*Pollable)(in0), (uint32)(in1))
+	if Fake.Poll == nil {
+		panic("Poll: no fake for wasi:io/poll@0.2.0 poll")
+	}
+	*result = Fake.Poll(in)
+	return
+}
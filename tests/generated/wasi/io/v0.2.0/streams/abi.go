@@ -0,0 +1,28 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+package streams
+
+import (
+	"go.bytecodealliance.org/cm"
+	"unsafe"
+)
+
+// abi_ResultListU8StreamErrorShape is used for storage in type result<list<u8>, stream-error> with the Canonical ABI memory layout.
+type abi_ResultListU8StreamErrorShape struct {
+	_     cm.HostLayout
+	shape [max(unsafe.Sizeof(*new(cm.List[uint8])), unsafe.Sizeof(*new(StreamError)))]byte
+}
+
+func fromABI_ResultListU8StreamError(v cm.Result[abi_ResultListU8StreamErrorShape, cm.List[uint8], StreamError]) (out cm.Result[cm.SplitResult[cm.List[uint8], StreamError], cm.List[uint8], StreamError]) {
+	if ok := v.OK(); ok != nil {
+		return cm.OK[cm.Result[cm.SplitResult[cm.List[uint8], StreamError], cm.List[uint8], StreamError]](*ok)
+	}
+	return cm.Err[cm.Result[cm.SplitResult[cm.List[uint8], StreamError], cm.List[uint8], StreamError]](*v.Err())
+}
+
+func toABI_ResultListU8StreamError(v cm.Result[cm.SplitResult[cm.List[uint8], StreamError], cm.List[uint8], StreamError]) (out cm.Result[abi_ResultListU8StreamErrorShape, cm.List[uint8], StreamError]) {
+	if ok := v.OK(); ok != nil {
+		return cm.OK[cm.Result[abi_ResultListU8StreamErrorShape, cm.List[uint8], StreamError]](*ok)
+	}
+	return cm.Err[cm.Result[abi_ResultListU8StreamErrorShape, cm.List[uint8], StreamError]](*v.Err())
+}
@@ -0,0 +1,461 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package streams
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:io/streams@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// InputStream contains the hooks for imported resource "wasi:io/streams@0.2.0#input-stream".
+	InputStream struct {
+		// Handles is an in-memory table of fake handles for resource "input-stream".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[InputStream, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "input-stream".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self InputStream)
+
+		// BlockingRead represents the imported method "blocking-read".
+		//
+		// Read bytes from a stream, after blocking until at least one byte can
+		// be read. Except for blocking, behavior is identical to `read`.
+		//
+		//	blocking-read: func(len: u64) -> result<list<u8>, stream-error>
+		BlockingRead func(self InputStream, len_ uint64) (result cm.Result[cm.SplitResult[cm.List[uint8], StreamError], cm.List[uint8], StreamError])
+
+		// BlockingSkip represents the imported method "blocking-skip".
+		//
+		// Skip bytes from a stream, after blocking until at least one byte
+		// can be skipped. Except for blocking behavior, identical to `skip`.
+		//
+		//	blocking-skip: func(len: u64) -> result<u64, stream-error>
+		BlockingSkip func(self InputStream, len_ uint64) (result cm.Result[uint64, uint64, StreamError])
+
+		// Read represents the imported method "read".
+		//
+		// Perform a non-blocking read from the stream.
+		//
+		// When the source of a `read` is binary data, the bytes from the source
+		// are returned verbatim. When the source of a `read` is known to the
+		// implementation to be text, bytes containing the UTF-8 encoding of the
+		// text are returned.
+		//
+		// This function returns a list of bytes containing the read data,
+		// when successful. The returned list will contain up to `len` bytes;
+		// it may return fewer than requested, but not more. The list is
+		// empty when no bytes are available for reading at this time. The
+		// pollable given by `subscribe` will be ready when more bytes are
+		// available.
+		//
+		// This function fails with a `stream-error` when the operation
+		// encounters an error, giving `last-operation-failed`, or when the
+		// stream is closed, giving `closed`.
+		//
+		// When the caller gives a `len` of 0, it represents a request to
+		// read 0 bytes. If the stream is still open, this call should
+		// succeed and return an empty list, or otherwise fail with `closed`.
+		//
+		// The `len` parameter is a `u64`, which could represent a list of u8 which
+		// is not possible to allocate in wasm32, or not desirable to allocate as
+		// as a return value by the callee. The callee may return a list of bytes
+		// less than `len` in size while more bytes are available for reading.
+		//
+		//	read: func(len: u64) -> result<list<u8>, stream-error>
+		Read func(self InputStream, len_ uint64) (result cm.Result[cm.SplitResult[cm.List[uint8], StreamError], cm.List[uint8], StreamError])
+
+		// Skip represents the imported method "skip".
+		//
+		// Skip bytes from a stream. Returns number of bytes skipped.
+		//
+		// Behaves identical to `read`, except instead of returning a list
+		// of bytes, returns the number of bytes consumed from the stream.
+		//
+		//	skip: func(len: u64) -> result<u64, stream-error>
+		Skip func(self InputStream, len_ uint64) (result cm.Result[uint64, uint64, StreamError])
+
+		// Subscribe represents the imported method "subscribe".
+		//
+		// Create a `pollable` which will resolve once either the specified stream
+		// has bytes available to read or the other end of the stream has been
+		// closed.
+		// The created `pollable` is a child resource of the `input-stream`.
+		// Implementations may trap if the `input-stream` is dropped before
+		// all derived `pollable`s created with this function are dropped.
+		//
+		//	subscribe: func() -> pollable
+		Subscribe func(self InputStream) (result Pollable)
+	}
+
+	// OutputStream contains the hooks for imported resource "wasi:io/streams@0.2.0#output-stream".
+	OutputStream struct {
+		// Handles is an in-memory table of fake handles for resource "output-stream".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[OutputStream, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "output-stream".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self OutputStream)
+
+		// BlockingFlush represents the imported method "blocking-flush".
+		//
+		// Request to flush buffered output, and block until flush completes
+		// and stream is ready for writing again.
+		//
+		//	blocking-flush: func() -> result<_, stream-error>
+		BlockingFlush func(self OutputStream) (result cm.Result[StreamError, struct{}, StreamError])
+
+		// BlockingSplice represents the imported method "blocking-splice".
+		//
+		// Read from one stream and write to another, with blocking.
+		//
+		// This is similar to `splice`, except that it blocks until the
+		// `output-stream` is ready for writing, and the `input-stream`
+		// is ready for reading, before performing the `splice`.
+		//
+		//	blocking-splice: func(src: borrow<input-stream>, len: u64) -> result<u64, stream-error>
+		BlockingSplice func(self OutputStream, src InputStream, len_ uint64) (result cm.Result[uint64, uint64, StreamError])
+
+		// BlockingWriteAndFlush represents the imported method "blocking-write-and-flush".
+		//
+		// Perform a write of up to 4096 bytes, and then flush the stream. Block
+		// until all of these operations are complete, or an error occurs.
+		//
+		// This is a convenience wrapper around the use of `check-write`,
+		// `subscribe`, `write`, and `flush`, and is implemented with the
+		// following pseudo-code:
+		//
+		//
+		// 	let pollable = this.subscribe();
+		// 	while !contents.is_empty() {
+		// 	// Wait for the stream to become writable
+		// 	pollable.block();
+		// 	let Ok(n) = this.check-write(); // eliding error handling
+		// 	let len = min(n, contents.len());
+		// 	let (chunk, rest) = contents.split_at(len);
+		// 	this.write(chunk  );            // eliding error handling
+		// 	contents = rest;
+		// 	}
+		// 	this.flush();
+		// 	// Wait for completion of `flush`
+		// 	pollable.block();
+		// 	// Check for any errors that arose during `flush`
+		// 	let _ = this.check-write();         // eliding error handling
+		//
+		//	blocking-write-and-flush: func(contents: list<u8>) -> result<_, stream-error>
+		BlockingWriteAndFlush func(self OutputStream, contents cm.List[uint8]) (result cm.Result[StreamError, struct{}, StreamError])
+
+		// BlockingWriteZeroesAndFlush represents the imported method "blocking-write-zeroes-and-flush".
+		//
+		// Perform a write of up to 4096 zeroes, and then flush the stream.
+		// Block until all of these operations are complete, or an error
+		// occurs.
+		//
+		// This is a convenience wrapper around the use of `check-write`,
+		// `subscribe`, `write-zeroes`, and `flush`, and is implemented with
+		// the following pseudo-code:
+		//
+		//
+		// 	let pollable = this.subscribe();
+		// 	while num_zeroes != 0 {
+		// 	// Wait for the stream to become writable
+		// 	pollable.block();
+		// 	let Ok(n) = this.check-write(); // eliding error handling
+		// 	let len = min(n, num_zeroes);
+		// 	this.write-zeroes(len);         // eliding error handling
+		// 	num_zeroes -= len;
+		// 	}
+		// 	this.flush();
+		// 	// Wait for completion of `flush`
+		// 	pollable.block();
+		// 	// Check for any errors that arose during `flush`
+		// 	let _ = this.check-write();         // eliding error handling
+		//
+		//	blocking-write-zeroes-and-flush: func(len: u64) -> result<_, stream-error>
+		BlockingWriteZeroesAndFlush func(self OutputStream, len_ uint64) (result cm.Result[StreamError, struct{}, StreamError])
+
+		// CheckWrite represents the imported method "check-write".
+		//
+		// Check readiness for writing. This function never blocks.
+		//
+		// Returns the number of bytes permitted for the next call to `write`,
+		// or an error. Calling `write` with more bytes than this function has
+		// permitted will trap.
+		//
+		// When this function returns 0 bytes, the `subscribe` pollable will
+		// become ready when this function will report at least 1 byte, or an
+		// error.
+		//
+		//	check-write: func() -> result<u64, stream-error>
+		CheckWrite func(self OutputStream) (result cm.Result[uint64, uint64, StreamError])
+
+		// Flush represents the imported method "flush".
+		//
+		// Request to flush buffered output. This function never blocks.
+		//
+		// This tells the output-stream that the caller intends any buffered
+		// output to be flushed. the output which is expected to be flushed
+		// is all that has been passed to `write` prior to this call.
+		//
+		// Upon calling this function, the `output-stream` will not accept any
+		// writes (`check-write` will return `ok(0)`) until the flush has
+		// completed. The `subscribe` pollable will become ready when the
+		// flush has completed and the stream can accept more writes.
+		//
+		//	flush: func() -> result<_, stream-error>
+		Flush func(self OutputStream) (result cm.Result[StreamError, struct{}, StreamError])
+
+		// Splice represents the imported method "splice".
+		//
+		// Read from one stream and write to another.
+		//
+		// The behavior of splice is equivelant to:
+		// 1. calling `check-write` on the `output-stream`
+		// 2. calling `read` on the `input-stream` with the smaller of the
+		// `check-write` permitted length and the `len` provided to `splice`
+		// 3. calling `write` on the `output-stream` with that read data.
+		//
+		// Any error reported by the call to `check-write`, `read`, or
+		// `write` ends the splice and reports that error.
+		//
+		// This function returns the number of bytes transferred; it may be less
+		// than `len`.
+		//
+		//	splice: func(src: borrow<input-stream>, len: u64) -> result<u64, stream-error>
+		Splice func(self OutputStream, src InputStream, len_ uint64) (result cm.Result[uint64, uint64, StreamError])
+
+		// Subscribe represents the imported method "subscribe".
+		//
+		// Create a `pollable` which will resolve once the output-stream
+		// is ready for more writing, or an error has occured. When this
+		// pollable is ready, `check-write` will return `ok(n)` with n>0, or an
+		// error.
+		//
+		// If the stream is closed, this pollable is always ready immediately.
+		//
+		// The created `pollable` is a child resource of the `output-stream`.
+		// Implementations may trap if the `output-stream` is dropped before
+		// all derived `pollable`s created with this function are dropped.
+		//
+		//	subscribe: func() -> pollable
+		Subscribe func(self OutputStream) (result Pollable)
+
+		// Write represents the imported method "write".
+		//
+		// Perform a write. This function never blocks.
+		//
+		// When the destination of a `write` is binary data, the bytes from
+		// `contents` are written verbatim. When the destination of a `write` is
+		// known to the implementation to be text, the bytes of `contents` are
+		// transcoded from UTF-8 into the encoding of the destination and then
+		// written.
+		//
+		// Precondition: check-write gave permit of Ok(n) and contents has a
+		// length of less than or equal to n. Otherwise, this function will trap.
+		//
+		// returns Err(closed) without writing if the stream has closed since
+		// the last call to check-write provided a permit.
+		//
+		//	write: func(contents: list<u8>) -> result<_, stream-error>
+		Write func(self OutputStream, contents cm.List[uint8]) (result cm.Result[StreamError, struct{}, StreamError])
+
+		// WriteZeroes represents the imported method "write-zeroes".
+		//
+		// Write zeroes to a stream.
+		//
+		// This should be used precisely like `write` with the exact same
+		// preconditions (must use check-write first), but instead of
+		// passing a list of bytes, you simply pass the number of zero-bytes
+		// that should be written.
+		//
+		//	write-zeroes: func(len: u64) -> result<_, stream-error>
+		WriteZeroes func(self OutputStream, len_ uint64) (result cm.Result[StreamError, struct{}, StreamError])
+	}
+}
+
+func wasmimport_InputStreamResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[InputStream]((uint32)(self0))
+	if Fake.InputStream.ResourceDrop == nil {
+		panic("InputStream.ResourceDrop: no fake for wasi:io/streams@0.2.0 [resource-drop]input-stream")
+	}
+	Fake.InputStream.ResourceDrop(self)
+	return
+}
+
+func wasmimport_InputStreamBlockingRead(self0 uint32, len0 uint64, result *cm.Result[abi_ResultListU8StreamErrorShape, cm.List[uint8], StreamError]) {
+	self := cm.Reinterpret[InputStream]((uint32)(self0))
+	len_ := (uint64)((uint64)(len0))
+	if Fake.InputStream.BlockingRead == nil {
+		panic("InputStream.BlockingRead: no fake for wasi:io/streams@0.2.0 [method]input-stream.blocking-read")
+	}
+	*result = toABI_ResultListU8StreamError(Fake.InputStream.BlockingRead(self, len_))
+	return
+}
+
+func wasmimport_InputStreamBlockingSkip(self0 uint32, len0 uint64, result *cm.Result[uint64, uint64, StreamError]) {
+	self := cm.Reinterpret[InputStream]((uint32)(self0))
+	len_ := (uint64)((uint64)(len0))
+	if Fake.InputStream.BlockingSkip == nil {
+		panic("InputStream.BlockingSkip: no fake for wasi:io/streams@0.2.0 [method]input-stream.blocking-skip")
+	}
+	*result = Fake.InputStream.BlockingSkip(self, len_)
+	return
+}
+
+func wasmimport_InputStreamRead(self0 uint32, len0 uint64, result *cm.Result[abi_ResultListU8StreamErrorShape, cm.List[uint8], StreamError]) {
+	self := cm.Reinterpret[InputStream]((uint32)(self0))
+	len_ := (uint64)((uint64)(len0))
+	if Fake.InputStream.Read == nil {
+		panic("InputStream.Read: no fake for wasi:io/streams@0.2.0 [method]input-stream.read")
+	}
+	*result = toABI_ResultListU8StreamError(Fake.InputStream.Read(self, len_))
+	return
+}
+
+func wasmimport_InputStreamSkip(self0 uint32, len0 uint64, result *cm.Result[uint64, uint64, StreamError]) {
+	self := cm.Reinterpret[InputStream]((uint32)(self0))
+	len_ := (uint64)((uint64)(len0))
+	if Fake.InputStream.Skip == nil {
+		panic("InputStream.Skip: no fake for wasi:io/streams@0.2.0 [method]input-stream.skip")
+	}
+	*result = Fake.InputStream.Skip(self, len_)
+	return
+}
+
+func wasmimport_InputStreamSubscribe(self0 uint32) (result0 uint32) {
+	self := cm.Reinterpret[InputStream]((uint32)(self0))
+	if Fake.InputStream.Subscribe == nil {
+		panic("InputStream.Subscribe: no fake for wasi:io/streams@0.2.0 [method]input-stream.subscribe")
+	}
+	result := Fake.InputStream.Subscribe(self)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
+
+func init() {
+	Fake.InputStream.ResourceDrop = func(self InputStream) {
+		Fake.InputStream.Handles.Drop(self)
+	}
+}
+
+func wasmimport_OutputStreamResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	if Fake.OutputStream.ResourceDrop == nil {
+		panic("OutputStream.ResourceDrop: no fake for wasi:io/streams@0.2.0 [resource-drop]output-stream")
+	}
+	Fake.OutputStream.ResourceDrop(self)
+	return
+}
+
+func wasmimport_OutputStreamBlockingFlush(self0 uint32, result *cm.Result[StreamError, struct{}, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	if Fake.OutputStream.BlockingFlush == nil {
+		panic("OutputStream.BlockingFlush: no fake for wasi:io/streams@0.2.0 [method]output-stream.blocking-flush")
+	}
+	*result = Fake.OutputStream.BlockingFlush(self)
+	return
+}
+
+func wasmimport_OutputStreamBlockingSplice(self0 uint32, src0 uint32, len0 uint64, result *cm.Result[uint64, uint64, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	src := cm.Reinterpret[InputStream]((uint32)(src0))
+	len_ := (uint64)((uint64)(len0))
+	if Fake.OutputStream.BlockingSplice == nil {
+		panic("OutputStream.BlockingSplice: no fake for wasi:io/streams@0.2.0 [method]output-stream.blocking-splice")
+	}
+	*result = Fake.OutputStream.BlockingSplice(self, src, len_)
+	return
+}
+
+func wasmimport_OutputStreamBlockingWriteAndFlush(self0 uint32, contents0 *uint8, contents1 uint32, result *cm.Result[StreamError, struct{}, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	contents := cm.LiftList[cm.List[uint8]]((*uint8)(contents0), (uint32)(contents1))
+	if Fake.OutputStream.BlockingWriteAndFlush == nil {
+		panic("OutputStream.BlockingWriteAndFlush: no fake for wasi:io/streams@0.2.0 [method]output-stream.blocking-write-and-flush")
+	}
+	*result = Fake.OutputStream.BlockingWriteAndFlush(self, contents)
+	return
+}
+
+func wasmimport_OutputStreamBlockingWriteZeroesAndFlush(self0 uint32, len0 uint64, result *cm.Result[StreamError, struct{}, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	len_ := (uint64)((uint64)(len0))
+	if Fake.OutputStream.BlockingWriteZeroesAndFlush == nil {
+		panic("OutputStream.BlockingWriteZeroesAndFlush: no fake for wasi:io/streams@0.2.0 [method]output-stream.blocking-write-zeroes-and-flush")
+	}
+	*result = Fake.OutputStream.BlockingWriteZeroesAndFlush(self, len_)
+	return
+}
+
+func wasmimport_OutputStreamCheckWrite(self0 uint32, result *cm.Result[uint64, uint64, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	if Fake.OutputStream.CheckWrite == nil {
+		panic("OutputStream.CheckWrite: no fake for wasi:io/streams@0.2.0 [method]output-stream.check-write")
+	}
+	*result = Fake.OutputStream.CheckWrite(self)
+	return
+}
+
+func wasmimport_OutputStreamFlush(self0 uint32, result *cm.Result[StreamError, struct{}, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	if Fake.OutputStream.Flush == nil {
+		panic("OutputStream.Flush: no fake for wasi:io/streams@0.2.0 [method]output-stream.flush")
+	}
+	*result = Fake.OutputStream.Flush(self)
+	return
+}
+
+func wasmimport_OutputStreamSplice(self0 uint32, src0 uint32, len0 uint64, result *cm.Result[uint64, uint64, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	src := cm.Reinterpret[InputStream]((uint32)(src0))
+	len_ := (uint64)((uint64)(len0))
+	if Fake.OutputStream.Splice == nil {
+		panic("OutputStream.Splice: no fake for wasi:io/streams@0.2.0 [method]output-stream.splice")
+	}
+	*result = Fake.OutputStream.Splice(self, src, len_)
+	return
+}
+
+func wasmimport_OutputStreamSubscribe(self0 uint32) (result0 uint32) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	if Fake.OutputStream.Subscribe == nil {
+		panic("OutputStream.Subscribe: no fake for wasi:io/streams@0.2.0 [method]output-stream.subscribe")
+	}
+	result := Fake.OutputStream.Subscribe(self)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
+
+func wasmimport_OutputStreamWrite(self0 uint32, contents0 *uint8, contents1 uint32, result *cm.Result[StreamError, struct{}, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	contents := cm.LiftList[cm.List[uint8]]((*uint8)(contents0), (uint32)(contents1))
+	if Fake.OutputStream.Write == nil {
+		panic("OutputStream.Write: no fake for wasi:io/streams@0.2.0 [method]output-stream.write")
+	}
+	*result = Fake.OutputStream.Write(self, contents)
+	return
+}
+
+func wasmimport_OutputStreamWriteZeroes(self0 uint32, len0 uint64, result *cm.Result[StreamError, struct{}, StreamError]) {
+	self := cm.Reinterpret[OutputStream]((uint32)(self0))
+	len_ := (uint64)((uint64)(len0))
+	if Fake.OutputStream.WriteZeroes == nil {
+		panic("OutputStream.WriteZeroes: no fake for wasi:io/streams@0.2.0 [method]output-stream.write-zeroes")
+	}
+	*result = Fake.OutputStream.WriteZeroes(self, len_)
+	return
+}
+
+func init() {
+	Fake.OutputStream.ResourceDrop = func(self OutputStream) {
+		Fake.OutputStream.Handles.Drop(self)
+	}
+}
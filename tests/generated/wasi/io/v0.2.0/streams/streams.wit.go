@@ -10,6 +10,7 @@
 package streams
 
 import (
+	"encoding/json"
 	"go.bytecodealliance.org/cm"
 	ioerror "tests/generated/wasi/io/v0.2.0/error"
 	"tests/generated/wasi/io/v0.2.0/poll"
@@ -74,6 +75,35 @@ func (v StreamError) String() string {
 	return _StreamErrorStrings[v.Tag()]
 }
 
+// MarshalJSON implements [json.Marshaler], returning a JSON object with a single
+// key for the variant case name.
+func (v StreamError) MarshalJSON() ([]byte, error) {
+	var data any
+	switch v.Tag() {
+	case 0:
+		data = v.LastOperationFailed()
+	}
+	return cm.MarshalVariantJSON(_StreamErrorStrings[:], v.Tag(), data)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON object with a
+// single key for the variant case name.
+func (v *StreamError) UnmarshalJSON(data []byte) error {
+	tag, value, err := cm.UnmarshalVariantJSON(_StreamErrorStrings[:], data)
+	if err != nil {
+		return err
+	}
+	switch tag {
+	case 0:
+		var payload Error
+		err = json.Unmarshal(value, &payload)
+		*v = StreamErrorLastOperationFailed(payload)
+	case 1:
+		*v = StreamErrorClosed()
+	}
+	return err
+}
+
 // InputStream represents the imported resource "wasi:io/streams@0.2.0#input-stream".
 //
 // An input bytestream.
@@ -107,10 +137,12 @@ func (self InputStream) ResourceDrop() {
 //	blocking-read: func(len: u64) -> result<list<u8>, stream-error>
 //
 //go:nosplit
-func (self InputStream) BlockingRead(len_ uint64) (result cm.Result[cm.List[uint8], cm.List[uint8], StreamError]) {
+func (self InputStream) BlockingRead(len_ uint64) (result cm.Result[cm.SplitResult[cm.List[uint8], StreamError], cm.List[uint8], StreamError]) {
 	self0 := cm.Reinterpret[uint32](self)
 	len0 := (uint64)(len_)
-	wasmimport_InputStreamBlockingRead((uint32)(self0), (uint64)(len0), &result)
+	var result_ cm.Result[abi_ResultListU8StreamErrorShape, cm.List[uint8], StreamError]
+	wasmimport_InputStreamBlockingRead((uint32)(self0), (uint64)(len0), &result_)
+	result = fromABI_ResultListU8StreamError(result_)
 	return
 }
 
@@ -161,10 +193,12 @@ func (self InputStream) BlockingSkip(len_ uint64) (result cm.Result[uint64, uint
 //	read: func(len: u64) -> result<list<u8>, stream-error>
 //
 //go:nosplit
-func (self InputStream) Read(len_ uint64) (result cm.Result[cm.List[uint8], cm.List[uint8], StreamError]) {
+func (self InputStream) Read(len_ uint64) (result cm.Result[cm.SplitResult[cm.List[uint8], StreamError], cm.List[uint8], StreamError]) {
 	self0 := cm.Reinterpret[uint32](self)
 	len0 := (uint64)(len_)
-	wasmimport_InputStreamRead((uint32)(self0), (uint64)(len0), &result)
+	var result_ cm.Result[abi_ResultListU8StreamErrorShape, cm.List[uint8], StreamError]
+	wasmimport_InputStreamRead((uint32)(self0), (uint64)(len0), &result_)
+	result = fromABI_ResultListU8StreamError(result_)
 	return
 }
 
@@ -0,0 +1,41 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package insecureseed
+
+// Fake contains hooks for the functions imported from "wasi:random/insecure-seed@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// InsecureSeed represents the imported function "insecure-seed".
+	//
+	// Return a 128-bit value that may contain a pseudo-random value.
+	//
+	// The returned value is not required to be computed from a CSPRNG, and may
+	// even be entirely deterministic. Host implementations are encouraged to
+	// provide pseudo-random values to any program exposed to
+	// attacker-controlled content, to enable DoS protection built into many
+	// languages' hash-map implementations.
+	//
+	// This function is intended to only be called once, by a source language
+	// to initialize Denial Of Service (DoS) protection in its hash-map
+	// implementation.
+	//
+	// # Expected future evolution
+	//
+	// This will likely be changed to a value import, to prevent it from being
+	// called multiple times and potentially used for purposes other than DoS
+	// protection.
+	//
+	//	insecure-seed: func() -> tuple<u64, u64>
+	InsecureSeed func() (result [2]uint64)
+}
+
+func wasmimport_InsecureSeed(result *[2]uint64) {
+	if Fake.InsecureSeed == nil {
+		panic("InsecureSeed: no fake for wasi:random/insecure-seed@0.2.0 insecure-seed")
+	}
+	*result = Fake.InsecureSeed()
+	return
+}
@@ -0,0 +1,56 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package insecure
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:random/insecure@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetInsecureRandomBytes represents the imported function "get-insecure-random-bytes".
+	//
+	// Return `len` insecure pseudo-random bytes.
+	//
+	// This function is not cryptographically secure. Do not use it for
+	// anything related to security.
+	//
+	// There are no requirements on the values of the returned bytes, however
+	// implementations are encouraged to return evenly distributed values with
+	// a long period.
+	//
+	//	get-insecure-random-bytes: func(len: u64) -> list<u8>
+	GetInsecureRandomBytes func(len_ uint64) (result cm.List[uint8])
+
+	// GetInsecureRandomU64 represents the imported function "get-insecure-random-u64".
+	//
+	// Return an insecure pseudo-random `u64` value.
+	//
+	// This function returns the same type of pseudo-random data as
+	// `get-insecure-random-bytes`, represented as a `u64`.
+	//
+	//	get-insecure-random-u64: func() -> u64
+	GetInsecureRandomU64 func() (result uint64)
+}
+
+func wasmimport_GetInsecureRandomBytes(len0 uint64, result *cm.List[uint8]) {
+	len_ := (uint64)((uint64)(len0))
+	if Fake.GetInsecureRandomBytes == nil {
+		panic("GetInsecureRandomBytes: no fake for wasi:random/insecure@0.2.0 get-insecure-random-bytes")
+	}
+	*result = Fake.GetInsecureRandomBytes(len_)
+	return
+}
+
+func wasmimport_GetInsecureRandomU64() (result0 uint64) {
+	if Fake.GetInsecureRandomU64 == nil {
+		panic("GetInsecureRandomU64: no fake for wasi:random/insecure@0.2.0 get-insecure-random-u64")
+	}
+	result := Fake.GetInsecureRandomU64()
+	result0 = (uint64)(result)
+	return
+}
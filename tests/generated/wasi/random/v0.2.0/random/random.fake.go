@@ -0,0 +1,60 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package random
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:random/random@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// GetRandomBytes represents the imported function "get-random-bytes".
+	//
+	// Return `len` cryptographically-secure random or pseudo-random bytes.
+	//
+	// This function must produce data at least as cryptographically secure and
+	// fast as an adequately seeded cryptographically-secure pseudo-random
+	// number generator (CSPRNG). It must not block, from the perspective of
+	// the calling program, under any circumstances, including on the first
+	// request and on requests for numbers of bytes. The returned data must
+	// always be unpredictable.
+	//
+	// This function must always return fresh data. Deterministic environments
+	// must omit this function, rather than implementing it with deterministic
+	// data.
+	//
+	//	get-random-bytes: func(len: u64) -> list<u8>
+	GetRandomBytes func(len_ uint64) (result cm.List[uint8])
+
+	// GetRandomU64 represents the imported function "get-random-u64".
+	//
+	// Return a cryptographically-secure random or pseudo-random `u64` value.
+	//
+	// This function returns the same type of data as `get-random-bytes`,
+	// represented as a `u64`.
+	//
+	//	get-random-u64: func() -> u64
+	GetRandomU64 func() (result uint64)
+}
+
+func wasmimport_GetRandomBytes(len0 uint64, result *cm.List[uint8]) {
+	len_ := (uint64)((uint64)(len0))
+	if Fake.GetRandomBytes == nil {
+		panic("GetRandomBytes: no fake for wasi:random/random@0.2.0 get-random-bytes")
+	}
+	*result = Fake.GetRandomBytes(len_)
+	return
+}
+
+func wasmimport_GetRandomU64() (result0 uint64) {
+	if Fake.GetRandomU64 == nil {
+		panic("GetRandomU64: no fake for wasi:random/random@0.2.0 get-random-u64")
+	}
+	result := Fake.GetRandomU64()
+	result0 = (uint64)(result)
+	return
+}
@@ -0,0 +1,30 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package instancenetwork
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:sockets/instance-network@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// InstanceNetwork represents the imported function "instance-network".
+	//
+	// Get a handle to the default network.
+	//
+	//	instance-network: func() -> network
+	InstanceNetwork func() (result Network)
+}
+
+func wasmimport_InstanceNetwork() (result0 uint32) {
+	if Fake.InstanceNetwork == nil {
+		panic("InstanceNetwork: no fake for wasi:sockets/instance-network@0.2.0 instance-network")
+	}
+	result := Fake.InstanceNetwork()
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
@@ -0,0 +1,129 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package ipnamelookup
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:sockets/ip-name-lookup@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// ResolveAddressStream contains the hooks for imported resource "wasi:sockets/ip-name-lookup@0.2.0#resolve-address-stream".
+	ResolveAddressStream struct {
+		// Handles is an in-memory table of fake handles for resource "resolve-address-stream".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[ResolveAddressStream, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "resolve-address-stream".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self ResolveAddressStream)
+
+		// ResolveNextAddress represents the imported method "resolve-next-address".
+		//
+		// Returns the next address from the resolver.
+		//
+		// This function should be called multiple times. On each call, it will
+		// return the next address in connection order preference. If all
+		// addresses have been exhausted, this function returns `none`.
+		//
+		// This function never returns IPv4-mapped IPv6 addresses.
+		//
+		// # Typical errors
+		// - `name-unresolvable`:          Name does not exist or has no suitable associated
+		// IP addresses. (EAI_NONAME, EAI_NODATA, EAI_ADDRFAMILY)
+		// - `temporary-resolver-failure`: A temporary failure in name resolution occurred.
+		// (EAI_AGAIN)
+		// - `permanent-resolver-failure`: A permanent failure in name resolution occurred.
+		// (EAI_FAIL)
+		// - `would-block`:                A result is not available yet. (EWOULDBLOCK, EAGAIN)
+		//
+		//	resolve-next-address: func() -> result<option<ip-address>, error-code>
+		ResolveNextAddress func(self ResolveAddressStream) (result cm.Result[OptionIPAddressShape, cm.Option[IPAddress], ErrorCode])
+
+		// Subscribe represents the imported method "subscribe".
+		//
+		// Create a `pollable` which will resolve once the stream is ready for I/O.
+		//
+		// Note: this function is here for WASI Preview2 only.
+		// It's planned to be removed when `future` is natively supported in Preview3.
+		//
+		//	subscribe: func() -> pollable
+		Subscribe func(self ResolveAddressStream) (result Pollable)
+	}
+
+	// ResolveAddresses represents the imported function "resolve-addresses".
+	//
+	// Resolve an internet host name to a list of IP addresses.
+	//
+	// Unicode domain names are automatically converted to ASCII using IDNA encoding.
+	// If the input is an IP address string, the address is parsed and returned
+	// as-is without making any external requests.
+	//
+	// See the wasi-socket proposal README.md for a comparison with getaddrinfo.
+	//
+	// This function never blocks. It either immediately fails or immediately
+	// returns successfully with a `resolve-address-stream` that can be used
+	// to (asynchronously) fetch the results.
+	//
+	// # Typical errors
+	// - `invalid-argument`: `name` is a syntactically invalid domain name or IP address.
+	//
+	// # References:
+	// - <https://pubs.opengroup.org/onlinepubs/9699919799/functions/getaddrinfo.html>
+	// - <https://man7.org/linux/man-pages/man3/getaddrinfo.3.html>
+	// - <https://learn.microsoft.com/en-us/windows/win32/api/ws2tcpip/nf-ws2tcpip-getaddrinfo>
+	// - <https://man.freebsd.org/cgi/man.cgi?query=getaddrinfo&sektion=3>
+	//
+	//	resolve-addresses: func(network: borrow<network>, name: string) -> result<resolve-address-stream,
+	//	error-code>
+	ResolveAddresses func(network_ Network, name string) (result cm.Result[ResolveAddressStream, ResolveAddressStream, ErrorCode])
+}
+
+func wasmimport_ResolveAddressStreamResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[ResolveAddressStream]((uint32)(self0))
+	if Fake.ResolveAddressStream.ResourceDrop == nil {
+		panic("ResolveAddressStream.ResourceDrop: no fake for wasi:sockets/ip-name-lookup@0.2.0 [resource-drop]resolve-address-stream")
+	}
+	Fake.ResolveAddressStream.ResourceDrop(self)
+	return
+}
+
+func wasmimport_ResolveAddressStreamResolveNextAddress(self0 uint32, result *cm.Result[OptionIPAddressShape, cm.Option[IPAddress], ErrorCode]) {
+	self := cm.Reinterpret[ResolveAddressStream]((uint32)(self0))
+	if Fake.ResolveAddressStream.ResolveNextAddress == nil {
+		panic("ResolveAddressStream.ResolveNextAddress: no fake for wasi:sockets/ip-name-lookup@0.2.0 [method]resolve-address-stream.resolve-next-address")
+	}
+	*result = Fake.ResolveAddressStream.ResolveNextAddress(self)
+	return
+}
+
+func wasmimport_ResolveAddressStreamSubscribe(self0 uint32) (result0 uint32) {
+	self := cm.Reinterpret[ResolveAddressStream]((uint32)(self0))
+	if Fake.ResolveAddressStream.Subscribe == nil {
+		panic("ResolveAddressStream.Subscribe: no fake for wasi:sockets/ip-name-lookup@0.2.0 [method]resolve-address-stream.subscribe")
+	}
+	result := Fake.ResolveAddressStream.Subscribe(self)
+	result0 = cm.Reinterpret[uint32](result)
+	return
+}
+
+func init() {
+	Fake.ResolveAddressStream.ResourceDrop = func(self ResolveAddressStream) {
+		Fake.ResolveAddressStream.Handles.Drop(self)
+	}
+}
+
+func wasmimport_ResolveAddresses(network0 uint32, name0 *uint8, name1 uint32, result *cm.Result[ResolveAddressStream, ResolveAddressStream, ErrorCode]) {
+	network_ := cm.Reinterpret[Network]((uint32)(network0))
+	name := cm.LiftString[string]((*uint8)(name0), (uint32)(name1))
+	if Fake.ResolveAddresses == nil {
+		panic("ResolveAddresses: no fake for wasi:sockets/ip-name-lookup@0.2.0 resolve-addresses")
+	}
+	*result = Fake.ResolveAddresses(network_, name)
+	return
+}
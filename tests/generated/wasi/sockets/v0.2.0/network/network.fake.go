@@ -0,0 +1,41 @@
+// Code generated by wit-bindgen-go. DO NOT EDIT.
+
+//go:build !wasm
+
+package network
+
+import (
+	"go.bytecodealliance.org/cm"
+)
+
+// Fake contains hooks for the functions imported from "wasi:sockets/network@0.2.0"
+// when this package is built for a target other than WebAssembly, such as with go test.
+// Calling an imported function with a nil hook panics.
+var Fake struct {
+	// Network contains the hooks for imported resource "wasi:sockets/network@0.2.0#network".
+	Network struct {
+		// Handles is an in-memory table of fake handles for resource "network".
+		// The default ResourceDrop hook removes a handle from Handles.
+		Handles cm.FakeHandles[Network, any]
+
+		// ResourceDrop represents the imported resource-drop for resource "network".
+		//
+		// Drops a resource handle.
+		ResourceDrop func(self Network)
+	}
+}
+
+func wasmimport_NetworkResourceDrop(self0 uint32) {
+	self := cm.Reinterpret[Network]((uint32)(self0))
+	if Fake.Network.ResourceDrop == nil {
+		panic("Network.ResourceDrop: no fake for wasi:sockets/network@0.2.0 [resource-drop]network")
+	}
+	Fake.Network.ResourceDrop(self)
+	return
+}
+
+func init() {
+	Fake.Network.ResourceDrop = func(self Network) {
+		Fake.Network.Handles.Drop(self)
+	}
+}
@@ -4,6 +4,7 @@
 package network
 
 import (
+	"encoding/json"
 	"go.bytecodealliance.org/cm"
 )
 
@@ -282,6 +283,39 @@ func (v IPAddress) String() string {
 	return _IPAddressStrings[v.Tag()]
 }
 
+// MarshalJSON implements [json.Marshaler], returning a JSON object with a single
+// key for the variant case name.
+func (v IPAddress) MarshalJSON() ([]byte, error) {
+	var data any
+	switch v.Tag() {
+	case 0:
+		data = v.IPv4()
+	case 1:
+		data = v.IPv6()
+	}
+	return cm.MarshalVariantJSON(_IPAddressStrings[:], v.Tag(), data)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON object with a
+// single key for the variant case name.
+func (v *IPAddress) UnmarshalJSON(data []byte) error {
+	tag, value, err := cm.UnmarshalVariantJSON(_IPAddressStrings[:], data)
+	if err != nil {
+		return err
+	}
+	switch tag {
+	case 0:
+		var payload IPv4Address
+		err = json.Unmarshal(value, &payload)
+		*v = IPAddressIPv4(payload)
+	case 1:
+		var payload IPv6Address
+		err = json.Unmarshal(value, &payload)
+		*v = IPAddressIPv6(payload)
+	}
+	return err
+}
+
 // IPv4SocketAddress represents the record "wasi:sockets/network@0.2.0#ipv4-socket-address".
 //
 //	record ipv4-socket-address {
@@ -357,3 +391,36 @@ var _IPSocketAddressStrings = [2]string{
 func (v IPSocketAddress) String() string {
 	return _IPSocketAddressStrings[v.Tag()]
 }
+
+// MarshalJSON implements [json.Marshaler], returning a JSON object with a single
+// key for the variant case name.
+func (v IPSocketAddress) MarshalJSON() ([]byte, error) {
+	var data any
+	switch v.Tag() {
+	case 0:
+		data = v.IPv4()
+	case 1:
+		data = v.IPv6()
+	}
+	return cm.MarshalVariantJSON(_IPSocketAddressStrings[:], v.Tag(), data)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON object with a
+// single key for the variant case name.
+func (v *IPSocketAddress) UnmarshalJSON(data []byte) error {
+	tag, value, err := cm.UnmarshalVariantJSON(_IPSocketAddressStrings[:], data)
+	if err != nil {
+		return err
+	}
+	switch tag {
+	case 0:
+		var payload IPv4SocketAddress
+		err = json.Unmarshal(value, &payload)
+		*v = IPSocketAddressIPv4(payload)
+	case 1:
+		var payload IPv6SocketAddress
+		err = json.Unmarshal(value, &payload)
+		*v = IPSocketAddressIPv6(payload)
+	}
+	return err
+}
@@ -4,6 +4,7 @@ package tcp
 
 import (
 	"go.bytecodealliance.org/cm"
+	"strconv"
 	"tests/generated/wasi/sockets/v0.2.0/network"
 	"unsafe"
 )
@@ -86,3 +87,47 @@ func lower_IPSocketAddress(v network.IPSocketAddress) (f0 uint32, f1 uint32, f2
 	}
 	return
 }
+
+func lift_IPv4Address(f0 uint32, f1 uint32, f2 uint32, f3 uint32) (v network.IPv4Address) {
+	v[0] = (uint8)(f0)
+	v[1] = (uint8)(f1)
+	v[2] = (uint8)(f2)
+	v[3] = (uint8)(f3)
+	return
+}
+
+func lift_IPv4SocketAddress(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32) (v network.IPv4SocketAddress) {
+	v.Port = (uint16)(f0)
+	v.Address = lift_IPv4Address(f1, f2, f3, f4)
+	return
+}
+
+func lift_IPv6Address(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32, f5 uint32, f6 uint32, f7 uint32) (v network.IPv6Address) {
+	v[0] = (uint16)(f0)
+	v[1] = (uint16)(f1)
+	v[2] = (uint16)(f2)
+	v[3] = (uint16)(f3)
+	v[4] = (uint16)(f4)
+	v[5] = (uint16)(f5)
+	v[6] = (uint16)(f6)
+	v[7] = (uint16)(f7)
+	return
+}
+
+func lift_IPv6SocketAddress(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32, f5 uint32, f6 uint32, f7 uint32, f8 uint32, f9 uint32, f10 uint32) (v network.IPv6SocketAddress) {
+	v.Port = (uint16)(f0)
+	v.FlowInfo = (uint32)(f1)
+	v.Address = lift_IPv6Address(f2, f3, f4, f5, f6, f7, f8, f9)
+	v.ScopeID = (uint32)(f10)
+	return
+}
+
+func lift_IPSocketAddress(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32, f5 uint32, f6 uint32, f7 uint32, f8 uint32, f9 uint32, f10 uint32, f11 uint32) (v network.IPSocketAddress) {
+	switch f0 {
+	case 0:
+		return cm.New[network.IPSocketAddress](0, lift_IPv4SocketAddress((uint32)(f1), (uint32)(f2), (uint32)(f3), (uint32)(f4), (uint32)(f5)))
+	case 1:
+		return cm.New[network.IPSocketAddress](1, lift_IPv6SocketAddress((uint32)(f1), (uint32)(f2), (uint32)(f3), (uint32)(f4), (uint32)(f5), (uint32)(f6), (uint32)(f7), (uint32)(f8), (uint32)(f9), (uint32)(f10), (uint32)(f11)))
+	}
+	panic("lift variant: unknown case: " + strconv.Itoa(int(f0)))
+}
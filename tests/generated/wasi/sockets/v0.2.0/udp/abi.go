@@ -4,6 +4,7 @@ package udp
 
 import (
 	"go.bytecodealliance.org/cm"
+	"strconv"
 	"tests/generated/wasi/sockets/v0.2.0/network"
 	"unsafe"
 )
@@ -75,6 +76,50 @@ func lower_IPSocketAddress(v network.IPSocketAddress) (f0 uint32, f1 uint32, f2
 	return
 }
 
+func lift_IPv4Address(f0 uint32, f1 uint32, f2 uint32, f3 uint32) (v network.IPv4Address) {
+	v[0] = (uint8)(f0)
+	v[1] = (uint8)(f1)
+	v[2] = (uint8)(f2)
+	v[3] = (uint8)(f3)
+	return
+}
+
+func lift_IPv4SocketAddress(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32) (v network.IPv4SocketAddress) {
+	v.Port = (uint16)(f0)
+	v.Address = lift_IPv4Address(f1, f2, f3, f4)
+	return
+}
+
+func lift_IPv6Address(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32, f5 uint32, f6 uint32, f7 uint32) (v network.IPv6Address) {
+	v[0] = (uint16)(f0)
+	v[1] = (uint16)(f1)
+	v[2] = (uint16)(f2)
+	v[3] = (uint16)(f3)
+	v[4] = (uint16)(f4)
+	v[5] = (uint16)(f5)
+	v[6] = (uint16)(f6)
+	v[7] = (uint16)(f7)
+	return
+}
+
+func lift_IPv6SocketAddress(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32, f5 uint32, f6 uint32, f7 uint32, f8 uint32, f9 uint32, f10 uint32) (v network.IPv6SocketAddress) {
+	v.Port = (uint16)(f0)
+	v.FlowInfo = (uint32)(f1)
+	v.Address = lift_IPv6Address(f2, f3, f4, f5, f6, f7, f8, f9)
+	v.ScopeID = (uint32)(f10)
+	return
+}
+
+func lift_IPSocketAddress(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32, f5 uint32, f6 uint32, f7 uint32, f8 uint32, f9 uint32, f10 uint32, f11 uint32) (v network.IPSocketAddress) {
+	switch f0 {
+	case 0:
+		return cm.New[network.IPSocketAddress](0, lift_IPv4SocketAddress((uint32)(f1), (uint32)(f2), (uint32)(f3), (uint32)(f4), (uint32)(f5)))
+	case 1:
+		return cm.New[network.IPSocketAddress](1, lift_IPv6SocketAddress((uint32)(f1), (uint32)(f2), (uint32)(f3), (uint32)(f4), (uint32)(f5), (uint32)(f6), (uint32)(f7), (uint32)(f8), (uint32)(f9), (uint32)(f10), (uint32)(f11)))
+	}
+	panic("lift variant: unknown case: " + strconv.Itoa(int(f0)))
+}
+
 // TupleIncomingDatagramStreamOutgoingDatagramStreamShape is used for storage in variant or result types.
 type TupleIncomingDatagramStreamOutgoingDatagramStreamShape struct {
 	_     cm.HostLayout
@@ -101,3 +146,30 @@ func lower_OptionIPSocketAddress(v cm.Option[IPSocketAddress]) (f0 uint32, f1 ui
 	}
 	return
 }
+
+func lift_OptionIPSocketAddress(f0 uint32, f1 uint32, f2 uint32, f3 uint32, f4 uint32, f5 uint32, f6 uint32, f7 uint32, f8 uint32, f9 uint32, f10 uint32, f11 uint32, f12 uint32) (v cm.Option[IPSocketAddress]) {
+	if f0 == 0 {
+		return
+	}
+	return (cm.Option[IPSocketAddress])(cm.Some[IPSocketAddress](lift_IPSocketAddress((uint32)(f1), (uint32)(f2), (uint32)(f3), (uint32)(f4), (uint32)(f5), (uint32)(f6), (uint32)(f7), (uint32)(f8), (uint32)(f9), (uint32)(f10), (uint32)(f11), (uint32)(f12))))
+}
+
+// abi_ResultListIncomingDatagramErrorCodeShape is used for storage in type result<list<incoming-datagram>, error-code> with the Canonical ABI memory layout.
+type abi_ResultListIncomingDatagramErrorCodeShape struct {
+	_     cm.HostLayout
+	shape [max(unsafe.Sizeof(*new(cm.List[IncomingDatagram])), unsafe.Sizeof(*new(ErrorCode)))]byte
+}
+
+func fromABI_ResultListIncomingDatagramErrorCode(v cm.Result[abi_ResultListIncomingDatagramErrorCodeShape, cm.List[IncomingDatagram], ErrorCode]) (out cm.Result[cm.SplitResult[cm.List[IncomingDatagram], ErrorCode], cm.List[IncomingDatagram], ErrorCode]) {
+	if ok := v.OK(); ok != nil {
+		return cm.OK[cm.Result[cm.SplitResult[cm.List[IncomingDatagram], ErrorCode], cm.List[IncomingDatagram], ErrorCode]](*ok)
+	}
+	return cm.Err[cm.Result[cm.SplitResult[cm.List[IncomingDatagram], ErrorCode], cm.List[IncomingDatagram], ErrorCode]](*v.Err())
+}
+
+func toABI_ResultListIncomingDatagramErrorCode(v cm.Result[cm.SplitResult[cm.List[IncomingDatagram], ErrorCode], cm.List[IncomingDatagram], ErrorCode]) (out cm.Result[abi_ResultListIncomingDatagramErrorCodeShape, cm.List[IncomingDatagram], ErrorCode]) {
+	if ok := v.OK(); ok != nil {
+		return cm.OK[cm.Result[abi_ResultListIncomingDatagramErrorCodeShape, cm.List[IncomingDatagram], ErrorCode]](*ok)
+	}
+	return cm.Err[cm.Result[abi_ResultListIncomingDatagramErrorCodeShape, cm.List[IncomingDatagram], ErrorCode]](*v.Err())
+}
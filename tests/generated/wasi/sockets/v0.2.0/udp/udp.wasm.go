@@ -1,5 +1,7 @@
 // Code generated by wit-bindgen-go. DO NOT EDIT.
 
+//go:build wasm
+
 package udp
 
 import (
@@ -70,7 +72,7 @@ func wasmimport_IncomingDatagramStreamResourceDrop(self0 uint32)
 
 //go:wasmimport wasi:sockets/udp@0.2.0 [method]incoming-datagram-stream.receive
 //go:noescape
-func wasmimport_IncomingDatagramStreamReceive(self0 uint32, maxResults0 uint64, result *cm.Result[cm.List[IncomingDatagram], cm.List[IncomingDatagram], ErrorCode])
+func wasmimport_IncomingDatagramStreamReceive(self0 uint32, maxResults0 uint64, result *cm.Result[abi_ResultListIncomingDatagramErrorCodeShape, cm.List[IncomingDatagram], ErrorCode])
 
 //go:wasmimport wasi:sockets/udp@0.2.0 [method]incoming-datagram-stream.subscribe
 //go:noescape
@@ -442,10 +442,12 @@ func (self IncomingDatagramStream) ResourceDrop() {
 //	receive: func(max-results: u64) -> result<list<incoming-datagram>, error-code>
 //
 //go:nosplit
-func (self IncomingDatagramStream) Receive(maxResults uint64) (result cm.Result[cm.List[IncomingDatagram], cm.List[IncomingDatagram], ErrorCode]) {
+func (self IncomingDatagramStream) Receive(maxResults uint64) (result cm.Result[cm.SplitResult[cm.List[IncomingDatagram], ErrorCode], cm.List[IncomingDatagram], ErrorCode]) {
 	self0 := cm.Reinterpret[uint32](self)
 	maxResults0 := (uint64)(maxResults)
-	wasmimport_IncomingDatagramStreamReceive((uint32)(self0), (uint64)(maxResults0), &result)
+	var result_ cm.Result[abi_ResultListIncomingDatagramErrorCodeShape, cm.List[IncomingDatagram], ErrorCode]
+	wasmimport_IncomingDatagramStreamReceive((uint32)(self0), (uint64)(maxResults0), &result_)
+	result = fromABI_ResultListIncomingDatagramErrorCode(result_)
 	return
 }
 
@@ -68,6 +68,83 @@ func TestJSON(t *testing.T) {
 			ptr(cm.ToList([]uint32{1, 2, 3})),
 			false,
 		},
+		{
+			"flags(none)",
+			`[]`,
+			ptr(types.DescriptorFlags(0)),
+			ptr(types.DescriptorFlags(0)),
+			false,
+		},
+		{
+			"flags(read, write)",
+			`["read","write"]`,
+			ptr(types.DescriptorFlags(0)),
+			ptr(types.DescriptorFlagsRead | types.DescriptorFlagsWrite),
+			false,
+		},
+		{
+			"flags(unknown)",
+			`["delete"]`,
+			ptr(types.DescriptorFlags(0)),
+			nil,
+			true,
+		},
+		{
+			"variant(no-change)",
+			`{"no-change":null}`,
+			&types.NewTimestamp{},
+			ptr(types.NewTimestampNoChange()),
+			false,
+		},
+		{
+			"variant(timestamp)",
+			`{"timestamp":{"seconds":1,"nanoseconds":2}}`,
+			&types.NewTimestamp{},
+			ptr(types.NewTimestampTimestamp(wallclock.DateTime{Seconds: 1, Nanoseconds: 2})),
+			false,
+		},
+		{
+			"variant(unknown)",
+			`{"later":null}`,
+			&types.NewTimestamp{},
+			nil,
+			true,
+		},
+		{
+			"option<descriptor-type>(none)",
+			`null`,
+			&cm.Option[types.DescriptorType]{},
+			ptr(cm.None[types.DescriptorType]()),
+			false,
+		},
+		{
+			"option<descriptor-type>(some)",
+			`"directory"`,
+			&cm.Option[types.DescriptorType]{},
+			ptr(cm.Some(types.DescriptorTypeDirectory)),
+			false,
+		},
+		{
+			"result<descriptor-type, error-code>(ok)",
+			`{"ok":"fifo"}`,
+			&cm.Result[types.DescriptorType, types.DescriptorType, types.ErrorCode]{},
+			ptr(cm.OK[cm.Result[types.DescriptorType, types.DescriptorType, types.ErrorCode]](types.DescriptorTypeFIFO)),
+			false,
+		},
+		{
+			"result<descriptor-type, error-code>(err)",
+			`{"err":"access"}`,
+			&cm.Result[types.DescriptorType, types.DescriptorType, types.ErrorCode]{},
+			ptr(cm.Err[cm.Result[types.DescriptorType, types.DescriptorType, types.ErrorCode]](types.ErrorCodeAccess)),
+			false,
+		},
+		{
+			"tuple<string, u64>",
+			`["a",1]`,
+			&cm.Tuple[string, uint64]{},
+			&cm.Tuple[string, uint64]{F0: "a", F1: 1},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -79,6 +156,9 @@ func TestJSON(t *testing.T) {
 				t.Errorf("json.Unmarshal(%q): expected no error, got error: %v", tt.json, err)
 				return
 			}
+			if tt.wantErr {
+				return
+			}
 			got, err := json.Marshal(tt.into)
 			if err != nil {
 				t.Error(err)
@@ -0,0 +1,99 @@
+//go:build !wasm
+
+package resources
+
+import (
+	"testing"
+
+	"tests/generated/example/resources/counters"
+	"tests/generated/example/resources/handles"
+)
+
+type counter struct {
+	n uint32
+}
+
+func (c *counter) Add(n uint32) uint32 {
+	c.n += n
+	return c.n
+}
+
+type countersImpl struct {
+	destroyed []counters.CounterValue
+}
+
+func (impl *countersImpl) Counter() counters.CounterInterface { return impl }
+
+func (impl *countersImpl) Constructor(start uint32) counters.Counter {
+	return counters.CounterResourceNew(counters.CounterTable.New(&counter{n: start}))
+}
+
+func (impl *countersImpl) Destructor(self counters.CounterValue) {
+	impl.destroyed = append(impl.destroyed, self)
+}
+
+func TestResourceTable(t *testing.T) {
+	impl := &countersImpl{}
+	counters.SetExports(impl)
+
+	c1 := impl.Constructor(10)
+	c2 := impl.Constructor(20)
+	if n := counters.CounterTable.Len(); n != 2 {
+		t.Fatalf("CounterTable.Len(): %d, expected 2", n)
+	}
+	v1 := counters.CounterTable.Value(c1.ResourceRep())
+	if got, want := v1.Add(1), uint32(11); got != want {
+		t.Errorf("Add(1): %d, expected %d", got, want)
+	}
+	if got, want := counters.CounterTable.Value(c2.ResourceRep()).Add(2), uint32(22); got != want {
+		t.Errorf("Add(2): %d, expected %d", got, want)
+	}
+
+	// Dropping the handle calls the destructor with the value released from the table.
+	c1.ResourceDrop()
+	if len(impl.destroyed) != 1 || impl.destroyed[0] != v1 {
+		t.Errorf("Destructor: called with %v, expected [%v]", impl.destroyed, v1)
+	}
+	if n := counters.CounterTable.Len(); n != 1 {
+		t.Errorf("CounterTable.Len(): %d, expected 1 after ResourceDrop", n)
+	}
+	if _, ok := counters.CounterTable.Get(c1.ResourceRep()); ok {
+		t.Errorf("CounterTable.Get(): found value for dropped handle")
+	}
+
+	c2.ResourceDrop()
+	if n := counters.CounterTable.Len(); n != 0 {
+		t.Errorf("CounterTable.Len(): %d, expected 0 after ResourceDrop", n)
+	}
+	if n := counters.Fake.Counter.Handles.Len(); n != 0 {
+		t.Errorf("Handles.Len(): %d, expected 0 after ResourceDrop", n)
+	}
+}
+
+func TestOwnedClose(t *testing.T) {
+	fake := &handles.Fake.File
+	o := fake.Handles.New("file").Owned()
+	if err := o.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+	if n := fake.Handles.Len(); n != 0 {
+		t.Errorf("Handles.Len(): %d, expected 0 after Close", n)
+	}
+
+	// A second Close must not drop the handle again.
+	drops := 0
+	defer func(drop func(handles.File)) { fake.ResourceDrop = drop }(fake.ResourceDrop)
+	fake.ResourceDrop = func(self handles.File) { drops++ }
+	if err := o.Close(); err == nil {
+		t.Errorf("Close(): expected error after Close")
+	}
+	if drops != 0 {
+		t.Errorf("ResourceDrop: called %d times after Close, expected 0", drops)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Handle(): expected panic after Close")
+		}
+	}()
+	o.Handle()
+}
@@ -6,4 +6,9 @@ package tests
 
 //go:generate rm -rf ./generated/*
 //go:generate mkdir -p ./generated
-//go:generate go run go.bytecodealliance.org/cmd/wit-bindgen-go generate --versioned -o ./generated ../testdata/wasi/cli.wit.json
+// Both runs write to ./generated. The generation manifest of one run would remove the files of the other,
+// so it is removed after each run.
+//go:generate go run go.bytecodealliance.org/cmd/wit-bindgen-go generate --versioned --fakes -o ./generated ../testdata/wasi/cli.wit.json
+//go:generate rm -f ./generated/.wit-bindgen-go.json
+//go:generate go run go.bytecodealliance.org/cmd/wit-bindgen-go generate --owned-resources --resource-tables --export-interfaces --fakes -o ./generated ./resources/resources.wit
+//go:generate rm -f ./generated/.wit-bindgen-go.json
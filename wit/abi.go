@@ -261,6 +261,12 @@ const (
 	//
 	// [flattened results]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
 	MaxFlatResults = 1
+
+	// MaxFlatAsyncParams is the maximum number of [flattened parameters] an async function
+	// can have when lowered as an import, as defined in the Component Model Canonical ABI.
+	//
+	// [flattened parameters]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
+	MaxFlatAsyncParams = 4
 )
 
 // CoreFunction returns a [Core WebAssembly function] of [Function] f.
@@ -271,6 +277,9 @@ const (
 // [Core WebAssembly function]: https://webassembly.github.io/spec/core/syntax/modules.html#syntax-func
 // [flattened]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
 func (f *Function) CoreFunction(op Direction) *Function {
+	if f.Async {
+		return f.asyncCoreFunction(op)
+	}
 	if len(f.Params) == 0 && len(f.Results) == 0 {
 		return f
 	}
@@ -299,6 +308,58 @@ func (f *Function) CoreFunction(op Direction) *Function {
 	return &cf
 }
 
+// asyncCoreFunction returns the Core WebAssembly function of async [Function] f.
+// Imported async functions are lowered with at most [MaxFlatAsyncParams] flat params,
+// and write their results to a caller-provided pointer. They return a packed subtask status.
+// Exported async functions are lifted using the callback ABI, and return a callback code.
+// Their results are returned by calling [Function.TaskReturn].
+func (f *Function) asyncCoreFunction(op Direction) *Function {
+	// Clone the function
+	cf := *f
+
+	maxFlatParams := MaxFlatParams
+	if op == Imported {
+		maxFlatParams = MaxFlatAsyncParams
+	}
+	cf.Params = flattenParams(f.Params)
+	if len(cf.Params) > maxFlatParams {
+		cf.Params = []Param{compoundParam("param", "params", f.Params)}
+	}
+
+	if op == Imported {
+		if len(f.Results) > 0 {
+			cf.Params = append(cf.Params, compoundParam("result", "results", f.Results))
+		}
+		cf.Results = []Param{{Name: "status", Type: U32{}}}
+	} else {
+		cf.Results = []Param{{Name: "code", Type: U32{}}}
+	}
+
+	return &cf
+}
+
+// TaskReturn returns the synthetic [task.return] function used by exported async [Function] f
+// to return its results. The returned function has no results, and its params are the results of f.
+// It returns nil if f is not async.
+//
+// [task.return]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#-canon-taskreturn
+func (f *Function) TaskReturn() *Function {
+	if !f.Async {
+		return nil
+	}
+	tf := &Function{
+		Name: "[task-return]" + f.Name,
+		Kind: &Freestanding{},
+	}
+	for _, r := range f.Results {
+		if r.Name == "" {
+			r.Name = "result"
+		}
+		tf.Params = append(tf.Params, r)
+	}
+	return tf
+}
+
 func flatParams(pfx string, flat []Type) []Param {
 	out := make([]Param, len(flat))
 	for i, t := range flat {
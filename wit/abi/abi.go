@@ -0,0 +1,180 @@
+// Package abi implements the [Canonical ABI] memory representation of Component Model values,
+// lifting and lowering a dynamic [Value] to and from a byte slice representing linear memory.
+// It is intended for host tools, fuzzers, and debuggers that work with Component Model values
+// outside of a WebAssembly guest.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+package abi
+
+import (
+	"fmt"
+	"strings"
+
+	"go.bytecodealliance.org/wit"
+)
+
+// Lower lowers [Value] v of [wit.Type] t into linear memory mem, returning a pointer to the value.
+// Memory for v and any strings or lists it contains is allocated by calling alloc,
+// which returns a pointer into mem for size bytes aligned to align, similar to the
+// Canonical ABI realloc function.
+func Lower(t wit.Type, v Value, mem []byte, alloc func(size, align uint32) uint32, opts ...Option) (uint32, error) {
+	c := newCodec(mem, alloc, opts)
+	ptr, err := c.alloc(uint32(t.Size()), uint32(t.Align()))
+	if err != nil {
+		return 0, err
+	}
+	return ptr, c.store(t, v, ptr)
+}
+
+// Lift lifts a [Value] of [wit.Type] t from linear memory mem at ptr.
+func Lift(t wit.Type, mem []byte, ptr uint32, opts ...Option) (Value, error) {
+	c := newCodec(mem, nil, opts)
+	if err := checkAlign(t, ptr, uint32(t.Align())); err != nil {
+		return nil, err
+	}
+	return c.load(t, ptr)
+}
+
+// Option represents a single configuration option for [Lower] or [Lift].
+type Option interface {
+	applyOption(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) applyOption(opts *options) {
+	f(opts)
+}
+
+type options struct {
+	encoding StringEncoding
+}
+
+// Encoding returns an [Option] that sets the [StringEncoding] of strings in linear memory.
+// The default is [UTF8].
+func Encoding(enc StringEncoding) Option {
+	return optionFunc(func(opts *options) {
+		opts.encoding = enc
+	})
+}
+
+// StringEncoding represents a Canonical ABI [string encoding].
+//
+// [string encoding]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#string-operations
+type StringEncoding int
+
+const (
+	// UTF8 encodes strings as UTF-8, with a length in bytes.
+	UTF8 StringEncoding = iota
+
+	// UTF16 encodes strings as little-endian UTF-16, with a length in 16-bit code units.
+	UTF16
+
+	// Latin1UTF16 encodes strings as Latin-1 if every character of the string can be represented in Latin-1,
+	// with a length in bytes. Otherwise, strings are encoded as UTF-16, with a length in 16-bit code units
+	// and the high bit of the length set.
+	Latin1UTF16
+)
+
+// String implements the [fmt.Stringer] interface.
+func (enc StringEncoding) String() string {
+	switch enc {
+	case UTF8:
+		return "utf8"
+	case UTF16:
+		return "utf16"
+	case Latin1UTF16:
+		return "latin1+utf16"
+	}
+	return fmt.Sprintf("StringEncoding(%d)", int(enc))
+}
+
+// codec lifts and lowers values to and from linear memory.
+type codec struct {
+	mem      []byte
+	realloc  func(size, align uint32) uint32
+	encoding StringEncoding
+}
+
+func newCodec(mem []byte, alloc func(size, align uint32) uint32, opts []Option) *codec {
+	var o options
+	for _, opt := range opts {
+		opt.applyOption(&o)
+	}
+	return &codec{
+		mem:      mem,
+		realloc:  alloc,
+		encoding: o.encoding,
+	}
+}
+
+// alloc allocates size bytes aligned to align, and checks that the returned memory is valid.
+func (c *codec) alloc(size, align uint32) (uint32, error) {
+	if c.realloc == nil {
+		return 0, fmt.Errorf("abi: no allocator")
+	}
+	ptr := c.realloc(size, align)
+	if ptr&(align-1) != 0 {
+		return 0, fmt.Errorf("abi: allocated pointer %d is not aligned to %d", ptr, align)
+	}
+	if _, err := c.bytes(ptr, size); err != nil {
+		return 0, err
+	}
+	return ptr, nil
+}
+
+// bytes returns n bytes of memory at ptr, or an error if the range is out of bounds.
+func (c *codec) bytes(ptr, n uint32) ([]byte, error) {
+	end := uint64(ptr) + uint64(n)
+	if end > uint64(len(c.mem)) {
+		return nil, fmt.Errorf("abi: memory access out of bounds: [%d:%d] with length %d", ptr, end, len(c.mem))
+	}
+	return c.mem[ptr:end], nil
+}
+
+// kind returns the despecialized kind of t, following type aliases.
+func kind(t wit.Type) wit.TypeDefKind {
+	for {
+		td, ok := t.(*wit.TypeDef)
+		if !ok {
+			return t
+		}
+		k := td.Root().Kind
+		if alias, ok := k.(wit.Type); ok {
+			t = alias
+			continue
+		}
+		return wit.Despecialize(k)
+	}
+}
+
+// payloadOffset returns the offset of the payload of variant v from the start of the variant.
+func payloadOffset(v *wit.Variant) uint32 {
+	var align uintptr = 1
+	for _, c := range v.Cases {
+		if c.Type != nil {
+			align = max(align, c.Type.Align())
+		}
+	}
+	return uint32(wit.Align(wit.Discriminant(len(v.Cases)).Size(), align))
+}
+
+func checkAlign(t wit.Type, ptr, align uint32) error {
+	if ptr&(align-1) != 0 {
+		return fmt.Errorf("abi: pointer %d to %s is not aligned to %d", ptr, typeName(t), align)
+	}
+	return nil
+}
+
+// typeName returns a human-readable name for t, for use in error messages.
+// Anonymous types that span multiple lines in WIT, such as records, are named by their kind.
+func typeName(t wit.Type) string {
+	if name := t.TypeName(); name != "" {
+		return name
+	}
+	s := t.WIT(nil, "")
+	if strings.Contains(s, "\n") {
+		s, _, _ = strings.Cut(s, " ")
+	}
+	return s
+}
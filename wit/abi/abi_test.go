@@ -0,0 +1,255 @@
+package abi
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+// memory is a bump allocator over a fixed-size linear memory.
+type memory struct {
+	mem  []byte
+	next uint32
+}
+
+func newMemory(size int) *memory {
+	return &memory{mem: make([]byte, size), next: 8}
+}
+
+func (m *memory) alloc(size, align uint32) uint32 {
+	ptr := uint32(wit.Align(uintptr(m.next), uintptr(align)))
+	m.next = ptr + size
+	return ptr
+}
+
+func typedef(kind wit.TypeDefKind) *wit.TypeDef {
+	return &wit.TypeDef{Kind: kind}
+}
+
+func record(types ...wit.Type) *wit.TypeDef {
+	r := &wit.Record{}
+	for i, t := range types {
+		r.Fields = append(r.Fields, wit.Field{Name: string(rune('a' + i)), Type: t})
+	}
+	return typedef(r)
+}
+
+func flags(n int) *wit.TypeDef {
+	return typedef(&wit.Flags{Flags: make([]wit.Flag, n)})
+}
+
+func TestRoundTrip(t *testing.T) {
+	resource := typedef(&wit.Resource{})
+	tests := []struct {
+		name string
+		t    wit.Type
+		v    Value
+	}{
+		{"bool", wit.Bool{}, Bool(true)},
+		{"s8", wit.S8{}, S8(-8)},
+		{"u8", wit.U8{}, U8(0xff)},
+		{"s16", wit.S16{}, S16(-1600)},
+		{"u16", wit.U16{}, U16(0xfff0)},
+		{"s32", wit.S32{}, S32(-320000)},
+		{"u32", wit.U32{}, U32(0xdeadbeef)},
+		{"s64", wit.S64{}, S64(-1 << 60)},
+		{"u64", wit.U64{}, U64(1<<64 - 1)},
+		{"f32", wit.F32{}, F32(3.25)},
+		{"f64", wit.F64{}, F64(-6.5e100)},
+		{"char", wit.Char{}, Char('🎉')},
+		{"string", wit.String{}, String("hello, world")},
+		{"empty string", wit.String{}, String("")},
+		{"alias", typedef(wit.U16{}), U16(7)},
+		{"record", record(wit.U8{}, wit.String{}, wit.U64{}, wit.Bool{}), Record{U8(1), String("two"), U64(3), Bool(true)}},
+		{"empty record", record(), Record{}},
+		{"tuple", typedef(&wit.Tuple{Types: []wit.Type{wit.U8{}, wit.F64{}}}), Record{U8(1), F64(2)}},
+		{"list<u8>", typedef(&wit.List{Type: wit.U8{}}), List{U8(1), U8(2), U8(3)}},
+		{"list<string>", typedef(&wit.List{Type: wit.String{}}), List{String("a"), String("bc"), String("")}},
+		{"list<list<u32>>", typedef(&wit.List{Type: typedef(&wit.List{Type: wit.U32{}})}), List{List{U32(1)}, List{}, List{U32(2), U32(3)}}},
+		{"empty list", typedef(&wit.List{Type: wit.U64{}}), List{}},
+		{"variant", typedef(&wit.Variant{Cases: []wit.Case{{Name: "a"}, {Name: "b", Type: wit.String{}}, {Name: "c", Type: wit.U64{}}}}), Variant{Case: 2, Value: U64(42)}},
+		{"variant without payload", typedef(&wit.Variant{Cases: []wit.Case{{Name: "a"}, {Name: "b", Type: wit.String{}}}}), Variant{Case: 0}},
+		{"enum", typedef(&wit.Enum{Cases: []wit.EnumCase{{Name: "a"}, {Name: "b"}, {Name: "c"}}}), Variant{Case: 2}},
+		{"option none", typedef(&wit.Option{Type: wit.String{}}), Variant{Case: 0}},
+		{"option some", typedef(&wit.Option{Type: wit.String{}}), Variant{Case: 1, Value: String("some")}},
+		{"result ok", typedef(&wit.Result{OK: wit.U32{}, Err: wit.String{}}), Variant{Case: 0, Value: U32(1)}},
+		{"result err", typedef(&wit.Result{OK: wit.U32{}, Err: wit.String{}}), Variant{Case: 1, Value: String("error")}},
+		{"result<_, _>", typedef(&wit.Result{}), Variant{Case: 1}},
+		{"flags 3", flags(3), Flags{true, false, true}},
+		{"flags 12", flags(12), Flags{false, true, false, false, false, false, false, false, false, false, false, true}},
+		{"flags 40", flags(40), func() Flags { f := make(Flags, 40); f[0], f[31], f[32], f[39] = true, true, true, true; return f }()},
+		{"own", typedef(&wit.Own{Type: resource}), Handle(1)},
+		{"borrow", typedef(&wit.Borrow{Type: resource}), Handle(2)},
+		{"stream", typedef(&wit.Stream{Type: wit.U8{}}), Handle(3)},
+		{"future", typedef(&wit.Future{}), Handle(4)},
+		{"error-context", typedef(&wit.ErrorContext{}), Handle(5)},
+	}
+	for _, enc := range []StringEncoding{UTF8, UTF16, Latin1UTF16} {
+		for _, tt := range tests {
+			t.Run(enc.String()+"/"+tt.name, func(t *testing.T) {
+				m := newMemory(1024)
+				ptr, err := Lower(tt.t, tt.v, m.mem, m.alloc, Encoding(enc))
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := Lift(tt.t, m.mem, ptr, Encoding(enc))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, tt.v) {
+					t.Errorf("Lift: %#v, expected %#v", got, tt.v)
+				}
+			})
+		}
+	}
+}
+
+func TestLowerLayout(t *testing.T) {
+	// record { a: u8, b: u32, c: string }
+	m := newMemory(64)
+	ptr, err := Lower(record(wit.U8{}, wit.U32{}, wit.String{}), Record{U8(1), U32(2), String("hi")}, m.mem, m.alloc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ptr != 8 {
+		t.Fatalf("Lower: ptr %d, expected 8", ptr)
+	}
+	want := []byte{
+		1, 0, 0, 0, // a
+		2, 0, 0, 0, // b
+		24, 0, 0, 0, // c: ptr
+		2, 0, 0, 0, // c: len
+		'h', 'i',
+	}
+	if got := m.mem[8 : 8+len(want)]; !bytes.Equal(got, want) {
+		t.Errorf("Lower: % x, expected % x", got, want)
+	}
+}
+
+func TestStringEncoding(t *testing.T) {
+	tests := []struct {
+		s     string
+		enc   StringEncoding
+		units uint32
+		data  []byte
+	}{
+		{"hé", UTF8, 3, []byte{'h', 0xc3, 0xa9}},
+		{"hé", UTF16, 2, []byte{'h', 0, 0xe9, 0}},
+		{"hé", Latin1UTF16, 2, []byte{'h', 0xe9}},
+		{"h🎉", UTF16, 3, []byte{'h', 0, 0x3c, 0xd8, 0x89, 0xdf}},
+		{"h🎉", Latin1UTF16, 3 | utf16Tag, []byte{'h', 0, 0x3c, 0xd8, 0x89, 0xdf}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.enc.String()+"/"+tt.s, func(t *testing.T) {
+			m := newMemory(64)
+			ptr, err := Lower(wit.String{}, String(tt.s), m.mem, m.alloc, Encoding(tt.enc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Lift(typedef(&wit.Tuple{Types: []wit.Type{wit.U32{}, wit.U32{}}}), m.mem, ptr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, units := uint32(got.(Record)[0].(U32)), uint32(got.(Record)[1].(U32))
+			if units != tt.units {
+				t.Errorf("code units: %#x, expected %#x", units, tt.units)
+			}
+			if data := m.mem[p : p+uint32(len(tt.data))]; !bytes.Equal(data, tt.data) {
+				t.Errorf("data: % x, expected % x", data, tt.data)
+			}
+		})
+	}
+}
+
+func TestLowerErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		t    wit.Type
+		v    Value
+	}{
+		{"wrong type", wit.U32{}, S32(1)},
+		{"nil value", wit.U32{}, nil},
+		{"invalid char", wit.Char{}, Char(0xd800)},
+		{"invalid string", wit.String{}, String("\xff")},
+		{"record fields", record(wit.U8{}, wit.U8{}), Record{U8(1)}},
+		{"case out of range", typedef(&wit.Option{Type: wit.U8{}}), Variant{Case: 2}},
+		{"missing payload", typedef(&wit.Option{Type: wit.U8{}}), Variant{Case: 1}},
+		{"unexpected payload", typedef(&wit.Option{Type: wit.U8{}}), Variant{Case: 0, Value: U8(1)}},
+		{"flags count", flags(3), Flags{true}},
+		{"out of memory", typedef(&wit.List{Type: wit.U64{}}), make(List, 100)},
+		{"resource", typedef(&wit.Resource{}), Handle(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMemory(64)
+			_, err := Lower(tt.t, tt.v, m.mem, m.alloc)
+			if err == nil {
+				t.Errorf("Lower: nil error, expected error")
+			}
+		})
+	}
+}
+
+func TestLiftErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		t    wit.Type
+		mem  []byte
+		ptr  uint32
+	}{
+		{"out of bounds", wit.U64{}, make([]byte, 12), 8},
+		{"unaligned", wit.U32{}, make([]byte, 8), 2},
+		{"invalid char", wit.Char{}, []byte{0, 0xd8, 0, 0}, 0},
+		{"case out of range", typedef(&wit.Option{Type: wit.U8{}}), []byte{2, 0}, 0},
+		{"invalid UTF-8", wit.String{}, []byte{8, 0, 0, 0, 1, 0, 0, 0, 0xff}, 0},
+		{"string out of bounds", wit.String{}, []byte{8, 0, 0, 0, 2, 0, 0, 0, 'a'}, 0},
+		{"unaligned list", typedef(&wit.List{Type: wit.U32{}}), []byte{9, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Lift(tt.t, tt.mem, tt.ptr)
+			if err == nil {
+				t.Errorf("Lift: nil error, expected error")
+			}
+		})
+	}
+}
+
+func TestLiftUTF16Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		mem  []byte
+	}{
+		{"unpaired high surrogate", []byte{8, 0, 0, 0, 1, 0, 0, 0, 0x3c, 0xd8}},
+		{"unpaired low surrogate", []byte{8, 0, 0, 0, 2, 0, 0, 0, 0x89, 0xdf, 'a', 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Lift(wit.String{}, tt.mem, 0, Encoding(UTF16))
+			if err == nil {
+				t.Errorf("Lift: nil error, expected error")
+			}
+		})
+	}
+}
+
+func FuzzLift(f *testing.F) {
+	typ := record(
+		wit.Char{},
+		typedef(&wit.List{Type: wit.String{}}),
+		typedef(&wit.Result{OK: typedef(&wit.Option{Type: wit.F64{}}), Err: flags(20)}),
+	)
+	m := newMemory(256)
+	v := Record{Char('x'), List{String("a"), String("bc")}, Variant{Case: 0, Value: Variant{Case: 1, Value: F64(1)}}}
+	if _, err := Lower(typ, v, m.mem, m.alloc); err != nil {
+		f.Fatal(err)
+	}
+	f.Add(m.mem[:m.next], uint32(8))
+	f.Fuzz(func(t *testing.T, mem []byte, ptr uint32) {
+		for _, enc := range []StringEncoding{UTF8, UTF16, Latin1UTF16} {
+			Lift(typ, mem, ptr, Encoding(enc))
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package abi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"go.bytecodealliance.org/wit"
+)
+
+// load loads a value of type t from memory at ptr.
+func (c *codec) load(t wit.Type, ptr uint32) (Value, error) {
+	mem, err := c.bytes(ptr, uint32(t.Size()))
+	if err != nil {
+		return nil, err
+	}
+	switch k := kind(t).(type) {
+	case wit.Bool:
+		return Bool(mem[0] != 0), nil
+	case wit.S8:
+		return S8(mem[0]), nil
+	case wit.U8:
+		return U8(mem[0]), nil
+	case wit.S16:
+		return S16(binary.LittleEndian.Uint16(mem)), nil
+	case wit.U16:
+		return U16(binary.LittleEndian.Uint16(mem)), nil
+	case wit.S32:
+		return S32(binary.LittleEndian.Uint32(mem)), nil
+	case wit.U32:
+		return U32(binary.LittleEndian.Uint32(mem)), nil
+	case wit.S64:
+		return S64(binary.LittleEndian.Uint64(mem)), nil
+	case wit.U64:
+		return U64(binary.LittleEndian.Uint64(mem)), nil
+	case wit.F32:
+		return F32(math.Float32frombits(binary.LittleEndian.Uint32(mem))), nil
+	case wit.F64:
+		return F64(math.Float64frombits(binary.LittleEndian.Uint64(mem))), nil
+	case wit.Char:
+		r := binary.LittleEndian.Uint32(mem)
+		if !validChar(r) {
+			return nil, fmt.Errorf("abi: invalid char %#x", r)
+		}
+		return Char(r), nil
+	case wit.String:
+		s, err := c.liftString(binary.LittleEndian.Uint32(mem), binary.LittleEndian.Uint32(mem[4:]))
+		if err != nil {
+			return nil, err
+		}
+		return String(s), nil
+	case *wit.Record:
+		v := make(Record, len(k.Fields))
+		var offset uintptr
+		for i, f := range k.Fields {
+			offset = wit.Align(offset, f.Type.Align())
+			v[i], err = c.load(f.Type, ptr+uint32(offset))
+			if err != nil {
+				return nil, err
+			}
+			offset += f.Type.Size()
+		}
+		return v, nil
+	case *wit.List:
+		p, n := binary.LittleEndian.Uint32(mem), binary.LittleEndian.Uint32(mem[4:])
+		size := uint32(k.Type.Size())
+		if err := checkAlign(k.Type, p, uint32(k.Type.Align())); err != nil {
+			return nil, err
+		}
+		if uint64(n)*uint64(size) > math.MaxUint32 {
+			return nil, fmt.Errorf("abi: list of %d elements is too large", n)
+		}
+		if _, err := c.bytes(p, n*size); err != nil {
+			return nil, err
+		}
+		v := make(List, n)
+		for i := range v {
+			v[i], err = c.load(k.Type, p+uint32(i)*size)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case *wit.Variant:
+		var tag uint32
+		switch wit.Discriminant(len(k.Cases)).(type) {
+		case wit.U8:
+			tag = uint32(mem[0])
+		case wit.U16:
+			tag = uint32(binary.LittleEndian.Uint16(mem))
+		default:
+			tag = binary.LittleEndian.Uint32(mem)
+		}
+		if uint64(tag) >= uint64(len(k.Cases)) {
+			return nil, fmt.Errorf("abi: case %d out of range for %s with %d cases", tag, typeName(t), len(k.Cases))
+		}
+		v := Variant{Case: tag}
+		if caseType := k.Cases[tag].Type; caseType != nil {
+			v.Value, err = c.load(caseType, ptr+payloadOffset(k))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return v, nil
+	case *wit.Flags:
+		v := make(Flags, len(k.Flags))
+		for i := range v {
+			v[i] = mem[i/8]&(1<<(i%8)) != 0
+		}
+		return v, nil
+	case *wit.Own, *wit.Borrow, *wit.Stream, *wit.Future, *wit.ErrorContext:
+		return Handle(binary.LittleEndian.Uint32(mem)), nil
+	}
+	return nil, fmt.Errorf("abi: cannot lift type %s", typeName(t))
+}
@@ -0,0 +1,176 @@
+package abi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"go.bytecodealliance.org/wit"
+)
+
+// store stores v of type t into memory at ptr.
+func (c *codec) store(t wit.Type, v Value, ptr uint32) error {
+	mem, err := c.bytes(ptr, uint32(t.Size()))
+	if err != nil {
+		return err
+	}
+	switch k := kind(t).(type) {
+	case wit.Bool:
+		if v, ok := v.(Bool); ok {
+			mem[0] = 0
+			if v {
+				mem[0] = 1
+			}
+			return nil
+		}
+	case wit.S8:
+		if v, ok := v.(S8); ok {
+			mem[0] = uint8(v)
+			return nil
+		}
+	case wit.U8:
+		if v, ok := v.(U8); ok {
+			mem[0] = uint8(v)
+			return nil
+		}
+	case wit.S16:
+		if v, ok := v.(S16); ok {
+			binary.LittleEndian.PutUint16(mem, uint16(v))
+			return nil
+		}
+	case wit.U16:
+		if v, ok := v.(U16); ok {
+			binary.LittleEndian.PutUint16(mem, uint16(v))
+			return nil
+		}
+	case wit.S32:
+		if v, ok := v.(S32); ok {
+			binary.LittleEndian.PutUint32(mem, uint32(v))
+			return nil
+		}
+	case wit.U32:
+		if v, ok := v.(U32); ok {
+			binary.LittleEndian.PutUint32(mem, uint32(v))
+			return nil
+		}
+	case wit.S64:
+		if v, ok := v.(S64); ok {
+			binary.LittleEndian.PutUint64(mem, uint64(v))
+			return nil
+		}
+	case wit.U64:
+		if v, ok := v.(U64); ok {
+			binary.LittleEndian.PutUint64(mem, uint64(v))
+			return nil
+		}
+	case wit.F32:
+		if v, ok := v.(F32); ok {
+			binary.LittleEndian.PutUint32(mem, math.Float32bits(float32(v)))
+			return nil
+		}
+	case wit.F64:
+		if v, ok := v.(F64); ok {
+			binary.LittleEndian.PutUint64(mem, math.Float64bits(float64(v)))
+			return nil
+		}
+	case wit.Char:
+		if v, ok := v.(Char); ok {
+			if !validChar(uint32(v)) {
+				return fmt.Errorf("abi: invalid char %U", rune(v))
+			}
+			binary.LittleEndian.PutUint32(mem, uint32(v))
+			return nil
+		}
+	case wit.String:
+		if v, ok := v.(String); ok {
+			p, units, err := c.lowerString(string(v))
+			if err != nil {
+				return err
+			}
+			binary.LittleEndian.PutUint32(mem, p)
+			binary.LittleEndian.PutUint32(mem[4:], units)
+			return nil
+		}
+	case *wit.Record:
+		if v, ok := v.(Record); ok {
+			if len(v) != len(k.Fields) {
+				return fmt.Errorf("abi: cannot lower record with %d fields as %s with %d fields", len(v), typeName(t), len(k.Fields))
+			}
+			var offset uintptr
+			for i, f := range k.Fields {
+				offset = wit.Align(offset, f.Type.Align())
+				if err := c.store(f.Type, v[i], ptr+uint32(offset)); err != nil {
+					return err
+				}
+				offset += f.Type.Size()
+			}
+			return nil
+		}
+	case *wit.List:
+		if v, ok := v.(List); ok {
+			size, align := uint32(k.Type.Size()), uint32(k.Type.Align())
+			n := uint64(len(v)) * uint64(size)
+			if n > math.MaxUint32 {
+				return fmt.Errorf("abi: list of %d elements is too large", len(v))
+			}
+			p, err := c.alloc(uint32(n), align)
+			if err != nil {
+				return err
+			}
+			for i, elem := range v {
+				if err := c.store(k.Type, elem, p+uint32(i)*size); err != nil {
+					return err
+				}
+			}
+			binary.LittleEndian.PutUint32(mem, p)
+			binary.LittleEndian.PutUint32(mem[4:], uint32(len(v)))
+			return nil
+		}
+	case *wit.Variant:
+		if v, ok := v.(Variant); ok {
+			if uint64(v.Case) >= uint64(len(k.Cases)) {
+				return fmt.Errorf("abi: case %d out of range for %s with %d cases", v.Case, typeName(t), len(k.Cases))
+			}
+			caseType := k.Cases[v.Case].Type
+			switch {
+			case caseType == nil && v.Value != nil:
+				return fmt.Errorf("abi: case %d of %s has no associated type, got %T", v.Case, typeName(t), v.Value)
+			case caseType != nil && v.Value == nil:
+				return fmt.Errorf("abi: case %d of %s requires a value", v.Case, typeName(t))
+			}
+			switch wit.Discriminant(len(k.Cases)).(type) {
+			case wit.U8:
+				mem[0] = uint8(v.Case)
+			case wit.U16:
+				binary.LittleEndian.PutUint16(mem, uint16(v.Case))
+			default:
+				binary.LittleEndian.PutUint32(mem, v.Case)
+			}
+			if caseType == nil {
+				return nil
+			}
+			return c.store(caseType, v.Value, ptr+payloadOffset(k))
+		}
+	case *wit.Flags:
+		if v, ok := v.(Flags); ok {
+			if len(v) != len(k.Flags) {
+				return fmt.Errorf("abi: cannot lower %d flags as %s with %d flags", len(v), typeName(t), len(k.Flags))
+			}
+			clear(mem)
+			for i, set := range v {
+				if set {
+					mem[i/8] |= 1 << (i % 8)
+				}
+			}
+			return nil
+		}
+	case *wit.Own, *wit.Borrow, *wit.Stream, *wit.Future, *wit.ErrorContext:
+		if v, ok := v.(Handle); ok {
+			binary.LittleEndian.PutUint32(mem, uint32(v))
+			return nil
+		}
+	default:
+		return fmt.Errorf("abi: cannot lower type %s", typeName(t))
+	}
+	return fmt.Errorf("abi: cannot lower %T as %s", v, typeName(t))
+}
@@ -0,0 +1,157 @@
+package abi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	// maxStringByteLength is the maximum length in bytes of a string in linear memory.
+	maxStringByteLength = 1<<31 - 1
+
+	// utf16Tag is set in the length of a [Latin1UTF16] string encoded as UTF-16.
+	utf16Tag = 1 << 31
+)
+
+// validChar returns true if r is a Unicode scalar value.
+func validChar(r uint32) bool {
+	return r < 0xd800 || (r >= 0xe000 && r < 0x110000)
+}
+
+// lowerString allocates and stores s with the configured [StringEncoding],
+// returning a pointer to the string and its length in code units.
+func (c *codec) lowerString(s string) (ptr, units uint32, err error) {
+	if !utf8.ValidString(s) {
+		return 0, 0, fmt.Errorf("abi: invalid UTF-8 string %q", s)
+	}
+	switch c.encoding {
+	case UTF8:
+		if len(s) > maxStringByteLength {
+			return 0, 0, fmt.Errorf("abi: string of %d bytes is too long", len(s))
+		}
+		ptr, err = c.alloc(uint32(len(s)), 1)
+		if err != nil {
+			return 0, 0, err
+		}
+		copy(c.mem[ptr:], s)
+		return ptr, uint32(len(s)), nil
+
+	case UTF16:
+		return c.lowerUTF16(s, 0)
+
+	case Latin1UTF16:
+		if strings.ContainsFunc(s, func(r rune) bool { return r > 0xff }) {
+			return c.lowerUTF16(s, utf16Tag)
+		}
+		n := utf8.RuneCountInString(s)
+		if n > maxStringByteLength {
+			return 0, 0, fmt.Errorf("abi: string of %d bytes is too long", n)
+		}
+		ptr, err = c.alloc(uint32(n), 2)
+		if err != nil {
+			return 0, 0, err
+		}
+		i := ptr
+		for _, r := range s {
+			c.mem[i] = byte(r)
+			i++
+		}
+		return ptr, uint32(n), nil
+	}
+	return 0, 0, fmt.Errorf("abi: unknown string encoding %v", c.encoding)
+}
+
+// lowerUTF16 allocates and stores s as UTF-16, returning a pointer to the string
+// and its length in 16-bit code units combined with tag.
+func (c *codec) lowerUTF16(s string, tag uint32) (ptr, units uint32, err error) {
+	u := utf16.Encode([]rune(s))
+	if 2*len(u) > maxStringByteLength {
+		return 0, 0, fmt.Errorf("abi: string of %d bytes is too long", 2*len(u))
+	}
+	ptr, err = c.alloc(uint32(2*len(u)), 2)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i, cu := range u {
+		binary.LittleEndian.PutUint16(c.mem[ptr+uint32(2*i):], cu)
+	}
+	return ptr, uint32(len(u)) | tag, nil
+}
+
+// liftString loads a string at ptr with length units, in code units of the configured [StringEncoding].
+func (c *codec) liftString(ptr, units uint32) (string, error) {
+	switch c.encoding {
+	case UTF8:
+		b, err := c.bytes(ptr, units)
+		if err != nil {
+			return "", err
+		}
+		if units > maxStringByteLength {
+			return "", fmt.Errorf("abi: string of %d bytes is too long", units)
+		}
+		if !utf8.Valid(b) {
+			return "", fmt.Errorf("abi: invalid UTF-8 string at %d", ptr)
+		}
+		return string(b), nil
+
+	case UTF16:
+		return c.liftUTF16(ptr, units)
+
+	case Latin1UTF16:
+		if units&utf16Tag != 0 {
+			return c.liftUTF16(ptr, units&^utf16Tag)
+		}
+		if ptr&1 != 0 {
+			return "", fmt.Errorf("abi: pointer %d to string is not aligned to 2", ptr)
+		}
+		b, err := c.bytes(ptr, units)
+		if err != nil {
+			return "", err
+		}
+		var sb strings.Builder
+		sb.Grow(len(b))
+		for _, ch := range b {
+			sb.WriteRune(rune(ch))
+		}
+		return sb.String(), nil
+	}
+	return "", fmt.Errorf("abi: unknown string encoding %v", c.encoding)
+}
+
+// liftUTF16 loads a UTF-16 string at ptr with length units in 16-bit code units.
+func (c *codec) liftUTF16(ptr, units uint32) (string, error) {
+	if ptr&1 != 0 {
+		return "", fmt.Errorf("abi: pointer %d to string is not aligned to 2", ptr)
+	}
+	if uint64(units)*2 > maxStringByteLength {
+		return "", fmt.Errorf("abi: string of %d bytes is too long", uint64(units)*2)
+	}
+	b, err := c.bytes(ptr, 2*units)
+	if err != nil {
+		return "", err
+	}
+	u := make([]uint16, units)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	var sb strings.Builder
+	sb.Grow(len(u))
+	for i := 0; i < len(u); i++ {
+		r := rune(u[i])
+		if utf16.IsSurrogate(r) {
+			if i+1 == len(u) {
+				return "", fmt.Errorf("abi: invalid UTF-16 string at %d", ptr)
+			}
+			r = utf16.DecodeRune(r, rune(u[i+1]))
+			if r == utf8.RuneError {
+				return "", fmt.Errorf("abi: invalid UTF-16 string at %d", ptr)
+			}
+			i++
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String(), nil
+}
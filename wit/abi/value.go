@@ -0,0 +1,96 @@
+package abi
+
+// Value is a dynamic representation of a Component Model value.
+// It is implemented by the types in this package. The Go type of a Value
+// depends on the [despecialized] WIT type it represents:
+//
+//   - bool, s8, u8, s16, u16, s32, u32, s64, u64, f32, f64, char, string: [Bool], [S8], [U8], [S16], [U16], [S32], [U32], [S64], [U64], [F32], [F64], [Char], [String].
+//   - record and tuple: [Record], with one element per field.
+//   - list: [List].
+//   - variant, enum, option, and result: [Variant]. An option is a variant with cases none (0) and some (1).
+//     A result is a variant with cases ok (0) and error (1).
+//   - flags: [Flags], with one element per flag.
+//   - own, borrow, stream, future, and error-context: [Handle].
+//
+// [despecialized]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#despecialization
+type Value interface {
+	isValue()
+}
+
+// Bool represents a WIT bool value.
+type Bool bool
+
+// S8 represents a WIT s8 value.
+type S8 int8
+
+// U8 represents a WIT u8 value.
+type U8 uint8
+
+// S16 represents a WIT s16 value.
+type S16 int16
+
+// U16 represents a WIT u16 value.
+type U16 uint16
+
+// S32 represents a WIT s32 value.
+type S32 int32
+
+// U32 represents a WIT u32 value.
+type U32 uint32
+
+// S64 represents a WIT s64 value.
+type S64 int64
+
+// U64 represents a WIT u64 value.
+type U64 uint64
+
+// F32 represents a WIT f32 value.
+type F32 float32
+
+// F64 represents a WIT f64 value.
+type F64 float64
+
+// Char represents a WIT char value, a Unicode scalar value.
+type Char rune
+
+// String represents a WIT string value.
+type String string
+
+// Record represents a WIT record or tuple value, with one [Value] per field, in order.
+type Record []Value
+
+// List represents a WIT list value.
+type List []Value
+
+// Variant represents a WIT variant, enum, option, or result value.
+// Case is the index of the case, and Value is the value of the associated type of the case,
+// or nil if the case has no associated type.
+type Variant struct {
+	Case  uint32
+	Value Value
+}
+
+// Flags represents a WIT flags value, with one bool per flag, in order.
+type Flags []bool
+
+// Handle represents a WIT resource handle (own or borrow), stream, future, or error-context value.
+type Handle uint32
+
+func (Bool) isValue()    {}
+func (S8) isValue()      {}
+func (U8) isValue()      {}
+func (S16) isValue()     {}
+func (U16) isValue()     {}
+func (S32) isValue()     {}
+func (U32) isValue()     {}
+func (S64) isValue()     {}
+func (U64) isValue()     {}
+func (F32) isValue()     {}
+func (F64) isValue()     {}
+func (Char) isValue()    {}
+func (String) isValue()  {}
+func (Record) isValue()  {}
+func (List) isValue()    {}
+func (Variant) isValue() {}
+func (Flags) isValue()   {}
+func (Handle) isValue()  {}
@@ -211,3 +211,63 @@ func TestHasBorrow(t *testing.T) {
 		})
 	}
 }
+
+func TestAsyncCoreFunction(t *testing.T) {
+	many := []Param{{"a", U32{}}, {"b", U32{}}, {"c", String{}}, {"d", U32{}}}
+	tests := []struct {
+		name    string
+		f       *Function
+		dir     Direction
+		params  int
+		pointer bool // params are passed by pointer
+		results int  // number of results, including a result pointer param if imported
+	}{
+		{"import no params", &Function{Async: true}, Imported, 0, false, 1},
+		{"import flat params", &Function{Async: true, Params: many[:3]}, Imported, 4, false, 1},
+		{"import too many params", &Function{Async: true, Params: many}, Imported, 1, true, 1},
+		{"import result", &Function{Async: true, Results: []Param{{Type: String{}}}}, Imported, 1, true, 1},
+		{"export flat params", &Function{Async: true, Params: many}, Exported, 5, false, 1},
+		{"export result", &Function{Async: true, Results: []Param{{Type: String{}}}}, Exported, 0, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cf := tt.f.CoreFunction(tt.dir)
+			if got, want := len(cf.Params), tt.params; got != want {
+				t.Errorf("len(Params): got %d, expected %d", got, want)
+			}
+			if tt.params > 0 {
+				var isPointer bool
+				if td, ok := cf.Params[0].Type.(*TypeDef); ok {
+					_, isPointer = td.Kind.(*Pointer)
+				}
+				if isPointer != tt.pointer {
+					t.Errorf("Params[0] is pointer: got %t, expected %t", isPointer, tt.pointer)
+				}
+			}
+			if got, want := len(cf.Results), tt.results; got != want {
+				t.Errorf("len(Results): got %d, expected %d", got, want)
+			}
+			if cf.Results[0].Type != (U32{}) {
+				t.Errorf("Results[0]: got %s, expected u32", cf.Results[0].Type.WIT(nil, ""))
+			}
+		})
+	}
+}
+
+func TestTaskReturn(t *testing.T) {
+	f := &Function{Name: "f", Kind: &Freestanding{}, Results: []Param{{Type: String{}}}}
+	if f.TaskReturn() != nil {
+		t.Errorf("TaskReturn(): expected nil for non-async function")
+	}
+	f.Async = true
+	tf := f.TaskReturn()
+	if got, want := tf.Name, "[task-return]f"; got != want {
+		t.Errorf("TaskReturn().Name: got %s, expected %s", got, want)
+	}
+	if len(tf.Params) != 1 || tf.Params[0].Name != "result" || len(tf.Results) != 0 {
+		t.Errorf("TaskReturn(): got params %v and results %v, expected a single result param", tf.Params, tf.Results)
+	}
+	if got, want := len(tf.CoreFunction(Imported).Params), 2; got != want {
+		t.Errorf("len(TaskReturn().CoreFunction(Imported).Params): got %d, expected %d", got, want)
+	}
+}
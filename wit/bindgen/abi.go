@@ -6,6 +6,289 @@ import (
 	"go.bytecodealliance.org/wit"
 )
 
+// variantLayout returns the type used for storage of a variant or result with associated types,
+// and whether the associated types must be split into separate storage.
+//
+// Associated types share storage, unless sharing would place a non-pointer value where
+// the storage type holds a pointer, or a pointer where the storage type does not,
+// which is not permitted by the Go garbage collector. Associated types are also split
+// if the Go representation of any type differs from its Canonical ABI memory layout.
+func variantLayout(types []wit.Type) (shape wit.Type, split bool) {
+	if len(types) <= 1 {
+		return variantShape(types), false
+	}
+	var size uintptr
+	var pointers []wit.Type
+	for _, t := range types {
+		if needsABI(t) {
+			return nil, true
+		}
+		if wit.HasPointer(t) {
+			pointers = append(pointers, t)
+		}
+		size = max(size, t.Size())
+	}
+	if len(pointers) == 0 {
+		return variantShape(types), false
+	}
+	for _, t := range pointers {
+		if t.Size() == size && canOverlay(t, types) {
+			return t, false
+		}
+	}
+	return nil, true
+}
+
+// SplitStorage reports whether the Go type generated for the variant or result type t
+// stores the associated types of its cases separately, rather than in shared storage.
+// Storage is split when the shared storage type would place pointers where the Go
+// garbage collector does not expect them, or when the Go representation of an
+// associated type differs from its Canonical ABI memory layout.
+func SplitStorage(t wit.Type) bool {
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		return false
+	}
+	var types []wit.Type
+	switch kind := td.Root().Kind.(type) {
+	case *wit.Variant:
+		types = kind.Types()
+	case *wit.Result:
+		types = kind.Types()
+	default:
+		return false
+	}
+	_, split := variantLayout(types)
+	return split
+}
+
+// canOverlay returns true if each type in types can be stored in memory of type shape,
+// such that pointers in types only overlay pointers in shape, and non-pointer values
+// in types only overlay non-pointer values or padding in shape.
+// The Go memory layout depends on the pointer size of the target, so types must
+// overlay shape on targets with 32-bit and 64-bit pointers.
+func canOverlay(shape wit.Type, types []wit.Type) bool {
+	for _, ptrSize := range ptrSizes {
+		words := gcWords(shape, ptrSize)
+		for _, t := range types {
+			if goSize(t, ptrSize) > goSize(shape, ptrSize) {
+				return false
+			}
+			for i, w := range gcWords(t, ptrSize) {
+				switch {
+				case w == gcPointer && words[i] != gcPointer:
+					return false
+				case w == gcScalar && words[i] == gcPointer:
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// needsABI returns true if the Go representation of t differs from its Canonical ABI
+// memory layout, which is the case if t contains a variant or result with split storage.
+// Values of these types are converted to and from an equivalent Go type with the
+// Canonical ABI memory layout when passed in memory between the host and guest.
+func needsABI(t wit.Type) bool {
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		return false
+	}
+	switch kind := wit.Despecialize(td.Root().Kind).(type) {
+	case wit.Type:
+		return needsABI(kind)
+	case *wit.Record:
+		for _, f := range kind.Fields {
+			if needsABI(f.Type) {
+				return true
+			}
+		}
+	case *wit.List:
+		return needsABI(kind.Type)
+	case *wit.Variant:
+		types := kind.Types()
+		if _, split := variantLayout(types); split {
+			return true
+		}
+		for _, t := range types {
+			if needsABI(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ptrSizes are the pointer sizes of the targets of generated Go code:
+// 4 for TinyGo wasm32 targets, and 8 for the Go wasm target and 64-bit hosts.
+var ptrSizes = []uintptr{4, 8}
+
+// gcWord describes the contents of a pointer-sized word of memory
+// from the perspective of the Go garbage collector.
+type gcWord uint8
+
+const (
+	gcNone    gcWord = iota // padding or unused memory
+	gcScalar                // non-pointer value
+	gcPointer               // pointer value
+)
+
+// gcWords returns a [gcWord] for each word of the Go memory layout of t
+// on a target with pointers of ptrSize bytes.
+func gcWords(t wit.Type, ptrSize uintptr) []gcWord {
+	words := make([]gcWord, (goSize(t, ptrSize)+ptrSize-1)/ptrSize)
+	markWords(words, ptrSize, 0, t)
+	return words
+}
+
+func markWords(words []gcWord, ptrSize, offset uintptr, t wit.Type) {
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		if _, ok := t.(wit.String); ok {
+			words[offset/ptrSize] = gcPointer
+			markScalar(words, ptrSize, offset+ptrSize, ptrSize)
+			return
+		}
+		markScalar(words, ptrSize, offset, t.Size())
+		return
+	}
+	switch kind := wit.Despecialize(td.Root().Kind).(type) {
+	case wit.Type:
+		markWords(words, ptrSize, offset, kind)
+	case *wit.Record:
+		for _, f := range kind.Fields {
+			offset = wit.Align(offset, goAlign(f.Type, ptrSize))
+			markWords(words, ptrSize, offset, f.Type)
+			offset += goSize(f.Type, ptrSize)
+		}
+	case *wit.List:
+		words[offset/ptrSize] = gcPointer
+		markScalar(words, ptrSize, offset+ptrSize, ptrSize)
+	case *wit.Variant:
+		disc := wit.Discriminant(len(kind.Cases))
+		markScalar(words, ptrSize, offset, disc.Size())
+		types := kind.Types()
+		if len(types) == 0 {
+			return
+		}
+		offset = wit.Align(offset+disc.Size(), variantDataAlign(types, ptrSize))
+		if shape, split := variantLayout(types); !split && wit.HasPointer(shape) {
+			markWords(words, ptrSize, offset, shape)
+		} else {
+			markScalar(words, ptrSize, offset, variantDataSize(types, ptrSize))
+		}
+	default:
+		markScalar(words, ptrSize, offset, t.Size())
+	}
+}
+
+// markScalar marks the words spanning size bytes at offset as non-pointer values,
+// unless already marked.
+func markScalar(words []gcWord, ptrSize, offset, size uintptr) {
+	for o := offset &^ (ptrSize - 1); o < offset+size; o += ptrSize {
+		if words[o/ptrSize] == gcNone {
+			words[o/ptrSize] = gcScalar
+		}
+	}
+}
+
+// goSize returns the size in bytes of the Go representation of t
+// on a target with pointers of ptrSize bytes. On a target with 32-bit pointers,
+// this is the size of the Canonical ABI memory layout of t.
+func goSize(t wit.Type, ptrSize uintptr) uintptr {
+	if ptrSize == 4 {
+		return t.Size()
+	}
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		if _, ok := t.(wit.String); ok {
+			return 2 * ptrSize
+		}
+		return t.Size()
+	}
+	switch kind := wit.Despecialize(td.Root().Kind).(type) {
+	case wit.Type:
+		return goSize(kind, ptrSize)
+	case *wit.Record:
+		var size uintptr
+		for _, f := range kind.Fields {
+			size = wit.Align(size, goAlign(f.Type, ptrSize)) + goSize(f.Type, ptrSize)
+		}
+		return wit.Align(size, goAlign(t, ptrSize))
+	case *wit.List:
+		return 2 * ptrSize
+	case *wit.Variant:
+		disc := wit.Discriminant(len(kind.Cases))
+		types := kind.Types()
+		if len(types) == 0 {
+			return disc.Size()
+		}
+		size := wit.Align(disc.Size(), variantDataAlign(types, ptrSize)) + variantDataSize(types, ptrSize)
+		return wit.Align(size, goAlign(t, ptrSize))
+	}
+	return t.Size()
+}
+
+// goAlign returns the alignment in bytes of the Go representation of t
+// on a target with pointers of ptrSize bytes.
+func goAlign(t wit.Type, ptrSize uintptr) uintptr {
+	if ptrSize == 4 {
+		return t.Align()
+	}
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		if _, ok := t.(wit.String); ok {
+			return ptrSize
+		}
+		return t.Align()
+	}
+	switch kind := wit.Despecialize(td.Root().Kind).(type) {
+	case wit.Type:
+		return goAlign(kind, ptrSize)
+	case *wit.Record:
+		align := uintptr(1)
+		for _, f := range kind.Fields {
+			align = max(align, goAlign(f.Type, ptrSize))
+		}
+		return align
+	case *wit.List:
+		return ptrSize
+	case *wit.Variant:
+		disc := wit.Discriminant(len(kind.Cases))
+		return max(disc.Align(), variantDataAlign(kind.Types(), ptrSize))
+	}
+	return t.Align()
+}
+
+// variantDataAlign returns the alignment of the storage for the associated types
+// of a variant or result on a target with pointers of ptrSize bytes.
+func variantDataAlign(types []wit.Type, ptrSize uintptr) uintptr {
+	align := uintptr(1)
+	for _, t := range types {
+		align = max(align, goAlign(t, ptrSize))
+	}
+	return align
+}
+
+// variantDataSize returns the size of the storage for the associated types
+// of a variant or result on a target with pointers of ptrSize bytes.
+// Split storage has a separate field for each type.
+func variantDataSize(types []wit.Type, ptrSize uintptr) uintptr {
+	var size uintptr
+	if _, split := variantLayout(types); split {
+		for _, t := range types {
+			size = wit.Align(size, goAlign(t, ptrSize)) + goSize(t, ptrSize)
+		}
+		return wit.Align(size, variantDataAlign(types, ptrSize))
+	}
+	for _, t := range types {
+		size = max(size, goSize(t, ptrSize))
+	}
+	return size
+}
+
 // variantShape returns the type with the greatest size that is not a bool.
 // If there are multiple types with the same size, it returns
 // the first type that contains a pointer.
@@ -63,3 +346,56 @@ func variantAlign(types []wit.Type) wit.Type {
 	})
 	return types[0]
 }
+
+// futuresAndStreams returns the future and stream types used in the params and results
+// of [wit.Function] f, in the order used by the Canonical ABI to number their
+// type-specific intrinsics, e.g. [stream-new-0] or [future-read-1].
+func futuresAndStreams(f *wit.Function) []*wit.TypeDef {
+	var out []*wit.TypeDef
+	for _, p := range f.Params {
+		out = appendFuturesAndStreams(out, p.Type)
+	}
+	for _, r := range f.Results {
+		out = appendFuturesAndStreams(out, r.Type)
+	}
+	return out
+}
+
+// appendFuturesAndStreams appends the future and stream types used in t to out,
+// in the order used by the Canonical ABI.
+func appendFuturesAndStreams(out []*wit.TypeDef, t wit.Type) []*wit.TypeDef {
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		return out
+	}
+	switch kind := td.Kind.(type) {
+	case wit.Type:
+		out = appendFuturesAndStreams(out, kind)
+	case *wit.Record:
+		for _, f := range kind.Fields {
+			out = appendFuturesAndStreams(out, f.Type)
+		}
+	case *wit.Tuple:
+		for _, t := range kind.Types {
+			out = appendFuturesAndStreams(out, t)
+		}
+	case *wit.Variant:
+		for _, c := range kind.Cases {
+			out = appendFuturesAndStreams(out, c.Type)
+		}
+	case *wit.Option:
+		out = appendFuturesAndStreams(out, kind.Type)
+	case *wit.Result:
+		out = appendFuturesAndStreams(out, kind.OK)
+		out = appendFuturesAndStreams(out, kind.Err)
+	case *wit.List:
+		out = appendFuturesAndStreams(out, kind.Type)
+	case *wit.Future:
+		out = appendFuturesAndStreams(out, kind.Type)
+		out = append(out, td)
+	case *wit.Stream:
+		out = appendFuturesAndStreams(out, kind.Type)
+		out = append(out, td)
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package bindgen
+
+import (
+	"slices"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+func TestVariantLayout(t *testing.T) {
+	listU8 := &wit.TypeDef{Kind: &wit.List{Type: wit.U8{}}}
+	split := &wit.TypeDef{Kind: &wit.Result{OK: wit.String{}, Err: wit.U64{}}}
+	tuple64 := &wit.TypeDef{Kind: &wit.Tuple{Types: []wit.Type{wit.U32{}, wit.U64{}}}}
+	tupleString := &wit.TypeDef{Kind: &wit.Tuple{Types: []wit.Type{wit.U32{}, wit.String{}, wit.U32{}}}}
+	tests := []struct {
+		name  string
+		types []wit.Type
+		split bool
+	}{
+		{"u32", []wit.Type{wit.U32{}}, false},
+		{"u32, u64", []wit.Type{wit.U32{}, wit.U64{}}, false},
+		{"string, list<u8>", []wit.Type{wit.String{}, listU8}, false},
+		{"string, u32", []wit.Type{wit.String{}, wit.U32{}}, true},
+		{"string, u64", []wit.Type{wit.String{}, wit.U64{}}, true},
+		{"list<u8>, u8", []wit.Type{listU8, wit.U8{}}, true},
+		{"u8, result<string, u64>", []wit.Type{wit.U8{}, split}, true},
+		{"list<result<string, u64>>", []wit.Type{&wit.TypeDef{Kind: &wit.List{Type: split}}}, false},
+		// The u64 overlaps the string pointer on 64-bit targets only
+		{"tuple<u32, u64>, tuple<u32, string, u32>", []wit.Type{tuple64, tupleString}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got := variantLayout(tt.types)
+			if got != tt.split {
+				t.Errorf("variantLayout(%s): split %t, expected %t", tt.name, got, tt.split)
+			}
+		})
+	}
+}
+
+func TestGCWords(t *testing.T) {
+	tuple64 := &wit.TypeDef{Kind: &wit.Tuple{Types: []wit.Type{wit.U32{}, wit.U64{}}}}
+	tupleString := &wit.TypeDef{Kind: &wit.Tuple{Types: []wit.Type{wit.U32{}, wit.String{}, wit.U32{}}}}
+	optionString := &wit.TypeDef{Kind: &wit.Option{Type: wit.String{}}}
+	tests := []struct {
+		name    string
+		t       wit.Type
+		ptrSize uintptr
+		want    []gcWord
+	}{
+		{"string", wit.String{}, 4, []gcWord{gcPointer, gcScalar}},
+		{"string", wit.String{}, 8, []gcWord{gcPointer, gcScalar}},
+		{"tuple<u32, u64>", tuple64, 4, []gcWord{gcScalar, gcNone, gcScalar, gcScalar}},
+		{"tuple<u32, u64>", tuple64, 8, []gcWord{gcScalar, gcScalar}},
+		{"tuple<u32, string, u32>", tupleString, 4, []gcWord{gcScalar, gcPointer, gcScalar, gcScalar}},
+		{"tuple<u32, string, u32>", tupleString, 8, []gcWord{gcScalar, gcPointer, gcScalar, gcScalar}},
+		{"option<string>", optionString, 4, []gcWord{gcScalar, gcPointer, gcScalar}},
+		{"option<string>", optionString, 8, []gcWord{gcScalar, gcPointer, gcScalar}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gcWords(tt.t, tt.ptrSize)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("gcWords(%s, %d): %v, expected %v", tt.name, tt.ptrSize, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitStorage(t *testing.T) {
+	tests := []struct {
+		name string
+		t    wit.Type
+		want bool
+	}{
+		{"u32", wit.U32{}, false},
+		{"result<string, u64>", &wit.TypeDef{Kind: &wit.Result{OK: wit.String{}, Err: wit.U64{}}}, true},
+		{"result<string, list<u8>>", &wit.TypeDef{Kind: &wit.Result{OK: wit.String{}, Err: &wit.TypeDef{Kind: &wit.List{Type: wit.U8{}}}}}, false},
+		{"variant { a(string), b(u32) }", &wit.TypeDef{Kind: &wit.Variant{Cases: []wit.Case{{Name: "a", Type: wit.String{}}, {Name: "b", Type: wit.U32{}}}}}, true},
+		{"option<string>", &wit.TypeDef{Kind: &wit.Option{Type: wit.String{}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitStorage(tt.t)
+			if got != tt.want {
+				t.Errorf("SplitStorage(%s): %t, expected %t", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeedsABI(t *testing.T) {
+	split := &wit.TypeDef{Kind: &wit.Result{OK: wit.String{}, Err: wit.U64{}}}
+	tests := []struct {
+		name string
+		t    wit.Type
+		want bool
+	}{
+		{"string", wit.String{}, false},
+		{"result<string, list<u8>>", &wit.TypeDef{Kind: &wit.Result{OK: wit.String{}, Err: &wit.TypeDef{Kind: &wit.List{Type: wit.U8{}}}}}, false},
+		{"result<string, u64>", split, true},
+		{"list<result<string, u64>>", &wit.TypeDef{Kind: &wit.List{Type: split}}, true},
+		{"option<result<string, u64>>", &wit.TypeDef{Kind: &wit.Option{Type: split}}, true},
+		{"tuple<u8, result<string, u64>>", &wit.TypeDef{Kind: &wit.Tuple{Types: []wit.Type{wit.U8{}, split}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := needsABI(tt.t)
+			if got != tt.want {
+				t.Errorf("needsABI(%s): %t, expected %t", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package bindgen
+
+import (
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+const asyncVTablesWIT = `package example:async;
+
+interface a {
+	recv: func() -> stream<u8>;
+	send: func(s: stream<u8>);
+}
+
+interface b {
+	pipe: func(s: option<stream<u8>>) -> option<stream<u8>>;
+}
+
+world w {
+	import a;
+	import b;
+	export b;
+}
+`
+
+// generatedContent generates Go bindings for res with opts and returns the content of each file,
+// keyed by its path relative to the package root.
+func generatedContent(t *testing.T, res *wit.Resolve, opts ...Option) map[string]string {
+	t.Helper()
+	pkgs, err := Go(res, append([]Option{GeneratedBy("test"), PackageRoot("example.com")}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := make(map[string]string)
+	for _, pkg := range pkgs {
+		for name, file := range pkg.Files {
+			b, err := file.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			content[strings.TrimPrefix(pkg.Path, "example.com/")+"/"+name] = string(b)
+		}
+	}
+	return content
+}
+
+func TestAsyncVTables(t *testing.T) {
+	res, err := wit.DecodeWIT(strings.NewReader(asyncVTablesWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := generatedContent(t, res)
+
+	// Streams of the same type in different import modules have separate vtables,
+	// and a stream type is declared once for each import module in a Go package.
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"example/async/a/a.wasm.go", []string{
+			"//go:wasmimport example:async/a [stream-new-0]recv\n",
+		}},
+		{"example/async/a/a.wit.go", []string{
+			"cm.BindStream(result, &_RecvStream0VTable)\n",
+			"func NewSendStream0() (cm.StreamWriter[uint8], cm.Stream[uint8]) {\n\treturn cm.NewStream[uint8](&_RecvStream0VTable)\n}\n",
+		}},
+		{"example/async/b/b.wasm.go", []string{
+			"//go:wasmimport example:async/b [stream-new-0]pipe\n",
+			"//go:wasmimport [export]example:async/b [stream-new-0]pipe\n",
+			"if v := s.Some(); v != nil {\n\t\tcm.BindStream(*v, &_ExportPipeStream0VTable)\n\t}\n",
+		}},
+		{"example/async/b/b.wit.go", []string{
+			"if v := result.Some(); v != nil {\n\t\tcm.BindStream(*v, &_PipeStream0VTable)\n\t}\n",
+			"func NewPipeStream0() (cm.StreamWriter[uint8], cm.Stream[uint8]) {\n\treturn cm.NewStream[uint8](&_PipeStream0VTable)\n}\n",
+			"func NewPipeStream1() (cm.StreamWriter[uint8], cm.Stream[uint8]) {\n\treturn cm.NewStream[uint8](&_ExportPipeStream0VTable)\n}\n",
+		}},
+	}
+	for _, tt := range tests {
+		got, ok := content[tt.file]
+		if !ok {
+			t.Errorf("%s: file not generated", tt.file)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: missing %q", tt.file, want)
+			}
+		}
+	}
+	if got := strings.Count(content["example/async/a/a.wasm.go"], "[stream-new-"); got != 1 {
+		t.Errorf("a.wasm.go: %d stream.new imports, expected 1", got)
+	}
+
+	validateGeneratedGo(t, res, "/async-vtables")
+}
+
+const asyncLowerWIT = `package example:async;
+
+interface a {
+	fetch: async func(url: string) -> result<list<u8>, string>;
+	many: async func(a: u32, b: u64, c: string, d: f32) -> tuple<u32, u64>;
+	sync: func(url: string) -> result<list<u8>, string>;
+}
+
+world w {
+	import a;
+}
+`
+
+// TestAsyncLower checks that params and results passed by pointer to an async-lowered import
+// are allocated on the heap and kept alive until the subtask returns, because the callee
+// accesses them while the calling goroutine is blocked and its stack may move.
+func TestAsyncLower(t *testing.T) {
+	res, err := wit.DecodeWIT(strings.NewReader(asyncLowerWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := generatedContent(t, res)
+	witFile := content["example/async/a/a.wit.go"]
+	wasmFile := content["example/async/a/a.wasm.go"]
+
+	for _, want := range []string{
+		"\tresult_ := new(cm.Result[cm.List[uint8], cm.List[uint8], string])\n" +
+			"\tstatus := wasmimport_Fetch((*uint8)(url0), (uint32)(url1), result_)\n" +
+			"\tcm.AwaitSubtask(status)\n" +
+			"\truntime.KeepAlive(result_)\n",
+		"\tparams := &wasmimport_Many_params{a: a, b: b, c: c, d: d}\n" +
+			"\tresult_ := new(cm.Tuple[uint32, uint64])\n" +
+			"\tstatus := wasmimport_Many(params, result_)\n" +
+			"\tcm.AwaitSubtask(status)\n" +
+			"\truntime.KeepAlive(params)\n" +
+			"\truntime.KeepAlive(result_)\n",
+		"wasmimport_Sync((*uint8)(url0), (uint32)(url1), &result)\n",
+	} {
+		if !strings.Contains(witFile, want) {
+			t.Errorf("a.wit.go: missing %q", want)
+		}
+	}
+	for _, want := range []string{
+		"//go:wasmimport example:async/a [async-lower]fetch\nfunc ",
+		"//go:wasmimport example:async/a [async-lower]many\nfunc ",
+		"//go:wasmimport example:async/a sync\n//go:noescape\nfunc ",
+	} {
+		if !strings.Contains(wasmFile, want) {
+			t.Errorf("a.wasm.go: missing %q", want)
+		}
+	}
+
+	validateGeneratedGo(t, res, "/async-lower")
+}
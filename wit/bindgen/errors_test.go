@@ -0,0 +1,60 @@
+package bindgen
+
+import (
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+const funcAdaptersWIT = `package example:errors;
+
+interface api {
+	enum code { invalid, failed }
+	parse: func(s: string) -> result<u32, string>;
+	check: func() -> result<_, code>;
+	count: func() -> result<u32>;
+}
+
+world w {
+	export api;
+}
+`
+
+func TestFuncAdapters(t *testing.T) {
+	res, err := wit.DecodeWIT(strings.NewReader(funcAdaptersWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, GeneratedBy("test"), PackageRoot("example.com"), IdiomaticErrors(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	for _, pkg := range pkgs {
+		if pkg.Path == "example.com/example/errors/api" {
+			b, err := pkg.Files["api.wit.go"].Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = string(b)
+		}
+	}
+
+	// Adapters convert errors into a string result with their message,
+	// and require a mapper for any other error type.
+	for _, want := range []string{
+		"func ParseFunc(f func(s string) (uint32, error)) func(s string) (result cm.Result[cm.SplitResult[uint32, string], uint32, string]) {\n",
+		"return cm.ResultFromFunc[cm.Result[cm.SplitResult[uint32, string], uint32, string]](v, err, error.Error)\n",
+		"func CheckFunc(f func() error, mapErr func(error) Code) func() (result cm.Result[Code, struct{}, Code]) {\n",
+		"return cm.ResultFromFunc[cm.Result[Code, struct{}, Code]](struct{}{}, err, mapErr)\n",
+		"func CountFunc(f func() (uint32, error)) func() (result cm.Result[uint32, uint32, struct{}]) {\n",
+		"return cm.ResultFromFunc[cm.Result[uint32, uint32, struct{}]](v, err, func(error) struct{} { return struct{}{} })\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("api.wit.go: missing %q", want)
+		}
+	}
+
+	validateGeneratedGo(t, res, "/func-adapters", IdiomaticErrors(true))
+}
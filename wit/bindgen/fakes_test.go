@@ -0,0 +1,70 @@
+package bindgen
+
+import (
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+const fakesWIT = `package example:fakes;
+
+interface i {
+	record fake {
+		a: u32,
+	}
+	record fake-handles {
+		b: u32,
+	}
+	resource r {
+		get: func() -> fake;
+	}
+	f: func(h: fake-handles);
+}
+
+world w {
+	import i;
+}
+`
+
+func TestFakes(t *testing.T) {
+	res, err := wit.DecodeWIT(strings.NewReader(fakesWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the Fakes option, no fakes are generated, and WIT names are not reserved.
+	content := generatedContent(t, res)
+	if _, ok := content["example/fakes/i/i.fake.go"]; ok {
+		t.Error("i.fake.go generated without the Fakes option")
+	}
+	for _, want := range []string{"type Fake struct {", "type FakeHandles struct {"} {
+		if !strings.Contains(content["example/fakes/i/i.wit.go"], want) {
+			t.Errorf("i.wit.go does not contain %q", want)
+		}
+	}
+	if strings.Contains(content["example/fakes/i/i.wasm.go"], "//go:build") {
+		t.Error("i.wasm.go has a build constraint without the Fakes option")
+	}
+
+	// With the Fakes option, the fake resource handles use cm.FakeHandles.
+	content = generatedContent(t, res, Fakes(true))
+	fake := content["example/fakes/i/i.fake.go"]
+	for _, want := range []string{
+		"//go:build !wasm\n",
+		"var Fake struct {",
+		"Handles cm.FakeHandles[R, any]\n",
+	} {
+		if !strings.Contains(fake, want) {
+			t.Errorf("i.fake.go does not contain %q:\n%s", want, fake)
+		}
+	}
+	if strings.Contains(fake, "type FakeHandles") {
+		t.Errorf("i.fake.go declares FakeHandles:\n%s", fake)
+	}
+	if !strings.Contains(content["example/fakes/i/i.wasm.go"], "//go:build wasm\n") {
+		t.Error("i.wasm.go is not constrained to wasm with the Fakes option")
+	}
+
+	validateGeneratedGo(t, res, "/fakes-names", Fakes(true))
+}
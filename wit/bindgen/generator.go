@@ -10,22 +10,18 @@ import (
 	"fmt"
 	"go/token"
 	"io"
-	"io/fs"
 	"path"
 	"path/filepath"
 	"runtime"
 	"slices"
 	"strconv"
 	"strings"
-	"testing/fstest"
 	"time"
 
-	"github.com/tetratelabs/wazero/sys"
 	"go.bytecodealliance.org/cm"
 	"go.bytecodealliance.org/internal/codec"
 	"go.bytecodealliance.org/internal/go/gen"
 	"go.bytecodealliance.org/internal/stringio"
-	"go.bytecodealliance.org/internal/wasm"
 	"go.bytecodealliance.org/internal/wasmtools"
 	"go.bytecodealliance.org/wit"
 	"go.bytecodealliance.org/wit/logging"
@@ -89,10 +85,24 @@ type typeUse struct {
 	typ *wit.TypeDef
 }
 
+// asyncModuleType identifies a stream or future type imported from a Wasm module into a Go package.
+// The Canonical ABI functions for each stream or future type are imported separately for each module.
+type asyncModuleType struct {
+	pkg    *gen.Package
+	module string
+	typ    *wit.TypeDef
+}
+
 type generator struct {
-	opts  options
-	res   *wit.Resolve
-	world *wit.World // optional, can be nil
+	opts options
+	res  *wit.Resolve
+
+	// worlds are the WIT worlds to generate.
+	worlds []*wit.World
+
+	// interfaceWorlds map each interface to the first world that imports or exports it.
+	// It is indexed on wit.Direction, either Imported or Exported.
+	interfaceWorlds [2]map[*wit.Interface]*wit.World
 
 	// versioned is set to true if there are multiple versions of a WIT package in res,
 	// which affects the generated Go package paths.
@@ -101,12 +111,27 @@ type generator struct {
 	// packages are Go packages indexed on Go package paths.
 	packages map[string]*gen.Package
 
+	// packageWorlds map Go package paths to the world that owns each Go package,
+	// or nil for packages of named interfaces, which are shared by all worlds.
+	packageWorlds map[string]*wit.World
+
 	// witPackages map wit.TypeOwner (World, Interface) to Go packages.
 	witPackages map[wit.TypeOwner]*gen.Package
 
 	// exportScopes map wit.TypeOwner to export scopes.
 	exportScopes map[wit.TypeOwner]gen.Scope
 
+	// fakeResources map resource types to their hooks in the fake file.
+	fakeResources map[*wit.TypeDef]*fakeResource
+
+	// exportInterfaces map wit.TypeOwner to Go interfaces for exports.
+	// Only used if the exportInterfaces option is set.
+	exportInterfaces map[wit.TypeOwner]*exportsInterface
+
+	// resourceTables map exported resource types to their Go value interface and table.
+	// Only used if the resourceTables option is set.
+	resourceTables map[*wit.TypeDef]*resourceTable
+
 	// moduleNames map wit.TypeOwner to the wasmimport/wasmexport module names.
 	moduleNames map[wit.TypeOwner]string
 
@@ -129,23 +154,45 @@ type generator struct {
 	lowerFunctions map[typeUse]function
 	liftFunctions  map[typeUse]function
 
+	// Go types with the Canonical ABI memory layout for types that need conversion,
+	// their variant and result shapes, and their conversion functions.
+	// See [needsABI] for more information.
+	abiTypes         map[typeUse]string
+	abiShapes        map[typeUse]string
+	toABIFunctions   map[typeUse]string
+	fromABIFunctions map[typeUse]string
+
+	// asyncVTables records the Go variable name of the [cm.StreamVTable] or [cm.FutureVTable]
+	// for each stream or future type imported from a Wasm module into a Go package.
+	asyncVTables map[asyncModuleType]string
+
 	wasmTools *wasmtools.Instance
 }
 
 func newGenerator(res *wit.Resolve, opts ...Option) (*generator, error) {
 	g := &generator{
-		packages:       make(map[string]*gen.Package),
-		witPackages:    make(map[wit.TypeOwner]*gen.Package),
-		exportScopes:   make(map[wit.TypeOwner]gen.Scope),
-		moduleNames:    make(map[wit.TypeOwner]string),
-		shapes:         make(map[typeUse]string),
-		lowerFunctions: make(map[typeUse]function),
-		liftFunctions:  make(map[typeUse]function),
+		packages:         make(map[string]*gen.Package),
+		packageWorlds:    make(map[string]*wit.World),
+		witPackages:      make(map[wit.TypeOwner]*gen.Package),
+		exportScopes:     make(map[wit.TypeOwner]gen.Scope),
+		exportInterfaces: make(map[wit.TypeOwner]*exportsInterface),
+		fakeResources:    make(map[*wit.TypeDef]*fakeResource),
+		resourceTables:   make(map[*wit.TypeDef]*resourceTable),
+		moduleNames:      make(map[wit.TypeOwner]string),
+		shapes:           make(map[typeUse]string),
+		lowerFunctions:   make(map[typeUse]function),
+		liftFunctions:    make(map[typeUse]function),
+		abiTypes:         make(map[typeUse]string),
+		abiShapes:        make(map[typeUse]string),
+		toABIFunctions:   make(map[typeUse]string),
+		fromABIFunctions: make(map[typeUse]string),
+		asyncVTables:     make(map[asyncModuleType]string),
 	}
 	for i := 0; i < 2; i++ {
 		g.types[i] = make(map[*wit.TypeDef]*typeDecl)
 		g.functions[i] = make(map[*wit.Function]*funcDecl)
 		g.defined[i] = make(map[wit.Node]bool)
+		g.interfaceWorlds[i] = make(map[*wit.Interface]*wit.World)
 	}
 	err := g.opts.apply(opts...)
 	if err != nil {
@@ -162,11 +209,26 @@ func newGenerator(res *wit.Resolve, opts ...Option) (*generator, error) {
 		g.opts.cmPackage = cmPackage
 	}
 	g.res = res
-	for _, g.world = range res.Worlds {
-		if g.world.Match(g.opts.world) {
-			break
+	if g.opts.allWorlds {
+		for _, w := range res.Worlds {
+			if matchWorld(w, g.opts.worldPatterns) {
+				g.worlds = append(g.worlds, w)
+			}
+		}
+		if len(g.worlds) == 0 && len(res.Worlds) != 0 {
+			return nil, fmt.Errorf("no worlds match %s", strings.Join(g.opts.worldPatterns, ", "))
+		}
+	} else {
+		var world *wit.World
+		for _, world = range res.Worlds {
+			if world.Match(g.opts.world) {
+				break
+			}
+			// otherwise chose the last world
+		}
+		if world != nil {
+			g.worlds = []*wit.World{world}
 		}
-		// otherwise chose the last world
 	}
 	g.wasmTools, err = wasmtools.New(context.Background())
 	if err != nil {
@@ -175,6 +237,28 @@ func newGenerator(res *wit.Resolve, opts ...Option) (*generator, error) {
 	return g, nil
 }
 
+// matchWorld returns true if [wit.World] w matches any of patterns, or if patterns is empty.
+// A pattern matches a world name (e.g. "command") or a fully-qualified world name with
+// or without a version (e.g. "wasi:cli/command@0.2.0"), using the syntax of [path.Match].
+func matchWorld(w *wit.World, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	id := w.Package.Name
+	id.Extension = w.Name
+	names := []string{w.Name, id.String()}
+	id.Version = nil
+	names = append(names, id.String())
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // TODO: factor this out
 func findWorld(r *wit.Resolve, pattern string) *wit.World {
 	for _, w := range r.Worlds {
@@ -187,7 +271,12 @@ func findWorld(r *wit.Resolve, pattern string) *wit.World {
 
 func (g *generator) generate() ([]*gen.Package, error) {
 	g.detectVersionedPackages()
-	err := g.defineWorlds()
+	var err error
+	if g.opts.host {
+		err = g.defineHostWorlds()
+	} else {
+		err = g.defineWorlds()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -234,13 +323,11 @@ func (g *generator) define(dir wit.Direction, v wit.Node) (defined bool) {
 // Options might override the Go package, including combining multiple
 // WIT interfaces and/or worlds into a single Go package.
 func (g *generator) defineWorlds() error {
-	g.opts.logger.Infof("Generating Go for %d world(s)\n", len(g.res.Worlds))
-	for _, w := range g.res.Worlds {
-		if w == g.world || g.world == nil {
-			err := g.defineWorld(w)
-			if err != nil {
-				return err
-			}
+	g.opts.logger.Infof("Generating Go for %d world(s)\n", len(g.worlds))
+	for _, w := range g.worlds {
+		err := g.defineWorld(w)
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -307,11 +394,28 @@ func (g *generator) defineWorld(w *wit.World) error {
 		}
 		return err == nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	g.defineSetExports(w)
+	return nil
 }
 
 func (g *generator) defineInterface(w *wit.World, dir wit.Direction, i *wit.Interface, name string) error {
+	// The Go package for an interface contains its imported and exported bindings.
+	// An interface imported by one world and exported by another would export functions
+	// from components of a world that imports it.
+	if other := g.interfaceWorlds[^dir&1][i]; other != nil && other != w && i.Name != nil {
+		id := i.Package.Name
+		id.Extension = *i.Name
+		return fmt.Errorf("interface %s is %s by world %s and %s by world %s: generate these worlds separately",
+			id.String(), dir, w.Name, ^dir&1, other.Name)
+	}
+	if g.interfaceWorlds[dir][i] == nil {
+		g.interfaceWorlds[dir][i] = w
+	}
+
 	if !g.define(dir, i) {
 		return nil
 	}
@@ -373,6 +477,10 @@ func (g *generator) defineInterface(w *wit.World, dir wit.Direction, i *wit.Inte
 		return true
 	})
 
+	if dir == wit.Exported {
+		g.defineSetExports(i)
+	}
+
 	return nil
 }
 
@@ -426,6 +534,20 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 		goName := scope.GetName(GoName(*t.Name, true))
 		stringio.Write(exportsFile, "\n// ", goName, " represents the caller-defined exports for ", t.WITKind(), " \"", g.moduleNames[t.Owner], "#", name, "\".\n")
 		stringio.Write(exportsFile, goName, " struct {")
+		if ei := g.exportsInterfaceFor(t.Owner); ei != nil {
+			ei.beginResource(exportsFile, t, goName, g.moduleNames[t.Owner]+"#"+name)
+		}
+		if g.opts.resourceTables {
+			g.resourceTables[t] = &resourceTable{
+				value: decl.file.DeclareName(decl.name + "Value"),
+				table: decl.file.DeclareName(decl.name + "Table"),
+			}
+		}
+	}
+
+	// Emit resource hooks in fake file.
+	if g.opts.fakes {
+		g.beginFakeResource(dir, t, decl.name, g.moduleNames[t.Owner]+"#"+name)
 	}
 
 	// Define any associated functions
@@ -436,6 +558,9 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 			if err != nil {
 				return nil
 			}
+			if g.opts.ownedResources {
+				g.defineOwned(decl, t)
+			}
 		}
 
 	case wit.Exported:
@@ -444,6 +569,9 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 			if err != nil {
 				return nil
 			}
+			if rt := g.resourceTables[t]; rt != nil {
+				rt.resourceNew = g.functions[wit.Imported][f].goFunc.name
+			}
 		}
 
 		if f := t.ResourceRep(); f != nil {
@@ -492,15 +620,78 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 		}
 	}
 
+	if g.opts.fakes {
+		g.endFakeResource(dir, t, decl.name)
+	}
+
 	// End struct definition here.
 	if dir == wit.Exported {
 		exportsFile := g.exportsFileFor(t.Owner)
 		stringio.Write(exportsFile, "\n}\n")
+		if ei := g.exportsInterfaceFor(t.Owner); ei != nil {
+			ei.endResource()
+		}
+		if rt := g.resourceTables[t]; rt != nil {
+			g.defineResourceTable(decl.file, t, g.moduleNames[t.Owner]+"#"+name, rt)
+		}
 	}
 
 	return nil
 }
 
+// defineOwned emits a method on imported resource type t that wraps an owned handle in a cm.Owned.
+func (g *generator) defineOwned(decl *typeDecl, t *wit.TypeDef) {
+	file := decl.file
+	cm := file.Import(g.opts.cmPackage)
+	name := decl.scope.DeclareName("Owned")
+	var b bytes.Buffer
+	stringio.Write(&b, "// ", name, " wraps owned handle self to ", t.WITKind(), " \"", t.TypeName(), "\" in a [", cm, ".Owned].\n")
+	b.WriteString("// Its Close method drops the handle, after which the handle cannot be used or dropped again.\n")
+	b.WriteString("// Call AutoDrop to drop the handle if it becomes unreachable without being closed.\n")
+	stringio.Write(&b, "func (self ", decl.name, ") ", name, "() *", cm, ".Owned[", decl.name, "] {\n")
+	stringio.Write(&b, "return ", cm, ".NewOwned(self)\n")
+	b.WriteString("}\n\n")
+	file.Write(b.Bytes())
+}
+
+// defineResourceTable emits the Go value interface and [cm.ResourceTable] for exported resource t.
+func (g *generator) defineResourceTable(file *gen.File, t *wit.TypeDef, qualifiedName string, rt *resourceTable) {
+	cm := file.Import(g.opts.cmPackage)
+	var b bytes.Buffer
+	stringio.Write(&b, "// ", rt.value, " represents the Go value of exported ", t.WITKind(), " \"", qualifiedName, "\".\n")
+	stringio.Write(&b, "// The methods of ", t.WITKind(), " \"", t.TypeName(), "\" are called on the value stored in [", rt.table, "]\n")
+	b.WriteString("// for the rep of the resource handle. For example, a Go type T implements\n")
+	stringio.Write(&b, "// ", rt.value, " with methods on *T.\n")
+	stringio.Write(&b, "type ", rt.value, " interface {\n", rt.methods.String(), "}\n\n")
+	stringio.Write(&b, "// ", rt.table, " holds the Go values of exported ", t.WITKind(), " \"", qualifiedName, "\", indexed by rep.\n")
+	stringio.Write(&b, "// Create a resource handle for a value with ", rt.resourceNew, "(", rt.table, ".New(v)).\n")
+	b.WriteString("// The value is released before the caller-defined destructor is called.\n")
+	stringio.Write(&b, "var ", rt.table, " ", cm, ".ResourceTable[", rt.value, "]\n\n")
+	file.Write(b.Bytes())
+}
+
+// resourceTable represents the Go value interface and table for an exported resource.
+// Only used if the resourceTables option is set.
+type resourceTable struct {
+	value       string          // Go name of the value interface, e.g. WaterValue
+	table       string          // Go name of the package-scoped cm.ResourceTable variable
+	resourceNew string          // Go name of the imported resource-new function
+	methods     strings.Builder // methods of the value interface
+}
+
+// resourceTableFor returns the [resourceTable] for the exported resource method
+// (including the destructor) f, or nil if f is not called with a Go value.
+func (g *generator) resourceTableFor(f *wit.Function) *resourceTable {
+	if !f.IsMethod() {
+		return nil
+	}
+	t, ok := f.Type().(*wit.TypeDef)
+	if !ok {
+		return nil
+	}
+	return g.resourceTables[t]
+}
+
 func (g *generator) declareTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeDef, goName string) (*typeDecl, error) {
 	decl, ok := g.types[dir][t]
 	if ok {
@@ -670,8 +861,10 @@ func (g *generator) typeDefRep(file *gen.File, dir wit.Direction, t *wit.TypeDef
 	}
 }
 
+// pointerRep returns a pointer to the Go type with the Canonical ABI memory layout for p.Type,
+// as pointers represent values passed in memory between the host and guest.
 func (g *generator) pointerRep(file *gen.File, dir wit.Direction, p *wit.Pointer) string {
-	return "*" + g.typeRep(file, dir, p.Type)
+	return "*" + g.abiTypeRep(file, dir, p.Type)
 }
 
 func (g *generator) typeRep(file *gen.File, dir wit.Direction, t wit.Type) string {
@@ -731,6 +924,17 @@ func (g *generator) primitiveRep(p wit.Primitive) string {
 }
 
 func (g *generator) recordRep(file *gen.File, dir wit.Direction, r *wit.Record, goName string) string {
+	return g.structRep(file, dir, r, goName, g.typeRep)
+}
+
+// compoundRep returns the Go struct type for anonymous record t, which represents the
+// flattened params or results of a function passed in memory, with the Canonical ABI memory layout.
+func (g *generator) compoundRep(file *gen.File, dir wit.Direction, t *wit.TypeDef, goName string) string {
+	return g.structRep(file, dir, t.Kind.(*wit.Record), goName, g.abiTypeRep)
+}
+
+// structRep returns a Go struct type for record r, with field types returned by typeRep.
+func (g *generator) structRep(file *gen.File, dir wit.Direction, r *wit.Record, goName string, typeRep func(*gen.File, wit.Direction, wit.Type) string) string {
 	exported := len(goName) == 0 || token.IsExported(goName)
 	var b strings.Builder
 	b.WriteString("struct {\n")
@@ -740,7 +944,7 @@ func (g *generator) recordRep(file *gen.File, dir wit.Direction, r *wit.Record,
 			b.WriteRune('\n')
 		}
 		b.WriteString(formatDocComments(f.Docs.Contents, false))
-		stringio.Write(&b, fieldName(f.Name, exported), " ", g.typeRep(file, dir, f.Type), " `json:\"", f.Name, "\"`\n")
+		stringio.Write(&b, fieldName(f.Name, exported), " ", typeRep(file, dir, f.Type), " `json:\"", f.Name, "\"`\n")
 	}
 	b.WriteRune('}')
 	return b.String()
@@ -816,7 +1020,24 @@ func (g *generator) flagsRep(file *gen.File, dir wit.Direction, flags *wit.Flags
 		}
 		b.WriteRune('\n')
 	}
-	b.WriteString(")\n")
+	b.WriteString(")\n\n")
+
+	stringsName := file.DeclareName("_" + GoName(goName, true) + "Strings")
+	stringio.Write(&b, "var ", stringsName, " = [", fmt.Sprintf("%d", len(flags.Flags)), "]string {\n")
+	for _, flag := range flags.Flags {
+		stringio.Write(&b, `"`, flag.Name, `"`, ",\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(formatDocComments("MarshalJSON implements [json.Marshaler], returning a JSON array of the names of the flags set in f.", true))
+	stringio.Write(&b, "func (f ", goName, ") MarshalJSON() ([]byte, error) {\n")
+	stringio.Write(&b, "return ", file.Import(g.opts.cmPackage), ".MarshalFlagsJSON(", stringsName, "[:], f)\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(formatDocComments("UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON array of flag names.", true))
+	stringio.Write(&b, "func (f *", goName, ") UnmarshalJSON(data []byte) error {\n")
+	stringio.Write(&b, "return ", file.Import(g.opts.cmPackage), ".UnmarshalFlagsJSON(", stringsName, "[:], f, data)\n")
+	b.WriteString("}\n")
 	return b.String()
 }
 
@@ -881,18 +1102,23 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, t *wit.TypeDef
 
 	disc := wit.Discriminant(len(v.Cases))
 	types := v.Types()
-	shape := variantShape(types)
+	shape, split := variantLayout(types)
 	align := variantAlign(types)
 
 	var typeShape string
-	if len(types) == 1 {
+	switch {
+	case split:
+		typeShape = g.splitShape(file, dir, v, goName)
+	case len(types) == 1 || wit.HasPointer(shape):
 		typeShape = g.typeRep(file, dir, shape)
-	} else {
+	default:
 		typeShape = g.typeShape(file, dir, shape)
 	}
 
 	decl, _ := g.typeDecl(dir, t)
 	scope := decl.scope
+	scope.DeclareName("MarshalJSON")
+	scope.DeclareName("UnmarshalJSON")
 
 	// Emit type
 	var b strings.Builder
@@ -900,10 +1126,13 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, t *wit.TypeDef
 	stringio.Write(&b, cm, ".Variant[", g.typeRep(file, dir, disc), ", ", typeShape, ", ", g.typeRep(file, dir, align), "]\n\n")
 
 	// Emit cases
+	caseNames := make([]string, len(v.Cases))
+	constructorNames := make([]string, len(v.Cases))
 	for i, c := range v.Cases {
 		caseNum := strconv.Itoa(i)
 		caseName := scope.DeclareName(GoName(c.Name, true))
 		constructorName := file.DeclareName(goName + caseName)
+		caseNames[i], constructorNames[i] = caseName, constructorName
 		typeRep := g.typeRep(file, dir, c.Type)
 
 		// Emit constructor
@@ -919,7 +1148,7 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, t *wit.TypeDef
 		if c.Type == nil {
 			stringio.Write(&b, "var ", dataName, " ", typeRep, "\n")
 		}
-		stringio.Write(&b, "return ", g.cmCall(file, "New["+goName+"]", caseNum+", "+dataName), "\n")
+		stringio.Write(&b, "return ", g.cmCall(file, variantFunc("New", split)+"["+goName+"]", caseNum+", "+dataName), "\n")
 		b.WriteString("}\n\n")
 
 		// Emit getter
@@ -933,7 +1162,7 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, t *wit.TypeDef
 			// Case with associated type T returns *T
 			stringio.Write(&b, "// ", caseName, " returns a non-nil *[", typeRep, "] if [", goName, "] represents the variant case \"", c.Name, "\".\n")
 			stringio.Write(&b, "func (self *", goName, ") ", caseName, "() *", typeRep, " {\n")
-			stringio.Write(&b, "return ", g.cmCall(file, "Case["+typeRep+"]", "self, "+caseNum))
+			stringio.Write(&b, "return ", g.cmCall(file, variantFunc("Case", split)+"["+typeRep+"]", "self, "+caseNum))
 			b.WriteString("}\n\n")
 		}
 	}
@@ -950,15 +1179,55 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, t *wit.TypeDef
 	stringio.Write(&b, "return ", stringsName, "[v.Tag()]\n")
 	b.WriteString("}\n\n")
 
+	// Emit JSON methods
+	b.WriteString(formatDocComments("MarshalJSON implements [json.Marshaler], returning a JSON object with a single key for the variant case name.", true))
+	stringio.Write(&b, "func (v ", goName, ") MarshalJSON() ([]byte, error) {\n")
+	b.WriteString("var data any\n")
+	b.WriteString("switch v.Tag() {\n")
+	for i, c := range v.Cases {
+		if c.Type == nil {
+			continue
+		}
+		stringio.Write(&b, "case ", strconv.Itoa(i), ":\n")
+		stringio.Write(&b, "data = v.", caseNames[i], "()\n")
+	}
+	b.WriteString("}\n")
+	stringio.Write(&b, "return ", cm, ".MarshalVariantJSON(", stringsName, "[:], v.Tag(), data)\n")
+	b.WriteString("}\n\n")
+
+	b.WriteString(formatDocComments("UnmarshalJSON implements [json.Unmarshaler], unmarshaling a JSON object with a single key for the variant case name.", true))
+	stringio.Write(&b, "func (v *", goName, ") UnmarshalJSON(data []byte) error {\n")
+	stringio.Write(&b, "tag, value, err := ", cm, ".UnmarshalVariantJSON(", stringsName, "[:], data)\n")
+	b.WriteString("if err != nil {\n")
+	b.WriteString("return err\n")
+	b.WriteString("}\n")
+	b.WriteString("switch tag {\n")
+	for i, c := range v.Cases {
+		stringio.Write(&b, "case ", strconv.Itoa(i), ":\n")
+		if c.Type == nil {
+			stringio.Write(&b, "*v = ", constructorNames[i], "()\n")
+			continue
+		}
+		stringio.Write(&b, "var payload ", g.typeRep(file, dir, c.Type), "\n")
+		stringio.Write(&b, "err = ", file.Import("encoding/json"), ".Unmarshal(value, &payload)\n")
+		stringio.Write(&b, "*v = ", constructorNames[i], "(payload)\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString("return err\n")
+	b.WriteString("}\n\n")
+
 	return b.String()
 }
 
 func (g *generator) resultRep(file *gen.File, dir wit.Direction, r *wit.Result) string {
 	var typeShape string
-	shape := variantShape(r.Types())
-	if len(r.Types()) == 1 {
+	shape, split := variantLayout(r.Types())
+	switch {
+	case split:
+		typeShape = file.Import(g.opts.cmPackage) + ".SplitResult[" + g.typeRep(file, dir, r.OK) + ", " + g.typeRep(file, dir, r.Err) + "]"
+	case len(r.Types()) == 1 || shape != nil && wit.HasPointer(shape):
 		typeShape = g.typeRep(file, dir, shape)
-	} else {
+	default:
 		typeShape = g.typeShape(file, dir, shape)
 	}
 
@@ -1073,6 +1342,262 @@ func (g *generator) typeDefShape(file *gen.File, dir wit.Direction, t *wit.TypeD
 	return name
 }
 
+// variantFunc returns the name of package cm function f ("New" or "Case"), which creates
+// or accesses a variant case, or its equivalent for a variant with split storage.
+func variantFunc(f string, split bool) string {
+	if !split {
+		return f
+	}
+	if f == "New" {
+		return "NewSplit"
+	}
+	return "Split" + f
+}
+
+// splitShape returns the name of a Go struct type used for storage in variant v,
+// with a separate field for each associated type, which implements [cm.SplitShape].
+func (g *generator) splitShape(file *gen.File, dir wit.Direction, v *wit.Variant, goName string) string {
+	abiFile := g.abiFile(file.Package)
+	name := abiFile.DeclareName(goName + "Shape")
+	fields := make(map[wit.Type]string)
+	var b bytes.Buffer
+	stringio.Write(&b, "// ", name, " is used for storage in variant type [", goName, "].\n")
+	stringio.Write(&b, "// Each associated type is stored in a separate field, so pointer and non-pointer values do not share memory.\n")
+	stringio.Write(&b, "type ", name, " struct {\n")
+	stringio.Write(&b, "_ ", abiFile.Import(g.opts.cmPackage), ".HostLayout\n")
+	for i, c := range v.Cases {
+		if c.Type == nil || fields[c.Type] != "" {
+			continue
+		}
+		fields[c.Type] = "f" + strconv.Itoa(i)
+		stringio.Write(&b, fields[c.Type], " ", g.typeRep(abiFile, dir, c.Type), "\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// CaseOffset implements [cm.SplitShape].\n")
+	stringio.Write(&b, "func (s *", name, ") CaseOffset(tag uint32) uintptr {\n")
+	b.WriteString("switch tag {\n")
+	for i, c := range v.Cases {
+		if c.Type == nil {
+			continue
+		}
+		stringio.Write(&b, "case ", strconv.Itoa(i), ":\n")
+		stringio.Write(&b, "return ", abiFile.Import("unsafe"), ".Offsetof(s.", fields[c.Type], ")\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString("return 0\n")
+	b.WriteString("}\n\n")
+	abiFile.Write(b.Bytes())
+	return name
+}
+
+// abiTypeRep returns the Go type with the Canonical ABI memory layout for t.
+// It returns the same type as typeRep unless t needs conversion. See [needsABI] for more information.
+func (g *generator) abiTypeRep(file *gen.File, dir wit.Direction, t wit.Type) string {
+	td, ok := t.(*wit.TypeDef)
+	if !ok || !needsABI(t) {
+		return g.typeRep(file, dir, t)
+	}
+	cm := file.Import(g.opts.cmPackage)
+	switch kind := td.Kind.(type) {
+	case wit.Type:
+		return g.abiTypeRep(file, dir, kind)
+	case *wit.Record:
+		if td.Name == nil {
+			// Anonymous records represent flattened function params or results,
+			// which are declared with the Canonical ABI memory layout.
+			return g.typeRep(file, dir, td)
+		}
+		return g.abiType(file, dir, td)
+	case *wit.Tuple:
+		if mono := kind.Type(); mono != nil {
+			return "[" + strconv.Itoa(len(kind.Types)) + "]" + g.abiTypeRep(file, dir, mono)
+		}
+		return g.abiType(file, dir, td)
+	case *wit.Variant:
+		types := kind.Types()
+		disc := wit.Discriminant(len(kind.Cases))
+		return cm + ".Variant[" + g.typeRep(file, dir, disc) + ", " + g.abiShape(file, dir, td, types) + ", " + g.abiTypeRep(file, dir, variantAlign(types)) + "]"
+	case *wit.Result:
+		return cm + ".Result[" + g.abiShape(file, dir, td, kind.Types()) + ", " + g.abiTypeRep(file, dir, kind.OK) + ", " + g.abiTypeRep(file, dir, kind.Err) + "]"
+	case *wit.Option:
+		return cm + ".Option[" + g.abiTypeRep(file, dir, kind.Type) + "]"
+	case *wit.List:
+		return cm + ".List[" + g.abiTypeRep(file, dir, kind.Type) + "]"
+	default:
+		panic(fmt.Sprintf("BUG: unexpected wit.TypeDef %T with Canonical ABI conversion", kind)) // should never reach here
+	}
+}
+
+// abiType returns the name of a Go struct type with the Canonical ABI memory layout
+// for record or tuple t, declared in the ABI file of the package of file.
+func (g *generator) abiType(file *gen.File, dir wit.Direction, t *wit.TypeDef) string {
+	use := typeUse{file.Package, dir, t}
+	name, ok := g.abiTypes[use]
+	if !ok {
+		abiFile := g.abiFile(file.Package)
+		name = abiFile.DeclareName("abi_" + g.typeDefGoName(dir, t))
+		g.abiTypes[use] = name
+		r := wit.Despecialize(t.Kind).(*wit.Record)
+		var b bytes.Buffer
+		stringio.Write(&b, "// ", name, " represents ", g.typeDocName(abiFile, dir, t), " with the Canonical ABI memory layout.\n")
+		stringio.Write(&b, "type ", name, " ", g.structRep(abiFile, dir, r, name, g.abiTypeRep), "\n\n")
+		abiFile.Write(b.Bytes())
+	}
+	return name
+}
+
+// typeDocName returns a name for t for use in doc comments: a doc link to the declared Go type,
+// or the WIT type for anonymous types.
+func (g *generator) typeDocName(file *gen.File, dir wit.Direction, t *wit.TypeDef) string {
+	if _, ok := g.typeDecl(dir, t); ok {
+		return "[" + g.typeRep(file, dir, t) + "]"
+	}
+	return "type " + t.WIT(nil, "")
+}
+
+// abiShape returns the name of a Go type used for storage in variant or result t
+// with the Canonical ABI memory layout, sized to hold the largest of types.
+// It does not contain pointers.
+func (g *generator) abiShape(file *gen.File, dir wit.Direction, t *wit.TypeDef, types []wit.Type) string {
+	use := typeUse{file.Package, dir, t}
+	name, ok := g.abiShapes[use]
+	if !ok {
+		abiFile := g.abiFile(file.Package)
+		name = abiFile.DeclareName("abi_" + g.typeDefGoName(dir, t) + "Shape")
+		g.abiShapes[use] = name
+		var b bytes.Buffer
+		stringio.Write(&b, "// ", name, " is used for storage in ", g.typeDocName(abiFile, dir, t), " with the Canonical ABI memory layout.\n")
+		stringio.Write(&b, "type ", name, " struct {\n")
+		stringio.Write(&b, "_ ", abiFile.Import(g.opts.cmPackage), ".HostLayout\n")
+		b.WriteString("shape [max(")
+		for i, t := range types {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, abiFile.Import("unsafe"), ".Sizeof(*new(", g.abiTypeRep(abiFile, dir, t), "))")
+		}
+		b.WriteString(")]byte\n")
+		b.WriteString("}\n\n")
+		abiFile.Write(b.Bytes())
+	}
+	return name
+}
+
+// toABI returns an expression that converts input of type t into its Canonical ABI memory layout.
+// It returns input unmodified unless t needs conversion. See [needsABI] for more information.
+func (g *generator) toABI(file *gen.File, dir wit.Direction, t wit.Type, input string) string {
+	if !needsABI(t) {
+		return input
+	}
+	return g.abiFunction(file, dir, t.(*wit.TypeDef), true) + "(" + input + ")"
+}
+
+// fromABI returns an expression that converts input from the Canonical ABI memory layout of t into t.
+// It returns input unmodified unless t needs conversion. See [needsABI] for more information.
+func (g *generator) fromABI(file *gen.File, dir wit.Direction, t wit.Type, input string) string {
+	if !needsABI(t) {
+		return input
+	}
+	return g.abiFunction(file, dir, t.(*wit.TypeDef), false) + "(" + input + ")"
+}
+
+// abiFunction returns the name of a function that converts a value of type t to (toABI == true)
+// or from its Canonical ABI memory layout, declared in the ABI file of the package of file.
+func (g *generator) abiFunction(file *gen.File, dir wit.Direction, t *wit.TypeDef, toABI bool) string {
+	if kind, ok := t.Kind.(*wit.TypeDef); ok {
+		// Type aliases are converted by the function for the aliased type.
+		return g.abiFunction(file, dir, kind, toABI)
+	}
+	funcs, prefix, convert := g.fromABIFunctions, "fromABI_", g.fromABI
+	if toABI {
+		funcs, prefix, convert = g.toABIFunctions, "toABI_", g.toABI
+	}
+	use := typeUse{file.Package, dir, t}
+	if name, ok := funcs[use]; ok {
+		return name
+	}
+	abiFile := g.abiFile(file.Package)
+	name := abiFile.DeclareName(prefix + g.typeDefGoName(dir, t))
+	funcs[use] = name
+
+	cm := abiFile.Import(g.opts.cmPackage)
+	goType, abiType := g.typeRep(abiFile, dir, t), g.abiTypeRep(abiFile, dir, t)
+	from, to := abiType, goType
+	if toABI {
+		from, to = goType, abiType
+	}
+	// caseRep returns the Go type of a variant case or list element in the input.
+	caseRep := func(t wit.Type) string {
+		if toABI {
+			return g.typeRep(abiFile, dir, t)
+		}
+		return g.abiTypeRep(abiFile, dir, t)
+	}
+
+	var b strings.Builder
+	stringio.Write(&b, "func ", name, "(v ", from, ") (out ", to, ") {\n")
+	switch kind := t.Kind.(type) {
+	case *wit.Record, *wit.Tuple:
+		if tup, ok := kind.(*wit.Tuple); ok && tup.Type() != nil {
+			// Monotypic tuples are represented as a fixed-length Go array
+			stringio.Write(&b, "for i := range v {\n")
+			stringio.Write(&b, "out[i] = ", convert(abiFile, dir, tup.Type(), "v[i]"), "\n")
+			b.WriteString("}\n")
+			b.WriteString("return\n")
+			break
+		}
+		// Fields of the Go type are exported, and fields of the Canonical ABI type are not.
+		for _, f := range wit.Despecialize(kind).(*wit.Record).Fields {
+			fromField, toField := fieldName(f.Name, false), fieldName(f.Name, true)
+			if toABI {
+				fromField, toField = toField, fromField
+			}
+			stringio.Write(&b, "out.", toField, " = ", convert(abiFile, dir, f.Type, "v."+fromField), "\n")
+		}
+		b.WriteString("return\n")
+	case *wit.Option:
+		b.WriteString("if some := v.Some(); some != nil {\n")
+		stringio.Write(&b, "out = ", to, "(", cm, ".Some(", convert(abiFile, dir, kind.Type, "*some"), "))\n")
+		b.WriteString("}\n")
+		b.WriteString("return\n")
+	case *wit.Result:
+		b.WriteString("if ok := v.OK(); ok != nil {\n")
+		stringio.Write(&b, "return ", cm, ".OK[", to, "](", convert(abiFile, dir, kind.OK, "*ok"), ")\n")
+		b.WriteString("}\n")
+		stringio.Write(&b, "return ", cm, ".Err[", to, "](", convert(abiFile, dir, kind.Err, "*v.Err()"), ")\n")
+	case *wit.Variant:
+		b.WriteString("switch v.Tag() {\n")
+		for i, c := range kind.Cases {
+			tag := strconv.Itoa(i)
+			stringio.Write(&b, "case ", tag, ":\n")
+			data := "struct{}{}"
+			if c.Type != nil {
+				data = convert(abiFile, dir, c.Type, "*"+cm+"."+variantFunc("Case", toABI && SplitStorage(t))+"["+caseRep(c.Type)+"](&v, "+tag+")")
+			}
+			stringio.Write(&b, "return ", cm, ".", variantFunc("New", !toABI && SplitStorage(t)), "[", to, "](", tag, ", ", data, ")\n")
+		}
+		b.WriteString("}\n")
+		b.WriteString("return\n")
+	case *wit.List:
+		elem := g.typeRep(abiFile, dir, kind.Type)
+		if toABI {
+			elem = g.abiTypeRep(abiFile, dir, kind.Type)
+		}
+		b.WriteString("s := v.Slice()\n")
+		stringio.Write(&b, "elems := make([]", elem, ", len(s))\n")
+		b.WriteString("for i := range s {\n")
+		stringio.Write(&b, "elems[i] = ", convert(abiFile, dir, kind.Type, "s[i]"), "\n")
+		b.WriteString("}\n")
+		stringio.Write(&b, "return ", to, "(", cm, ".ToList(elems))\n")
+	default:
+		panic(fmt.Sprintf("BUG: unexpected wit.TypeDef %T with Canonical ABI conversion", kind)) // should never reach here
+	}
+	b.WriteString("}\n\n")
+	abiFile.WriteString(b.String())
+	return name
+}
+
 // typeDefGoName returns a mangled Go name for t.
 func (g *generator) typeDefGoName(dir wit.Direction, t *wit.TypeDef) string {
 	if decl, ok := g.types[dir][t]; ok && decl.name != "" {
@@ -1119,7 +1644,7 @@ func (g *generator) lowerTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeD
 	case *wit.Option:
 		return g.lowerOption(file, dir, t, input)
 	case *wit.List:
-		return g.cmCall(file, "LowerList", input)
+		return g.cmCall(file, "LowerList", g.toABI(file, dir, t, input))
 	case *wit.Resource, *wit.Own, *wit.Borrow,
 		*wit.ErrorContext, *wit.Stream, *wit.Future:
 		return g.cmCall(file, "Reinterpret["+g.typeRep(file, dir, flat[0])+"]", input)
@@ -1211,7 +1736,7 @@ func (g *generator) lowerVariant(file *gen.File, dir wit.Direction, t *wit.TypeD
 		}
 		caseNum := strconv.Itoa(i)
 		// caseName := decl.scope.GetName(GoName(c.Name, true))
-		input := "*" + g.cmCall(abiFile, "Case["+g.typeRep(file, dir, c.Type)+"]", "&v, "+caseNum)
+		input := "*" + g.cmCall(abiFile, variantFunc("Case", SplitStorage(t))+"["+g.typeRep(file, dir, c.Type)+"]", "&v, "+caseNum)
 		stringio.Write(&b, "case ", caseNum, ": // ", c.Name, "\n")
 		b.WriteString(g.lowerVariantCaseInto(abiFile, dir, c.Type, flat[1:], input))
 	}
@@ -1333,7 +1858,7 @@ func (g *generator) liftTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeDe
 	case *wit.Option:
 		return g.liftOption(file, dir, t, input)
 	case *wit.List:
-		return g.cmCall(file, "LiftList["+g.typeRep(file, dir, t)+"]", input)
+		return g.fromABI(file, dir, t, g.cmCall(file, "LiftList["+g.abiTypeRep(file, dir, t)+"]", input))
 	case *wit.Resource, *wit.Own, *wit.Borrow,
 		*wit.ErrorContext, *wit.Stream, *wit.Future:
 		return g.cmCall(file, "Reinterpret["+g.typeRep(file, dir, t)+"]", input)
@@ -1422,7 +1947,7 @@ func (g *generator) liftVariant(file *gen.File, dir wit.Direction, t *wit.TypeDe
 	for i, c := range v.Cases {
 		tag := strconv.Itoa(i)
 		stringio.Write(&b, "case ", tag, ":\n")
-		stringio.Write(&b, "return ", g.cmCall(abiFile, "New["+g.typeRep(abiFile, dir, t)+"]", tag+", "+g.liftVariantCase(abiFile, dir, c.Type, flat[1:])), "\n")
+		stringio.Write(&b, "return ", g.cmCall(abiFile, variantFunc("New", SplitStorage(t))+"["+g.typeRep(abiFile, dir, t)+"]", tag+", "+g.liftVariantCase(abiFile, dir, c.Type, flat[1:])), "\n")
 	}
 	b.WriteString("}\n")
 	stringio.Write(&b, "panic(\"lift variant: unknown case: \" + ", abiFile.Import("strconv"), ".Itoa(int(f0)))\n")
@@ -1627,7 +2152,11 @@ func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *w
 	switch dir {
 	case wit.Imported:
 		goPrefix = "wasmimport_"
-		linkerName = module + " " + f.Name
+		if f.Async {
+			linkerName = module + " [async-lower]" + f.Name
+		} else {
+			linkerName = module + " " + f.Name
+		}
 
 	case wit.Exported:
 		scope = g.exportScopes[owner]
@@ -1637,6 +2166,9 @@ func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *w
 		} else {
 			linkerName = module + "#" + f.Name
 		}
+		if f.Async {
+			linkerName = "[async-lift]" + linkerName
+		}
 
 	case importedWithExportedTypes:
 		dir = wit.Imported  // Imported function...
@@ -1729,22 +2261,10 @@ func (g *generator) defineFunction(owner wit.TypeOwner, dir wit.Direction, f *wi
 	case wit.Imported, importedWithExportedTypes:
 		return g.defineImportedFunction(decl)
 	case wit.Exported:
-		err := g.defineExportedFunction(decl)
-		if err != nil {
-			return err
-		}
-		// A post-return function is mechanically different than
-		// other functions, in that it might not have a user-defined
-		// implementation. Since Go has a GC, this work can be deferred.
-		// Additional design work is needed here.
-		// if pf := f.PostReturn(dir); pf != nil {
-		// 	return g.defineFunction(owner, dir, pf)
-		// }
+		return g.defineExportedFunction(decl)
 	default:
 		return errors.New("BUG: unknown direction " + dir.String())
 	}
-
-	return nil
 }
 
 func (g *generator) defineImportedFunction(decl *funcDecl) error {
@@ -1753,6 +2273,11 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 		return nil
 	}
 
+	err := g.defineAsyncPayloads(decl)
+	if err != nil {
+		return err
+	}
+
 	file := decl.goFunc.file
 
 	// Bridging between Go and wasm function
@@ -1787,11 +2312,18 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 			g.declareTypeDef(file, dir, t, decl.wasmFunc.name+"_results")
 			compoundResults.typ = t
 		} else if len(decl.goFunc.results) > 0 && derefPointer(p.typ) == decl.goFunc.results[0].typ {
-			last(callParams).name = decl.goFunc.results[0].name // Ensure results local, not results_
+			if !needsABI(decl.goFunc.results[0].typ) && !decl.f.Async {
+				last(callParams).name = decl.goFunc.results[0].name // Ensure results local, not results_
+			}
 			pointerResult = p
 		}
 	}
 
+	// The callee of an async-lowered import reads params and writes results after
+	// the wasmimport function returns, while the calling goroutine is blocked and its
+	// stack may move. Params and results passed by pointer are allocated on the heap.
+	async := decl.f.Async
+
 	var b bytes.Buffer
 
 	// Emit docs
@@ -1812,15 +2344,25 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 
 	// Lower into wasmimport variables
 	if pointerParam.typ != nil {
-		stringio.Write(&b, callParams[0].name, " := &", decl.goFunc.params[0].name, "\n")
+		p := decl.goFunc.params[0]
+		if needsABI(p.typ) || async {
+			stringio.Write(&b, callParams[0].name, " := new(", g.abiTypeRep(file, p.dir, p.typ), ")\n")
+			stringio.Write(&b, "*", callParams[0].name, " = ", g.toABI(file, p.dir, p.typ, p.name), "\n")
+		} else {
+			stringio.Write(&b, callParams[0].name, " := &", p.name, "\n")
+		}
 	} else if compoundParams.typ != nil {
-		stringio.Write(&b, compoundParams.name, " := ", g.typeRep(file, compoundParams.dir, compoundParams.typ), "{ ")
+		stringio.Write(&b, compoundParams.name, " := ")
+		if async {
+			b.WriteRune('&')
+		}
+		stringio.Write(&b, g.typeRep(file, compoundParams.dir, compoundParams.typ), "{ ")
 		for i, p := range decl.goFunc.params {
 			if i > 0 {
 				b.WriteString(", ")
 			}
 			// compound parameter struct field names are identical to parameter names
-			stringio.Write(&b, p.name, ": ", p.name)
+			stringio.Write(&b, p.name, ": ", g.toABI(file, p.dir, p.typ, p.name))
 		}
 		b.WriteString(" }\n")
 	} else if len(callParams) > 0 {
@@ -1839,8 +2381,14 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 	}
 
 	// Declare result variables
-	if compoundResults.typ != nil {
+	if compoundResults.typ != nil && async {
+		stringio.Write(&b, compoundResults.name, " := new(", g.typeRep(file, compoundResults.dir, compoundResults.typ), ")\n")
+	} else if compoundResults.typ != nil {
 		stringio.Write(&b, "var ", compoundResults.name, " ", g.typeRep(file, compoundResults.dir, compoundResults.typ), "\n")
+	} else if r := decl.goFunc.results; pointerResult.typ != nil && async {
+		stringio.Write(&b, last(callParams).name, " := new(", g.abiTypeRep(file, r[0].dir, r[0].typ), ")\n")
+	} else if r := decl.goFunc.results; pointerResult.typ != nil && needsABI(r[0].typ) {
+		stringio.Write(&b, "var ", last(callParams).name, " ", g.abiTypeRep(file, r[0].dir, r[0].typ), "\n")
 	}
 
 	// Emit call to wasmimport function
@@ -1861,41 +2409,105 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 		t := derefPointer(p.typ)
 		// TODO: this logic is ugly
 		if t != nil && (t == compoundParams.typ || t == compoundResults.typ || p.typ == pointerResult.typ) {
-			b.WriteRune('&')
+			if !async {
+				b.WriteRune('&')
+			}
 			b.WriteString(p.name)
 		} else {
 			b.WriteString(g.cast(file, p.dir, p.typ, p.typ, p.name))
 		}
 	}
 	b.WriteString(")\n")
-	if compoundResults.typ != nil {
-		rec := wit.KindOf[*wit.Record](compoundResults.typ)
-		b.WriteString("return ")
-		for i, f := range rec.Fields {
-			if i > 0 {
-				b.WriteString(", ")
-			}
-			stringio.Write(&b, compoundResults.name, ".", fieldName(f.Name, false))
+	if async {
+		// Block the calling goroutine until the subtask has returned.
+		// Params may be read and results written by the callee until then.
+		stringio.Write(&b, file.Import(g.opts.cmPackage), ".AwaitSubtask(", callResults[0].name, ")\n")
+		if compoundParams.typ != nil {
+			stringio.Write(&b, file.Import("runtime"), ".KeepAlive(", compoundParams.name, ")\n")
+		} else if pointerParam.typ != nil {
+			stringio.Write(&b, file.Import("runtime"), ".KeepAlive(", callParams[0].name, ")\n")
 		}
-		b.WriteString("\n")
-	} else if len(callResults) > 0 {
-		i := 0
-		for _, r := range decl.goFunc.results {
-			flat := r.typ.Flat()
-			stringio.Write(&b, r.name, " = ", g.liftType(file, r.dir, r.typ, g.liftTypeInput(file, r.dir, r.typ, callResults[i:i+len(flat)])), "\n")
-			i += len(flat)
+		if compoundResults.typ != nil || pointerResult.typ != nil {
+			stringio.Write(&b, file.Import("runtime"), ".KeepAlive(", last(callParams).name, ")\n")
+		}
+		for _, p := range decl.goFunc.params {
+			if wit.HasPointer(p.typ) {
+				stringio.Write(&b, file.Import("runtime"), ".KeepAlive(", p.name, ")\n")
+			}
 		}
-		b.WriteString("return\n")
 	} else {
+		// Params converted into the Canonical ABI memory layout hold pointers
+		// that are not visible to the Go garbage collector.
+		for _, p := range decl.goFunc.params {
+			if needsABI(p.typ) && wit.HasPointer(p.typ) {
+				stringio.Write(&b, file.Import("runtime"), ".KeepAlive(", p.name, ")\n")
+			}
+		}
+	}
+	if r := decl.goFunc.results; pointerResult.typ != nil && async {
+		stringio.Write(&b, r[0].name, " = ", g.fromABI(file, r[0].dir, r[0].typ, "*"+last(callParams).name), "\n")
+	} else if r := decl.goFunc.results; pointerResult.typ != nil && needsABI(r[0].typ) {
+		stringio.Write(&b, r[0].name, " = ", g.fromABI(file, r[0].dir, r[0].typ, last(callParams).name), "\n")
+	}
+
+	// Bind streams and futures received from the callee
+	var bind bytes.Buffer
+	for _, r := range decl.goFunc.results {
+		g.bindFuturesAndStreams(&bind, file, r.dir, decl, r.typ, r.name)
+	}
+
+	if compoundResults.typ != nil {
+		rec := wit.KindOf[*wit.Record](compoundResults.typ)
+		if bind.Len() > 0 {
+			for i, r := range decl.goFunc.results {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(r.name)
+			}
+			b.WriteString(" = ")
+		} else {
+			b.WriteString("return ")
+		}
+		for i, f := range rec.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, g.fromABI(file, dir, f.Type, compoundResults.name+"."+fieldName(f.Name, false)))
+		}
+		b.WriteString("\n")
+		if bind.Len() > 0 {
+			b.Write(bind.Bytes())
+			b.WriteString("return\n")
+		}
+	} else if len(callResults) > 0 && !decl.f.Async {
+		i := 0
+		for _, r := range decl.goFunc.results {
+			flat := r.typ.Flat()
+			stringio.Write(&b, r.name, " = ", g.liftType(file, r.dir, r.typ, g.liftTypeInput(file, r.dir, r.typ, callResults[i:i+len(flat)])), "\n")
+			i += len(flat)
+		}
+		b.Write(bind.Bytes())
+		b.WriteString("return\n")
+	} else {
+		b.Write(bind.Bytes())
 		b.WriteString("return\n")
 	}
 	b.WriteString("}\n\n")
 
+	// Emit wrapper function that returns a Go error
+	if r := errorResult(decl.f); r != nil && g.opts.idiomaticErrors {
+		g.defineTryFunction(&b, decl, r)
+	}
+
 	// Emit wasmimport function in wasm file
 	wasmFile := decl.wasmFunc.file
 
 	stringio.Write(wasmFile, "//go:wasmimport ", decl.linkerName, "\n")
-	wasmFile.WriteString("//go:noescape\n")
+	if !async {
+		// Pointers passed to an async-lowered import escape until the subtask returns.
+		wasmFile.WriteString("//go:noescape\n")
+	}
 	wasmFile.WriteString("func ")
 	if decl.wasmFunc.isMethod() {
 		stringio.Write(wasmFile, "(", decl.wasmFunc.receiver.name, " ", g.typeRep(wasmFile, decl.wasmFunc.receiver.dir, decl.wasmFunc.receiver.typ), ") ", decl.wasmFunc.name)
@@ -1906,19 +2518,24 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 
 	wasmFile.WriteString("\n\n")
 
+	// Emit fake wasmimport function for non-wasm builds
+	if g.opts.fakes {
+		g.defineFakeFunction(decl)
+	}
+
 	// Emit shared types
 	if t, ok := compoundParams.typ.(*wit.TypeDef); ok {
 		td, _ := g.typeDecl(dir, t)
 		stringio.Write(&b, "// ", td.name, " represents the flattened function params for [", decl.wasmFunc.name, "].\n")
 		stringio.Write(&b, "// See the Canonical ABI flattening rules for more information.\n")
-		stringio.Write(&b, "type ", td.name, " ", g.typeDefRep(file, dir, t, td.name), "\n\n")
+		stringio.Write(&b, "type ", td.name, " ", g.compoundRep(file, dir, t, td.name), "\n\n")
 	}
 
 	if t, ok := compoundResults.typ.(*wit.TypeDef); ok {
 		td, _ := g.typeDecl(dir, t)
 		stringio.Write(&b, "// ", td.name, " represents the flattened function results for [", decl.wasmFunc.name, "].\n")
 		stringio.Write(&b, "// See the Canonical ABI flattening rules for more information.\n")
-		stringio.Write(&b, "type ", td.name, " ", g.typeDefRep(file, dir, t, td.name), "\n\n")
+		stringio.Write(&b, "type ", td.name, " ", g.compoundRep(file, dir, t, td.name), "\n\n")
 	}
 
 	// Write to file
@@ -1932,6 +2549,12 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 	if !g.define(dir, decl.f) {
 		return nil
 	}
+
+	err := g.defineAsyncPayloads(decl)
+	if err != nil {
+		return err
+	}
+
 	file := decl.goFunc.file
 	scope := g.exportScopes[decl.owner]
 
@@ -1967,151 +2590,1081 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 		}
 	}
 
-	// Emit exports declaration in exports file
-	{
-		exportsFile := g.exportsFileFor(decl.owner)
-		stringio.Write(exportsFile, "\n", g.functionDocs(dir, decl.f, decl.goFunc.name))
-		stringio.Write(exportsFile, decl.goFunc.name, " func", g.functionSignature(exportsFile, decl.goFunc), "\n")
+	// Declare optional post-return function for results containing pointers
+	var postReturn function
+	if decl.f.PostReturn(dir) != nil {
+		postScope := scope
+		if decl.f.IsMethod() {
+			td, _ := g.typeDecl(dir, decl.f.Type().(*wit.TypeDef))
+			postScope = td.scope
+		}
+		postReturn = function{
+			file:   file,
+			name:   postScope.DeclareName(decl.goFunc.name + "PostReturn"),
+			params: decl.goFunc.results,
+		}
+	}
+
+	// Methods of exported resources with a resource table are called on the Go value,
+	// and the destructor is called with the Go value released from the table.
+	rt := g.resourceTableFor(decl.f)
+	isDtor := strings.HasPrefix(decl.f.Name, "[dtor]")
+	signature := func(file *gen.File) string {
+		if rt != nil && isDtor {
+			return "(" + decl.goFunc.params[0].name + " " + rt.value + ") "
+		}
+		return g.functionSignature(file, decl.goFunc)
+	}
+
+	// Emit exports declaration in exports file
+	if rt != nil && !isDtor {
+		method := decl.goFunc
+		method.params = method.params[1:]
+		stringio.Write(&rt.methods, g.functionDocs(dir, decl.f, decl.goFunc.name), decl.goFunc.name, g.functionSignature(file, method), "\n\n")
+	}
+	{
+		exportsFile := g.exportsFileFor(decl.owner)
+		if rt == nil || isDtor {
+			stringio.Write(exportsFile, "\n", g.functionDocs(dir, decl.f, decl.goFunc.name))
+			stringio.Write(exportsFile, decl.goFunc.name, " func", signature(exportsFile), "\n")
+		}
+		if postReturn.name != "" {
+			stringio.Write(exportsFile, "\n// ", postReturn.name, " represents the optional post-return function for ", decl.goFunc.name, ".\n")
+			stringio.Write(exportsFile, "// If set, it is called after the results of ", decl.goFunc.name, " are copied to the caller,\n")
+			stringio.Write(exportsFile, "// and may be used to release any resources held by the results.\n")
+			stringio.Write(exportsFile, postReturn.name, " func", g.functionSignature(exportsFile, postReturn), "\n")
+		}
+		if ei := g.exportsInterfaceFor(decl.owner); ei != nil {
+			if rt == nil || isDtor {
+				ei.addMethod(decl.goFunc.name, g.functionDocs(dir, decl.f, decl.goFunc.name), signature(exportsFile))
+			}
+			if postReturn.name != "" {
+				ei.addPostReturn(postReturn.name, g.functionSignature(exportsFile, postReturn))
+			}
+		}
+	}
+
+	// Emit wasmexport function in wasm file
+	wasmFile := decl.wasmFunc.file
+
+	stringio.Write(wasmFile, "//go:wasmexport ", decl.linkerName, "\n")
+	stringio.Write(wasmFile, "//export ", decl.linkerName, "\n") // TODO: remove this once TinyGo supports go:wasmexport.
+	stringio.Write(wasmFile, "func ", decl.wasmFunc.name, g.functionSignature(wasmFile, decl.wasmFunc))
+
+	// Emit function body
+	wasmFile.WriteString(" {\n")
+
+	// Lift arguments
+	if compoundParams.typ == nil {
+		i := 0
+		for _, p := range callParams {
+			if i < len(decl.wasmFunc.params) && p.typ == derefPointer(decl.wasmFunc.params[i].typ) {
+				stringio.Write(wasmFile, p.name, " := ", g.fromABI(wasmFile, p.dir, p.typ, "*"+decl.wasmFunc.params[i].name), "\n")
+				i++
+				continue
+			}
+			flat := p.typ.Flat()
+			var input string
+			if len(flat) > 0 && len(decl.wasmFunc.params) > 0 {
+				input = g.liftTypeInput(wasmFile, p.dir, p.typ, decl.wasmFunc.params[i:i+len(flat)])
+			}
+			stringio.Write(wasmFile, p.name, " := ", g.liftType(wasmFile, p.dir, p.typ, input), "\n")
+			i += len(flat)
+		}
+	}
+
+	// Bind streams and futures received from the caller
+	var args []string
+	if compoundParams.typ != nil {
+		rec := wit.KindOf[*wit.Record](compoundParams.typ)
+		for _, f := range rec.Fields {
+			arg := g.fromABI(wasmFile, dir, f.Type, compoundParams.name+"."+fieldName(f.Name, false))
+			if len(appendFuturesAndStreams(nil, f.Type)) > 0 {
+				name := decl.wasmFunc.scope.DeclareName(GoName(f.Name, false))
+				stringio.Write(wasmFile, name, " := ", arg, "\n")
+				g.bindFuturesAndStreams(wasmFile, wasmFile, dir, decl, f.Type, name)
+				arg = name
+			}
+			args = append(args, arg)
+		}
+	} else {
+		for _, p := range callParams {
+			if isPointer(p.typ) {
+				args = append(args, "*"+p.name)
+			} else {
+				g.bindFuturesAndStreams(wasmFile, wasmFile, p.dir, decl, p.typ, p.name)
+				args = append(args, p.name)
+			}
+		}
+	}
+
+	// Async functions call the caller-defined Go function in a new goroutine
+	var task string
+	if decl.f.Async {
+		cm := wasmFile.Import(g.opts.cmPackage)
+		task = decl.wasmFunc.scope.DeclareName("task")
+		stringio.Write(wasmFile, decl.wasmFunc.results[0].name, " = ", cm, ".StartTask(func(", task, " *", cm, ".Task) {\n")
+	}
+
+	// Results converted into the Canonical ABI memory layout are lowered after the call
+	resultsNeedABI := slices.ContainsFunc(callResults, func(r param) bool { return needsABI(r.typ) })
+
+	// Emit call to caller-defined Go function
+	if compoundResults.typ != nil && !resultsNeedABI {
+		rec := wit.KindOf[*wit.Record](compoundResults.typ)
+		stringio.Write(wasmFile, compoundResults.name, " = new(", g.typeRep(wasmFile, compoundResults.dir, compoundResults.typ), ")\n")
+		for i, f := range rec.Fields {
+			if i > 0 {
+				wasmFile.WriteString(", ")
+			}
+			stringio.Write(wasmFile, compoundResults.name, ".", fieldName(f.Name, false))
+		}
+		wasmFile.WriteString(" = ")
+	} else if len(callResults) > 0 {
+		for i, r := range callResults {
+			if i > 0 {
+				wasmFile.WriteString(", ")
+			}
+			wasmFile.WriteString(r.name)
+		}
+		wasmFile.WriteString(" := ")
+	}
+
+	// Emit caller-defined function name
+	exports := file.GetName(g.exportsName())
+	fqName := exports + "." + decl.goFunc.name
+	if t := decl.f.Type(); t != nil {
+		fqName = exports + "." + scope.GetName(GoName(t.TypeName(), true)) + "." + decl.goFunc.name
+	}
+	// Emit call params
+	switch {
+	case rt != nil && isDtor:
+		stringio.Write(wasmFile, fqName, "(", rt.table, ".Drop(", args[0], "))\n")
+	case rt != nil:
+		stringio.Write(wasmFile, rt.table, ".Value(", args[0], ").", decl.goFunc.name, "(", strings.Join(args[1:], ", "), ")\n")
+	default:
+		stringio.Write(wasmFile, fqName, "(", strings.Join(args, ", "), ")\n")
+	}
+
+	// Lower results
+	var taskReturn []byte
+	var pinned string
+	if decl.f.Async {
+		taskReturn = g.defineTaskReturn(decl, task, callResults)
+		wasmFile.WriteString("})\n")
+	} else if compoundResults.typ != nil && resultsNeedABI {
+		rec := wit.KindOf[*wit.Record](compoundResults.typ)
+		var b strings.Builder
+		stringio.Write(&b, g.typeRep(wasmFile, compoundResults.dir, compoundResults.typ), "{")
+		for i, f := range rec.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, fieldName(f.Name, false), ": ", g.toABI(wasmFile, dir, f.Type, callResults[i].name))
+		}
+		b.WriteString("}")
+		if postReturn.name != "" {
+			pinned = g.definePinned(decl, compoundResults.typ)
+			g.lowerPinned(decl, pinned, b.String(), callResults)
+		} else {
+			stringio.Write(wasmFile, compoundResults.name, " = &", b.String(), "\n")
+		}
+	} else if len(callResults) > 0 && compoundResults.typ == nil {
+		i := 0
+		for _, r := range callResults {
+			if i < len(decl.wasmFunc.results) {
+				wr := decl.wasmFunc.results[i]
+				if r.typ == derefPointer(wr.typ) {
+					switch {
+					case !needsABI(r.typ):
+						stringio.Write(wasmFile, wr.name, " = &", r.name, "\n")
+					case postReturn.name != "":
+						pinned = g.definePinned(decl, r.typ)
+						g.lowerPinned(decl, pinned, g.toABI(wasmFile, r.dir, r.typ, r.name), callResults)
+					default:
+						stringio.Write(wasmFile, wr.name, " = new(", g.abiTypeRep(wasmFile, r.dir, r.typ), ")\n")
+						stringio.Write(wasmFile, "*", wr.name, " = ", g.toABI(wasmFile, r.dir, r.typ, r.name), "\n")
+					}
+					i++
+					continue
+				}
+			}
+			flat := r.typ.Flat()
+			if len(flat) == 0 {
+				stringio.Write(wasmFile, "_ = ", r.name, "\n")
+			} else {
+				for j := range flat {
+					if j > 0 {
+						wasmFile.WriteString(", ")
+					}
+					wasmFile.WriteString(decl.wasmFunc.results[i].name)
+					i++
+				}
+				stringio.Write(wasmFile, " = ", g.lowerType(wasmFile, dir, r.typ, r.name), "\n")
+			}
+		}
+	}
+
+	// Retain results until the post-return function is called
+	if postReturn.name != "" {
+		stringio.Write(wasmFile, wasmFile.Import(g.opts.cmPackage), ".Pin(", decl.wasmFunc.results[0].name, ")\n")
+	}
+
+	wasmFile.WriteString("return\n")
+	wasmFile.WriteString("}\n\n")
+
+	// Emit post-return function
+	if postReturn.name != "" {
+		g.definePostReturn(decl, postReturn, fqName, compoundResults, pinned)
+	}
+
+	// Emit task.return and callback for async function
+	if decl.f.Async {
+		wasmFile.Write(taskReturn)
+		callbackName := wasmFile.DeclareName(decl.wasmFunc.name + "Callback")
+		stringio.Write(wasmFile, "//go:wasmexport [callback]", decl.linkerName, "\n")
+		stringio.Write(wasmFile, "//export [callback]", decl.linkerName, "\n") // TODO: remove this once TinyGo supports go:wasmexport.
+		stringio.Write(wasmFile, "func ", callbackName, "(event uint32, waitable uint32, payload uint32) uint32 {\n")
+		stringio.Write(wasmFile, "return ", wasmFile.Import(g.opts.cmPackage), ".TaskCallback(event, waitable, payload)\n")
+		wasmFile.WriteString("}\n\n")
+	}
+
+	var b bytes.Buffer
+
+	// Emit default function body
+	if isDtor || strings.HasPrefix(decl.f.Name, "cabi_post_") {
+		stringio.Write(&b, "func init() {")
+		stringio.Write(&b, fqName, " = func", signature(file), " {}\n")
+		b.WriteString("}\n\n")
+	}
+
+	// Emit adapter function for implementations that return a Go error
+	if r := errorResult(decl.f); r != nil && g.opts.idiomaticErrors && rt == nil {
+		field := strings.TrimPrefix(fqName, exports+".")
+		impl := "Exports." + field
+		if ei := g.exportsInterfaceFor(decl.owner); ei != nil {
+			impl = ei.methodName(decl.f, decl.goFunc.name)
+		}
+		g.defineFuncAdapter(&b, decl, r, field, impl)
+	}
+
+	// Emit shared types
+	if t, ok := compoundParams.typ.(*wit.TypeDef); ok {
+		td, _ := g.typeDecl(dir, t)
+		stringio.Write(&b, "// ", td.name, " represents the flattened function params for [", decl.wasmFunc.name, "].\n")
+		stringio.Write(&b, "// See the Canonical ABI flattening rules for more information.\n")
+		stringio.Write(&b, "type ", td.name, " ", g.compoundRep(file, dir, t, td.name), "\n\n")
+	}
+
+	if t, ok := compoundResults.typ.(*wit.TypeDef); ok {
+		td, _ := g.typeDecl(dir, t)
+		stringio.Write(&b, "// ", td.name, " represents the flattened function results for [", decl.wasmFunc.name, "].\n")
+		stringio.Write(&b, "// See the Canonical ABI flattening rules for more information.\n")
+		stringio.Write(&b, "type ", td.name, " ", g.compoundRep(file, dir, t, td.name), "\n\n")
+	}
+
+	// Write to file
+	file.Write(b.Bytes())
+
+	return g.ensureEmptyAsm(file.Package)
+}
+
+// errorResult returns the [wit.Result] returned by f if f returns a single result
+// with an OK or error type, which can be represented as a Go error. Otherwise it returns nil.
+func errorResult(f *wit.Function) *wit.Result {
+	if len(f.Results) != 1 {
+		return nil
+	}
+	td, ok := f.Results[0].Type.(*wit.TypeDef)
+	if !ok {
+		return nil
+	}
+	r, ok := td.Root().Kind.(*wit.Result)
+	if !ok || (r.OK == nil && r.Err == nil) {
+		return nil
+	}
+	return r
+}
+
+// defineTryFunction emits a wrapper for the imported function in decl, which returns
+// a Go error instead of [wit.Result] r.
+func (g *generator) defineTryFunction(b *bytes.Buffer, decl *funcDecl, r *wit.Result) {
+	file := decl.goFunc.file
+	result := decl.goFunc.results[0]
+	params := decl.goFunc.params
+	var name, target string
+	if decl.goFunc.isMethod() {
+		params = params[1:]
+		td, _ := g.typeDecl(decl.goFunc.receiver.dir, decl.f.Type().(*wit.TypeDef))
+		name = td.scope.DeclareName("Try" + decl.goFunc.name)
+		target = td.name + "." + decl.goFunc.name
+	} else {
+		name = file.DeclareName("Try" + decl.goFunc.name)
+		target = decl.goFunc.name
+	}
+
+	scope := gen.NewScope(file)
+	for _, p := range decl.goFunc.params {
+		scope.DeclareName(p.name)
+	}
+	errName := scope.DeclareName("err")
+
+	// Emit docs
+	stringio.Write(b, "// ", name, " calls [", target, "], returning a Go error if it returns an error result.\n")
+	stringio.Write(b, "// The returned error is a *[", file.Import(g.opts.cmPackage), ".ResultError] wrapping the error value.\n")
+
+	// Emit function signature
+	b.WriteString("func ")
+	if decl.goFunc.isMethod() {
+		stringio.Write(b, "(", decl.goFunc.receiver.name, " ", g.typeRep(file, decl.goFunc.receiver.dir, decl.goFunc.receiver.typ), ") ")
+	}
+	b.WriteString(name)
+	b.WriteString(g.functionSignature(file, function{params: params}))
+	if r.OK != nil {
+		stringio.Write(b, "(", g.typeRep(file, result.dir, r.OK), ", error)")
+	} else {
+		b.WriteString("error")
+	}
+	b.WriteString(" {\n")
+
+	// Emit function body
+	var call strings.Builder
+	stringio.Write(&call, file.Import(g.opts.cmPackage), ".ResultValue(")
+	if decl.goFunc.isMethod() {
+		stringio.Write(&call, decl.goFunc.receiver.name, ".")
+	}
+	stringio.Write(&call, decl.goFunc.name, "(")
+	for i, p := range params {
+		if i > 0 {
+			call.WriteString(", ")
+		}
+		call.WriteString(p.name)
+	}
+	call.WriteString("))")
+	if r.OK != nil {
+		stringio.Write(b, "return ", call.String(), "\n")
+	} else {
+		stringio.Write(b, "_, ", errName, " := ", call.String(), "\n")
+		stringio.Write(b, "return ", errName, "\n")
+	}
+	b.WriteString("}\n\n")
+}
+
+// defineFuncAdapter emits an adapter for the exported function in decl, which converts
+// a caller-defined function that returns a Go error into a function that returns [wit.Result] r.
+// Argument field is the name of the function in the Exports struct, e.g. "Read" or "Resource.Read".
+// Go errors are converted into the error type of r with its message if it is a string.
+// Otherwise the adapter requires a caller-defined function to convert them.
+func (g *generator) defineFuncAdapter(b *bytes.Buffer, decl *funcDecl, r *wit.Result, field, impl string) {
+	file := decl.goFunc.file
+	result := decl.goFunc.results[0]
+	name := file.DeclareName(strings.ReplaceAll(field, ".", "") + "Func")
+	cm := file.Import(g.opts.cmPackage)
+
+	scope := gen.NewScope(file)
+	for _, p := range decl.goFunc.params {
+		scope.DeclareName(p.name)
+	}
+	scope.DeclareName(result.name)
+	f := scope.DeclareName("f")
+	v := scope.DeclareName("v")
+	err := scope.DeclareName("err")
+
+	// Choose the function that converts a Go error into the error type
+	var mapErr, mapErrParam string
+	switch r.Err.(type) {
+	case nil:
+		mapErr = "func(error) struct{} { return struct{}{} }"
+	case wit.String:
+		mapErr = "error.Error"
+	default:
+		mapErr = scope.DeclareName("mapErr")
+		mapErrParam = ", " + mapErr + " func(error) " + g.typeRep(file, result.dir, r.Err)
+	}
+
+	// Emit docs
+	stringio.Write(b, "// ", name, " adapts ", f, ", a function that returns a Go error, into an implementation of ", impl, ".\n")
+	stringio.Write(b, "// Errors returned by ", f, " are converted into an error result with [", cm, ".ResultFromFunc]")
+	switch {
+	case mapErrParam != "":
+		stringio.Write(b, ".\n// Errors that do not wrap a *[", cm, ".ResultError] are converted with ", mapErr, ".\n")
+	case r.Err != nil:
+		b.WriteString(",\n// using the error message of errors that do not wrap a *[" + cm + ".ResultError].\n")
+	default:
+		b.WriteString(".\n")
+	}
+
+	// Emit function signature
+	var tryResults string
+	if r.OK != nil {
+		tryResults = "(" + g.typeRep(file, result.dir, r.OK) + ", error)"
+	} else {
+		tryResults = "error"
+	}
+	adapted := function{params: decl.goFunc.params, results: decl.goFunc.results}
+	stringio.Write(b, "func ", name, "(", f, " func", g.functionSignature(file, function{params: decl.goFunc.params}), tryResults, mapErrParam, ") ")
+	stringio.Write(b, "func", g.functionSignature(file, adapted), " {\n")
+
+	// Emit function body
+	stringio.Write(b, "return func", g.functionSignature(file, adapted), " {\n")
+	if r.OK != nil {
+		stringio.Write(b, v, ", ")
+	}
+	stringio.Write(b, err, " := ", f, "(")
+	for i, p := range decl.goFunc.params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(p.name)
+	}
+	b.WriteString(")\n")
+	if r.OK == nil {
+		v = "struct{}{}"
+	}
+	stringio.Write(b, "return ", cm, ".ResultFromFunc[", g.typeRep(file, result.dir, result.typ), "](", v, ", ", err, ", ", mapErr, ")\n")
+	b.WriteString("}\n")
+	b.WriteString("}\n\n")
+}
+
+// beginFakeResource emits the start of the hooks and in-memory handle table
+// for resource type t with Go name goName in the fake file.
+func (g *generator) beginFakeResource(dir wit.Direction, t *wit.TypeDef, goName, qualifiedName string) {
+	file := g.fakeFileFor(t.Owner)
+	cm := file.Import(g.opts.cmPackage)
+	value := "any"
+	if dir == wit.Exported {
+		value = cm + ".Rep"
+	}
+	stringio.Write(file, "\n// ", goName, " contains the hooks for ", dir.String(), " ", t.WITKind(), " \"", qualifiedName, "\".\n")
+	stringio.Write(file, goName, " struct {\n")
+	stringio.Write(file, "// Handles is an in-memory table of fake handles for ", t.WITKind(), " \"", *t.Name, "\".\n")
+	if dir == wit.Exported {
+		b := "// The default ResourceNew, ResourceRep, and ResourceDrop hooks use Handles to map handles to reps.\n"
+		file.WriteString(b)
+	} else {
+		file.WriteString("// The default ResourceDrop hook removes a handle from Handles.\n")
+	}
+	stringio.Write(file, "Handles ", cm, ".FakeHandles[", goName, ", ", value, "]\n")
+	r := &fakeResource{dir: dir, name: goName, scope: gen.NewScope(nil)}
+	r.scope.DeclareName("Handles")
+	g.fakeResources[t] = r
+}
+
+// endFakeResource emits the end of the hooks for resource type t in the fake file,
+// and default hooks for the resource administrative functions.
+func (g *generator) endFakeResource(dir wit.Direction, t *wit.TypeDef, goName string) {
+	file := g.fakeFileFor(t.Owner)
+	file.WriteString("}\n")
+
+	r := g.fakeResources[t]
+	if r.init.Len() > 0 {
+		file.Trailer += "func init() {\n" + r.init.String() + "}\n\n"
+	}
+}
+
+// fakeResource represents the hooks for a resource type in the fake file.
+type fakeResource struct {
+	dir   wit.Direction
+	name  string
+	scope gen.Scope
+	init  bytes.Buffer
+}
+
+// defineFakeResourceHook emits the default hook for resource administrative function decl,
+// using the in-memory handle table for resource type t.
+func (g *generator) defineFakeResourceHook(file *gen.File, t *wit.TypeDef, decl *funcDecl, hook string) {
+	r := g.fakeResources[t]
+	handles := file.GetName("Fake") + "." + r.name + ".Handles"
+	p := decl.goFunc.params[0].name
+	b := &r.init
+	stringio.Write(b, hook, " = func", g.functionSignature(file, function{params: decl.goFunc.params, results: decl.goFunc.results}), " {\n")
+	switch {
+	case strings.HasPrefix(decl.f.Name, "[resource-new]"):
+		stringio.Write(b, "return ", handles, ".New(", p, ")\n")
+	case strings.HasPrefix(decl.f.Name, "[resource-rep]"):
+		stringio.Write(b, "rep, _ := ", handles, ".Get(", p, ")\n")
+		b.WriteString("return rep\n")
+	case r.dir == wit.Exported && t.Destructor() != nil:
+		stringio.Write(b, "if rep, ok := ", handles, ".Get(", p, "); ok {\n")
+		stringio.Write(b, handles, ".Drop(", p, ")\n")
+		arg := "rep"
+		if rt := g.resourceTables[t]; rt != nil {
+			arg = rt.table + ".Drop(rep)"
+		}
+		stringio.Write(b, file.GetName(g.exportsName()), ".", g.exportScopes[t.Owner].GetName(GoName(*t.Name, true)), ".Destructor(", arg, ")\n")
+		b.WriteString("}\n")
+	default:
+		stringio.Write(b, handles, ".Drop(", p, ")\n")
+	}
+	b.WriteString("}\n")
+}
+
+// defineFakeFunction emits a fake implementation of the wasmimport function in decl,
+// which lifts its arguments, calls the corresponding hook in the fake file, and lowers its results.
+func (g *generator) defineFakeFunction(decl *funcDecl) {
+	file := g.fakeFileFor(decl.owner)
+	dir := wit.Imported
+	wasm := decl.wasmFunc
+
+	// Emit hook declaration
+	hook := decl.goFunc.name
+	fqName := file.GetName("Fake") + "."
+	t, _ := decl.f.Type().(*wit.TypeDef)
+	r := g.fakeResources[t]
+	if r != nil {
+		hook = r.scope.DeclareName(GoName(decl.f.BaseName(), true))
+		fqName += r.name + "."
+	}
+	fqName += hook
+	if r != nil && decl.f.IsAdmin() {
+		g.defineFakeResourceHook(file, t, decl, fqName)
+	}
+	hookFunc := function{params: decl.goFunc.params, results: decl.goFunc.results}
+	docs := g.functionDocs(dir, decl.f, hook)
+	if decl.f.IsAdmin() {
+		docs = strings.TrimSuffix(docs, "//\n")
+	}
+	stringio.Write(file, "\n", docs)
+	stringio.Write(file, hook, " func", g.functionSignature(file, hookFunc), "\n")
+
+	// Bridging between wasm and Go function
+	var b bytes.Buffer
+	scope := gen.NewScope(wasm.scope)
+	callParams := slices.Clone(decl.goFunc.params)
+	for i := range callParams {
+		callParams[i].name = scope.DeclareName(callParams[i].name)
+	}
+	callResults := slices.Clone(decl.goFunc.results)
+	for i := range callResults {
+		callResults[i].name = scope.DeclareName(callResults[i].name)
+	}
+
+	// Identify params passed by pointer
+	wasmParams := wasm.params
+	var status param
+	if decl.f.Async {
+		status = wasm.results[0]
+	}
+	var compoundParams, compoundResults, pointerParam, pointerResult param
+	if len(wasmParams) > 0 {
+		p := wasmParams[0]
+		t := derefAnonRecord(p.typ)
+		if len(callParams) > 0 && t != nil {
+			compoundParams = p
+			compoundParams.typ = t
+		} else if len(callParams) > 0 && derefPointer(p.typ) == callParams[0].typ {
+			pointerParam = p
+		}
+
+		p = *last(wasmParams)
+		t = derefAnonRecord(p.typ)
+		if len(callResults) > 0 && t != nil && t != compoundParams.typ {
+			compoundResults = p
+			compoundResults.typ = t
+		} else if len(callResults) > 0 && derefPointer(p.typ) == callResults[0].typ && (len(wasmParams) > 1 || pointerParam.typ == nil) {
+			pointerResult = p
+		}
+	}
+
+	stringio.Write(&b, "func ")
+	if wasm.isMethod() {
+		stringio.Write(&b, "(", wasm.receiver.name, " ", g.typeRep(file, wasm.receiver.dir, wasm.receiver.typ), ") ")
+	}
+	stringio.Write(&b, wasm.name, g.functionSignature(file, wasm), " {\n")
+
+	// Lift arguments
+	switch {
+	case pointerParam.typ != nil:
+		p := callParams[0]
+		stringio.Write(&b, p.name, " := ", g.fromABI(file, p.dir, p.typ, "*"+pointerParam.name), "\n")
+	case compoundParams.typ != nil:
+		rec := wit.KindOf[*wit.Record](compoundParams.typ)
+		for i, f := range rec.Fields {
+			stringio.Write(&b, callParams[i].name, " := ", g.fromABI(file, dir, f.Type, compoundParams.name+"."+fieldName(f.Name, false)), "\n")
+		}
+	default:
+		i := 0
+		for _, p := range callParams {
+			flat := p.typ.Flat()
+			var input string
+			if len(flat) > 0 {
+				input = g.liftTypeInput(file, p.dir, p.typ, wasmParams[i:i+len(flat)])
+			}
+			stringio.Write(&b, p.name, " := ", g.liftType(file, p.dir, p.typ, input), "\n")
+			i += len(flat)
+		}
+	}
+
+	// Emit call to hook
+	stringio.Write(&b, "if ", fqName, " == nil {\n")
+	stringio.Write(&b, "panic(", strconv.Quote(strings.TrimPrefix(fqName, file.GetName("Fake")+".")+": no fake for "+decl.linkerName), ")\n")
+	b.WriteString("}\n")
+	resultsNeedABI := slices.ContainsFunc(callResults, func(r param) bool { return needsABI(r.typ) })
+	var call strings.Builder
+	stringio.Write(&call, fqName, "(")
+	for i, p := range callParams {
+		if i > 0 {
+			call.WriteString(", ")
+		}
+		call.WriteString(p.name)
+	}
+	call.WriteString(")")
+	switch {
+	case compoundResults.typ != nil && !resultsNeedABI:
+		rec := wit.KindOf[*wit.Record](compoundResults.typ)
+		for i, f := range rec.Fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, compoundResults.name, ".", fieldName(f.Name, false))
+		}
+		b.WriteString(" = ")
+	case pointerResult.typ != nil:
+		r := callResults[0]
+		stringio.Write(&b, "*", pointerResult.name, " = ", g.toABI(file, r.dir, r.typ, call.String()), "\n")
+	case len(callResults) > 0:
+		for i, r := range callResults {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(r.name)
+		}
+		b.WriteString(" := ")
+	}
+	if pointerResult.typ == nil {
+		stringio.Write(&b, call.String(), "\n")
+	}
+
+	// Lower results
+	if compoundResults.typ != nil && resultsNeedABI {
+		rec := wit.KindOf[*wit.Record](compoundResults.typ)
+		for i, f := range rec.Fields {
+			stringio.Write(&b, compoundResults.name, ".", fieldName(f.Name, false), " = ", g.toABI(file, dir, f.Type, callResults[i].name), "\n")
+		}
+	} else if compoundResults.typ == nil && pointerResult.typ == nil && len(callResults) > 0 {
+		i := 0
+		for _, r := range callResults {
+			flat := r.typ.Flat()
+			if len(flat) == 0 {
+				stringio.Write(&b, "_ = ", r.name, "\n")
+				continue
+			}
+			for j := range flat {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(wasm.results[i].name)
+				i++
+			}
+			stringio.Write(&b, " = ", g.lowerType(file, dir, r.typ, r.name), "\n")
+		}
+	}
+	if status.typ != nil {
+		// The fake returns synchronously, so the subtask has returned.
+		stringio.Write(&b, status.name, " = uint32(", file.Import(g.opts.cmPackage), ".SubtaskReturned)\n")
+	}
+	b.WriteString("return\n")
+	b.WriteString("}\n\n")
+
+	file.Trailer += b.String()
+}
+
+// definePostReturn emits the Canonical ABI post-return function for the exported function in decl.
+// It calls the optional caller-defined post-return function, then releases the pinned results.
+// If pinned is not empty, it names the type that retains the Go values of the results.
+func (g *generator) definePostReturn(decl *funcDecl, postReturn function, fqName string, compoundResults param, pinned string) {
+	wasmFile := decl.wasmFunc.file
+	linkerName := "cabi_post_" + decl.linkerName
+	wasmName := wasmFile.DeclareName(decl.wasmFunc.name + "PostReturn")
+	result := decl.wasmFunc.results[0]
+
+	stringio.Write(wasmFile, "//go:wasmexport ", linkerName, "\n")
+	stringio.Write(wasmFile, "//export ", linkerName, "\n") // TODO: remove this once TinyGo supports go:wasmexport.
+	stringio.Write(wasmFile, "func ", wasmName, "(", result.name, " ", g.typeRep(wasmFile, result.dir, result.typ), ") {\n")
+
+	// Emit call to optional caller-defined post-return function
+	fqName = strings.TrimSuffix(fqName, decl.goFunc.name) + postReturn.name
+	stringio.Write(wasmFile, "if ", fqName, " != nil {\n")
+	if pinned != "" {
+		stringio.Write(wasmFile, "pinned := (*", pinned, ")(", wasmFile.Import("unsafe"), ".Pointer(", result.name, "))\n")
+	}
+	stringio.Write(wasmFile, fqName, "(")
+	if pinned != "" {
+		for i := range decl.goFunc.results {
+			if i > 0 {
+				wasmFile.WriteString(", ")
+			}
+			stringio.Write(wasmFile, "pinned.r", strconv.Itoa(i))
+		}
+	} else if compoundResults.typ != nil {
+		rec := wit.KindOf[*wit.Record](compoundResults.typ)
+		for i, f := range rec.Fields {
+			if i > 0 {
+				wasmFile.WriteString(", ")
+			}
+			stringio.Write(wasmFile, result.name, ".", fieldName(f.Name, false))
+		}
+	} else {
+		stringio.Write(wasmFile, "*", result.name)
+	}
+	wasmFile.WriteString(")\n")
+	wasmFile.WriteString("}\n")
+
+	stringio.Write(wasmFile, wasmFile.Import(g.opts.cmPackage), ".Unpin(", result.name, ")\n")
+	wasmFile.WriteString("}\n\n")
+}
+
+// definePinned declares a Go struct type that retains the results of the exported function in decl
+// until its post-return function is called. Results with the Canonical ABI memory layout of type t
+// are stored in the first field, followed by the Go values they were converted from, which hold
+// pointers that are not visible to the Go garbage collector in the Canonical ABI memory layout.
+func (g *generator) definePinned(decl *funcDecl, t wit.Type) string {
+	file := decl.goFunc.file
+	name := file.DeclareName(decl.wasmFunc.name + "_pinned")
+	var b bytes.Buffer
+	stringio.Write(&b, "// ", name, " retains the results of [", decl.wasmFunc.name, "] until its post-return function is called.\n")
+	stringio.Write(&b, "type ", name, " struct {\n")
+	stringio.Write(&b, "abi ", g.abiTypeRep(file, wit.Exported, t), "\n")
+	for i, r := range decl.goFunc.results {
+		stringio.Write(&b, "r", strconv.Itoa(i), " ", g.typeRep(file, r.dir, r.typ), "\n")
+	}
+	b.WriteString("}\n\n")
+	file.Write(b.Bytes())
+	return name
+}
+
+// lowerPinned emits a value of the type named pinned that holds abi and results,
+// and assigns a pointer to abi to the result of the wasmexport function in decl.
+func (g *generator) lowerPinned(decl *funcDecl, pinned, abi string, results []param) {
+	wasmFile := decl.wasmFunc.file
+	local := decl.wasmFunc.scope.DeclareName("pinned")
+	stringio.Write(wasmFile, local, " := &", pinned, "{abi: ", abi)
+	for i, r := range results {
+		stringio.Write(wasmFile, ", r", strconv.Itoa(i), ": ", r.name)
+	}
+	wasmFile.WriteString("}\n")
+	stringio.Write(wasmFile, decl.wasmFunc.results[0].name, " = &", local, ".abi\n")
+}
+
+// defineAsyncPayloads declares the type-specific Canonical ABI functions for each
+// stream or future type used by the function in decl, unless already declared for
+// the same import module in the Go package. It also declares a constructor for each
+// stream or future type sent by the function: in the params of an imported function,
+// or the results of an exported function.
+func (g *generator) defineAsyncPayloads(decl *funcDecl) error {
+	types := futuresAndStreams(decl.f)
+	if len(types) == 0 {
+		return nil
+	}
+
+	file := g.fileFor(decl.owner)
+	wasmFile := decl.wasmFunc.file
+	tdir := decl.dir
+	module := g.asyncModule(decl)
+	baseName := strings.TrimPrefix(strings.TrimPrefix(decl.wasmFunc.name, "wasmimport_"), "wasmexport_")
+	cm := file.Import(g.opts.cmPackage)
+	unsafe := wasmFile.Import("unsafe")
+
+	// Imported functions send the streams and futures in their params,
+	// and exported functions send the streams and futures in their results.
+	var params []*wit.TypeDef
+	for _, p := range decl.f.Params {
+		params = appendFuturesAndStreams(params, p.Type)
+	}
+	sent := func(i int) bool {
+		return (i < len(params)) == (decl.dir != wit.Exported)
+	}
+
+	// The functions for exported functions are imported from a separate module.
+	vtableBaseName := baseName
+	if decl.dir == wit.Exported {
+		vtableBaseName = "Export" + baseName
+	}
+
+	for i, t := range types {
+		var kind, goKind string
+		var payload wit.Type
+		switch k := t.Kind.(type) {
+		case *wit.Stream:
+			kind, goKind, payload = "stream", "Stream", k.Type
+		case *wit.Future:
+			kind, goKind, payload = "future", "Future", k.Type
+		}
+		if needsABI(payload) {
+			// Stream and future buffers are read and written directly by the host.
+			return fmt.Errorf("%s: payload with split variant or result storage is not supported", t.WIT(nil, ""))
+		}
+		if len(appendFuturesAndStreams(nil, payload)) > 0 {
+			// Handles read from a stream or future would not be bound to a vtable.
+			return fmt.Errorf("%s: payload with a stream or future is not supported", t.WIT(nil, ""))
+		}
+		rep := g.typeRep(file, tdir, payload)
+		suffix := strconv.Itoa(i)
+
+		key := asyncModuleType{file.Package, module, t}
+		vtable, ok := g.asyncVTables[key]
+		if !ok {
+			vtable = file.DeclareName("_" + vtableBaseName + goKind + suffix + "VTable")
+			g.asyncVTables[key] = vtable
+			var b bytes.Buffer
+			stringio.Write(&b, "// ", vtable, " contains the Canonical ABI functions for ", t.WIT(nil, ""), " imported from \"", module, "\".\n")
+			stringio.Write(&b, "var ", vtable, " ", cm, ".", goKind, "VTable\n\n")
+			file.Write(b.Bytes())
+			g.defineAsyncVTable(decl, kind, goKind, suffix, vtable, unsafe)
+		}
+
+		if !sent(i) {
+			continue
+		}
+
+		// Emit constructor
+		name := declareDirectedName(file, decl.dir, "New"+baseName+goKind+suffix)
+		witName := decl.f.BaseName()
+		if decl.f.IsFreestanding() {
+			witName = decl.f.Name
+		}
+		var b bytes.Buffer
+		stringio.Write(&b, "// ", name, " returns the writable and readable ends of a new ", kind, " for the ", decl.dir.String(), " ", decl.f.WITKind(), " \"", witName, "\".\n")
+		b.WriteString("//\n")
+		stringio.Write(&b, "//\t", t.WIT(nil, ""), "\n")
+		stringio.Write(&b, "func ", name, "() (", cm, ".", goKind, "Writer[", rep, "], ", cm, ".", goKind, "[", rep, "]) {\n")
+		stringio.Write(&b, "return ", cm, ".New", goKind, "[", rep, "](&", vtable, ")\n")
+		b.WriteString("}\n\n")
+		file.Write(b.Bytes())
+	}
+
+	return g.ensureEmptyAsm(wasmFile.Package)
+}
+
+// defineAsyncVTable emits the wasmimport functions for the type-specific Canonical ABI functions
+// of the stream or future at index suffix in the type of the function in decl, and assigns them
+// to the fields of vtable.
+func (g *generator) defineAsyncVTable(decl *funcDecl, kind, goKind, suffix, vtable, unsafe string) {
+	wasmFile := decl.wasmFunc.file
+	module := g.asyncModule(decl)
+	baseName := strings.TrimPrefix(strings.TrimPrefix(decl.wasmFunc.name, "wasmimport_"), "wasmexport_")
+	if decl.dir == wit.Exported {
+		baseName = "Export" + baseName
+	}
+	funcs := []struct {
+		field     string
+		name      string
+		signature string
+	}{
+		{"New", "new", "() uint64"},
+		{"Read", "read", "(handle uint32, buf " + unsafe + ".Pointer, n uint32) uint32"},
+		{"Write", "write", "(handle uint32, buf " + unsafe + ".Pointer, n uint32) uint32"},
+		{"CancelRead", "cancel-read", "(handle uint32) uint32"},
+		{"CancelWrite", "cancel-write", "(handle uint32) uint32"},
+		{"DropReadable", "drop-readable", "(handle uint32)"},
+		{"DropWritable", "drop-writable", "(handle uint32)"},
+	}
+	var b bytes.Buffer
+	stringio.Write(&b, "func init() {\n")
+	stringio.Write(&b, vtable, " = ", wasmFile.Import(g.opts.cmPackage), ".", goKind, "VTable{\n")
+	var decls bytes.Buffer
+	for _, f := range funcs {
+		signature := f.signature
+		if kind == "future" {
+			signature = strings.Replace(signature, ", n uint32", "", 1)
+		}
+		// Reads and writes are lowered async, so they return instead of blocking.
+		linkerName := "[" + kind + "-" + f.name + "-" + suffix + "]" + decl.f.Name
+		if f.name == "read" || f.name == "write" {
+			linkerName = "[async-lower]" + linkerName
+		}
+		name := wasmFile.DeclareName("wasmimport_" + baseName + goKind + f.field + suffix)
+		stringio.Write(&b, f.field, ": ", name, ",\n")
+		stringio.Write(&decls, "//go:wasmimport ", module, " ", linkerName, "\n")
+		if !strings.HasPrefix(linkerName, "[async-lower]") {
+			decls.WriteString("//go:noescape\n")
+		}
+		stringio.Write(&decls, "func ", name, signature, "\n\n")
+	}
+	b.WriteString("}\n")
+	b.WriteString("}\n\n")
+	wasmFile.Write(b.Bytes())
+	wasmFile.Write(decls.Bytes())
+}
+
+// asyncModule returns the module name of the type-specific Canonical ABI functions
+// for the streams and futures used by the function in decl.
+func (g *generator) asyncModule(decl *funcDecl) string {
+	module := g.moduleNames[decl.owner]
+	if _, ok := decl.owner.(*wit.World); ok {
+		module = "$root"
 	}
+	if decl.dir == wit.Exported {
+		module = "[export]" + module
+	}
+	return module
+}
 
-	// Emit wasmexport function in wasm file
-	wasmFile := decl.wasmFunc.file
-
-	stringio.Write(wasmFile, "//go:wasmexport ", decl.linkerName, "\n")
-	stringio.Write(wasmFile, "//export ", decl.linkerName, "\n") // TODO: remove this once TinyGo supports go:wasmexport.
-	stringio.Write(wasmFile, "func ", decl.wasmFunc.name, g.functionSignature(wasmFile, decl.wasmFunc))
-
-	// Emit function body
-	wasmFile.WriteString(" {\n")
-
-	// Lift arguments
-	if compoundParams.typ == nil {
-		i := 0
-		for _, p := range callParams {
-			if i < len(decl.wasmFunc.params) && p.typ == derefPointer(decl.wasmFunc.params[i].typ) {
-				stringio.Write(wasmFile, p.name, " := *", decl.wasmFunc.params[i].name, "\n")
-				i++
+// bindFuturesAndStreams writes statements to w that bind each stream or future in expr,
+// a Go value of type t received by the function in decl, to its [cm.StreamVTable] or [cm.FutureVTable].
+// The vtables must have been declared by [generator.defineAsyncPayloads].
+func (g *generator) bindFuturesAndStreams(w stringio.Writer, file *gen.File, dir wit.Direction, decl *funcDecl, t wit.Type, expr string) {
+	td, ok := t.(*wit.TypeDef)
+	if !ok || len(appendFuturesAndStreams(nil, td)) == 0 {
+		return
+	}
+	// Parenthesize a dereferenced expression before a selector or method call.
+	sel := expr
+	if strings.HasPrefix(expr, "*") {
+		sel = "(" + expr + ")"
+	}
+	cm := file.Import(g.opts.cmPackage)
+	switch kind := td.Kind.(type) {
+	case wit.Type:
+		g.bindFuturesAndStreams(w, file, dir, decl, kind, expr)
+	case *wit.Record:
+		exported := true
+		if d, ok := g.typeDecl(dir, td); ok {
+			exported = token.IsExported(d.name)
+		}
+		for _, f := range kind.Fields {
+			g.bindFuturesAndStreams(w, file, dir, decl, f.Type, sel+"."+fieldName(f.Name, exported))
+		}
+	case *wit.Tuple:
+		if typ := kind.Type(); typ != nil {
+			stringio.Write(w, "for _, v := range ", expr, " {\n")
+			g.bindFuturesAndStreams(w, file, dir, decl, typ, "v")
+			stringio.Write(w, "}\n")
+			break
+		}
+		for i, typ := range kind.Types {
+			g.bindFuturesAndStreams(w, file, dir, decl, typ, sel+".F"+strconv.Itoa(i))
+		}
+	case *wit.Variant:
+		for i, c := range kind.Cases {
+			if len(appendFuturesAndStreams(nil, c.Type)) == 0 {
 				continue
 			}
-			flat := p.typ.Flat()
-			var input string
-			if len(flat) > 0 && len(decl.wasmFunc.params) > 0 {
-				input = g.liftTypeInput(wasmFile, p.dir, p.typ, decl.wasmFunc.params[i:i+len(flat)])
+			stringio.Write(w, "if v := ", cm, ".", variantFunc("Case", SplitStorage(td)), "[", g.typeRep(file, dir, c.Type), "](&", expr, ", ", strconv.Itoa(i), "); v != nil {\n")
+			g.bindFuturesAndStreams(w, file, dir, decl, c.Type, "*v")
+			stringio.Write(w, "}\n")
+		}
+	case *wit.Option:
+		stringio.Write(w, "if v := ", sel, ".Some(); v != nil {\n")
+		g.bindFuturesAndStreams(w, file, dir, decl, kind.Type, "*v")
+		stringio.Write(w, "}\n")
+	case *wit.Result:
+		for _, c := range []struct {
+			method string
+			typ    wit.Type
+		}{{"OK", kind.OK}, {"Err", kind.Err}} {
+			if len(appendFuturesAndStreams(nil, c.typ)) == 0 {
+				continue
 			}
-			stringio.Write(wasmFile, p.name, " := ", g.liftType(wasmFile, p.dir, p.typ, input), "\n")
-			i += len(flat)
+			stringio.Write(w, "if v := ", sel, ".", c.method, "(); v != nil {\n")
+			g.bindFuturesAndStreams(w, file, dir, decl, c.typ, "*v")
+			stringio.Write(w, "}\n")
 		}
+	case *wit.List:
+		stringio.Write(w, "for _, v := range ", sel, ".Slice() {\n")
+		g.bindFuturesAndStreams(w, file, dir, decl, kind.Type, "v")
+		stringio.Write(w, "}\n")
+	case *wit.Stream:
+		stringio.Write(w, cm, ".BindStream(", expr, ", &", g.asyncVTables[asyncModuleType{file.Package, g.asyncModule(decl), td}], ")\n")
+	case *wit.Future:
+		stringio.Write(w, cm, ".BindFuture(", expr, ", &", g.asyncVTables[asyncModuleType{file.Package, g.asyncModule(decl), td}], ")\n")
 	}
+}
 
-	// Emit call to caller-defined Go function
-	if compoundResults.typ != nil {
-		rec := wit.KindOf[*wit.Record](compoundResults.typ)
-		stringio.Write(wasmFile, compoundResults.name, " = new(", g.typeRep(wasmFile, compoundResults.dir, compoundResults.typ), ")\n")
-		for i, f := range rec.Fields {
-			if i > 0 {
-				wasmFile.WriteString(", ")
-			}
-			stringio.Write(wasmFile, compoundResults.name, ".", fieldName(f.Name, false))
-		}
-		wasmFile.WriteString(" = ")
-	} else if len(callResults) > 0 {
-		for i, r := range callResults {
-			if i > 0 {
-				wasmFile.WriteString(", ")
-			}
-			wasmFile.WriteString(r.name)
-		}
-		wasmFile.WriteString(" := ")
+// defineTaskReturn emits a call to the task.return function for async exported function decl,
+// lowering results, unless the cancellation of task was acknowledged.
+// It returns the corresponding wasmimport function declaration.
+func (g *generator) defineTaskReturn(decl *funcDecl, task string, results []param) []byte {
+	dir := wit.Exported
+	file := decl.goFunc.file
+	wasmFile := decl.wasmFunc.file
+
+	module := g.moduleNames[decl.owner]
+	if _, ok := decl.owner.(*wit.World); ok {
+		module = "$root"
 	}
+	tf := decl.f.TaskReturn()
+	wasmName := wasmFile.DeclareName("wasmimport_" + strings.TrimPrefix(decl.wasmFunc.name, "wasmexport_") + "TaskReturn")
+	wasmFunc := g.goFunction(wasmFile, dir, wit.Imported, tf.CoreFunction(wit.Imported), wasmName)
 
-	// Emit caller-defined function name
-	fqName := file.GetName("Exports") + "." + decl.goFunc.name
-	if t := decl.f.Type(); t != nil {
-		fqName = file.GetName("Exports") + "." + scope.GetName(GoName(t.TypeName(), true)) + "." + decl.goFunc.name
+	var compoundParams param
+	if len(wasmFunc.params) > 0 {
+		if t := derefAnonRecord(wasmFunc.params[0].typ); t != nil {
+			compoundParams = wasmFunc.params[0]
+			g.declareTypeDef(file, dir, t, wasmName+"_params")
+			compoundParams.typ = t
+		}
 	}
-	stringio.Write(wasmFile, fqName, "(")
 
-	// Emit call params
-	if compoundParams.typ != nil {
+	// Lower results into task.return params
+	stringio.Write(wasmFile, "if !", task, ".Return() {\nreturn\n}\n")
+	var args []string
+	switch {
+	case compoundParams.typ != nil:
 		rec := wit.KindOf[*wit.Record](compoundParams.typ)
+		var b strings.Builder
+		stringio.Write(&b, "&", g.typeRep(wasmFile, dir, compoundParams.typ), "{")
 		for i, f := range rec.Fields {
 			if i > 0 {
-				wasmFile.WriteString(", ")
+				b.WriteString(", ")
 			}
-			stringio.Write(wasmFile, compoundParams.name, ".", fieldName(f.Name, false))
+			stringio.Write(&b, fieldName(f.Name, false), ": ", g.toABI(wasmFile, dir, f.Type, results[i].name))
 		}
-	} else {
-		for i, p := range callParams {
-			if i > 0 {
-				wasmFile.WriteString(", ")
-			}
-			if isPointer(p.typ) {
-				wasmFile.WriteString("*")
-			}
-			wasmFile.WriteString(p.name)
+		b.WriteString("}")
+		args = append(args, b.String())
+	case len(wasmFunc.params) == 1 && len(results) == 1 && isPointer(wasmFunc.params[0].typ):
+		r := results[0]
+		if !needsABI(r.typ) {
+			args = append(args, "&"+r.name)
+			break
 		}
-	}
-	wasmFile.WriteString(")\n")
-
-	// Lower results
-	if len(callResults) > 0 && compoundResults.typ == nil {
+		name := decl.wasmFunc.scope.DeclareName(wasmFunc.params[0].name)
+		stringio.Write(wasmFile, name, " := ", g.toABI(wasmFile, dir, r.typ, r.name), "\n")
+		args = append(args, "&"+name)
+	default:
 		i := 0
-		for _, r := range callResults {
-			if i < len(decl.wasmFunc.results) {
-				wr := decl.wasmFunc.results[i]
-				if r.typ == derefPointer(wr.typ) {
-					stringio.Write(wasmFile, wr.name, " = &", r.name, "\n")
-					i++
-					continue
-				}
-			}
+		for _, r := range results {
 			flat := r.typ.Flat()
 			if len(flat) == 0 {
-				stringio.Write(wasmFile, "_ = ", r.name, "\n")
-			} else {
-				for j := range flat {
-					if j > 0 {
-						wasmFile.WriteString(", ")
-					}
-					wasmFile.WriteString(decl.wasmFunc.results[i].name)
-					i++
+				continue
+			}
+			for j := range flat {
+				name := decl.wasmFunc.scope.DeclareName(wasmFunc.params[i].name)
+				if j > 0 {
+					wasmFile.WriteString(", ")
 				}
-				stringio.Write(wasmFile, " = ", g.lowerType(wasmFile, dir, r.typ, r.name), "\n")
+				wasmFile.WriteString(name)
+				args = append(args, name)
+				i++
 			}
+			stringio.Write(wasmFile, " := ", g.lowerType(wasmFile, dir, r.typ, r.name), "\n")
 		}
 	}
-
-	wasmFile.WriteString("return\n")
-	wasmFile.WriteString("}\n\n")
-
-	var b bytes.Buffer
-
-	// Emit default function body
-	if strings.HasPrefix(decl.f.Name, "[dtor]") || strings.HasPrefix(decl.f.Name, "cabi_post_") {
-		stringio.Write(&b, "func init() {")
-		stringio.Write(&b, fqName, " = func", g.functionSignature(file, decl.goFunc), " {}\n")
-		b.WriteString("}\n\n")
+	stringio.Write(wasmFile, wasmName, "(", strings.Join(args, ", "), ")\n")
+	for _, r := range results {
+		// Results converted into the Canonical ABI memory layout hold pointers
+		// that are not visible to the Go garbage collector.
+		if needsABI(r.typ) && wit.HasPointer(r.typ) {
+			stringio.Write(wasmFile, wasmFile.Import("runtime"), ".KeepAlive(", r.name, ")\n")
+		}
 	}
 
 	// Emit shared types
 	if t, ok := compoundParams.typ.(*wit.TypeDef); ok {
 		td, _ := g.typeDecl(dir, t)
-		stringio.Write(&b, "// ", td.name, " represents the flattened function params for [", decl.wasmFunc.name, "].\n")
-		stringio.Write(&b, "// See the Canonical ABI flattening rules for more information.\n")
-		stringio.Write(&b, "type ", td.name, " ", g.typeDefRep(file, dir, t, td.name), "\n\n")
-	}
-
-	if t, ok := compoundResults.typ.(*wit.TypeDef); ok {
-		td, _ := g.typeDecl(dir, t)
-		stringio.Write(&b, "// ", td.name, " represents the flattened function results for [", decl.wasmFunc.name, "].\n")
+		var b bytes.Buffer
+		stringio.Write(&b, "// ", td.name, " represents the flattened function params for [", wasmName, "].\n")
 		stringio.Write(&b, "// See the Canonical ABI flattening rules for more information.\n")
-		stringio.Write(&b, "type ", td.name, " ", g.typeDefRep(file, dir, t, td.name), "\n\n")
+		stringio.Write(&b, "type ", td.name, " ", g.compoundRep(file, dir, t, td.name), "\n\n")
+		file.Write(b.Bytes())
 	}
 
-	// Write to file
-	file.Write(b.Bytes())
-
-	return g.ensureEmptyAsm(file.Package)
+	// Declare wasmimport function
+	var b bytes.Buffer
+	stringio.Write(&b, "//go:wasmimport [export]", module, " ", tf.Name, "\n")
+	b.WriteString("//go:noescape\n")
+	stringio.Write(&b, "func ", wasmName, g.functionSignature(wasmFile, wasmFunc), "\n\n")
+	return b.Bytes()
 }
 
 func (g *generator) functionSignature(file *gen.File, f function) string {
@@ -2263,26 +3816,181 @@ func (g *generator) exportsFileFor(owner wit.TypeOwner) *gen.File {
 	file := pkg.File(path.Base(pkg.Path) + ".exports.go")
 	file.GeneratedBy = g.opts.generatedBy
 	if len(file.Header) == 0 {
-		exports := file.GetName("Exports")
+		exports := file.GetName(g.exportsName())
 		var b strings.Builder
 		stringio.Write(&b, "// ", exports, " represents the caller-defined exports from \"", g.moduleNames[owner], "\".\n")
 		stringio.Write(&b, "var ", exports, " struct {")
 		file.Header = b.String()
 	}
-	file.Trailer = "}\n"
+	if file.Trailer == "" {
+		file.Trailer = "}\n"
+	}
 	return file
 }
 
+// exportsName returns the name of the package-scoped variable that holds the caller-defined exports.
+// If the exportInterfaces option is set, the variable is not exported, and is set with SetExports.
+func (g *generator) exportsName() string {
+	if g.opts.exportInterfaces {
+		return "exports"
+	}
+	return "Exports"
+}
+
+// exportsInterfaceFor returns the [exportsInterface] for owner, or nil if
+// the exportInterfaces option is not set.
+func (g *generator) exportsInterfaceFor(owner wit.TypeOwner) *exportsInterface {
+	if !g.opts.exportInterfaces {
+		return nil
+	}
+	ei := g.exportInterfaces[owner]
+	if ei == nil {
+		file := g.exportsFileFor(owner)
+		ei = &exportsInterface{
+			name:    file.GetName("Interface"),
+			exports: file.GetName(g.exportsName()),
+			scope:   gen.NewScope(file),
+		}
+		ei.impl = ei.scope.DeclareName("impl")
+		g.exportInterfaces[owner] = ei
+	}
+	return ei
+}
+
+// defineSetExports emits the Go interface(s) and SetExports function for the exports of owner,
+// if the exportInterfaces option is set.
+func (g *generator) defineSetExports(owner wit.TypeOwner) {
+	ei := g.exportInterfaces[owner]
+	if ei == nil {
+		return
+	}
+	file := g.exportsFileFor(owner)
+	setExports := file.GetName("SetExports")
+
+	var b strings.Builder
+	b.WriteString("}\n\n")
+	stringio.Write(&b, "// ", ei.name, " represents the caller-defined exports from \"", g.moduleNames[owner], "\".\n")
+	stringio.Write(&b, "// Register an implementation with [", setExports, "].\n")
+	stringio.Write(&b, "type ", ei.name, " interface {\n", ei.methods.String(), "}\n\n")
+	b.WriteString(ei.resources.String())
+
+	stringio.Write(&b, "// ", setExports, " sets the caller-defined implementation of the exports from \"", g.moduleNames[owner], "\".\n")
+	b.WriteString("// It must be called before any exported function is called, such as from an init function.\n")
+	if ei.postReturn {
+		b.WriteString("//\n")
+		stringio.Write(&b, "// If ", ei.impl, " implements an optional post-return method, such as NamePostReturn for a method Name,\n")
+		b.WriteString("// it is called after the results of the corresponding method are copied to the caller.\n")
+	}
+	stringio.Write(&b, "func ", setExports, "(", ei.impl, " ", ei.name, ") {\n", ei.set.String(), "}\n")
+	file.Trailer = b.String()
+}
+
+// exportsInterface represents the Go interface(s) for the exports of a WIT world or interface,
+// and the body of the SetExports function that registers an implementation.
+type exportsInterface struct {
+	name    string    // Go name of the interface, e.g. Interface
+	exports string    // Go name of the package-scoped exports variable
+	scope   gen.Scope // SetExports function scope
+	impl    string    // SetExports param name
+
+	methods    strings.Builder // methods of the interface
+	resources  strings.Builder // resource interface declarations
+	set        strings.Builder // body of SetExports
+	postReturn bool            // true if any exported function has a post-return method
+
+	// the resource being defined, if any
+	resource        string          // Go name of the resource field in the exports variable
+	resourceName    string          // Go name of the resource interface
+	resourceVar     string          // Go name of the resource variable in SetExports
+	resourceMethods strings.Builder // methods of the resource interface
+}
+
+func (ei *exportsInterface) beginResource(file *gen.File, t *wit.TypeDef, goName, qualifiedName string) {
+	ei.resource = goName
+	ei.resourceName = file.DeclareName(goName + "Interface")
+	ei.resourceVar = ei.scope.DeclareName(GoName(*t.Name, false))
+	ei.resourceMethods.Reset()
+	stringio.Write(&ei.resources, "// ", ei.resourceName, " represents the caller-defined exports for ", t.WITKind(), " \"", qualifiedName, "\".\n")
+	stringio.Write(&ei.methods, "// ", goName, " returns the caller-defined exports for ", t.WITKind(), " \"", qualifiedName, "\".\n")
+	stringio.Write(&ei.methods, goName, "() ", ei.resourceName, "\n\n")
+	stringio.Write(&ei.set, ei.resourceVar, " := ", ei.impl, ".", goName, "()\n")
+}
+
+func (ei *exportsInterface) endResource() {
+	stringio.Write(&ei.resources, "type ", ei.resourceName, " interface {\n", ei.resourceMethods.String(), "}\n\n")
+	ei.resource = ""
+}
+
+// methodName returns the qualified Go name of the interface method for f, e.g. Interface.Func.
+func (ei *exportsInterface) methodName(f *wit.Function, goName string) string {
+	if f.Type() != nil {
+		return ei.resourceName + "." + goName
+	}
+	return ei.name + "." + goName
+}
+
+// addMethod adds an interface method, and the corresponding assignment in SetExports.
+func (ei *exportsInterface) addMethod(name, docs, signature string) {
+	if ei.resource != "" {
+		stringio.Write(&ei.resourceMethods, docs, name, signature, "\n\n")
+		stringio.Write(&ei.set, ei.exports, ".", ei.resource, ".", name, " = ", ei.resourceVar, ".", name, "\n")
+	} else {
+		stringio.Write(&ei.methods, docs, name, signature, "\n\n")
+		stringio.Write(&ei.set, ei.exports, ".", name, " = ", ei.impl, ".", name, "\n")
+	}
+}
+
+// addPostReturn adds an assignment of an optional post-return method in SetExports.
+func (ei *exportsInterface) addPostReturn(name, signature string) {
+	ei.postReturn = true
+	field := ei.exports + "." + name
+	v := ei.impl
+	if ei.resource != "" {
+		field = ei.exports + "." + ei.resource + "." + name
+		v = ei.resourceVar
+	}
+	stringio.Write(&ei.set, "if f, ok := ", v, ".(interface{ ", name, signature, " }); ok {\n")
+	stringio.Write(&ei.set, field, " = f.", name, "\n")
+	ei.set.WriteString("}\n")
+}
+
 func (g *generator) wasmFileFor(owner wit.TypeOwner) *gen.File {
 	pkg := g.packageFor(owner)
 	file := pkg.File(path.Base(pkg.Path) + ".wasm.go")
 	file.GeneratedBy = g.opts.generatedBy
+	if g.opts.fakes && file.GoBuild == "" {
+		file.GoBuild = "wasm"
+	}
 	if len(file.Header) == 0 {
 		file.Header = fmt.Sprintf("// This file contains wasmimport and wasmexport declarations for \"%s\".\n\n", owner.WITPackage().Name.String())
 	}
 	return file
 }
 
+// fakeFileFor returns the file with the fake implementations of the wasmimport functions
+// for owner, used when the package is built for a target other than WebAssembly.
+func (g *generator) fakeFileFor(owner wit.TypeOwner) *gen.File {
+	pkg := g.packageFor(owner)
+	file := pkg.File(path.Base(pkg.Path) + ".fake.go")
+	file.GeneratedBy = g.opts.generatedBy
+	if file.GoBuild == "" {
+		file.GoBuild = "!wasm"
+	}
+	if len(file.Header) == 0 {
+		fake := file.GetName("Fake")
+		var b strings.Builder
+		stringio.Write(&b, "// ", fake, " contains hooks for the functions imported from \"", g.moduleNames[owner], "\"\n")
+		b.WriteString("// when this package is built for a target other than WebAssembly, such as with go test.\n")
+		b.WriteString("// Calling an imported function with a nil hook panics.\n")
+		stringio.Write(&b, "var ", fake, " struct {")
+		file.Header = b.String()
+	}
+	if file.Trailer == "" {
+		file.Trailer = "}\n"
+	}
+	return file
+}
+
 func (g *generator) cgoFileFor(owner wit.TypeOwner) *gen.File {
 	pkg := g.packageFor(owner)
 	file := pkg.File(path.Base(pkg.Path) + ".cgo.go")
@@ -2330,44 +4038,42 @@ func (g *generator) newPackage(w *wit.World, i *wit.Interface, name string) (*ge
 		return pkg, nil
 	}
 
-	// Create the package path and name
-	var segments []string
-	if g.opts.packageRoot != "" && g.opts.packageRoot != "std" {
-		segments = append(segments, g.opts.packageRoot)
-	}
-	segments = append(segments, id.Namespace, id.Package)
-	if g.versioned && id.Version != nil {
-		segments = append(segments, "v"+id.Version.String())
-	}
-	segments = append(segments, id.Extension)
-	if name != id.Extension {
-		segments = append(segments, name) // for anonymous interfaces nested under worlds
+	// Named interfaces are shared by all worlds.
+	pkgWorld := w
+	if i != nil && i.Name != nil {
+		pkgWorld = nil
 	}
-	pkgPath := strings.Join(segments, "/")
 
-	// TODO: write tests for this
-	goName := GoPackageName(name)
-	// Ensure local name doesn’t conflict with Go keywords or predeclared identifiers
-	if gen.UniqueName(goName, gen.IsReserved) != goName {
-		// Try with package prefix, like error -> ioerror
-		goName = FlatName(id.Package + goName)
-		if gen.UniqueName(goName, gen.IsReserved) != goName {
-			// Try with namespace prefix, like ioerror -> wasiioerror
-			goName = gen.UniqueName(FlatName(id.Namespace+goName), gen.IsReserved)
+	pkgPath, goName := g.goPackage(id, name)
+	if other := g.packages[pkgPath]; other != nil {
+		otherWorld := g.packageWorlds[pkgPath]
+		if pkgWorld == nil || otherWorld == nil || pkgWorld != otherWorld {
+			for o, p := range g.witPackages {
+				if p == other {
+					return nil, fmt.Errorf("Go package %s for %s %s conflicts with %s %s",
+						pkgPath, owner.WITKind(), g.moduleNames[owner], o.WITKind(), g.moduleNames[o])
+				}
+			}
 		}
 	}
-
 	pkg = gen.NewPackage(pkgPath + "#" + goName)
 	g.packages[pkg.Path] = pkg
+	g.packageWorlds[pkg.Path] = pkgWorld
 	g.witPackages[owner] = pkg
 	g.exportScopes[owner] = gen.NewScope(nil)
-	pkg.DeclareName("Exports")
+	pkg.DeclareName(g.exportsName())
+	if g.opts.fakes {
+		pkg.DeclareName("Fake")
+	}
+	if g.opts.exportInterfaces {
+		pkg.DeclareName("Interface")
+		pkg.DeclareName("SetExports")
+	}
 
-	// Write a WebAssembly file that includes a custom section
-	// with a name prefixed with "component-type". The contents are the
-	// Component Model definition for a world that encapsulates the
+	// Validate the WIT for a synthesized world that encapsulates the
 	// Component Model types and functions imported into and/or exported
-	// from this Go package.
+	// from this Go package. The build command embeds the component type
+	// for the world of the component when it creates the component.
 	{
 		// Synthesize a unique-ish name
 		worldID := w.Package.Name
@@ -2377,9 +4083,7 @@ func (g *generator) newPackage(w *wit.World, i *wit.Interface, name string) (*ge
 		}
 		worldName := worldID.String()
 		worldName = replacer.Replace(worldName)
-		// libFile := pkg.File("lib" + worldName + ".a")
 
-		// Generate wasm file
 		res, world := synthesizeWorld(g.res, w, worldName)
 		witText := res.WIT(wit.Filter(world, i), "")
 		world.Package.Worlds.Delete(worldName) // Undo mutation
@@ -2387,54 +4091,79 @@ func (g *generator) newPackage(w *wit.World, i *wit.Interface, name string) (*ge
 			witFile := g.witFileFor(owner)
 			witFile.WriteString(witText)
 		}
-		content, err := g.componentEmbed(witText)
+		err := g.validateWIT(witText, wit.HasAsync(w))
 		if err != nil {
 			g.opts.logger.Errorf("WIT:\n%s\n\n", witText)
 			return nil, err
 		}
-		componentType := &wasm.CustomSection{
-			Name:     "component-type:" + worldName,
-			Contents: content,
-		}
-		if false {
-			sysoFile := pkg.File(path.Base(pkg.Path) + ".wasm.syso")
-			wasm.Write(sysoFile, []wasm.Section{&wasm.LinkingSection{}, componentType})
+	}
+
+	return pkg, nil
+}
+
+// goPackage returns the Go package path and name for WIT package id.
+// Argument name is the name of the WIT world or interface, which may differ
+// from id.Extension for anonymous interfaces nested under worlds.
+func (g *generator) goPackage(id wit.Ident, name string) (pkgPath, goName string) {
+	var segments []string
+	if g.opts.packageRoot != "" && g.opts.packageRoot != "std" {
+		segments = append(segments, g.opts.packageRoot)
+	}
+	segments = append(segments, id.Namespace, id.Package)
+	if g.versioned && id.Version != nil {
+		segments = append(segments, "v"+id.Version.String())
+	}
+	segments = append(segments, id.Extension)
+	if name != id.Extension {
+		segments = append(segments, name) // for anonymous interfaces nested under worlds
+	}
+	pkgPath = strings.Join(segments, "/")
+	if name == id.Extension {
+		if p := g.packagePathOverride(id); p != "" {
+			pkgPath = p
+			name = path.Base(p)
 		}
+	}
 
-		// Write Cgo file
-		// cgoFile := g.cgoFileFor(owner)
-		// stringio.Write(cgoFile, "// #cgo LDFLAGS: -L. -l", lib, "\n")
-		// stringio.Write(cgoFile, "import \"C\"\n")
+	// TODO: write tests for this
+	goName = GoPackageName(name)
+	// Ensure local name doesn’t conflict with Go keywords or predeclared identifiers
+	if gen.UniqueName(goName, gen.IsReserved) != goName {
+		// Try with package prefix, like error -> ioerror
+		goName = FlatName(id.Package + goName)
+		if gen.UniqueName(goName, gen.IsReserved) != goName {
+			// Try with namespace prefix, like ioerror -> wasiioerror
+			goName = gen.UniqueName(FlatName(id.Namespace+goName), gen.IsReserved)
+		}
 	}
+	return pkgPath, goName
+}
 
-	return pkg, nil
+// packagePathOverride returns the Go package path for the WIT interface or world id
+// specified with the [PackagePaths] option, or "" if not specified.
+func (g *generator) packagePathOverride(id wit.Ident) string {
+	if p, ok := g.opts.packagePaths[id.String()]; ok {
+		return p
+	}
+	id.Version = nil
+	return g.opts.packagePaths[id.String()]
 }
 
 var replacer = strings.NewReplacer("/", "-", ":", "-", "@", "-v", ".", "", "%", "")
 
-// componentEmbed runs generated WIT through wasm-tools to generate a wasm file with a component-type custom section.
-func (g *generator) componentEmbed(witData string) ([]byte, error) {
+// validateWIT validates generated WIT by encoding it as a component-type custom section with wasm-tools.
+// The vendored wasm-tools cannot parse async functions, so if async is true, validateWIT
+// instead validates the WIT with package wit.
+func (g *generator) validateWIT(witData string, async bool) error {
+	if async {
+		_, err := wit.DecodeWIT(strings.NewReader(witData))
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-
 	// TODO: --all-features?
-	filename := "component.wit"
-	args := []string{"component", "embed", "--only-custom", filename}
-	fsMap := map[string]fs.FS{
-		"": fstest.MapFS{
-			filename: &fstest.MapFile{Data: []byte(witData)},
-		},
-	}
-	stdout := &bytes.Buffer{}
-	stderr := &bytes.Buffer{}
-	err := g.wasmTools.Run(ctx, nil, stdout, stderr, fsMap, args...)
-	if err != nil {
-		if _, ok := err.(*sys.ExitError); ok {
-			return nil, fmt.Errorf("wasm-tools: %s", stderr.String())
-		}
-		return nil, fmt.Errorf("wasm-tools: %w", err)
-	}
-	return stdout.Bytes(), err
+	_, err := g.wasmTools.ComponentEmbed(ctx, witData, "")
+	return err
 }
 
 func synthesizeWorld(r *wit.Resolve, w *wit.World, name string) (*wit.Resolve, *wit.World) {
@@ -0,0 +1,1563 @@
+package bindgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.bytecodealliance.org/internal/go/gen"
+	"go.bytecodealliance.org/internal/stringio"
+	"go.bytecodealliance.org/wit"
+)
+
+const (
+	wazeroPackage    = "github.com/tetratelabs/wazero"
+	wazeroAPIPackage = "github.com/tetratelabs/wazero/api"
+)
+
+// errHostUnsupported is returned when generating host bindings for a WIT world
+// that uses a feature not yet supported, such as async functions.
+var errHostUnsupported = errors.New("not supported by host bindings")
+
+// hostGenerator generates host-side Go bindings for a WIT world, targeting the wazero runtime.
+// The generated Go package registers host functions for imported interfaces and functions on
+// a [wazero.HostModuleBuilder], and calls exported functions on an instantiated guest module,
+// lifting and lowering Component Model values from and into linear memory.
+type hostGenerator struct {
+	*generator
+
+	w    *wit.World
+	pkg  *gen.Package
+	file *gen.File // Go bindings
+	abi  *gen.File // ABI functions
+
+	// types map wit.TypeDef to their Go name in the generated package.
+	types map[*wit.TypeDef]string
+
+	// abiNames map Go type representations to the name suffix of their ABI functions,
+	// e.g. loadListString, storeListString, liftListString, and lowerListString.
+	abiNames map[string]string
+
+	// abiScope is the scope for ABI function name suffixes.
+	abiScope gen.Scope
+
+	// exportedResources map resources implemented by the guest to the name of their
+	// resource table field in the generated Resources struct.
+	exportedResources map[*wit.TypeDef]string
+
+	// err is the first error encountered, such as an unsupported type.
+	err error
+}
+
+func (g *generator) defineHostWorlds() error {
+	g.opts.logger.Infof("Generating Go host bindings for %d world(s)\n", len(g.worlds))
+	for _, w := range g.worlds {
+		h := &hostGenerator{
+			generator: g,
+			w:         w,
+			types:     make(map[*wit.TypeDef]string),
+			abiNames:  make(map[string]string),
+			abiScope:  gen.NewScope(nil),
+
+			exportedResources: make(map[*wit.TypeDef]string),
+		}
+		err := h.defineWorld()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *hostGenerator) defineWorld() error {
+	id := h.w.Package.Name
+	id.Extension = h.w.Name
+	h.moduleNames[h.w] = id.String()
+
+	pkgPath, goName := h.goPackage(id, id.Extension)
+	h.pkg = gen.NewPackage(pkgPath + "#" + goName)
+	h.packages[h.pkg.Path] = h.pkg
+	h.witPackages[h.w] = h.pkg
+
+	h.file = h.pkg.File(path.Base(h.pkg.Path) + ".host.go")
+	h.file.GeneratedBy = h.opts.generatedBy
+	h.file.PackageDocs = "Package " + h.pkg.Name + " contains host bindings for " + h.w.WITKind() + " \"" + id.String() + "\",\n" +
+		"using the wazero WebAssembly runtime.\n"
+	h.abi = h.pkg.File("abi.host.go")
+	h.abi.GeneratedBy = h.opts.generatedBy
+	h.defineHostABI()
+
+	var imports []*wit.Function
+	var importTypes []*wit.TypeDef
+	h.w.Imports.All()(func(name string, v wit.WorldItem) bool {
+		switch v := v.(type) {
+		case *wit.InterfaceRef:
+			h.defineImportedInterface(v.Interface, name)
+		case *wit.TypeDef:
+			h.typeRep(v)
+			importTypes = append(importTypes, v)
+		case *wit.Function:
+			imports = append(imports, v)
+		}
+		return h.err == nil
+	})
+	if h.err != nil {
+		return h.err
+	}
+	if len(imports) > 0 || hasResources(importTypes) {
+		h.defineImports("$root", "Imports", "world \""+h.moduleNames[h.w]+"\"", importTypes, imports)
+	}
+
+	var exports []*wit.Function
+	h.w.Exports.All()(func(name string, v wit.WorldItem) bool {
+		switch v := v.(type) {
+		case *wit.InterfaceRef:
+			h.defineExportedInterface(v.Interface, name)
+		case *wit.Function:
+			exports = append(exports, v)
+		}
+		return h.err == nil
+	})
+	if h.err != nil {
+		return h.err
+	}
+	if len(exports) > 0 {
+		h.defineExports("", "Exports", "world \""+h.moduleNames[h.w]+"\"", "", nil, exports)
+	}
+
+	return h.err
+}
+
+func (h *hostGenerator) interfaceName(i *wit.Interface, name string) (module, goName string) {
+	if i.Name == nil {
+		return name, GoName(name, true)
+	}
+	id := i.Package.Name
+	id.Extension = *i.Name
+	return id.String(), GoName(*i.Name, true)
+}
+
+func (h *hostGenerator) defineImportedInterface(i *wit.Interface, name string) {
+	module, goName := h.interfaceName(i, name)
+	h.moduleNames[i] = module
+
+	var types []*wit.TypeDef
+	i.TypeDefs.All()(func(_ string, t *wit.TypeDef) bool {
+		h.typeRep(t)
+		types = append(types, t)
+		return h.err == nil
+	})
+	var funcs []*wit.Function
+	i.Functions.All()(func(_ string, f *wit.Function) bool {
+		funcs = append(funcs, f)
+		return true
+	})
+	if len(funcs) == 0 && !hasResources(types) {
+		return
+	}
+	h.defineImports(module, goName+"Imports", "interface \""+module+"\"", types, funcs)
+}
+
+func (h *hostGenerator) defineExportedInterface(i *wit.Interface, name string) {
+	module, goName := h.interfaceName(i, name)
+	h.moduleNames[i] = module
+
+	var resources []*wit.TypeDef
+	i.TypeDefs.All()(func(_ string, t *wit.TypeDef) bool {
+		if _, ok := t.Kind.(*wit.Resource); ok {
+			if _, ok := h.types[t]; ok {
+				h.fail(fmt.Errorf("resource type %s imported and exported: %w", t.TypeName(), errHostUnsupported))
+				return false
+			}
+			h.exportedResources[t] = fieldName(*t.Name, false)
+			resources = append(resources, t)
+		}
+		h.typeRep(t)
+		return h.err == nil
+	})
+	var funcs []*wit.Function
+	i.Functions.All()(func(_ string, f *wit.Function) bool {
+		funcs = append(funcs, f)
+		return true
+	})
+	if len(funcs) == 0 && len(resources) == 0 {
+		return
+	}
+	var res string
+	if len(resources) > 0 {
+		res = h.defineExportedResources(module, goName+"Resources", "interface \""+module+"\"", resources)
+	}
+	h.defineExports(module+"#", goName+"Exports", "interface \""+module+"\"", res, resources, funcs)
+}
+
+func hasResources(types []*wit.TypeDef) bool {
+	for _, t := range types {
+		if _, ok := t.Kind.(*wit.Resource); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// fail records err, if no previous error was recorded.
+func (h *hostGenerator) fail(err error) {
+	if h.err == nil {
+		h.err = err
+	}
+}
+
+// declareName declares a unique Go name in the generated package for a WIT type or interface
+// with owner. If name is already declared, it is prefixed with the interface and package name.
+func (h *hostGenerator) declareName(owner wit.TypeOwner, name string) string {
+	goName := GoName(name, true)
+	if !h.file.HasName(goName) {
+		return h.file.DeclareName(goName)
+	}
+	if i, ok := owner.(*wit.Interface); ok && i.Name != nil {
+		goName = GoName(*i.Name+"-"+name, true)
+		if !h.file.HasName(goName) {
+			return h.file.DeclareName(goName)
+		}
+		goName = GoName(i.Package.Name.Package+"-"+*i.Name+"-"+name, true)
+	}
+	return h.file.DeclareName(goName)
+}
+
+// hostFunction represents a Go function or method for [wit.Function] f, with a leading context.Context param.
+type hostFunction struct {
+	f       *wit.Function
+	scope   gen.Scope
+	name    string
+	params  []param
+	results []param
+}
+
+func (h *hostGenerator) hostFunction(f *wit.Function, name string, reserved ...string) *hostFunction {
+	scope := gen.NewScope(h.file)
+	for _, name := range reserved {
+		scope.DeclareName(name)
+	}
+	hf := &hostFunction{
+		f:       f,
+		scope:   scope,
+		name:    name,
+		params:  h.goParams(scope, wit.Imported, f.Params),
+		results: h.goParams(scope, wit.Imported, f.Results),
+	}
+	if len(hf.results) == 1 && f.Results[0].Name == "" {
+		hf.results[0].name = scope.DeclareName("result")
+	}
+	return hf
+}
+
+func (h *hostGenerator) signature(hf *hostFunction, withError bool) string {
+	var b strings.Builder
+	stringio.Write(&b, "(ctx ", h.file.Import("context"), ".Context")
+	for _, p := range hf.params {
+		stringio.Write(&b, ", ", p.name, " ", h.typeRep(p.typ))
+	}
+	b.WriteString(")")
+	if len(hf.results) == 0 && !withError {
+		return b.String()
+	}
+	b.WriteString(" (")
+	for i, r := range hf.results {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		stringio.Write(&b, r.name, " ", h.typeRep(r.typ))
+	}
+	if withError {
+		if len(hf.results) > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("err error")
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// methodName returns the Go method name for [wit.Function] f in a Go interface or struct with scope.
+func (h *hostGenerator) methodName(scope gen.Scope, f *wit.Function) string {
+	switch f.Kind.(type) {
+	case *wit.Constructor:
+		return scope.DeclareName("New" + h.typeRep(f.Type()))
+	case *wit.Method, *wit.Static:
+		return scope.DeclareName(h.typeRep(f.Type()) + GoName(f.BaseName(), true))
+	}
+	return scope.DeclareName(GoName(f.Name, true))
+}
+
+// defineImports emits a Go interface for the host-defined functions imported from module,
+// and functions to register the interface with a [wazero.HostModuleBuilder].
+func (h *hostGenerator) defineImports(module, goName, desc string, types []*wit.TypeDef, funcs []*wit.Function) {
+	var b bytes.Buffer
+	context := h.file.Import("context")
+	wazero := h.file.Import(wazeroPackage)
+	api := h.file.Import(wazeroAPIPackage)
+
+	name := h.file.DeclareName(goName)
+	scope := gen.NewScope(nil)
+
+	type hostImport struct {
+		*hostFunction
+		linkerName string
+	}
+	var decls []hostImport
+	for _, f := range funcs {
+		if f.Async {
+			h.fail(fmt.Errorf("async function %s: %w", f.Name, errHostUnsupported))
+			return
+		}
+		decls = append(decls, hostImport{h.hostFunction(f, h.methodName(scope, f), "b", "ctx", "mod", "stack", "i", "impl"), f.Name})
+	}
+
+	// Emit Go interface
+	stringio.Write(&b, "// ", name, " represents the host implementation of the imported ", desc, ".\n")
+	stringio.Write(&b, "// Register an implementation with [Register", strings.TrimSuffix(name, "Imports"), "] or [Instantiate", strings.TrimSuffix(name, "Imports"), "].\n")
+	stringio.Write(&b, "type ", name, " interface {\n")
+	for _, d := range decls {
+		stringio.Write(&b, "// ", d.name, " implements the imported ", d.f.WITKind(), " \"", d.f.Name, "\".\n")
+		stringio.Write(&b, "//\n")
+		b.WriteString(formatDocComments(strings.TrimSuffix(d.f.WIT(nil, d.f.BaseName()), ";"), true))
+		stringio.Write(&b, d.name, h.signature(d.hostFunction, false), "\n\n")
+	}
+	var drops []*wit.TypeDef
+	for _, t := range types {
+		if _, ok := t.Kind.(*wit.Resource); ok {
+			drops = append(drops, t)
+			dropName := scope.DeclareName("Drop" + h.typeRep(t))
+			stringio.Write(&b, "// ", dropName, " is called when the guest drops a handle to resource \"", t.TypeName(), "\".\n")
+			stringio.Write(&b, dropName, "(ctx ", context, ".Context, self ", h.typeRep(t), ")\n\n")
+		}
+	}
+	b.WriteString("}\n\n")
+
+	// Emit Register function
+	base := strings.TrimSuffix(name, "Imports")
+	register := h.file.DeclareName("Register" + base)
+	instantiate := h.file.DeclareName("Instantiate" + base)
+	stringio.Write(&b, "// ", register, " registers the host functions for the imported ", desc, " on b,\n")
+	stringio.Write(&b, "// which call the methods of impl. Builder b must have module name \"", module, "\".\n")
+	stringio.Write(&b, "func ", register, "(b ", wazero, ".HostModuleBuilder, impl ", name, ") ", wazero, ".HostModuleBuilder {\n")
+	for _, d := range decls {
+		h.defineImportedFunction(&b, d.hostFunction, d.linkerName)
+	}
+	for _, t := range drops {
+		stringio.Write(&b, "b.NewFunctionBuilder().\n")
+		stringio.Write(&b, "WithGoModuleFunction(", api, ".GoModuleFunc(func(ctx ", context, ".Context, mod ", api, ".Module, stack []uint64) {\n")
+		stringio.Write(&b, "impl.", scope.GetName("Drop"+h.typeRep(t)), "(ctx, ", h.typeRep(t), "(stack[0]))\n")
+		stringio.Write(&b, "}), []", api, ".ValueType{", api, ".ValueTypeI32}, nil).\n")
+		stringio.Write(&b, "Export(", strconv.Quote("[resource-drop]"+*t.Name), ")\n")
+	}
+	b.WriteString("return b\n")
+	b.WriteString("}\n\n")
+
+	// Emit Instantiate function
+	stringio.Write(&b, "// ", instantiate, " instantiates a host module in r for the imported ", desc, ",\n")
+	stringio.Write(&b, "// with host functions that call the methods of impl.\n")
+	stringio.Write(&b, "func ", instantiate, "(ctx ", context, ".Context, r ", wazero, ".Runtime, impl ", name, ") (", api, ".Module, error) {\n")
+	stringio.Write(&b, "return ", register, "(r.NewHostModuleBuilder(", strconv.Quote(module), "), impl).Instantiate(ctx)\n")
+	b.WriteString("}\n\n")
+
+	h.file.Write(b.Bytes())
+}
+
+// defineImportedFunction emits a host function registration for imported function hf.
+func (h *hostGenerator) defineImportedFunction(b *bytes.Buffer, hf *hostFunction, linkerName string) {
+	context := h.file.Import("context")
+	api := h.file.Import(wazeroAPIPackage)
+	f := hf.f
+	paramTypes := flatTypes(f.Params)
+	resultTypes := flatTypes(f.Results)
+	spillParams := len(paramTypes) > wit.MaxFlatParams
+	spillResults := len(resultTypes) > wit.MaxFlatResults
+
+	stringio.Write(b, "b.NewFunctionBuilder().\n")
+	stringio.Write(b, "WithGoModuleFunction(", api, ".GoModuleFunc(func(ctx ", context, ".Context, mod ", api, ".Module, stack []uint64) {\n")
+	if spillParams || spillResults || !h.inline(f.Params) || !h.inline(f.Results) {
+		b.WriteString("i := newInstance(ctx, mod)\n")
+	}
+
+	// Lift params
+	if spillParams {
+		ptr := hf.scope.DeclareName("params")
+		stringio.Write(b, ptr, " := uint32(stack[0])\n")
+		offsets := fieldOffsets(f.Params)
+		for k, p := range hf.params {
+			stringio.Write(b, p.name, " := ", h.load(h.file, p.typ, offsetExpr(ptr, offsets[k])), "\n")
+		}
+	} else {
+		var off int
+		for _, p := range hf.params {
+			stringio.Write(b, p.name, " := ", h.lift(h.file, p.typ, "stack", off), "\n")
+			off += len(p.typ.Flat())
+		}
+	}
+
+	// Call host function
+	var assign bool
+	for k, r := range hf.results {
+		if k > 0 {
+			b.WriteString(", ")
+		}
+		if !spillResults && len(r.typ.Flat()) == 0 {
+			b.WriteString("_")
+		} else {
+			b.WriteString(r.name)
+			assign = true
+		}
+	}
+	if assign {
+		b.WriteString(" := ")
+	} else if len(hf.results) > 0 {
+		b.WriteString(" = ")
+	}
+	stringio.Write(b, "impl.", hf.name, "(ctx")
+	for _, p := range hf.params {
+		stringio.Write(b, ", ", p.name)
+	}
+	b.WriteString(")\n")
+
+	// Lower results
+	if spillResults {
+		index := len(paramTypes)
+		if spillParams {
+			index = 1
+		}
+		ptr := hf.scope.DeclareName("results")
+		stringio.Write(b, ptr, " := uint32(stack[", strconv.Itoa(index), "])\n")
+		offsets := fieldOffsets(f.Results)
+		for k, r := range hf.results {
+			stringio.Write(b, h.store(h.file, r.typ, offsetExpr(ptr, offsets[k]), r.name), "\n")
+		}
+	} else {
+		var off int
+		for _, r := range hf.results {
+			if n := len(r.typ.Flat()); n > 0 {
+				stringio.Write(b, h.lower(h.file, r.typ, r.name, "stack", off), "\n")
+				off += n
+			}
+		}
+	}
+
+	// Emit core function type
+	wasmParams := paramTypes
+	if spillParams {
+		wasmParams = []wit.Type{wit.U32{}}
+	}
+	var wasmResults []wit.Type
+	if spillResults {
+		wasmParams = append(wasmParams, wit.U32{})
+	} else {
+		wasmResults = resultTypes
+	}
+	stringio.Write(b, "}), ", h.valueTypes(wasmParams), ", ", h.valueTypes(wasmResults), ").\n")
+	stringio.Write(b, "Export(", strconv.Quote(linkerName), ")\n")
+}
+
+// defineExportedResources emits a Go struct with the handle tables for resources implemented by the guest,
+// and functions to register the resource functions the guest imports from module "[export]<module>".
+// It returns the Go name of the struct.
+func (h *hostGenerator) defineExportedResources(module, goName, desc string, resources []*wit.TypeDef) string {
+	var b bytes.Buffer
+	context := h.file.Import("context")
+	wazero := h.file.Import(wazeroPackage)
+	api := h.file.Import(wazeroAPIPackage)
+	h.defineResourceABI()
+
+	name := h.file.DeclareName(goName)
+	base := strings.TrimSuffix(name, "Resources")
+	register := h.file.DeclareName("Register" + base + "Resources")
+	instantiate := h.file.DeclareName("Instantiate" + base + "Resources")
+	linkerModule := "[export]" + module
+
+	stringio.Write(&b, "// ", name, " holds the handles to resources implemented by the guest for the exported ", desc, ".\n")
+	stringio.Write(&b, "// Register it with [", register, "] or [", instantiate, "] before instantiating the guest module.\n")
+	stringio.Write(&b, "type ", name, " struct {\n")
+	for _, t := range resources {
+		stringio.Write(&b, h.exportedResources[t], " resourceTable\n")
+	}
+	b.WriteString("}\n\n")
+
+	// Emit Register function
+	stringio.Write(&b, "// ", register, " registers the functions the guest calls to create, access, and drop\n")
+	stringio.Write(&b, "// the handles in res on b. Builder b must have module name \"", linkerModule, "\".\n")
+	stringio.Write(&b, "func ", register, "(b ", wazero, ".HostModuleBuilder, res *", name, ") ", wazero, ".HostModuleBuilder {\n")
+	i32 := h.valueTypes([]wit.Type{wit.U32{}})
+	for _, t := range resources {
+		field := "res." + h.exportedResources[t]
+		for _, f := range []struct{ name, body, results string }{
+			{"[resource-new]", "stack[0] = uint64(" + field + ".new(uint32(stack[0])))\n", i32},
+			{"[resource-rep]", "stack[0] = uint64(" + field + ".rep(uint32(stack[0])))\n", i32},
+			{"[resource-drop]", "newInstance(ctx, mod).dtor(" + strconv.Quote(module+"#[dtor]"+*t.Name) + ", " + field + ".drop(uint32(stack[0])))\n", "nil"},
+		} {
+			stringio.Write(&b, "b.NewFunctionBuilder().\n")
+			stringio.Write(&b, "WithGoModuleFunction(", api, ".GoModuleFunc(func(ctx ", context, ".Context, mod ", api, ".Module, stack []uint64) {\n")
+			b.WriteString(f.body)
+			stringio.Write(&b, "}), ", i32, ", ", f.results, ").\n")
+			stringio.Write(&b, "Export(", strconv.Quote(f.name+*t.Name), ")\n")
+		}
+	}
+	b.WriteString("return b\n")
+	b.WriteString("}\n\n")
+
+	// Emit Instantiate function
+	stringio.Write(&b, "// ", instantiate, " instantiates a host module in r with the functions the guest calls\n")
+	stringio.Write(&b, "// to create, access, and drop the handles in res.\n")
+	stringio.Write(&b, "func ", instantiate, "(ctx ", context, ".Context, r ", wazero, ".Runtime, res *", name, ") (", api, ".Module, error) {\n")
+	stringio.Write(&b, "return ", register, "(r.NewHostModuleBuilder(", strconv.Quote(linkerModule), "), res).Instantiate(ctx)\n")
+	b.WriteString("}\n\n")
+
+	h.file.Write(b.Bytes())
+	return name
+}
+
+// defineExports emits a Go struct with methods that call the exported functions of a guest module instance.
+// Argument prefix is prepended to each function name, e.g. "example:foo/bar#".
+// If the exports include resources, argument res is the Go name of the struct with their handles.
+func (h *hostGenerator) defineExports(prefix, goName, desc, res string, resources []*wit.TypeDef, funcs []*wit.Function) {
+	var b bytes.Buffer
+	context := h.file.Import("context")
+	api := h.file.Import(wazeroAPIPackage)
+
+	name := h.file.DeclareName(goName)
+	constructor := h.file.DeclareName("New" + name)
+	scope := gen.NewScope(nil)
+	scope.DeclareName("mod")
+	scope.DeclareName("res")
+
+	stringio.Write(&b, "// ", name, " represents the exported ", desc, ",\n")
+	stringio.Write(&b, "// implemented by a guest module instance.\n")
+	stringio.Write(&b, "type ", name, " struct {\n")
+	b.WriteString("mod ")
+	stringio.Write(&b, api, ".Module\n")
+	if res != "" {
+		stringio.Write(&b, "res *", res, "\n")
+	}
+	b.WriteString("}\n\n")
+
+	if res != "" {
+		stringio.Write(&b, "// ", constructor, " returns a [", name, "] that calls the exported functions of guest module instance mod,\n")
+		stringio.Write(&b, "// with the resource handles in res.\n")
+		stringio.Write(&b, "func ", constructor, "(mod ", api, ".Module, res *", res, ") *", name, " {\n")
+		stringio.Write(&b, "return &", name, "{mod: mod, res: res}\n")
+	} else {
+		stringio.Write(&b, "// ", constructor, " returns a [", name, "] that calls the exported functions of guest module instance mod.\n")
+		stringio.Write(&b, "func ", constructor, "(mod ", api, ".Module) *", name, " {\n")
+		stringio.Write(&b, "return &", name, "{mod: mod}\n")
+	}
+	b.WriteString("}\n\n")
+
+	for _, f := range funcs {
+		if f.Async {
+			h.fail(fmt.Errorf("async function %s: %w", f.Name, errHostUnsupported))
+			return
+		}
+		hf := h.hostFunction(f, h.methodName(scope, f), "ctx", "e", "i", "err", "params", "results", "ptr")
+		h.defineExportedFunction(&b, name, hf, prefix+f.Name, resources)
+	}
+
+	for _, t := range resources {
+		dropName := scope.DeclareName("Drop" + h.typeRep(t))
+		stringio.Write(&b, "// ", dropName, " drops handle self to resource \"", t.TypeName(), "\",\n")
+		stringio.Write(&b, "// calling its destructor in the guest if exported.\n")
+		stringio.Write(&b, "func (e *", name, ") ", dropName, "(ctx ", context, ".Context, self ", h.typeRep(t), ") (err error) {\n")
+		b.WriteString("i := newInstance(ctx, e.mod)\n")
+		b.WriteString("defer recoverError(&err)\n")
+		stringio.Write(&b, "i.dtor(", strconv.Quote(prefix+"[dtor]"+*t.Name), ", e.res.", h.exportedResources[t], ".drop(uint32(self)))\n")
+		b.WriteString("return\n")
+		b.WriteString("}\n\n")
+	}
+
+	h.file.Write(b.Bytes())
+}
+
+// defineExportedFunction emits a method on Go struct recv that calls exported function hf.
+// Borrowed handles to resources in resources are lowered as the representation of the resource.
+func (h *hostGenerator) defineExportedFunction(b *bytes.Buffer, recv string, hf *hostFunction, linkerName string, resources []*wit.TypeDef) {
+	f := hf.f
+	paramTypes := flatTypes(f.Params)
+	resultTypes := flatTypes(f.Results)
+
+	// A borrowed handle passed to the guest that implements the resource is lowered as its rep.
+	values := make([]string, len(hf.params))
+	for k, p := range hf.params {
+		values[k] = p.name
+		if t := h.borrowedResource(p.typ); t != nil {
+			if _, ok := h.exportedResources[t]; ok {
+				if !slices.Contains(resources, t) {
+					h.fail(fmt.Errorf("borrowed resource %s from another interface: %w", t.TypeName(), errHostUnsupported))
+					return
+				}
+				values[k] = "e.res." + h.exportedResources[t] + ".rep(uint32(" + p.name + "))"
+			}
+		} else if len(h.exportedResources) > 0 && wit.HasBorrow(p.typ) {
+			h.fail(fmt.Errorf("borrowed resource in param %s of %s: %w", p.typ.WIT(nil, ""), f.Name, errHostUnsupported))
+			return
+		}
+	}
+
+	stringio.Write(b, "// ", hf.name, " calls the exported ", f.WITKind(), " \"", f.Name, "\".\n")
+	if f.Docs.Contents != "" {
+		b.WriteString("//\n")
+		b.WriteString(formatDocComments(f.Docs.Contents, false))
+	}
+	b.WriteString("//\n")
+	b.WriteString(formatDocComments(strings.TrimSuffix(f.WIT(nil, f.BaseName()), ";"), true))
+	stringio.Write(b, "func (e *", recv, ") ", hf.name, h.signature(hf, true), " {\n")
+	b.WriteString("i := newInstance(ctx, e.mod)\n")
+	b.WriteString("defer recoverError(&err)\n")
+
+	// Lower params
+	if len(paramTypes) > wit.MaxFlatParams {
+		r := &wit.Record{}
+		for _, p := range f.Params {
+			r.Fields = append(r.Fields, wit.Field{Name: p.Name, Type: p.Type})
+		}
+		stringio.Write(b, "ptr := i.realloc(", strconv.Itoa(int(r.Size())), ", ", strconv.Itoa(int(r.Align())), ")\n")
+		offsets := fieldOffsets(f.Params)
+		for k, p := range hf.params {
+			stringio.Write(b, h.store(h.file, p.typ, offsetExpr("ptr", offsets[k]), values[k]), "\n")
+		}
+		b.WriteString("params := []uint64{uint64(ptr)}\n")
+	} else if len(paramTypes) > 0 {
+		stringio.Write(b, "params := make([]uint64, ", strconv.Itoa(len(paramTypes)), ")\n")
+		var off int
+		for k, p := range hf.params {
+			stringio.Write(b, h.lower(h.file, p.typ, values[k], "params", off), "\n")
+			off += len(p.typ.Flat())
+		}
+	}
+
+	// Call exported function
+	if len(resultTypes) > 0 {
+		b.WriteString("results := ")
+	}
+	stringio.Write(b, "i.call(", strconv.Quote(linkerName))
+	if len(paramTypes) > 0 {
+		b.WriteString(", params...")
+	}
+	b.WriteString(")\n")
+
+	// Lift results
+	if len(resultTypes) > wit.MaxFlatResults {
+		b.WriteString("ptr := uint32(results[0])\n")
+		offsets := fieldOffsets(f.Results)
+		for k, r := range hf.results {
+			stringio.Write(b, r.name, " = ", h.load(h.file, r.typ, offsetExpr("ptr", offsets[k])), "\n")
+		}
+		stringio.Write(b, "i.postReturn(", strconv.Quote("cabi_post_"+linkerName), ", results)\n")
+	} else {
+		var off int
+		for _, r := range hf.results {
+			stringio.Write(b, r.name, " = ", h.lift(h.file, r.typ, "results", off), "\n")
+			off += len(r.typ.Flat())
+		}
+	}
+	b.WriteString("return\n")
+	b.WriteString("}\n\n")
+}
+
+// inline returns true if params can be lifted and lowered without accessing linear memory.
+func (h *hostGenerator) inline(params []wit.Param) bool {
+	for _, p := range params {
+		switch t := p.Type.(type) {
+		case wit.String:
+			return false
+		case *wit.TypeDef:
+			if _, ok := t.Root().Kind.(wit.String); ok {
+				return false
+			}
+			if _, ok := t.Root().Kind.(wit.Primitive); ok || len(t.Flat()) == 0 {
+				continue
+			}
+			if _, size := h.scalar(t); size == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// valueTypes returns a Go expression for a slice of wazero value types for flat types.
+func (h *hostGenerator) valueTypes(types []wit.Type) string {
+	if len(types) == 0 {
+		return "nil"
+	}
+	api := h.file.Import(wazeroAPIPackage)
+	var b strings.Builder
+	stringio.Write(&b, "[]", api, ".ValueType{")
+	for i, t := range types {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		switch t.(type) {
+		case wit.U64:
+			stringio.Write(&b, api, ".ValueTypeI64")
+		case wit.F32:
+			stringio.Write(&b, api, ".ValueTypeF32")
+		case wit.F64:
+			stringio.Write(&b, api, ".ValueTypeF64")
+		default:
+			stringio.Write(&b, api, ".ValueTypeI32")
+		}
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func flatTypes(params []wit.Param) []wit.Type {
+	var flat []wit.Type
+	for _, p := range params {
+		flat = append(flat, p.Type.Flat()...)
+	}
+	return flat
+}
+
+// fieldOffsets returns the byte offsets of params laid out in memory as a record.
+func fieldOffsets(params []wit.Param) []uintptr {
+	offsets := make([]uintptr, len(params))
+	var off uintptr
+	for i, p := range params {
+		off = wit.Align(off, p.Type.Align())
+		offsets[i] = off
+		off += p.Type.Size()
+	}
+	return offsets
+}
+
+func offsetExpr(ptr string, off uintptr) string {
+	if off == 0 {
+		return ptr
+	}
+	return ptr + "+" + strconv.Itoa(int(off))
+}
+
+// typeRep returns the Go type representation of [wit.Type] t in the generated package.
+func (h *hostGenerator) typeRep(t wit.Type) string {
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		if t.Name != nil {
+			return h.typeDecl(t)
+		}
+		return h.kindRep(t.Kind)
+	case wit.Bool:
+		return "bool"
+	case wit.S8:
+		return "int8"
+	case wit.U8:
+		return "uint8"
+	case wit.S16:
+		return "int16"
+	case wit.U16:
+		return "uint16"
+	case wit.S32:
+		return "int32"
+	case wit.U32:
+		return "uint32"
+	case wit.S64:
+		return "int64"
+	case wit.U64:
+		return "uint64"
+	case wit.F32:
+		return "float32"
+	case wit.F64:
+		return "float64"
+	case wit.Char:
+		return "rune"
+	case wit.String:
+		return "string"
+	}
+	h.fail(fmt.Errorf("type %s: %w", t.WIT(nil, ""), errHostUnsupported))
+	return "any"
+}
+
+func (h *hostGenerator) kindRep(kind wit.TypeDefKind) string {
+	switch kind := kind.(type) {
+	case *wit.TypeDef:
+		return h.typeRep(kind)
+	case *wit.Tuple:
+		var b strings.Builder
+		b.WriteString("struct {")
+		for i, t := range kind.Types {
+			if i > 0 {
+				b.WriteString("; ")
+			}
+			stringio.Write(&b, "F", strconv.Itoa(i), " ", h.typeRep(t))
+		}
+		b.WriteString("}")
+		return b.String()
+	case *wit.List:
+		return "[]" + h.typeRep(kind.Type)
+	case *wit.Option:
+		return "*" + h.typeRep(kind.Type)
+	case *wit.Result:
+		return h.abi.GetName("Result") + "[" + h.optionalTypeRep(kind.OK) + ", " + h.optionalTypeRep(kind.Err) + "]"
+	case *wit.Own:
+		return h.typeRep(kind.Type)
+	case *wit.Borrow:
+		return h.typeRep(kind.Type)
+	case wit.Primitive:
+		return h.typeRep(kind)
+	}
+	h.fail(fmt.Errorf("type %s: %w", kind.WIT(nil, ""), errHostUnsupported))
+	return "any"
+}
+
+func (h *hostGenerator) optionalTypeRep(t wit.Type) string {
+	if t == nil {
+		return "struct{}"
+	}
+	return h.typeRep(t)
+}
+
+// typeDecl declares and defines named [wit.TypeDef] t, returning its Go name.
+func (h *hostGenerator) typeDecl(t *wit.TypeDef) string {
+	if name, ok := h.types[t]; ok {
+		return name
+	}
+	name := h.declareName(t.Owner, *t.Name)
+	h.types[t] = name
+
+	var b bytes.Buffer
+	stringio.Write(&b, "// ", name, " represents the ", t.WITKind(), " \"", h.qualifiedName(t), "\".\n")
+	if t.Docs.Contents != "" {
+		b.WriteString("//\n")
+		b.WriteString(formatDocComments(t.Docs.Contents, false))
+	}
+
+	switch kind := t.Kind.(type) {
+	case *wit.Record:
+		stringio.Write(&b, "type ", name, " struct {\n")
+		for _, f := range kind.Fields {
+			if f.Docs.Contents != "" {
+				b.WriteString(formatDocComments(f.Docs.Contents, false))
+			}
+			stringio.Write(&b, fieldName(f.Name, true), " ", h.typeRep(f.Type), "\n")
+		}
+		b.WriteString("}\n\n")
+
+	case *wit.Variant:
+		scope := gen.NewScope(nil)
+		scope.DeclareName("Tag")
+		stringio.Write(&b, "//\n// Tag is the variant case. Cases with a value store it in the field with the same name.\n")
+		stringio.Write(&b, "type ", name, " struct {\n")
+		stringio.Write(&b, "Tag uint32\n")
+		for _, c := range kind.Cases {
+			if c.Type != nil {
+				stringio.Write(&b, scope.DeclareName(GoName(c.Name, true)), " ", h.typeRep(c.Type), "\n")
+			}
+		}
+		b.WriteString("}\n\n")
+		b.WriteString("const (\n")
+		for i, c := range kind.Cases {
+			stringio.Write(&b, h.file.DeclareName(name+GoName(c.Name, true)), " uint32 = ", strconv.Itoa(i), "\n")
+		}
+		b.WriteString(")\n\n")
+
+	case *wit.Enum:
+		stringio.Write(&b, "type ", name, " ", h.typeRep(wit.Discriminant(len(kind.Cases))), "\n\n")
+		b.WriteString("const (\n")
+		for i, c := range kind.Cases {
+			stringio.Write(&b, h.file.DeclareName(name+GoName(c.Name, true)), " ", name, " = ", strconv.Itoa(i), "\n")
+		}
+		b.WriteString(")\n\n")
+
+	case *wit.Flags:
+		if len(kind.Flags) > 32 {
+			h.fail(fmt.Errorf("flags type %s with more than 32 flags: %w", t.TypeName(), errHostUnsupported))
+			return name
+		}
+		stringio.Write(&b, "type ", name, " ", h.typeRep(flagsType(kind)), "\n\n")
+		b.WriteString("const (\n")
+		for i, f := range kind.Flags {
+			stringio.Write(&b, h.file.DeclareName(name+GoName(f.Name, true)), " ", name, " = 1 << ", strconv.Itoa(i), "\n")
+		}
+		b.WriteString(")\n\n")
+
+	case *wit.Resource:
+		if _, ok := h.exportedResources[t]; ok {
+			stringio.Write(&b, "// It is a handle to a resource implemented by the guest.\n")
+		} else {
+			stringio.Write(&b, "// It is a handle to a resource implemented by the host.\n")
+		}
+		stringio.Write(&b, "type ", name, " uint32\n\n")
+
+	default:
+		stringio.Write(&b, "type ", name, " = ", h.kindRep(kind), "\n\n")
+	}
+
+	h.file.Write(b.Bytes())
+	return name
+}
+
+func (h *hostGenerator) qualifiedName(t *wit.TypeDef) string {
+	if name := h.moduleNames[t.Owner]; name != "" {
+		return name + "#" + *t.Name
+	}
+	return t.TypeName()
+}
+
+func flagsType(f *wit.Flags) wit.Type {
+	switch f.Size() {
+	case 1:
+		return wit.U8{}
+	case 2:
+		return wit.U16{}
+	}
+	return wit.U32{}
+}
+
+// load returns a Go expression that loads a value of [wit.Type] t from linear memory at ptr.
+func (h *hostGenerator) load(file *gen.File, t wit.Type, ptr string) string {
+	switch t := t.(type) {
+	case wit.Bool:
+		return "(i.readU8(" + ptr + ") != 0)"
+	case wit.S8:
+		return "int8(i.readU8(" + ptr + "))"
+	case wit.U8:
+		return "i.readU8(" + ptr + ")"
+	case wit.S16:
+		return "int16(i.readU16(" + ptr + "))"
+	case wit.U16:
+		return "i.readU16(" + ptr + ")"
+	case wit.S32:
+		return "int32(i.readU32(" + ptr + "))"
+	case wit.U32:
+		return "i.readU32(" + ptr + ")"
+	case wit.S64:
+		return "int64(i.readU64(" + ptr + "))"
+	case wit.U64:
+		return "i.readU64(" + ptr + ")"
+	case wit.F32:
+		return file.Import("math") + ".Float32frombits(i.readU32(" + ptr + "))"
+	case wit.F64:
+		return file.Import("math") + ".Float64frombits(i.readU64(" + ptr + "))"
+	case wit.Char:
+		return "rune(i.readU32(" + ptr + "))"
+	case wit.String:
+		return "i.loadString(" + ptr + ")"
+	case *wit.TypeDef:
+		if p, ok := t.Root().Kind.(wit.Primitive); ok {
+			return h.load(file, p, ptr)
+		}
+		if rep, size := h.scalar(t); size > 0 {
+			return rep + "(i.readU" + strconv.Itoa(int(size)*8) + "(" + ptr + "))"
+		}
+		return "i.load" + h.abiName(t) + "(" + ptr + ")"
+	}
+	return ""
+}
+
+// store returns a Go statement that stores Go value v of [wit.Type] t into linear memory at ptr.
+func (h *hostGenerator) store(file *gen.File, t wit.Type, ptr, v string) string {
+	switch t := t.(type) {
+	case wit.Bool:
+		return "i.writeU8(" + ptr + ", uint8(boolToU32(" + v + ")))"
+	case wit.S8, wit.U8:
+		return "i.writeU8(" + ptr + ", uint8(" + v + "))"
+	case wit.S16, wit.U16:
+		return "i.writeU16(" + ptr + ", uint16(" + v + "))"
+	case wit.S32, wit.U32, wit.Char:
+		return "i.writeU32(" + ptr + ", uint32(" + v + "))"
+	case wit.S64, wit.U64:
+		return "i.writeU64(" + ptr + ", uint64(" + v + "))"
+	case wit.F32:
+		return "i.writeU32(" + ptr + ", " + file.Import("math") + ".Float32bits(" + v + "))"
+	case wit.F64:
+		return "i.writeU64(" + ptr + ", " + file.Import("math") + ".Float64bits(" + v + "))"
+	case wit.String:
+		return "i.storeString(" + ptr + ", " + v + ")"
+	case *wit.TypeDef:
+		if p, ok := t.Root().Kind.(wit.Primitive); ok {
+			return h.store(file, p, ptr, v)
+		}
+		if _, size := h.scalar(t); size > 0 {
+			bits := strconv.Itoa(int(size) * 8)
+			return "i.writeU" + bits + "(" + ptr + ", uint" + bits + "(" + v + "))"
+		}
+		return "i.store" + h.abiName(t) + "(" + ptr + ", " + v + ")"
+	}
+	return ""
+}
+
+// lift returns a Go expression that lifts a value of [wit.Type] t from flat values flat[off:].
+func (h *hostGenerator) lift(file *gen.File, t wit.Type, flat string, off int) string {
+	n := len(t.Flat())
+	f := flat + "[" + strconv.Itoa(off) + "]"
+	switch t := t.(type) {
+	case wit.Bool:
+		return "(uint32(" + f + ") != 0)"
+	case wit.S8:
+		return "int8(" + f + ")"
+	case wit.U8:
+		return "uint8(" + f + ")"
+	case wit.S16:
+		return "int16(" + f + ")"
+	case wit.U16:
+		return "uint16(" + f + ")"
+	case wit.S32:
+		return "int32(" + f + ")"
+	case wit.U32:
+		return "uint32(" + f + ")"
+	case wit.S64:
+		return "int64(" + f + ")"
+	case wit.U64:
+		return f
+	case wit.F32:
+		return file.Import("math") + ".Float32frombits(uint32(" + f + "))"
+	case wit.F64:
+		return file.Import("math") + ".Float64frombits(" + f + ")"
+	case wit.Char:
+		return "rune(" + f + ")"
+	case wit.String:
+		return "i.readString(uint32(" + f + "), uint32(" + flat + "[" + strconv.Itoa(off+1) + "]))"
+	case *wit.TypeDef:
+		if p, ok := t.Root().Kind.(wit.Primitive); ok {
+			return h.lift(file, p, flat, off)
+		}
+		if rep, size := h.scalar(t); size > 0 {
+			return rep + "(" + f + ")"
+		}
+		if n == 0 {
+			return "*new(" + h.typeRep(t) + ")"
+		}
+		return "i.lift" + h.abiName(t) + "(" + flat + "[" + strconv.Itoa(off) + ":" + strconv.Itoa(off+n) + "])"
+	}
+	return ""
+}
+
+// lower returns a Go statement that lowers Go value v of [wit.Type] t into flat values flat[off:].
+func (h *hostGenerator) lower(file *gen.File, t wit.Type, v, flat string, off int) string {
+	n := len(t.Flat())
+	f := flat + "[" + strconv.Itoa(off) + "]"
+	switch t := t.(type) {
+	case wit.Bool:
+		return f + " = uint64(boolToU32(" + v + "))"
+	case wit.S8, wit.S16, wit.S32:
+		return f + " = uint64(uint32(" + v + "))"
+	case wit.U8, wit.U16, wit.U32, wit.S64, wit.Char:
+		return f + " = uint64(" + v + ")"
+	case wit.U64:
+		return f + " = " + v
+	case wit.F32:
+		return f + " = uint64(" + file.Import("math") + ".Float32bits(" + v + "))"
+	case wit.F64:
+		return f + " = " + file.Import("math") + ".Float64bits(" + v + ")"
+	case wit.String:
+		return f + ", " + flat + "[" + strconv.Itoa(off+1) + "] = i.lowerString(" + v + ")"
+	case *wit.TypeDef:
+		if p, ok := t.Root().Kind.(wit.Primitive); ok {
+			return h.lower(file, p, v, flat, off)
+		}
+		if _, size := h.scalar(t); size > 0 {
+			return f + " = uint64(" + v + ")"
+		}
+		if n == 0 {
+			return ""
+		}
+		return "i.lower" + h.abiName(t) + "(" + v + ", " + flat + "[" + strconv.Itoa(off) + ":" + strconv.Itoa(off+n) + "])"
+	}
+	return ""
+}
+
+// borrowedResource returns the resource type of [wit.Type] t if t is a borrowed handle, otherwise nil.
+func (h *hostGenerator) borrowedResource(t wit.Type) *wit.TypeDef {
+	if td, ok := t.(*wit.TypeDef); ok {
+		if b, ok := td.Root().Kind.(*wit.Borrow); ok {
+			return b.Type.Root()
+		}
+	}
+	return nil
+}
+
+// scalar returns the Go type and byte size of [wit.TypeDef] t if it is represented
+// as a single unsigned integer, such as an enum, flags, or resource handle. Otherwise it returns 0.
+func (h *hostGenerator) scalar(t *wit.TypeDef) (string, uintptr) {
+	switch kind := t.Root().Kind.(type) {
+	case *wit.Enum, *wit.Flags, *wit.Resource:
+		return h.typeRep(t), t.Size()
+	case *wit.Own:
+		return h.typeRep(kind.Type), 4
+	case *wit.Borrow:
+		return h.typeRep(kind.Type), 4
+	}
+	return "", 0
+}
+
+// abiName returns the name suffix for the load, store, lift, and lower functions for
+// [wit.TypeDef] t, defining them if necessary.
+func (h *hostGenerator) abiName(t *wit.TypeDef) string {
+	rep := h.typeRep(t.Root())
+	if name, ok := h.abiNames[rep]; ok {
+		return name
+	}
+	name := h.abiScope.DeclareName(h.abiTypeName(t.Root()))
+	h.abiNames[rep] = name
+	h.defineABIFunctions(t.Root(), rep, name)
+	return name
+}
+
+// abiTypeName returns a descriptive Go name for [wit.Type] t, used to name ABI functions.
+func (h *hostGenerator) abiTypeName(t wit.Type) string {
+	td, ok := t.(*wit.TypeDef)
+	if !ok {
+		return GoName(t.WIT(nil, ""), true)
+	}
+	if td.Name != nil {
+		return h.typeRep(td)
+	}
+	switch kind := td.Kind.(type) {
+	case *wit.List:
+		return "List" + h.abiTypeName(kind.Type)
+	case *wit.Option:
+		return "Option" + h.abiTypeName(kind.Type)
+	case *wit.Result:
+		name := "Result"
+		for _, t := range []wit.Type{kind.OK, kind.Err} {
+			if t == nil {
+				name += "Empty"
+			} else {
+				name += h.abiTypeName(t)
+			}
+		}
+		return name
+	case *wit.Tuple:
+		name := "Tuple"
+		for _, t := range kind.Types {
+			name += h.abiTypeName(t)
+		}
+		return name
+	}
+	return "Type"
+}
+
+// defineABIFunctions emits the load, store, lift, and lower functions for
+// [wit.TypeDef] t with Go type rep, with function name suffix name.
+func (h *hostGenerator) defineABIFunctions(t *wit.TypeDef, rep, name string) {
+	var load, store, lift, lower bytes.Buffer
+	stringio.Write(&load, "func (i *instance) load", name, "(ptr uint32) (v ", rep, ") {\n")
+	stringio.Write(&store, "func (i *instance) store", name, "(ptr uint32, v ", rep, ") {\n")
+	stringio.Write(&lift, "func (i *instance) lift", name, "(flat []uint64) (v ", rep, ") {\n")
+	stringio.Write(&lower, "func (i *instance) lower", name, "(v ", rep, ", flat []uint64) {\n")
+
+	switch kind := t.Kind.(type) {
+	case *wit.Record:
+		params := make([]wit.Param, len(kind.Fields))
+		for k, f := range kind.Fields {
+			params[k] = wit.Param{Name: f.Name, Type: f.Type}
+		}
+		h.defineStructABI(&load, &store, &lift, &lower, params, func(k int) string {
+			return "v." + fieldName(kind.Fields[k].Name, true)
+		})
+
+	case *wit.Tuple:
+		params := make([]wit.Param, len(kind.Types))
+		for k, t := range kind.Types {
+			params[k] = wit.Param{Type: t}
+		}
+		h.defineStructABI(&load, &store, &lift, &lower, params, func(k int) string {
+			return "v.F" + strconv.Itoa(k)
+		})
+
+	case *wit.List:
+		elem := kind.Type
+		size := strconv.Itoa(int(elem.Size()))
+		align := strconv.Itoa(int(elem.Align()))
+		if _, ok := elem.(wit.U8); ok {
+			stringio.Write(&load, "return i.readBytes(i.readU32(ptr), i.readU32(ptr+4))\n")
+			stringio.Write(&store, "p, n := i.writeBytes(v)\n")
+			stringio.Write(&lift, "return i.readBytes(uint32(flat[0]), uint32(flat[1]))\n")
+			stringio.Write(&lower, "p, n := i.writeBytes(v)\n")
+		} else {
+			stringio.Write(&load, "return i.lift", name, "([]uint64{uint64(i.readU32(ptr)), uint64(i.readU32(ptr+4))})\n")
+			stringio.Write(&lift, "ptr, n := uint32(flat[0]), uint32(flat[1])\n")
+			stringio.Write(&lift, "v = make(", rep, ", n)\n")
+			stringio.Write(&lift, "for k := range v {\n")
+			stringio.Write(&lift, "v[k] = ", h.load(h.abi, elem, "ptr+uint32(k)*"+size), "\n")
+			lift.WriteString("}\n")
+			lift.WriteString("return\n")
+			stringio.Write(&store, "p, n := i.write", name, "(v)\n")
+			stringio.Write(&lower, "p, n := i.write", name, "(v)\n")
+
+			// Helper to allocate and store list elements
+			var write bytes.Buffer
+			stringio.Write(&write, "func (i *instance) write", name, "(v ", rep, ") (ptr, n uint32) {\n")
+			write.WriteString("n = uint32(len(v))\n")
+			stringio.Write(&write, "ptr = i.realloc(n*", size, ", ", align, ")\n")
+			write.WriteString("for k := range v {\n")
+			stringio.Write(&write, h.store(h.abi, elem, "ptr+uint32(k)*"+size, "v[k]"), "\n")
+			write.WriteString("}\n")
+			write.WriteString("return\n")
+			write.WriteString("}\n\n")
+			defer h.abi.Write(write.Bytes())
+		}
+		store.WriteString("i.writeU32(ptr, p)\n")
+		store.WriteString("i.writeU32(ptr+4, n)\n")
+		lower.WriteString("flat[0], flat[1] = uint64(p), uint64(n)\n")
+
+	case *wit.Option:
+		v := kind.Despecialize().(*wit.Variant)
+		h.defineVariantABI(&load, &store, &lift, &lower, v,
+			"boolToU32(v != nil)",
+			func(k int, set func(string) string) string {
+				if k == 0 {
+					return ""
+				}
+				return "x := " + set("") + "\nv = &x\n"
+			},
+			func(k int) string { return "*v" })
+
+	case *wit.Result:
+		v := kind.Despecialize().(*wit.Variant)
+		h.defineVariantABI(&load, &store, &lift, &lower, v,
+			"boolToU32(v.IsErr)",
+			func(k int, set func(string) string) string {
+				field := [2]string{"OK", "Err"}[k]
+				s := ""
+				if k == 1 {
+					s = "v.IsErr = true\n"
+				}
+				if v.Cases[k].Type != nil {
+					s += "v." + field + " = " + set("") + "\n"
+				}
+				return s
+			},
+			func(k int) string { return "v." + [2]string{"OK", "Err"}[k] })
+
+	case *wit.Variant:
+		scope := gen.NewScope(nil)
+		scope.DeclareName("Tag")
+		fields := make([]string, len(kind.Cases))
+		for k, c := range kind.Cases {
+			if c.Type != nil {
+				fields[k] = scope.DeclareName(GoName(c.Name, true))
+			}
+		}
+		h.defineVariantABI(&load, &store, &lift, &lower, kind,
+			"v.Tag",
+			func(k int, set func(string) string) string {
+				s := "v.Tag = " + strconv.Itoa(k) + "\n"
+				if fields[k] != "" {
+					s += "v." + fields[k] + " = " + set("") + "\n"
+				}
+				return s
+			},
+			func(k int) string { return "v." + fields[k] })
+
+	default:
+		h.fail(errors.New("BUG: unexpected type " + t.WIT(nil, "") + " for host ABI functions"))
+	}
+
+	load.WriteString("}\n\n")
+	store.WriteString("}\n\n")
+	lift.WriteString("}\n\n")
+	lower.WriteString("}\n\n")
+	h.abi.Write(load.Bytes())
+	h.abi.Write(store.Bytes())
+	h.abi.Write(lift.Bytes())
+	h.abi.Write(lower.Bytes())
+}
+
+// defineStructABI emits the bodies of the ABI functions for a Go struct with fields of params.
+// Argument field returns the Go expression for the field at index k.
+func (h *hostGenerator) defineStructABI(load, store, lift, lower *bytes.Buffer, params []wit.Param, field func(k int) string) {
+	offsets := fieldOffsets(params)
+	var off int
+	for k, p := range params {
+		stringio.Write(load, field(k), " = ", h.load(h.abi, p.Type, offsetExpr("ptr", offsets[k])), "\n")
+		stringio.Write(store, h.store(h.abi, p.Type, offsetExpr("ptr", offsets[k]), field(k)), "\n")
+		stringio.Write(lift, field(k), " = ", h.lift(h.abi, p.Type, "flat", off), "\n")
+		stringio.Write(lower, h.lower(h.abi, p.Type, field(k), "flat", off), "\n")
+		off += len(p.Type.Flat())
+	}
+	load.WriteString("return\n")
+	lift.WriteString("return\n")
+}
+
+// defineVariantABI emits the bodies of the ABI functions for a Go representation of variant v.
+// Argument tag is a Go expression for the variant case of v.
+// Argument set returns Go statements that set the case k, calling value to get the Go expression for the case value.
+// Argument get returns the Go expression for the value of case k.
+func (h *hostGenerator) defineVariantABI(load, store, lift, lower *bytes.Buffer, v *wit.Variant, tag string, set func(k int, value func(string) string) string, get func(k int) string) {
+	disc := wit.Discriminant(len(v.Cases))
+	var align uintptr = 1
+	for _, c := range v.Cases {
+		if c.Type != nil {
+			align = max(align, c.Type.Align())
+		}
+	}
+	payload := offsetExpr("ptr", wit.Align(disc.Size(), align))
+
+	stringio.Write(load, "switch ", h.load(h.abi, disc, "ptr"), " {\n")
+	stringio.Write(lift, "switch uint32(flat[0]) {\n")
+	stringio.Write(store, h.store(h.abi, disc, "ptr", tag), "\n")
+	stringio.Write(store, "switch ", tag, " {\n")
+	stringio.Write(lower, "flat[0] = uint64(", tag, ")\n")
+	stringio.Write(lower, "switch ", tag, " {\n")
+	for k, c := range v.Cases {
+		stringio.Write(load, "case ", strconv.Itoa(k), ":\n")
+		stringio.Write(lift, "case ", strconv.Itoa(k), ":\n")
+		load.WriteString(set(k, func(string) string { return h.load(h.abi, c.Type, payload) }))
+		lift.WriteString(set(k, func(string) string { return h.lift(h.abi, c.Type, "flat", 1) }))
+		if c.Type != nil {
+			stringio.Write(store, "case ", strconv.Itoa(k), ":\n")
+			stringio.Write(store, h.store(h.abi, c.Type, payload, get(k)), "\n")
+			stringio.Write(lower, "case ", strconv.Itoa(k), ":\n")
+			stringio.Write(lower, h.lower(h.abi, c.Type, get(k), "flat", 1), "\n")
+		}
+	}
+	load.WriteString("}\n")
+	load.WriteString("return\n")
+	lift.WriteString("}\n")
+	lift.WriteString("return\n")
+	store.WriteString("}\n")
+	lower.WriteString("}\n")
+}
+
+// defineHostABI emits the instance type and common functions used by the generated host bindings.
+func (h *hostGenerator) defineHostABI() {
+	file := h.abi
+	for _, name := range []string{"instance", "newInstance", "recoverError", "boolToU32", "errOutOfRange", "Result"} {
+		file.DeclareName(name)
+	}
+	r := strings.NewReplacer(
+		"context.", file.Import("context")+".",
+		"errors.", file.Import("errors")+".",
+		"fmt.", file.Import("fmt")+".",
+		"api.", file.Import(wazeroAPIPackage)+".",
+	)
+	file.WriteString(r.Replace(hostABI))
+}
+
+// defineResourceABI emits the resource table type used for resources implemented by the guest, once.
+func (h *hostGenerator) defineResourceABI() {
+	file := h.abi
+	if file.HasName("resourceTable") {
+		return
+	}
+	file.DeclareName("resourceTable")
+	r := strings.NewReplacer("fmt.", file.Import("fmt")+".")
+	file.WriteString(r.Replace(hostResourceABI))
+}
+
+const hostResourceABI = `// resourceTable maps the handles to resources implemented by the guest to their representation.
+// Handle 0 is never used.
+type resourceTable struct {
+	next uint32
+	reps map[uint32]uint32
+}
+
+// new returns a new handle to the resource with representation rep.
+func (t *resourceTable) new(rep uint32) uint32 {
+	if t.reps == nil {
+		t.reps = make(map[uint32]uint32)
+	}
+	t.next++
+	t.reps[t.next] = rep
+	return t.next
+}
+
+// rep returns the representation of the resource with handle.
+func (t *resourceTable) rep(handle uint32) uint32 {
+	rep, ok := t.reps[handle]
+	if !ok {
+		panic(fmt.Errorf("unknown resource handle %d", handle))
+	}
+	return rep
+}
+
+// drop removes handle from t and returns the representation of the resource.
+func (t *resourceTable) drop(handle uint32) uint32 {
+	rep := t.rep(handle)
+	delete(t.reps, handle)
+	return rep
+}
+
+// dtor calls the exported destructor name with rep, if exported.
+func (i *instance) dtor(name string, rep uint32) {
+	if f := i.mod.ExportedFunction(name); f != nil {
+		if _, err := f.Call(i.ctx, uint64(rep)); err != nil {
+			panic(err)
+		}
+	}
+}
+
+`
+
+const hostABI = `// Result represents a Component Model result type.
+// If IsErr is true, Err holds the error value. Otherwise, OK holds the OK value.
+type Result[OK, Err any] struct {
+	OK    OK
+	Err   Err
+	IsErr bool
+}
+
+// instance represents a guest module instance, with access to its linear memory
+// and cabi_realloc function for lifting and lowering Component Model values.
+// Its methods panic on error, which is recovered by recoverError or the wazero runtime.
+type instance struct {
+	ctx context.Context
+	mod api.Module
+	mem api.Memory
+}
+
+func newInstance(ctx context.Context, mod api.Module) *instance {
+	return &instance{ctx: ctx, mod: mod, mem: mod.Memory()}
+}
+
+var errOutOfRange = errors.New("memory access out of range")
+
+// recoverError recovers from a panic in the calling function, and sets *err.
+func recoverError(err *error) {
+	if r := recover(); r != nil {
+		if e, ok := r.(error); ok {
+			*err = e
+		} else {
+			*err = fmt.Errorf("%v", r)
+		}
+	}
+}
+
+// call calls the exported function name with params, and returns its results.
+func (i *instance) call(name string, params ...uint64) []uint64 {
+	f := i.mod.ExportedFunction(name)
+	if f == nil {
+		panic(fmt.Errorf("exported function %q not found", name))
+	}
+	results, err := f.Call(i.ctx, params...)
+	if err != nil {
+		panic(err)
+	}
+	return results
+}
+
+// postReturn calls the post-return function name with results, if exported.
+func (i *instance) postReturn(name string, results []uint64) {
+	if f := i.mod.ExportedFunction(name); f != nil {
+		if _, err := f.Call(i.ctx, results...); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// realloc allocates size bytes with alignment align in linear memory with the exported cabi_realloc function.
+func (i *instance) realloc(size, align uint32) uint32 {
+	if size == 0 {
+		return 0
+	}
+	results := i.call("cabi_realloc", 0, 0, uint64(align), uint64(size))
+	return uint32(results[0])
+}
+
+func (i *instance) readU8(ptr uint32) uint8 {
+	v, ok := i.mem.ReadByte(ptr)
+	if !ok {
+		panic(errOutOfRange)
+	}
+	return v
+}
+
+func (i *instance) readU16(ptr uint32) uint16 {
+	v, ok := i.mem.ReadUint16Le(ptr)
+	if !ok {
+		panic(errOutOfRange)
+	}
+	return v
+}
+
+func (i *instance) readU32(ptr uint32) uint32 {
+	v, ok := i.mem.ReadUint32Le(ptr)
+	if !ok {
+		panic(errOutOfRange)
+	}
+	return v
+}
+
+func (i *instance) readU64(ptr uint32) uint64 {
+	v, ok := i.mem.ReadUint64Le(ptr)
+	if !ok {
+		panic(errOutOfRange)
+	}
+	return v
+}
+
+func (i *instance) writeU8(ptr uint32, v uint8) {
+	if !i.mem.WriteByte(ptr, v) {
+		panic(errOutOfRange)
+	}
+}
+
+func (i *instance) writeU16(ptr uint32, v uint16) {
+	if !i.mem.WriteUint16Le(ptr, v) {
+		panic(errOutOfRange)
+	}
+}
+
+func (i *instance) writeU32(ptr uint32, v uint32) {
+	if !i.mem.WriteUint32Le(ptr, v) {
+		panic(errOutOfRange)
+	}
+}
+
+func (i *instance) writeU64(ptr uint32, v uint64) {
+	if !i.mem.WriteUint64Le(ptr, v) {
+		panic(errOutOfRange)
+	}
+}
+
+// readBytes returns a copy of n bytes of linear memory at ptr.
+func (i *instance) readBytes(ptr, n uint32) []byte {
+	b, ok := i.mem.Read(ptr, n)
+	if !ok {
+		panic(errOutOfRange)
+	}
+	return append([]byte(nil), b...)
+}
+
+// writeBytes allocates linear memory for b and copies b into it.
+func (i *instance) writeBytes(b []byte) (ptr, n uint32) {
+	n = uint32(len(b))
+	ptr = i.realloc(n, 1)
+	if !i.mem.Write(ptr, b) {
+		panic(errOutOfRange)
+	}
+	return ptr, n
+}
+
+func (i *instance) readString(ptr, n uint32) string {
+	b, ok := i.mem.Read(ptr, n)
+	if !ok {
+		panic(errOutOfRange)
+	}
+	return string(b)
+}
+
+func (i *instance) loadString(ptr uint32) string {
+	return i.readString(i.readU32(ptr), i.readU32(ptr+4))
+}
+
+func (i *instance) storeString(ptr uint32, s string) {
+	p, n := i.writeBytes([]byte(s))
+	i.writeU32(ptr, p)
+	i.writeU32(ptr+4, n)
+}
+
+func (i *instance) lowerString(s string) (uint64, uint64) {
+	p, n := i.writeBytes([]byte(s))
+	return uint64(p), uint64(n)
+}
+
+func boolToU32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+`
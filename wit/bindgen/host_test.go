@@ -0,0 +1,301 @@
+//go:build !tinygo
+
+package bindgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"go.bytecodealliance.org/internal/go/gen"
+	"go.bytecodealliance.org/internal/relpath"
+	"go.bytecodealliance.org/internal/wasmtools"
+	"go.bytecodealliance.org/wit"
+)
+
+const hostRoundTripWIT = `package example:roundtrip;
+
+interface types {
+	variant value {
+		none,
+		text(string),
+		number(u32),
+	}
+}
+
+interface host {
+	use types.{value};
+	echo-string: func(s: string) -> string;
+	echo-list: func(l: list<string>) -> list<string>;
+	echo-value: func(v: value) -> value;
+}
+
+interface guest {
+	use types.{value};
+	resource counter {
+		constructor(start: u32);
+		add: func(n: u32) -> u32;
+	}
+	echo-string: func(s: string) -> string;
+	echo-list: func(l: list<string>) -> list<string>;
+	echo-value: func(v: value) -> value;
+	dropped: func() -> u32;
+}
+
+world roundtrip {
+	import host;
+	export guest;
+}
+`
+
+// hostRoundTripWAT is a guest module for hostRoundTripWIT. Its echo functions pass their
+// arguments to the host import with the same name, and return the result the host stored in
+// the return area at address 16. Counters are stored at the address of their representation.
+const hostRoundTripWAT = `(module
+	(import "example:roundtrip/host" "echo-string" (func $echo-string (param i32 i32 i32)))
+	(import "example:roundtrip/host" "echo-list" (func $echo-list (param i32 i32 i32)))
+	(import "example:roundtrip/host" "echo-value" (func $echo-value (param i32 i32 i32 i32)))
+	(import "[export]example:roundtrip/guest" "[resource-new]counter" (func $counter-new (param i32) (result i32)))
+	(memory (export "memory") 1)
+	(global $heap (mut i32) (i32.const 4096))
+	(global $counters (mut i32) (i32.const 1024))
+	(global $dropped (mut i32) (i32.const 0))
+	(func (export "cabi_realloc") (param i32 i32) (param $align i32) (param $size i32) (result i32)
+		(local $ptr i32)
+		(local.set $ptr
+			(i32.and
+				(i32.add (global.get $heap) (i32.sub (local.get $align) (i32.const 1)))
+				(i32.sub (i32.const 0) (local.get $align))))
+		(global.set $heap (i32.add (local.get $ptr) (local.get $size)))
+		(local.get $ptr))
+	(func (export "example:roundtrip/guest#echo-string") (param i32 i32) (result i32)
+		(call $echo-string (local.get 0) (local.get 1) (i32.const 16))
+		(i32.const 16))
+	(func (export "example:roundtrip/guest#echo-list") (param i32 i32) (result i32)
+		(call $echo-list (local.get 0) (local.get 1) (i32.const 16))
+		(i32.const 16))
+	(func (export "example:roundtrip/guest#echo-value") (param i32 i32 i32) (result i32)
+		(call $echo-value (local.get 0) (local.get 1) (local.get 2) (i32.const 16))
+		(i32.const 16))
+	(func (export "example:roundtrip/guest#[constructor]counter") (param $start i32) (result i32)
+		(local $rep i32)
+		(local.set $rep (global.get $counters))
+		(global.set $counters (i32.add (local.get $rep) (i32.const 4)))
+		(i32.store (local.get $rep) (local.get $start))
+		(call $counter-new (local.get $rep)))
+	(func (export "example:roundtrip/guest#[method]counter.add") (param $self i32) (param $n i32) (result i32)
+		(i32.store (local.get $self) (i32.add (i32.load (local.get $self)) (local.get $n)))
+		(i32.load (local.get $self)))
+	(func (export "example:roundtrip/guest#[dtor]counter") (param i32)
+		(global.set $dropped (i32.add (global.get $dropped) (i32.const 1))))
+	(func (export "example:roundtrip/guest#dropped") (result i32)
+		(global.get $dropped))
+)
+`
+
+// hostRoundTripMain runs the guest module in os.Args[1] with the generated host bindings,
+// printing the values that round-trip through the guest and host.
+const hostRoundTripMain = `package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/tetratelabs/wazero"
+
+	roundtrip "PKG"
+)
+
+type host struct{}
+
+func (host) EchoString(ctx context.Context, s string) string { return s + " from host" }
+
+func (host) EchoList(ctx context.Context, l []string) []string { return append(l, "host") }
+
+func (host) EchoValue(ctx context.Context, v roundtrip.Value) roundtrip.Value {
+	if v.Tag == roundtrip.ValueNumber {
+		v.Number++
+	}
+	return v
+}
+
+func main() {
+	ctx := context.Background()
+	wasm, err := os.ReadFile(os.Args[1])
+	check(err)
+	r := wazero.NewRuntime(ctx)
+	defer r.Close(ctx)
+	_, err = roundtrip.InstantiateHost(ctx, r, host{})
+	check(err)
+	res := &roundtrip.GuestResources{}
+	_, err = roundtrip.InstantiateGuestResources(ctx, r, res)
+	check(err)
+	mod, err := r.Instantiate(ctx, wasm)
+	check(err)
+	guest := roundtrip.NewGuestExports(mod, res)
+
+	s, err := guest.EchoString(ctx, "hello")
+	check(err)
+	fmt.Printf("%q\n", s)
+
+	l, err := guest.EchoList(ctx, []string{"a", "b"})
+	check(err)
+	fmt.Printf("%q\n", l)
+
+	for _, v := range []roundtrip.Value{
+		{Tag: roundtrip.ValueNone},
+		{Tag: roundtrip.ValueText, Text: "text"},
+		{Tag: roundtrip.ValueNumber, Number: 41},
+	} {
+		v, err := guest.EchoValue(ctx, v)
+		check(err)
+		fmt.Printf("%+v\n", v)
+	}
+
+	c1, err := guest.NewCounter(ctx, 10)
+	check(err)
+	c2, err := guest.NewCounter(ctx, 20)
+	check(err)
+	n1, err := guest.CounterAdd(ctx, c1, 1)
+	check(err)
+	n2, err := guest.CounterAdd(ctx, c2, 2)
+	check(err)
+	fmt.Println(n1, n2)
+	check(guest.DropCounter(ctx, c1))
+	dropped, err := guest.Dropped(ctx)
+	check(err)
+	fmt.Println(dropped)
+	_, err = guest.CounterAdd(ctx, c1, 1)
+	fmt.Println(err)
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`
+
+const hostRoundTripOutput = `"hello from host"
+["a" "b" "host"]
+{Tag:0 Text: Number:0}
+{Tag:1 Text:text Number:0}
+{Tag:2 Text: Number:42}
+11 22
+1
+unknown resource handle 1
+`
+
+// TestHostRoundTrip runs a guest module with generated host bindings in the wazero runtime,
+// round-tripping strings, lists, variants, and resources between the host and guest.
+func TestHostRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+	if !canGo() {
+		t.Skip("skipping test: cannot run go command")
+	}
+	ctx := context.Background()
+
+	// Assemble the guest module and check that it is a valid component module for the world.
+	w, err := wasmtools.New(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	err = w.Run(ctx, nil, &stdout, &stderr, map[string]fs.FS{
+		"": fstest.MapFS{
+			"roundtrip.wit": &fstest.MapFile{Data: []byte(hostRoundTripWIT)},
+			"guest.wat":     &fstest.MapFile{Data: []byte(hostRoundTripWAT)},
+		},
+	}, "component", "embed", "roundtrip.wit", "guest.wat")
+	if err != nil {
+		t.Fatalf("wasm-tools: %v: %s", err, stderr.Bytes())
+	}
+	wasm := stdout.Bytes()
+	if _, err := w.ComponentNew(ctx, wasm, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := wit.DecodeWIT(strings.NewReader(hostRoundTripWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := path.Join(generatedPath, "host-roundtrip")
+	if err := os.MkdirAll(dir, fs.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	out, err := relpath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgPath, err := gen.PackagePath(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, GeneratedBy("test"), PackageRoot(pkgPath), Host(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Build the generated bindings and the test program from an overlay, without writing to out.
+	overlay := make(map[string]string)
+	tmp := t.TempDir()
+	addFile := func(name string, src []byte) {
+		tmpFile := filepath.Join(tmp, strings.ReplaceAll(filepath.ToSlash(name), "/", "_"))
+		if err := os.WriteFile(tmpFile, src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		overlay[name] = tmpFile
+	}
+	var bindings string
+	for _, pkg := range pkgs {
+		if !pkg.HasContent() {
+			continue
+		}
+		bindings = pkg.Path
+		dir := filepath.Join(out, strings.TrimPrefix(pkg.Path, pkgPath))
+		for _, file := range pkg.Files {
+			src, err := file.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			addFile(filepath.Join(dir, file.Name), src)
+		}
+	}
+	main := filepath.Join(out, "main", "main.go")
+	addFile(main, []byte(strings.Replace(hostRoundTripMain, "PKG", bindings, 1)))
+	overlayJSON, err := json.Marshal(map[string]any{"Replace": overlay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlayFile := filepath.Join(tmp, "overlay.json")
+	guestFile := filepath.Join(tmp, "guest.wasm")
+	if err := os.WriteFile(overlayFile, overlayJSON, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(guestFile, wasm, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("go", "run", "-overlay", overlayFile, main, guestFile)
+	stderr.Reset()
+	cmd.Stderr = &stderr
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, stderr.Bytes())
+	}
+	if string(got) != hostRoundTripOutput {
+		t.Errorf("output:\n%s\nexpected:\n%s", got, hostRoundTripOutput)
+	}
+}
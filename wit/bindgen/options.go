@@ -1,6 +1,10 @@
 package bindgen
 
 import (
+	"fmt"
+	"path"
+
+	"go.bytecodealliance.org/wit"
 	"go.bytecodealliance.org/wit/logging"
 )
 
@@ -28,15 +32,51 @@ type options struct {
 	// packageRoot is the root Go package or module path used in generated code.
 	packageRoot string
 
+	// packagePaths maps fully-qualified WIT interface or world names to Go package paths,
+	// overriding the Go package path derived from the WIT name.
+	packagePaths map[string]string
+
 	// cmPackage is the package path to the "cm" or Component Model package with basic types.
 	// Default: go.bytecodealliance.org/cm.
 	cmPackage string
 
+	// allWorlds determines if every WIT world matching worldPatterns is generated,
+	// rather than the single world selected by world.
+	allWorlds bool
+
+	// worldPatterns are glob patterns that select the worlds generated if allWorlds is set.
+	// Default: all worlds.
+	worldPatterns []string
+
 	// versioned determines if Go packages are generated with version numbers.
 	versioned bool
 
 	// generateWIT determines if WIT files will be generated for each world and interface.
 	generateWIT bool
+
+	// idiomaticErrors determines if functions that return a result will have
+	// additional Go functions generated that return a Go error.
+	idiomaticErrors bool
+
+	// exportInterfaces determines if exports are generated as Go interfaces
+	// registered with a SetExports function, instead of assignable function fields.
+	exportInterfaces bool
+
+	// resourceTables determines if the Go values of exported resources are stored in a
+	// cm.ResourceTable, and exported resource methods are called on the Go value.
+	resourceTables bool
+
+	// ownedResources determines if imported resource types have an Owned method
+	// that wraps an owned handle in a cm.Owned.
+	ownedResources bool
+
+	// fakes determines if a Fake struct with hooks for imported functions is generated
+	// in a *.fake.go file for builds on targets other than WebAssembly.
+	fakes bool
+
+	// host determines if host-side bindings for the wazero runtime are generated,
+	// instead of guest bindings.
+	host bool
 }
 
 func (opts *options) apply(o ...Option) error {
@@ -75,6 +115,28 @@ func World(world string) Option {
 	})
 }
 
+// AllWorlds returns an [Option] that specifies that every WIT world will be generated
+// into a single tree of Go packages, rather than a single world. Interfaces shared by
+// multiple worlds are generated once. If any patterns are specified, only worlds that match
+// a pattern are generated. A pattern matches a world name, e.g. "command", or a fully-qualified
+// world name with or without a version, e.g. "wasi:cli/*" or "wasi:cli/command@0.2.0",
+// using the syntax of [path.Match].
+//
+// An error is returned if two WIT worlds or interfaces map to the same Go package,
+// or if an interface is imported by one world and exported by another.
+func AllWorlds(patterns ...string) Option {
+	return optionFunc(func(opts *options) error {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid world pattern %q: %w", pattern, err)
+			}
+		}
+		opts.allWorlds = true
+		opts.worldPatterns = patterns
+		return nil
+	})
+}
+
 // PackageRoot returns an [Option] that specifies the root Go package path for generated Go packages.
 func PackageRoot(path string) Option {
 	return optionFunc(func(opts *options) error {
@@ -83,6 +145,29 @@ func PackageRoot(path string) Option {
 	})
 }
 
+// PackagePaths returns an [Option] that overrides the Go package path generated for
+// WIT interfaces or worlds. The keys of paths are fully-qualified WIT names with or without
+// a version, e.g. "wasi:io/streams@0.2.0" or "wasi:io/streams". A name with a version is
+// preferred over a name without a version. The values are Go package paths.
+func PackagePaths(paths map[string]string) Option {
+	return optionFunc(func(opts *options) error {
+		for name, pkgPath := range paths {
+			id, err := wit.ParseIdent(name)
+			if err != nil {
+				return fmt.Errorf("invalid WIT name %q: %w", name, err)
+			}
+			if id.Extension == "" {
+				return fmt.Errorf("WIT name %q is not an interface or world", name)
+			}
+			if pkgPath == "" {
+				return fmt.Errorf("empty Go package path for %s", name)
+			}
+		}
+		opts.packagePaths = paths
+		return nil
+	})
+}
+
 // CMPackage returns an [Option] that specifies the package path to the
 // Component Model utility package (default: go.bytecodealliance.org/cm).
 func CMPackage(path string) Option {
@@ -109,3 +194,71 @@ func WIT(generateWIT bool) Option {
 		return nil
 	})
 }
+
+// IdiomaticErrors returns an [Option] that specifies that additional Go functions will be generated
+// for each function that returns a WIT result. Imported functions will have a wrapper that returns
+// (T, error), and exported functions will have an adapter for implementations that return (T, error).
+func IdiomaticErrors(idiomaticErrors bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.idiomaticErrors = idiomaticErrors
+		return nil
+	})
+}
+
+// ExportInterfaces returns an [Option] that specifies that exports will be generated as Go interfaces,
+// with a Go interface for each exported resource, and a SetExports function that registers an implementation.
+// A missing method is a compile-time error, rather than a nil function panic at runtime.
+func ExportInterfaces(exportInterfaces bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.exportInterfaces = exportInterfaces
+		return nil
+	})
+}
+
+// ResourceTables returns an [Option] that specifies that the Go values of exported resources
+// are stored in a generated [cm.ResourceTable] for each resource. Exported resource methods are
+// called on the Go value for the resource rep, and the value is released when the resource is destroyed.
+//
+// [cm.ResourceTable]: https://pkg.go.dev/go.bytecodealliance.org/cm#ResourceTable
+func ResourceTables(resourceTables bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.resourceTables = resourceTables
+		return nil
+	})
+}
+
+// OwnedResources returns an [Option] that specifies that each imported resource type has an Owned method,
+// which wraps an owned handle in a [cm.Owned]. A cm.Owned implements [io.Closer], and poisons itself
+// after the handle is dropped, so a handle cannot be used or dropped again.
+//
+// [cm.Owned]: https://pkg.go.dev/go.bytecodealliance.org/cm#Owned
+func OwnedResources(ownedResources bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.ownedResources = ownedResources
+		return nil
+	})
+}
+
+// Fakes returns an [Option] that specifies that a *.fake.go file is generated for each Go package
+// with imported functions, for builds on targets other than WebAssembly, such as with go test.
+// The file declares a Fake struct with a swappable function hook for each imported function,
+// and a [cm.FakeHandles] table for each resource type. The wasmimport declarations in *.wasm.go
+// files are constrained to WebAssembly builds.
+//
+// [cm.FakeHandles]: https://pkg.go.dev/go.bytecodealliance.org/cm#FakeHandles
+func Fakes(fakes bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.fakes = fakes
+		return nil
+	})
+}
+
+// Host returns an [Option] that specifies that host-side Go bindings will be generated
+// for the wazero WebAssembly runtime, instead of guest bindings. Host bindings register
+// imported functions on a wazero host module, and call exported functions on a guest module instance.
+func Host(host bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.host = host
+		return nil
+	})
+}
@@ -4,6 +4,7 @@
 package bindgen
 
 import (
+	"errors"
 	"flag"
 	"go/token"
 	"io/fs"
@@ -80,7 +81,7 @@ var canGo = sync.OnceValue[bool](func() bool {
 })
 
 // validateGeneratedGo loads the Go package(s) generated
-func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
+func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string, opts ...Option) {
 	if !canGo() {
 		t.Log("skipping test: cannot run go command")
 		return
@@ -105,12 +106,17 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 		return
 	}
 
-	pkgs, err := Go(res,
+	opts = append([]Option{
 		GeneratedBy("test"),
 		PackageRoot(pkgPath),
 		Versioned(true),
 		Logger(logging.NewLogger(os.Stderr, logging.LevelWarn)),
-	)
+	}, opts...)
+	pkgs, err := Go(res, opts...)
+	if errors.Is(err, errHostUnsupported) {
+		t.Logf("skipping test: %v", err)
+		return
+	}
 	if err != nil {
 		t.Error(err)
 		return
@@ -212,19 +218,39 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 	}
 }
 
+// testdataOptions are the sets of options used to generate Go bindings for each testdata file.
+// Generated packages for each set are validated under a path with prefix.
+var testdataOptions = []struct {
+	name   string
+	prefix string
+	opts   []Option
+}{
+	{"default", "", nil},
+	{"host", "/host", []Option{Host(true)}},
+	{"interfaces", "/interfaces", []Option{ExportInterfaces(true)}},
+	{"tables", "/tables", []Option{ResourceTables(true), ExportInterfaces(true)}},
+	{"errors", "/errors", []Option{IdiomaticErrors(true)}},
+	{"owned", "/owned", []Option{OwnedResources(true)}},
+	{"fakes", "/fakes", []Option{Fakes(true)}},
+}
+
 func TestGenerateTestdata(t *testing.T) {
 	if testing.Short() {
 		// t.Skip is not available in TinyGo, requires runtime.Goexit()
 		return
 	}
-	err := loadTestdata(func(path string, res *wit.Resolve) error {
-		t.Run(path, func(t *testing.T) {
-			origin := strings.TrimSuffix(strings.TrimPrefix(path, testdataPath), ".wit.json")
-			validateGeneratedGo(t, res, origin)
+	for _, tt := range testdataOptions {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadTestdata(func(path string, res *wit.Resolve) error {
+				t.Run(path, func(t *testing.T) {
+					origin := strings.TrimSuffix(strings.TrimPrefix(path, testdataPath), ".wit.json")
+					validateGeneratedGo(t, res, tt.prefix+origin, tt.opts...)
+				})
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
 		})
-		return nil
-	})
-	if err != nil {
-		t.Error(err)
 	}
 }
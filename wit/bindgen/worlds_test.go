@@ -0,0 +1,157 @@
+package bindgen
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+const allWorldsWIT = `package example:app@0.1.0;
+
+interface shared {
+	record point { x: s32, y: s32 }
+	get: func() -> point;
+}
+
+interface runner {
+	run: func();
+}
+
+world cli {
+	import shared;
+	export run: func();
+}
+
+world handler {
+	import shared;
+	export runner;
+}
+
+world plugin {
+	import shared;
+}
+`
+
+func packagePaths(t *testing.T, res *wit.Resolve, opts ...Option) ([]string, error) {
+	t.Helper()
+	pkgs, err := Go(res, append([]Option{GeneratedBy("test"), PackageRoot("example.com")}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, pkg := range pkgs {
+		if pkg.HasContent() {
+			paths = append(paths, strings.TrimPrefix(pkg.Path, "example.com/"))
+		}
+	}
+	slices.Sort(paths)
+	return paths, nil
+}
+
+func TestAllWorlds(t *testing.T) {
+	res, err := wit.DecodeWIT(strings.NewReader(allWorldsWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		patterns []string
+		want     []string
+	}{
+		{nil, []string{"example/app/cli", "example/app/handler", "example/app/plugin", "example/app/runner", "example/app/shared"}},
+		{[]string{"c*"}, []string{"example/app/cli", "example/app/shared"}},
+		{[]string{"example:app/handler"}, []string{"example/app/handler", "example/app/runner", "example/app/shared"}},
+		{[]string{"example:app/plugin@0.1.0", "cli"}, []string{"example/app/cli", "example/app/plugin", "example/app/shared"}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.patterns, ","), func(t *testing.T) {
+			got, err := packagePaths(t, res, AllWorlds(tt.patterns...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AllWorlds(%q): %v, expected %v", tt.patterns, got, tt.want)
+			}
+		})
+	}
+
+	validateGeneratedGo(t, res, "/all-worlds", AllWorlds())
+}
+
+func TestAllWorldsErrors(t *testing.T) {
+	res, err := wit.DecodeWIT(strings.NewReader(allWorldsWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflict, err := wit.DecodeWIT(strings.NewReader(`package example:app;
+
+interface shared {
+	get: func() -> u32;
+}
+
+world client {
+	import shared;
+}
+
+world server {
+	export shared;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		res  *wit.Resolve
+		opt  Option
+		want string
+	}{
+		{"invalid pattern", res, AllWorlds("["), "invalid world pattern"},
+		{"no match", res, AllWorlds("service"), "no worlds match"},
+		{"import and export", conflict, AllWorlds(), "generate these worlds separately"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := packagePaths(t, tt.res, tt.opt)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Go: %v, expected error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	// Each world can be generated separately.
+	for _, world := range []string{"client", "server"} {
+		if _, err := packagePaths(t, conflict, World(world)); err != nil {
+			t.Errorf("World(%q): %v", world, err)
+		}
+	}
+}
+
+func TestPackagePaths(t *testing.T) {
+	res, err := wit.DecodeWIT(strings.NewReader(allWorldsWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := packagePaths(t, res, World("handler"), PackagePaths(map[string]string{
+		"example:app/shared":        "example.com/internal/geometry",
+		"example:app/handler@0.1.0": "example.com/handler",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"example/app/runner", "handler", "internal/geometry"}
+	if !slices.Equal(got, want) {
+		t.Errorf("PackagePaths: %v, expected %v", got, want)
+	}
+
+	for _, name := range []string{"example:app", "example:app/shared@x"} {
+		_, err := packagePaths(t, res, PackagePaths(map[string]string{name: "example.com/x"}))
+		if err == nil {
+			t.Errorf("PackagePaths(%q): nil error, expected error", name)
+		}
+	}
+}
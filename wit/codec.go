@@ -2,6 +2,7 @@ package wit
 
 import (
 	"io"
+	"strings"
 
 	"github.com/coreos/go-semver/semver"
 	"go.bytecodealliance.org/internal/codec"
@@ -37,7 +38,7 @@ func (res *Resolve) ResolveCodec(v any) codec.Codec {
 
 	// Enums
 	case *FunctionKind:
-		return &functionKindCodec{v}
+		return &functionKindCodec{v: v}
 	case *Handle:
 		return &handleCodec{v}
 	case *Stability:
@@ -574,7 +575,7 @@ func (f *Function) DecodeField(dec codec.Decoder, name string) error {
 	case "name":
 		return dec.Decode(&f.Name)
 	case "kind":
-		return dec.Decode(&f.Kind)
+		return dec.Decode(&functionKindCodec{&f.Kind, &f.Async})
 	case "params":
 		return codec.DecodeSlice(dec, &f.Params)
 	case "results":
@@ -588,19 +589,27 @@ func (f *Function) DecodeField(dec codec.Decoder, name string) error {
 }
 
 type functionKindCodec struct {
-	v *FunctionKind
+	v     *FunctionKind
+	async *bool // optional
 }
 
 func (c *functionKindCodec) DecodeString(s string) error {
 	switch s {
 	case "freestanding":
 		*c.v = &Freestanding{}
+	case "async-freestanding":
+		*c.v = &Freestanding{}
+		c.setAsync()
 	}
 	return nil
 }
 
 func (c *functionKindCodec) DecodeField(dec codec.Decoder, name string) error {
 	var err error
+	if after, ok := strings.CutPrefix(name, "async-"); ok {
+		name = after
+		c.setAsync()
+	}
 	switch name {
 	case "method":
 		v := &Method{}
@@ -618,6 +627,12 @@ func (c *functionKindCodec) DecodeField(dec codec.Decoder, name string) error {
 	return err
 }
 
+func (c *functionKindCodec) setAsync() {
+	if c.async != nil {
+		*c.async = true
+	}
+}
+
 // DecodeField implements the [codec.FieldDecoder] interface
 // to decode a struct or JSON object.
 func (p *Param) DecodeField(dec codec.Decoder, name string) error {
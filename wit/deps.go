@@ -0,0 +1,88 @@
+package wit
+
+import (
+	"os"
+	"slices"
+	"strings"
+)
+
+// ForeignPackages parses the WIT files at path, which may be a WIT file or a directory
+// of WIT files, and returns the identifiers of the packages they reference in use
+// statements, world imports and exports, and include statements, sorted by name.
+// Packages declared at path are not included. Files in a deps directory are not parsed.
+// References are not resolved, so the referenced packages need not be present.
+// The returned identifiers have no Extension, and may have no Version.
+func ForeignPackages(path string) ([]Ident, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []*astFile
+	if fi.IsDir() {
+		files, err = parseDir(path)
+	} else {
+		var f *astFile
+		f, err = parseWITFile(path)
+		files = []*astFile{f}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	declared := make(map[string]bool)
+	refs := make(map[string]Ident)
+	ref := func(p *astUsePath) {
+		if p == nil || p.pkg == nil {
+			return
+		}
+		id := *p.pkg
+		id.Extension = ""
+		refs[id.String()] = id
+	}
+	iface := func(i *astInterface) {
+		for _, item := range i.items {
+			if u, ok := item.(*astUse); ok {
+				ref(&u.path)
+			}
+		}
+	}
+	for _, f := range files {
+		for _, pkg := range append([]*astPackage{f.pkg}, f.packages...) {
+			if pkg.name != nil {
+				declared[pkg.name.String()] = true
+			}
+			for _, u := range pkg.uses {
+				ref(&u.path)
+			}
+			for _, i := range pkg.ifaces {
+				iface(i)
+			}
+			for _, w := range pkg.worlds {
+				for _, item := range w.items {
+					switch item := item.(type) {
+					case *astUse:
+						ref(&item.path)
+					case *astExtern:
+						ref(item.path)
+						if item.iface != nil {
+							iface(item.iface)
+						}
+					case *astInclude:
+						ref(&item.path)
+					}
+				}
+			}
+		}
+	}
+
+	var ids []Ident
+	for name, id := range refs {
+		if !declared[name] {
+			ids = append(ids, id)
+		}
+	}
+	slices.SortFunc(ids, func(a, b Ident) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return ids, nil
+}
@@ -0,0 +1,669 @@
+package wit
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/coreos/go-semver/semver"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// ChangeKind classifies a [Change] between two versions of a WIT definition.
+// A greater ChangeKind represents a more significant change.
+type ChangeKind int
+
+const (
+	// DocOnly represents a change to documentation or other metadata that does not affect compatibility.
+	DocOnly ChangeKind = iota + 1
+
+	// Additive represents a backward-compatible change, such as a new function, type, or interface.
+	// Changes to @unstable features are also classified as additive.
+	Additive
+
+	// Breaking represents a backward-incompatible change, such as a removed function
+	// or a new record field.
+	Breaking
+)
+
+// String implements [fmt.Stringer], returning a string representation of k.
+func (k ChangeKind) String() string {
+	switch k {
+	case 0:
+		return "none"
+	case DocOnly:
+		return "doc-only"
+	case Additive:
+		return "additive"
+	case Breaking:
+		return "breaking"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change represents a single difference between two versions of a WIT definition.
+type Change struct {
+	// Kind classifies the change.
+	Kind ChangeKind
+
+	// Path identifies the changed item, such as "wasi:http/types",
+	// "wasi:http/types#[method]fields.get", or "wasi:http/types#method.get".
+	// Package names in Path are unversioned.
+	Path string
+
+	// Message describes the change.
+	Message string
+}
+
+// String implements [fmt.Stringer], returning a string representation of c.
+func (c *Change) String() string {
+	return c.Kind.String() + ": " + c.Path + ": " + c.Message
+}
+
+// PackageDiff represents the changes between two versions of a WIT [Package].
+type PackageDiff struct {
+	// Old is the previous version of the package, or nil if the package was added.
+	Old *Package
+
+	// New is the next version of the package, or nil if the package was removed.
+	New *Package
+
+	// Changes lists the changes between Old and New.
+	Changes []*Change
+}
+
+// Name returns the unversioned name of the package in d.
+func (d *PackageDiff) Name() string {
+	if d.New != nil {
+		return d.New.Name.UnversionedString()
+	}
+	return d.Old.Name.UnversionedString()
+}
+
+// Kind returns the most significant [ChangeKind] in d, or 0 if d has no changes.
+func (d *PackageDiff) Kind() ChangeKind {
+	var kind ChangeKind
+	for _, c := range d.Changes {
+		kind = max(kind, c.Kind)
+	}
+	return kind
+}
+
+// MinVersion returns the minimum version of the next package required by the changes in d,
+// using the [Semantic Versioning] rules for the previous package version. A breaking change
+// requires a major version bump, or a minor version bump for a 0.x version. An additive change
+// requires a minor version bump, or a patch version bump for a 0.x version.
+// MinVersion returns nil if either package is nil or the previous package is unversioned.
+//
+// [Semantic Versioning]: https://semver.org/
+func (d *PackageDiff) MinVersion() *semver.Version {
+	if d.Old == nil || d.New == nil || d.Old.Name.Version == nil {
+		return nil
+	}
+	v := *d.Old.Name.Version
+	v.PreRelease = ""
+	v.Metadata = ""
+	switch d.Kind() {
+	case Breaking:
+		if v.Major == 0 {
+			v.BumpMinor()
+		} else {
+			v.BumpMajor()
+		}
+	case Additive:
+		if v.Major == 0 {
+			v.BumpPatch()
+		} else {
+			v.BumpMinor()
+		}
+	default:
+		v = *d.Old.Name.Version
+	}
+	return &v
+}
+
+// CheckVersion reports whether the version of the next package in d is sufficient
+// for the changes in d, returning an error describing the required version if not.
+func (d *PackageDiff) CheckVersion() error {
+	min := d.MinVersion()
+	if min == nil {
+		return nil
+	}
+	v := d.New.Name.Version
+	if v == nil {
+		return fmt.Errorf("%s: missing version for %s changes (expected %s or later)", d.Name(), d.Kind(), min)
+	}
+	if v.LessThan(*min) {
+		return fmt.Errorf("%s: version %s is not sufficient for %s changes (expected %s or later)", d.Name(), v, d.Kind(), min)
+	}
+	return nil
+}
+
+// Diff compares the packages in old and new, returning a [PackageDiff] for each package with changes.
+// Packages are matched by their unversioned name. Items with a @since version
+// greater than the version of their package are ignored.
+func Diff(old, new *Resolve) []*PackageDiff {
+	var diffs []*PackageDiff
+	oldPackages := make(map[string]*Package)
+	for _, p := range old.Packages {
+		oldPackages[p.Name.UnversionedString()] = p
+	}
+	newPackages := make(map[string]bool)
+	for _, p := range new.Packages {
+		name := p.Name.UnversionedString()
+		newPackages[name] = true
+		d := &differ{diff: &PackageDiff{Old: oldPackages[name], New: p}}
+		if d.diff.Old == nil {
+			d.add(Additive, name, "package added")
+		} else {
+			d.diffPackage(d.diff.Old, p)
+		}
+		if len(d.diff.Changes) > 0 {
+			diffs = append(diffs, d.diff)
+		}
+	}
+	for _, p := range old.Packages {
+		name := p.Name.UnversionedString()
+		if !newPackages[name] {
+			d := &differ{diff: &PackageDiff{Old: p}}
+			d.add(Breaking, name, "package removed")
+			diffs = append(diffs, d.diff)
+		}
+	}
+	return diffs
+}
+
+// differ records the changes between two versions of a [Package].
+type differ struct {
+	diff     *PackageDiff
+	unstable bool // true when comparing @unstable items
+}
+
+func (d *differ) add(kind ChangeKind, path, format string, args ...any) {
+	if d.unstable && kind == Breaking {
+		kind = Additive
+	}
+	d.diff.Changes = append(d.diff.Changes, &Change{Kind: kind, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *differ) versions() (oldVersion, newVersion *semver.Version) {
+	return d.diff.Old.Name.Version, d.diff.New.Name.Version
+}
+
+// isActive reports whether an item with Stability s is enabled in a package with version v.
+func isActive(s Stability, v *semver.Version) bool {
+	if s, ok := s.(*Stable); ok && v != nil {
+		return !v.LessThan(s.Since)
+	}
+	return true
+}
+
+// diffMaps compares the active items in ordered maps a and b, calling removed, added,
+// or changed for each item, in the order of a, followed by items added in b.
+func diffMaps[V any](d *differ, a, b *ordered.Map[string, V], key func(string, V) string, stability func(V) Stability,
+	removed, added func(string, V), changed func(string, V, V)) {
+	oldVersion, newVersion := d.versions()
+	bItems := make(map[string]V)
+	b.All()(func(name string, v V) bool {
+		if isActive(stability(v), newVersion) {
+			bItems[key(name, v)] = v
+		}
+		return true
+	})
+	aItems := make(map[string]bool)
+	a.All()(func(name string, v V) bool {
+		if !isActive(stability(v), oldVersion) {
+			return true
+		}
+		k := key(name, v)
+		aItems[k] = true
+		if bv, ok := bItems[k]; ok {
+			changed(k, v, bv)
+		} else {
+			removed(k, v)
+		}
+		return true
+	})
+	b.All()(func(name string, v V) bool {
+		k := key(name, v)
+		if _, ok := bItems[k]; ok && !aItems[k] {
+			added(k, v)
+		}
+		return true
+	})
+}
+
+func (d *differ) diffDocs(path string, a, b *Docs) {
+	if a.Contents != b.Contents {
+		d.add(DocOnly, path, "docs changed")
+	}
+}
+
+// diffStability compares the stability of an item, and reports whether it is unstable
+// in both a and b, in which case changes to the item are not breaking.
+func (d *differ) diffStability(path string, a, b Stability) (unstable bool) {
+	as, aStable := a.(*Stable)
+	bs, bStable := b.(*Stable)
+	au, aUnstable := a.(*Unstable)
+	bu, bUnstable := b.(*Unstable)
+	switch {
+	case aUnstable && bUnstable:
+		if au.Feature != bu.Feature {
+			d.add(Additive, path, "unstable feature changed from %q to %q", au.Feature, bu.Feature)
+		}
+		return true
+	case aUnstable && !bUnstable:
+		d.add(Additive, path, "stabilized")
+	case !aUnstable && bUnstable:
+		d.add(Breaking, path, "changed to @unstable(feature = %s)", bu.Feature)
+	case aStable && bStable:
+		if !as.Since.Equal(bs.Since) {
+			d.add(DocOnly, path, "@since version changed from %s to %s", &as.Since, &bs.Since)
+		}
+		if as.Deprecated == nil && bs.Deprecated != nil {
+			d.add(DocOnly, path, "deprecated")
+		}
+	}
+	return false
+}
+
+// isUnstable reports whether an item with Stability s is unstable.
+func isUnstable(s Stability) bool {
+	_, ok := s.(*Unstable)
+	return ok
+}
+
+// unstableKind returns kind, or [Additive] if the item with Stability s is unstable.
+func unstableKind(kind ChangeKind, s Stability) ChangeKind {
+	if isUnstable(s) && kind == Breaking {
+		return Additive
+	}
+	return kind
+}
+
+func (d *differ) diffPackage(a, b *Package) {
+	name := b.Name.UnversionedString()
+	d.diffDocs(name, &a.Docs, &b.Docs)
+	diffMaps(d, &a.Interfaces, &b.Interfaces,
+		func(name string, _ *Interface) string { return name },
+		func(i *Interface) Stability { return i.Stability },
+		func(_ string, i *Interface) {
+			d.add(unstableKind(Breaking, i.Stability), interfacePath(i), "interface removed")
+		},
+		func(_ string, i *Interface) {
+			d.add(Additive, interfacePath(i), "interface added")
+		},
+		func(_ string, a, b *Interface) {
+			d.diffInterface(interfacePath(b), a, b)
+		})
+	diffMaps(d, &a.Worlds, &b.Worlds,
+		func(name string, _ *World) string { return name },
+		func(w *World) Stability { return w.Stability },
+		func(_ string, w *World) {
+			d.add(unstableKind(Breaking, w.Stability), worldPath(w), "world removed")
+		},
+		func(_ string, w *World) {
+			d.add(Additive, worldPath(w), "world added")
+		},
+		func(_ string, a, b *World) {
+			d.diffWorld(a, b)
+		})
+}
+
+func interfacePath(i *Interface) string {
+	if i.Name == nil || i.Package == nil {
+		return "interface"
+	}
+	id := i.Package.Name
+	id.Extension = *i.Name
+	return id.UnversionedString()
+}
+
+func worldPath(w *World) string {
+	id := w.Package.Name
+	id.Extension = w.Name
+	return id.UnversionedString()
+}
+
+func (d *differ) diffInterface(path string, a, b *Interface) {
+	unstable := d.unstable
+	defer func() { d.unstable = unstable }()
+	d.unstable = d.diffStability(path, a.Stability, b.Stability) || unstable
+	d.diffDocs(path, &a.Docs, &b.Docs)
+	d.diffTypeDefs(path, &a.TypeDefs, &b.TypeDefs)
+	d.diffFunctions(path, &a.Functions, &b.Functions)
+}
+
+func (d *differ) diffTypeDefs(path string, a, b *ordered.Map[string, *TypeDef]) {
+	diffMaps(d, a, b,
+		func(name string, _ *TypeDef) string { return name },
+		func(t *TypeDef) Stability { return t.Stability },
+		func(name string, t *TypeDef) {
+			d.add(unstableKind(Breaking, t.Stability), path+"#"+name, "%s removed", t.WITKind())
+		},
+		func(name string, t *TypeDef) {
+			d.add(Additive, path+"#"+name, "%s added", t.WITKind())
+		},
+		func(name string, a, b *TypeDef) {
+			d.diffTypeDef(path+"#"+name, a, b)
+		})
+}
+
+func (d *differ) diffFunctions(path string, a, b *ordered.Map[string, *Function]) {
+	diffMaps(d, a, b,
+		func(name string, _ *Function) string { return name },
+		func(f *Function) Stability { return f.Stability },
+		func(name string, f *Function) {
+			d.add(unstableKind(Breaking, f.Stability), path+"#"+name, "%s removed", f.WITKind())
+		},
+		func(name string, f *Function) {
+			d.add(Additive, path+"#"+name, "%s added", f.WITKind())
+		},
+		func(name string, a, b *Function) {
+			d.diffFunction(path+"#"+name, a, b)
+		})
+}
+
+func (d *differ) diffWorld(a, b *World) {
+	path := worldPath(b)
+	unstable := d.unstable
+	defer func() { d.unstable = unstable }()
+	d.unstable = d.diffStability(path, a.Stability, b.Stability) || unstable
+	d.diffDocs(path, &a.Docs, &b.Docs)
+
+	// A new import is compatible with existing components, which can only use a subset
+	// of the imports of a world. A new export must be implemented by existing components.
+	d.diffWorldItems(path+" import ", &a.Imports, &b.Imports, Additive)
+	d.diffWorldItems(path+" export ", &a.Exports, &b.Exports, Breaking)
+}
+
+func (d *differ) diffWorldItems(path string, a, b *ordered.Map[string, WorldItem], addedKind ChangeKind) {
+	diffMaps(d, a, b, worldItemKey, worldItemStability,
+		func(name string, item WorldItem) {
+			d.add(unstableKind(Breaking, worldItemStability(item)), path+name, "%s removed", worldItemKind(item))
+		},
+		func(name string, item WorldItem) {
+			d.add(unstableKind(addedKind, worldItemStability(item)), path+name, "%s added", worldItemKind(item))
+		},
+		func(name string, a, b WorldItem) {
+			d.diffWorldItem(path+name, a, b)
+		})
+}
+
+// worldItemKey returns the unversioned name of a [WorldItem],
+// so an interface is matched across package versions.
+func worldItemKey(name string, item WorldItem) string {
+	if ref, ok := item.(*InterfaceRef); ok && ref.Interface.Name != nil && ref.Interface.Package != nil {
+		return interfacePath(ref.Interface)
+	}
+	return name
+}
+
+func worldItemKind(item WorldItem) string {
+	if ref, ok := item.(*InterfaceRef); ok {
+		return ref.Interface.WITKind()
+	}
+	return item.WITKind()
+}
+
+func worldItemStability(item WorldItem) Stability {
+	switch item := item.(type) {
+	case *InterfaceRef:
+		return item.Stability
+	case *TypeDef:
+		return item.Stability
+	case *Function:
+		return item.Stability
+	}
+	return nil
+}
+
+func (d *differ) diffWorldItem(path string, a, b WorldItem) {
+	switch a := a.(type) {
+	case *InterfaceRef:
+		b, ok := b.(*InterfaceRef)
+		if !ok {
+			break
+		}
+		unstable := d.unstable
+		defer func() { d.unstable = unstable }()
+		d.unstable = d.diffStability(path, a.Stability, b.Stability) || unstable
+		// Named interfaces are compared with their package.
+		if a.Interface.Name == nil && b.Interface.Name == nil {
+			d.diffInterface(path, a.Interface, b.Interface)
+		}
+		return
+	case *TypeDef:
+		if b, ok := b.(*TypeDef); ok {
+			d.diffTypeDef(path, a, b)
+			return
+		}
+	case *Function:
+		if b, ok := b.(*Function); ok {
+			d.diffFunction(path, a, b)
+			return
+		}
+	}
+	d.add(Breaking, path, "changed from %s to %s", worldItemKind(a), worldItemKind(b))
+}
+
+func (d *differ) diffTypeDef(path string, a, b *TypeDef) {
+	unstable := d.unstable
+	defer func() { d.unstable = unstable }()
+	d.unstable = d.diffStability(path, a.Stability, b.Stability) || unstable
+	d.diffDocs(path, &a.Docs, &b.Docs)
+
+	if a.WITKind() != b.WITKind() {
+		d.add(Breaking, path, "changed from %s to %s", a.WITKind(), b.WITKind())
+		return
+	}
+
+	switch ak := a.Kind.(type) {
+	case *Record:
+		bk := b.Kind.(*Record)
+		d.diffCases(path, "field", len(ak.Fields), len(bk.Fields),
+			func(i int) string { return ak.Fields[i].Name },
+			func(i int) string { return bk.Fields[i].Name },
+			func(i, j int) {
+				fa, fb := &ak.Fields[i], &bk.Fields[j]
+				d.diffDocs(path+"."+fb.Name, &fa.Docs, &fb.Docs)
+				if !sameType(fa.Type, fb.Type) {
+					d.add(Breaking, path+"."+fb.Name, "field type changed from %s to %s", typeString(fa.Type), typeString(fb.Type))
+				}
+			})
+
+	case *Variant:
+		bk := b.Kind.(*Variant)
+		d.diffCases(path, "case", len(ak.Cases), len(bk.Cases),
+			func(i int) string { return ak.Cases[i].Name },
+			func(i int) string { return bk.Cases[i].Name },
+			func(i, j int) {
+				ca, cb := &ak.Cases[i], &bk.Cases[j]
+				d.diffDocs(path+"."+cb.Name, &ca.Docs, &cb.Docs)
+				if !sameType(ca.Type, cb.Type) {
+					d.add(Breaking, path+"."+cb.Name, "case type changed from %s to %s", typeString(ca.Type), typeString(cb.Type))
+				}
+			})
+
+	case *Enum:
+		bk := b.Kind.(*Enum)
+		d.diffCases(path, "case", len(ak.Cases), len(bk.Cases),
+			func(i int) string { return ak.Cases[i].Name },
+			func(i int) string { return bk.Cases[i].Name },
+			func(i, j int) {
+				d.diffDocs(path+"."+bk.Cases[j].Name, &ak.Cases[i].Docs, &bk.Cases[j].Docs)
+			})
+
+	case *Flags:
+		bk := b.Kind.(*Flags)
+		d.diffCases(path, "flag", len(ak.Flags), len(bk.Flags),
+			func(i int) string { return ak.Flags[i].Name },
+			func(i int) string { return bk.Flags[i].Name },
+			func(i, j int) {
+				d.diffDocs(path+"."+bk.Flags[j].Name, &ak.Flags[i].Docs, &bk.Flags[j].Docs)
+			})
+
+	case *Resource:
+		// Resource methods are compared with the functions of the type owner.
+
+	default:
+		if !sameKind(a.Kind, b.Kind) {
+			d.add(Breaking, path, "type changed from %s to %s", kindString(a.Kind), kindString(b.Kind))
+		}
+	}
+}
+
+// diffCases compares the named fields, cases, or flags of a type.
+// Any addition, removal, or reordering changes the Canonical ABI representation of the type,
+// and is a breaking change.
+func (d *differ) diffCases(path, kind string, aLen, bLen int, aName, bName func(int) string, changed func(i, j int)) {
+	bIndex := make(map[string]int, bLen)
+	for j := 0; j < bLen; j++ {
+		bIndex[bName(j)] = j
+	}
+	aIndex := make(map[string]int, aLen)
+	for i := 0; i < aLen; i++ {
+		name := aName(i)
+		aIndex[name] = i
+		j, ok := bIndex[name]
+		if !ok {
+			d.add(Breaking, path+"."+name, "%s removed", kind)
+			continue
+		}
+		if i != j {
+			d.add(Breaking, path+"."+name, "%s moved from position %d to %d", kind, i, j)
+		}
+		changed(i, j)
+	}
+	for j := 0; j < bLen; j++ {
+		name := bName(j)
+		if _, ok := aIndex[name]; !ok {
+			d.add(Breaking, path+"."+name, "%s added", kind)
+		}
+	}
+}
+
+func (d *differ) diffFunction(path string, a, b *Function) {
+	unstable := d.unstable
+	defer func() { d.unstable = unstable }()
+	d.unstable = d.diffStability(path, a.Stability, b.Stability) || unstable
+	d.diffDocs(path, &a.Docs, &b.Docs)
+
+	if a.WITKind() != b.WITKind() {
+		d.add(Breaking, path, "changed from %s to %s", a.WITKind(), b.WITKind())
+	}
+	if a.Async != b.Async {
+		if b.Async {
+			d.add(Breaking, path, "changed to async")
+		} else {
+			d.add(Breaking, path, "changed to sync")
+		}
+	}
+	if !sameParams(a.Params, b.Params) {
+		d.add(Breaking, path, "params changed from (%s) to (%s)", paramsWIT(a.Params, false), paramsWIT(b.Params, false))
+	}
+	if !sameParams(a.Results, b.Results) {
+		d.add(Breaking, path, "results changed from (%s) to (%s)", paramsWIT(a.Results, false), paramsWIT(b.Results, false))
+	}
+}
+
+func sameParams(a, b []Param) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !sameType(a[i].Type, b[i].Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameType reports whether types a and b, which may belong to different [Resolve] values, are equivalent.
+// Named types are compared by name and owner. Anonymous types are compared structurally.
+func sameType(a, b Type) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	at, aok := a.(*TypeDef)
+	bt, bok := b.(*TypeDef)
+	if aok != bok {
+		return false
+	}
+	if !aok {
+		return reflect.TypeOf(a) == reflect.TypeOf(b)
+	}
+	if at.Name != nil || bt.Name != nil {
+		return at.Name != nil && bt.Name != nil && *at.Name == *bt.Name && ownerPath(at.Owner) == ownerPath(bt.Owner)
+	}
+	return sameKind(at.Kind, bt.Kind)
+}
+
+// sameKind reports whether the anonymous [TypeDefKind] values a and b are equivalent.
+func sameKind(a, b TypeDefKind) bool {
+	switch a := a.(type) {
+	case *TypeDef:
+		b, ok := b.(*TypeDef)
+		return ok && sameType(a, b)
+	case *Own:
+		b, ok := b.(*Own)
+		return ok && sameType(a.Type, b.Type)
+	case *Borrow:
+		b, ok := b.(*Borrow)
+		return ok && sameType(a.Type, b.Type)
+	case *Option:
+		b, ok := b.(*Option)
+		return ok && sameType(a.Type, b.Type)
+	case *List:
+		b, ok := b.(*List)
+		return ok && sameType(a.Type, b.Type)
+	case *Future:
+		b, ok := b.(*Future)
+		return ok && sameType(a.Type, b.Type)
+	case *Stream:
+		b, ok := b.(*Stream)
+		return ok && sameType(a.Type, b.Type)
+	case *Result:
+		b, ok := b.(*Result)
+		return ok && sameType(a.OK, b.OK) && sameType(a.Err, b.Err)
+	case *Tuple:
+		b, ok := b.(*Tuple)
+		if !ok || len(a.Types) != len(b.Types) {
+			return false
+		}
+		for i := range a.Types {
+			if !sameType(a.Types[i], b.Types[i]) {
+				return false
+			}
+		}
+		return true
+	case Type:
+		b, ok := b.(Type)
+		return ok && sameType(a, b)
+	}
+	return reflect.TypeOf(a) == reflect.TypeOf(b)
+}
+
+func ownerPath(o TypeOwner) string {
+	switch o := o.(type) {
+	case *Interface:
+		return interfacePath(o)
+	case *World:
+		return worldPath(o)
+	}
+	return ""
+}
+
+func typeString(t Type) string {
+	if t == nil {
+		return "none"
+	}
+	return t.WIT(nil, "")
+}
+
+func kindString(k TypeDefKind) string {
+	if t, ok := k.(Type); ok {
+		return typeString(t)
+	}
+	return k.WIT(nil, "")
+}
@@ -0,0 +1,189 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		want    []string
+		kind    ChangeKind
+		version string
+		wantErr bool
+	}{
+		{
+			"no changes",
+			`package foo:bar@1.0.0; interface i { f: func(); }`,
+			`package foo:bar@1.0.0; interface i { f: func(); }`,
+			nil,
+			0,
+			"1.0.0",
+			false,
+		},
+		{
+			"doc-only",
+			`package foo:bar@1.0.0; interface i { f: func(); }`,
+			`package foo:bar@1.0.1; interface i { /// Docs for f.
+			f: func(); }`,
+			[]string{"doc-only: foo:bar/i#f: docs changed"},
+			DocOnly,
+			"1.0.0",
+			false,
+		},
+		{
+			"function added",
+			`package foo:bar@1.0.0; interface i { f: func(); }`,
+			`package foo:bar@1.1.0; interface i { f: func(); g: func(); }`,
+			[]string{"additive: foo:bar/i#g: function added"},
+			Additive,
+			"1.1.0",
+			false,
+		},
+		{
+			"function added with insufficient version",
+			`package foo:bar@1.0.0; interface i { f: func(); }`,
+			`package foo:bar@1.0.1; interface i { f: func(); g: func(); }`,
+			[]string{"additive: foo:bar/i#g: function added"},
+			Additive,
+			"1.1.0",
+			true,
+		},
+		{
+			"function removed",
+			`package foo:bar@0.2.0; interface i { f: func(); g: func(); }`,
+			`package foo:bar@0.2.1; interface i { f: func(); }`,
+			[]string{"breaking: foo:bar/i#g: function removed"},
+			Breaking,
+			"0.3.0",
+			true,
+		},
+		{
+			"function params changed",
+			`package foo:bar@1.0.0; interface i { f: func(a: u32) -> string; }`,
+			`package foo:bar@2.0.0; interface i { f: func(a: u64) -> string; }`,
+			[]string{"breaking: foo:bar/i#f: params changed from (a: u32) to (a: u64)"},
+			Breaking,
+			"2.0.0",
+			false,
+		},
+		{
+			"record field added",
+			`package foo:bar@1.0.0; interface i { record r { a: u32 } }`,
+			`package foo:bar@2.0.0; interface i { record r { a: u32, b: string } }`,
+			[]string{"breaking: foo:bar/i#r.b: field added"},
+			Breaking,
+			"2.0.0",
+			false,
+		},
+		{
+			"variant case type changed",
+			`package foo:bar@1.0.0; interface i { variant v { a(u32), b } }`,
+			`package foo:bar@2.0.0; interface i { variant v { a(list<u8>), b } }`,
+			[]string{"breaking: foo:bar/i#v.a: case type changed from u32 to list<u8>"},
+			Breaking,
+			"2.0.0",
+			false,
+		},
+		{
+			"enum case removed",
+			`package foo:bar@1.0.0; interface i { enum e { a, b } }`,
+			`package foo:bar@2.0.0; interface i { enum e { a } }`,
+			[]string{"breaking: foo:bar/i#e.b: case removed"},
+			Breaking,
+			"2.0.0",
+			false,
+		},
+		{
+			"interface added",
+			`package foo:bar@1.0.0; interface i { }`,
+			`package foo:bar@1.1.0; interface i { } interface j { }`,
+			[]string{"additive: foo:bar/j: interface added"},
+			Additive,
+			"1.1.0",
+			false,
+		},
+		{
+			"world export added",
+			`package foo:bar@1.0.0; interface i { } world w { import i; }`,
+			`package foo:bar@2.0.0; interface i { } world w { import i; export i; }`,
+			[]string{"breaking: foo:bar/w export foo:bar/i: interface added"},
+			Breaking,
+			"2.0.0",
+			false,
+		},
+		{
+			"@since gated",
+			`package foo:bar@1.0.0; interface i { f: func(); }`,
+			`package foo:bar@1.0.0; interface i { f: func(); @since(version = 1.1.0) g: func(); }`,
+			nil,
+			0,
+			"1.0.0",
+			false,
+		},
+		{
+			"@since active",
+			`package foo:bar@1.0.0; interface i { f: func(); }`,
+			`package foo:bar@1.1.0; interface i { f: func(); @since(version = 1.1.0) g: func(); }`,
+			[]string{"additive: foo:bar/i#g: function added"},
+			Additive,
+			"1.1.0",
+			false,
+		},
+		{
+			"@unstable removed",
+			`package foo:bar@1.0.0; interface i { f: func(); @unstable(feature = x) g: func(); }`,
+			`package foo:bar@1.1.0; interface i { f: func(); }`,
+			[]string{"additive: foo:bar/i#g: function removed"},
+			Additive,
+			"1.1.0",
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, err := DecodeWIT(strings.NewReader(tt.old))
+			if err != nil {
+				t.Fatal(err)
+			}
+			new, err := DecodeWIT(strings.NewReader(tt.new))
+			if err != nil {
+				t.Fatal(err)
+			}
+			diffs := Diff(old, new)
+			if len(tt.want) == 0 {
+				if len(diffs) != 0 {
+					t.Errorf("Diff(): %d package diffs, expected 0: %v", len(diffs), diffs[0].Changes)
+				}
+				return
+			}
+			if len(diffs) != 1 {
+				t.Fatalf("Diff(): %d package diffs, expected 1", len(diffs))
+			}
+			d := diffs[0]
+			var got []string
+			for _, c := range d.Changes {
+				got = append(got, c.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Changes:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+			if got, want := d.Kind(), tt.kind; got != want {
+				t.Errorf("Kind(): %v, expected %v", got, want)
+			}
+			if got, want := d.MinVersion().String(), tt.version; got != want {
+				t.Errorf("MinVersion(): %s, expected %s", got, want)
+			}
+			err = d.CheckVersion()
+			if tt.wantErr && err == nil {
+				t.Errorf("CheckVersion(): expected error")
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("CheckVersion(): %v", err)
+			}
+		})
+	}
+}
@@ -5,7 +5,8 @@
 // WIT ([WebAssembly Interface Type]) is an interface definition language with rich types, functions, and methods,
 // used to define the interface of a [Component].
 //
-// Note: this package depends on the [wasm-tools] exectuable to parse WIT into an intermediary JSON representation.
+// WIT text is parsed natively by [LoadWIT] and [DecodeWIT]. Binary-encoded WIT packages are decoded
+// with [wasm-tools], which is embedded in this module as a WebAssembly program.
 //
 // # Structure
 //
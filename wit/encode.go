@@ -0,0 +1,526 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// EncodeJSON encodes res into the JSON representation produced by [wasm-tools]
+// and writes it to w. The output is indented, and can be decoded by [DecodeJSON].
+//
+// [wasm-tools]: https://crates.io/crates/wasm-tools
+func EncodeJSON(w io.Writer, res *Resolve) error {
+	data, err := res.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	err = json.Indent(&buf, data, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+// It encodes res into the index-based JSON representation of a fully-resolved
+// WIT package produced by [wasm-tools], with worlds, interfaces, types, and packages
+// referenced by their index in res.
+//
+// [wasm-tools]: https://crates.io/crates/wasm-tools
+func (res *Resolve) MarshalJSON() ([]byte, error) {
+	e := newJSONEncoder(res)
+	v, err := e.resolve()
+	if err != nil {
+		return nil, err
+	}
+	return v.MarshalJSON()
+}
+
+// jsonObject is a JSON object with fields in a specific order.
+type jsonObject []jsonField
+
+type jsonField struct {
+	name  string
+	value any
+}
+
+// add appends a field to o.
+func (o *jsonObject) add(name string, value any) {
+	*o = append(*o, jsonField{name, value})
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := enc.Encode(f.name); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := enc.Encode(f.value); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonEncoder translates a [Resolve] into JSON values,
+// replacing references with indices.
+type jsonEncoder struct {
+	res        *Resolve
+	worlds     map[*World]int
+	interfaces map[*Interface]int
+	typeDefs   map[*TypeDef]int
+	packages   map[*Package]int
+}
+
+func newJSONEncoder(res *Resolve) *jsonEncoder {
+	return &jsonEncoder{
+		res:        res,
+		worlds:     indices(res.Worlds),
+		interfaces: indices(res.Interfaces),
+		typeDefs:   indices(res.TypeDefs),
+		packages:   indices(res.Packages),
+	}
+}
+
+func indices[T comparable](s []T) map[T]int {
+	m := make(map[T]int, len(s))
+	for i, v := range s {
+		m[v] = i
+	}
+	return m
+}
+
+func (e *jsonEncoder) resolve() (jsonObject, error) {
+	worlds := []any{}
+	for _, w := range e.res.Worlds {
+		v, err := e.world(w)
+		if err != nil {
+			return nil, err
+		}
+		worlds = append(worlds, v)
+	}
+	interfaces := []any{}
+	for _, i := range e.res.Interfaces {
+		v, err := e.iface(i)
+		if err != nil {
+			return nil, err
+		}
+		interfaces = append(interfaces, v)
+	}
+	types := []any{}
+	for _, t := range e.res.TypeDefs {
+		v, err := e.typeDef(t)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, v)
+	}
+	packages := []any{}
+	for _, p := range e.res.Packages {
+		v, err := e.pkg(p)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, v)
+	}
+	return jsonObject{
+		{"worlds", worlds},
+		{"interfaces", interfaces},
+		{"types", types},
+		{"packages", packages},
+	}, nil
+}
+
+func (e *jsonEncoder) world(w *World) (jsonObject, error) {
+	imports, err := e.worldItems(w, &w.Imports)
+	if err != nil {
+		return nil, err
+	}
+	exports, err := e.worldItems(w, &w.Exports)
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := e.packageRef(w.Package)
+	if err != nil {
+		return nil, err
+	}
+	o := jsonObject{
+		{"name", w.Name},
+		{"imports", imports},
+		{"exports", exports},
+		{"package", pkg},
+	}
+	e.docs(&o, &w.Docs)
+	e.stability(&o, w.Stability)
+	return o, nil
+}
+
+func (e *jsonEncoder) worldItems(w *World, items *ordered.Map[string, WorldItem]) (jsonObject, error) {
+	o := jsonObject{}
+	var err error
+	items.All()(func(name string, item WorldItem) bool {
+		var v any
+		switch item := item.(type) {
+		case *InterfaceRef:
+			var id int
+			id, err = e.interfaceRef(item.Interface)
+			ref := jsonObject{{"id", id}}
+			e.stability(&ref, item.Stability)
+			v = jsonObject{{"interface", ref}}
+		case *TypeDef:
+			var id int
+			id, err = e.typeDefRef(item)
+			v = jsonObject{{"type", id}}
+		case *Function:
+			var f jsonObject
+			f, err = e.function(item)
+			v = jsonObject{{"function", f}}
+		default:
+			err = fmt.Errorf("world %s: unknown world item %T", w.Name, item)
+		}
+		o.add(name, v)
+		return err == nil
+	})
+	return o, err
+}
+
+func (e *jsonEncoder) iface(i *Interface) (jsonObject, error) {
+	types := jsonObject{}
+	var err error
+	i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		var id int
+		id, err = e.typeDefRef(t)
+		types.add(name, id)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	functions := jsonObject{}
+	i.Functions.All()(func(name string, f *Function) bool {
+		var v jsonObject
+		v, err = e.function(f)
+		functions.add(name, v)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := e.packageRef(i.Package)
+	if err != nil {
+		return nil, err
+	}
+	o := jsonObject{
+		{"name", i.Name},
+		{"types", types},
+		{"functions", functions},
+	}
+	e.docs(&o, &i.Docs)
+	e.stability(&o, i.Stability)
+	o.add("package", pkg)
+	return o, nil
+}
+
+func (e *jsonEncoder) typeDef(t *TypeDef) (jsonObject, error) {
+	kind, err := e.typeDefKind(t.Kind)
+	if err != nil {
+		return nil, err
+	}
+	var owner any
+	switch o := t.Owner.(type) {
+	case nil:
+	case *Interface:
+		id, err := e.interfaceRef(o)
+		if err != nil {
+			return nil, err
+		}
+		owner = jsonObject{{"interface", id}}
+	case *World:
+		id, err := e.worldRef(o)
+		if err != nil {
+			return nil, err
+		}
+		owner = jsonObject{{"world", id}}
+	default:
+		return nil, fmt.Errorf("unknown type owner %T", o)
+	}
+	o := jsonObject{
+		{"name", t.Name},
+		{"kind", kind},
+		{"owner", owner},
+	}
+	e.docs(&o, &t.Docs)
+	e.stability(&o, t.Stability)
+	return o, nil
+}
+
+func (e *jsonEncoder) typeDefKind(kind TypeDefKind) (any, error) {
+	switch kind := kind.(type) {
+	case Type:
+		t, err := e.typ(kind)
+		return jsonObject{{"type", t}}, err
+	case *Record:
+		fields := []any{}
+		for i := range kind.Fields {
+			f := &kind.Fields[i]
+			t, err := e.typ(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			o := jsonObject{{"name", f.Name}, {"type", t}}
+			e.docs(&o, &f.Docs)
+			fields = append(fields, o)
+		}
+		return jsonObject{{"record", jsonObject{{"fields", fields}}}}, nil
+	case *Resource:
+		return "resource", nil
+	case *Own:
+		id, err := e.typeDefRef(kind.Type)
+		return jsonObject{{"handle", jsonObject{{"own", id}}}}, err
+	case *Borrow:
+		id, err := e.typeDefRef(kind.Type)
+		return jsonObject{{"handle", jsonObject{{"borrow", id}}}}, err
+	case *Flags:
+		flags := []any{}
+		for i := range kind.Flags {
+			f := &kind.Flags[i]
+			o := jsonObject{{"name", f.Name}}
+			e.docs(&o, &f.Docs)
+			flags = append(flags, o)
+		}
+		return jsonObject{{"flags", jsonObject{{"flags", flags}}}}, nil
+	case *Tuple:
+		types := []any{}
+		for _, t := range kind.Types {
+			v, err := e.typ(t)
+			if err != nil {
+				return nil, err
+			}
+			types = append(types, v)
+		}
+		return jsonObject{{"tuple", jsonObject{{"types", types}}}}, nil
+	case *Variant:
+		cases := []any{}
+		for i := range kind.Cases {
+			c := &kind.Cases[i]
+			t, err := e.typ(c.Type)
+			if err != nil {
+				return nil, err
+			}
+			o := jsonObject{{"name", c.Name}, {"type", t}}
+			e.docs(&o, &c.Docs)
+			cases = append(cases, o)
+		}
+		return jsonObject{{"variant", jsonObject{{"cases", cases}}}}, nil
+	case *Enum:
+		cases := []any{}
+		for i := range kind.Cases {
+			c := &kind.Cases[i]
+			o := jsonObject{{"name", c.Name}}
+			e.docs(&o, &c.Docs)
+			cases = append(cases, o)
+		}
+		return jsonObject{{"enum", jsonObject{{"cases", cases}}}}, nil
+	case *Option:
+		t, err := e.typ(kind.Type)
+		return jsonObject{{"option", t}}, err
+	case *Result:
+		ok, err := e.typ(kind.OK)
+		if err != nil {
+			return nil, err
+		}
+		errType, err := e.typ(kind.Err)
+		return jsonObject{{"result", jsonObject{{"ok", ok}, {"err", errType}}}}, err
+	case *List:
+		t, err := e.typ(kind.Type)
+		return jsonObject{{"list", t}}, err
+	case *Future:
+		t, err := e.typ(kind.Type)
+		return jsonObject{{"future", t}}, err
+	case *Stream:
+		t, err := e.typ(kind.Type)
+		return jsonObject{{"stream", t}}, err
+	case *ErrorContext:
+		return "error-context", nil
+	}
+	return nil, fmt.Errorf("cannot encode type kind %T as JSON", kind)
+}
+
+// typ returns the JSON representation of [Type] t:
+// an index for a [TypeDef], a string for a primitive type, or nil.
+func (e *jsonEncoder) typ(t Type) (any, error) {
+	switch t := t.(type) {
+	case nil:
+		return nil, nil
+	case *TypeDef:
+		return e.typeDefRef(t)
+	}
+	return t.WITKind(), nil
+}
+
+func (e *jsonEncoder) function(f *Function) (jsonObject, error) {
+	var kind any
+	var err error
+	// Async functions have an async- prefix on their kind, e.g. async-method.
+	prefix := ""
+	if f.Async {
+		prefix = "async-"
+	}
+	switch k := f.Kind.(type) {
+	case *Freestanding:
+		kind = prefix + "freestanding"
+	case *Method:
+		var t any
+		t, err = e.typ(k.Type)
+		kind = jsonObject{{prefix + "method", t}}
+	case *Static:
+		var t any
+		t, err = e.typ(k.Type)
+		kind = jsonObject{{prefix + "static", t}}
+	case *Constructor:
+		var t any
+		t, err = e.typ(k.Type)
+		kind = jsonObject{{"constructor", t}}
+	default:
+		err = fmt.Errorf("function %s: unknown function kind %T", f.Name, k)
+	}
+	if err != nil {
+		return nil, err
+	}
+	params := []any{}
+	for _, p := range f.Params {
+		t, err := e.typ(p.Type)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, jsonObject{{"name", p.Name}, {"type", t}})
+	}
+	results := []any{}
+	for _, p := range f.Results {
+		t, err := e.typ(p.Type)
+		if err != nil {
+			return nil, err
+		}
+		o := jsonObject{}
+		if p.Name != "" {
+			o.add("name", p.Name)
+		}
+		o.add("type", t)
+		results = append(results, o)
+	}
+	o := jsonObject{
+		{"name", f.Name},
+		{"kind", kind},
+		{"params", params},
+		{"results", results},
+	}
+	e.docs(&o, &f.Docs)
+	e.stability(&o, f.Stability)
+	return o, nil
+}
+
+func (e *jsonEncoder) pkg(p *Package) (jsonObject, error) {
+	interfaces := jsonObject{}
+	var err error
+	p.Interfaces.All()(func(name string, i *Interface) bool {
+		var id int
+		id, err = e.interfaceRef(i)
+		interfaces.add(name, id)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	worlds := jsonObject{}
+	p.Worlds.All()(func(name string, w *World) bool {
+		var id int
+		id, err = e.worldRef(w)
+		worlds.add(name, id)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Package names are not escaped in JSON.
+	name := p.Name.Namespace + ":" + p.Name.Package
+	if p.Name.Version != nil {
+		name += "@" + p.Name.Version.String()
+	}
+	o := jsonObject{{"name", name}}
+	e.docs(&o, &p.Docs)
+	o.add("interfaces", interfaces)
+	o.add("worlds", worlds)
+	return o, nil
+}
+
+// docs adds a docs field to o if d is not empty.
+func (e *jsonEncoder) docs(o *jsonObject, d *Docs) {
+	if d.Contents != "" {
+		o.add("docs", jsonObject{{"contents", d.Contents}})
+	}
+}
+
+// stability adds a stability field to o if s is not nil.
+func (e *jsonEncoder) stability(o *jsonObject, s Stability) {
+	var v jsonObject
+	switch s := s.(type) {
+	case *Stable:
+		stable := jsonObject{{"since", s.Since.String()}}
+		if s.Deprecated != nil {
+			stable.add("deprecated", s.Deprecated.String())
+		}
+		v = jsonObject{{"stable", stable}}
+	case *Unstable:
+		unstable := jsonObject{{"feature", s.Feature}}
+		if s.Deprecated != nil {
+			unstable.add("deprecated", s.Deprecated.String())
+		}
+		v = jsonObject{{"unstable", unstable}}
+	default:
+		return
+	}
+	o.add("stability", v)
+}
+
+func (e *jsonEncoder) worldRef(w *World) (int, error) {
+	return ref(e.worlds, w, "world")
+}
+
+func (e *jsonEncoder) interfaceRef(i *Interface) (int, error) {
+	return ref(e.interfaces, i, "interface")
+}
+
+func (e *jsonEncoder) typeDefRef(t *TypeDef) (int, error) {
+	return ref(e.typeDefs, t, "type")
+}
+
+// packageRef returns the index of [Package] p, or nil if p is nil.
+func (e *jsonEncoder) packageRef(p *Package) (any, error) {
+	if p == nil {
+		return nil, nil
+	}
+	return ref(e.packages, p, "package")
+}
+
+func ref[T comparable](m map[T]int, v T, kind string) (int, error) {
+	i, ok := m[v]
+	if !ok {
+		return 0, fmt.Errorf("%s not found in Resolve", kind)
+	}
+	return i, nil
+}
@@ -0,0 +1,680 @@
+// Package format implements standard formatting of WIT source.
+package format
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.bytecodealliance.org/internal/witlex"
+)
+
+// Source formats WIT source src in canonical style and returns the result.
+// Comments, doc comments, attributes, and the order of items are preserved, as are
+// single blank lines between items in interface, world, and resource bodies.
+// Record, flags, enum, and variant bodies written on a single line without
+// comments remain on a single line.
+//
+// Formatting is limited to a single file, so each file of a multi-file package
+// may be formatted independently. If src is not syntactically valid WIT,
+// Source returns an error with the line and column of the problem.
+func Source(src []byte) (out []byte, err error) {
+	tokens, err := lex(string(src))
+	if err != nil {
+		return nil, err
+	}
+	p := &printer{tokens: tokens}
+	defer func() {
+		if r := recover(); r != nil {
+			b, ok := r.(bailout)
+			if !ok {
+				panic(r)
+			}
+			out, err = nil, b.err
+		}
+	}()
+	p.file()
+	return []byte(p.out.String()), nil
+}
+
+// token is a single lexical token in WIT source, with the comments that precede it.
+type token = witlex.Token
+
+// lex splits WIT source into tokens. The last token is always [witlex.EOF].
+func lex(src string) ([]token, error) {
+	l := witlex.New("", src)
+	var tokens []token
+	for {
+		t, err := l.Next()
+		if err != nil {
+			var e *witlex.Error
+			if errors.As(err, &e) {
+				return nil, fmt.Errorf("%d:%d: %s", e.Pos.Line, e.Pos.Col, e.Msg)
+			}
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		if t.Kind == witlex.EOF {
+			return tokens, nil
+		}
+	}
+}
+
+// bailout is panicked by the printer on a syntax error and recovered by [Source].
+type bailout struct {
+	err error
+}
+
+// separator is the whitespace written before the next token.
+type separator int
+
+const (
+	sepNone separator = iota
+	sepSpace
+	sepNewline
+	sepBlank
+)
+
+type printer struct {
+	tokens []token
+	pos    int
+	out    strings.Builder
+	depth  int
+
+	sep       separator // separator before the next token
+	keepBlank bool      // preserve a blank line before the next token
+}
+
+func (p *printer) peek() *token {
+	return &p.tokens[p.pos]
+}
+
+func (p *printer) peekAt(n int) *token {
+	return &p.tokens[min(p.pos+n, len(p.tokens)-1)]
+}
+
+func (p *printer) errorf(t *token, format string, args ...any) {
+	panic(bailout{fmt.Errorf("%d:%d: %s", t.Pos.Line, t.Pos.Col, fmt.Sprintf(format, args...))})
+}
+
+func (p *printer) space() {
+	p.sep = sepSpace
+}
+
+// newline starts the next token on a new line. If keepBlank is true,
+// a blank line before the next token in the source is preserved.
+func (p *printer) newline(keepBlank bool) {
+	p.sep = sepNewline
+	p.keepBlank = keepBlank
+}
+
+func (p *printer) blank() {
+	p.sep = sepBlank
+}
+
+func (p *printer) flush(sep separator) {
+	if p.out.Len() == 0 {
+		return
+	}
+	switch sep {
+	case sepSpace:
+		p.out.WriteByte(' ')
+	case sepBlank:
+		p.out.WriteByte('\n')
+		fallthrough
+	case sepNewline:
+		p.out.WriteByte('\n')
+		p.out.WriteString(strings.Repeat("\t", p.depth))
+	}
+}
+
+func (p *printer) write(text string) {
+	p.flush(p.sep)
+	p.out.WriteString(text)
+	p.sep = sepNone
+	p.keepBlank = false
+}
+
+// lineSep returns the separator before a token or comment preceded by n newlines in the source.
+func lineSep(sep separator, n int, keepBlank bool) separator {
+	if n >= 2 && keepBlank && sep == sepNewline {
+		return sepBlank
+	}
+	if n >= 1 && sep < sepNewline {
+		return sepNewline
+	}
+	return sep
+}
+
+// comments writes the comments preceding token t and sets the separator before t.
+// A comment on the same line as the previous token is kept on that line, after that token.
+// A block comment followed by t on the same line is written before t, unless t is
+// closing punctuation such as `,` or `)`.
+func (p *printer) comments(t *token) {
+	if len(t.Comments) == 0 {
+		if p.keepBlank {
+			p.sep = lineSep(p.sep, t.Newlines, true)
+		}
+		return
+	}
+	sep := p.sep
+	keepBlank := p.keepBlank
+	block := sep >= sepNewline
+	for i, c := range t.Comments {
+		if i == 0 && c.Newlines == 0 && p.out.Len() > 0 && p.trailing(t, i) {
+			p.out.WriteByte(' ')
+			p.out.WriteString(commentText(&c))
+			if c.IsLine() {
+				sep = max(sep, sepNewline)
+			}
+		} else {
+			p.flush(lineSep(sep, c.Newlines, keepBlank))
+			p.out.WriteString(commentText(&c))
+			sep = sepSpace
+			if c.IsLine() {
+				sep = sepNewline
+			}
+			keepBlank = block
+		}
+	}
+	p.sep = lineSep(sep, t.Newlines, keepBlank)
+	p.keepBlank = false
+	t.Comments = nil
+}
+
+// trailing reports whether comment i of token t, on the same line as the previous token,
+// trails the previous token rather than preceding the next comment or t.
+func (p *printer) trailing(t *token, i int) bool {
+	c := &t.Comments[i]
+	if c.IsLine() {
+		return true
+	}
+	if i+1 < len(t.Comments) {
+		return t.Comments[i+1].Newlines > 0
+	}
+	return t.Newlines > 0 || t.Is(",") || t.Is(";") || t.Is(")") || t.Is(">") || t.Is("}")
+}
+
+// commentText returns the text of comment c, without trailing whitespace after a line comment.
+func commentText(c *witlex.Comment) string {
+	if c.IsLine() {
+		return strings.TrimRight(c.Text, " \t\r")
+	}
+	return c.Text
+}
+
+// emit writes the next token and its preceding comments.
+func (p *printer) emit() *token {
+	t := p.peek()
+	p.comments(t)
+	p.write(t.Raw())
+	p.pos++
+	return t
+}
+
+// skip omits the next token, writing only its preceding comments.
+func (p *printer) skip() {
+	p.comments(p.peek())
+	p.pos++
+}
+
+func (p *printer) expect(s string) {
+	if t := p.peek(); !t.Is(s) {
+		p.errorf(t, "expected `%s`, found %s", s, t)
+	}
+	p.emit()
+}
+
+func (p *printer) ident() {
+	if t := p.peek(); t.Kind != witlex.Ident {
+		p.errorf(t, "expected identifier, found %s", t)
+	}
+	p.emit()
+}
+
+func (p *printer) version() {
+	if t := p.peek(); t.Kind != witlex.Version {
+		p.errorf(t, "expected version, found %s", t)
+	}
+	p.emit()
+}
+
+// file formats a WIT file: an optional package declaration followed by
+// top-level items, each separated by a blank line.
+func (p *printer) file() {
+	p.newline(true)
+	p.items(p.packageItem)
+	eof := p.peek()
+	if eof.Kind != witlex.EOF {
+		p.errorf(eof, "unexpected %s", eof)
+	}
+	p.newline(true)
+	p.comments(eof)
+	if p.out.Len() > 0 {
+		p.out.WriteByte('\n')
+	}
+}
+
+// items formats package items until the end of file or a closing brace.
+// Items are separated by a blank line, except for consecutive use statements.
+func (p *printer) items(item func()) {
+	first := true
+	use := false
+	for t := p.peek(); t.Kind != witlex.EOF && !t.Is("}"); t = p.peek() {
+		if !first {
+			if use && t.Is("use") {
+				p.newline(true)
+			} else {
+				p.blank()
+			}
+		}
+		first = false
+		use = t.Is("use")
+		item()
+	}
+}
+
+func (p *printer) packageItem() {
+	p.attributes()
+	t := p.peek()
+	switch {
+	case t.Is("package"):
+		p.emit()
+		p.space()
+		p.path()
+		if p.peek().Is(";") {
+			p.emit()
+			return
+		}
+		p.block(func() { p.items(p.packageItem) })
+	case t.Is("interface"):
+		p.emit()
+		p.space()
+		p.ident()
+		p.block(p.interfaceItems)
+	case t.Is("world"):
+		p.emit()
+		p.space()
+		p.ident()
+		p.block(p.worldItems)
+	case t.Is("use"):
+		p.use()
+	default:
+		p.errorf(t, "expected `package`, `interface`, `world`, or `use`, found %s", t)
+	}
+}
+
+// block formats a brace-delimited body, with each item of the body on its own line.
+// An empty body without comments is written as {}.
+func (p *printer) block(body func()) {
+	p.space()
+	p.expect("{")
+	if t := p.peek(); t.Is("}") && len(t.Comments) == 0 {
+		p.emit()
+		return
+	}
+	p.depth++
+	p.newline(false)
+	body()
+	p.close()
+}
+
+// close formats the closing brace of a multi-line body,
+// keeping comments before the brace indented within the body.
+func (p *printer) close() {
+	t := p.peek()
+	if !t.Is("}") {
+		p.errorf(t, "expected `}`, found %s", t)
+	}
+	p.newline(true)
+	p.comments(t)
+	p.depth--
+	p.newline(false)
+	p.emit()
+}
+
+// lines formats items of a multi-line body until a closing brace,
+// preserving single blank lines between them.
+func (p *printer) lines(item func()) {
+	for t := p.peek(); !t.Is("}"); t = p.peek() {
+		if t.Kind == witlex.EOF {
+			p.errorf(t, "expected `}`, found %s", t)
+		}
+		item()
+		p.newline(true)
+	}
+}
+
+func (p *printer) interfaceItems() {
+	p.lines(p.interfaceItem)
+}
+
+func (p *printer) interfaceItem() {
+	p.attributes()
+	t := p.peek()
+	switch {
+	case t.Is("use"):
+		p.use()
+	case isTypeKeyword(t):
+		p.typeDef()
+	default:
+		p.ident()
+		p.expect(":")
+		p.space()
+		p.funcType()
+		p.expect(";")
+	}
+}
+
+func (p *printer) worldItems() {
+	p.lines(p.worldItem)
+}
+
+func (p *printer) worldItem() {
+	p.attributes()
+	t := p.peek()
+	switch {
+	case t.Is("use"):
+		p.use()
+	case t.Is("import") || t.Is("export"):
+		p.emit()
+		p.space()
+		p.extern()
+	case t.Is("include"):
+		p.emit()
+		p.space()
+		p.path()
+		if p.peek().Is("with") {
+			p.space()
+			p.emit()
+			p.space()
+			p.expect("{")
+			p.space()
+			p.list("}", func() {
+				p.ident()
+				p.space()
+				p.expect("as")
+				p.space()
+				p.ident()
+			})
+			p.space()
+			p.expect("}")
+			// The with form is not terminated by a semicolon.
+			return
+		}
+		p.expect(";")
+	case isTypeKeyword(t):
+		p.typeDef()
+	default:
+		p.errorf(t, "expected world item, found %s", t)
+	}
+}
+
+// extern formats the imported or exported item following import or export in a world.
+func (p *printer) extern() {
+	if p.peekAt(1).Is(":") {
+		switch next := p.peekAt(2); {
+		case next.Is("interface") && p.peekAt(3).Is("{"):
+			p.ident()
+			p.expect(":")
+			p.space()
+			p.emit()
+			p.block(p.interfaceItems)
+			return
+		case next.Is("func") || next.Is("async") && p.peekAt(3).Is("func"):
+			p.ident()
+			p.expect(":")
+			p.space()
+			p.funcType()
+			p.expect(";")
+			return
+		}
+	}
+	p.path()
+	p.expect(";")
+}
+
+// use formats a use statement in a package, interface, or world.
+func (p *printer) use() {
+	p.expect("use")
+	p.space()
+	p.path()
+	if p.peek().Is(".") {
+		p.emit()
+		p.expect("{")
+		p.list("}", func() {
+			p.ident()
+			if p.peek().Is("as") {
+				p.space()
+				p.emit()
+				p.space()
+				p.ident()
+			}
+		})
+		p.expect("}")
+	}
+	if p.peek().Is("as") {
+		p.space()
+		p.emit()
+		p.space()
+		p.ident()
+	}
+	p.expect(";")
+}
+
+// path formats a package name or a reference to an interface or world,
+// such as foo, wasi:io, or wasi:io/streams@0.2.0.
+func (p *printer) path() {
+	p.ident()
+	for p.peek().Is(":") || p.peek().Is("/") {
+		p.emit()
+		p.ident()
+	}
+	if p.peek().Is("@") {
+		p.emit()
+		p.version()
+	}
+}
+
+// attributes formats attributes such as @since, @unstable, and @deprecated, each on its own line.
+func (p *printer) attributes() {
+	for p.peek().Is("@") {
+		p.emit()
+		p.ident()
+		p.expect("(")
+		p.list(")", func() {
+			p.ident()
+			if p.peek().Is("=") {
+				p.space()
+				p.emit()
+				p.space()
+				if t := p.peek(); t.Kind != witlex.Ident && t.Kind != witlex.Version {
+					p.errorf(t, "expected attribute value, found %s", t)
+				}
+				p.emit()
+			}
+		})
+		p.expect(")")
+		p.newline(false)
+	}
+}
+
+// list formats a comma-separated list of items on a single line until the end token.
+// A trailing comma is omitted.
+func (p *printer) list(end string, item func()) {
+	for !p.peek().Is(end) {
+		item()
+		if !p.peek().Is(",") {
+			return
+		}
+		if p.peekAt(1).Is(end) {
+			p.skip()
+			return
+		}
+		p.emit()
+		p.space()
+	}
+}
+
+func isTypeKeyword(t *token) bool {
+	switch {
+	case t.Is("type"), t.Is("resource"), t.Is("record"), t.Is("flags"), t.Is("enum"), t.Is("variant"):
+		return true
+	}
+	return false
+}
+
+// typeDef formats a type definition in an interface or world.
+func (p *printer) typeDef() {
+	t := p.emit()
+	p.space()
+	p.ident()
+	switch t.Text {
+	case "type":
+		p.space()
+		p.expect("=")
+		p.space()
+		p.typ()
+		p.expect(";")
+	case "resource":
+		if p.peek().Is(";") {
+			p.emit()
+			return
+		}
+		p.block(func() { p.lines(p.resourceItem) })
+	case "record":
+		p.fields(func() {
+			p.ident()
+			p.expect(":")
+			p.space()
+			p.typ()
+		})
+	case "flags", "enum":
+		p.fields(p.ident)
+	case "variant":
+		p.fields(func() {
+			p.ident()
+			if p.peek().Is("(") {
+				p.emit()
+				p.typ()
+				p.expect(")")
+			}
+		})
+	}
+}
+
+// fields formats the body of a record, flags, enum, or variant. A body written on a
+// single line without comments stays on a single line; otherwise each field is
+// written on its own line with a trailing comma.
+func (p *printer) fields(field func()) {
+	p.space()
+	p.expect("{")
+	inline := true
+	for i := p.pos; i < len(p.tokens); i++ {
+		t := &p.tokens[i]
+		if t.Newlines > 0 || len(t.Comments) > 0 {
+			inline = false
+		}
+		if t.Is("}") || t.Kind == witlex.EOF {
+			break
+		}
+	}
+	if inline {
+		if p.peek().Is("}") {
+			p.emit()
+			return
+		}
+		p.space()
+		p.list("}", field)
+		p.space()
+		p.expect("}")
+		return
+	}
+
+	p.depth++
+	p.newline(false)
+	p.lines(func() {
+		field()
+		switch t := p.peek(); {
+		case t.Is(","):
+			p.emit()
+		case t.Is("}"):
+			p.write(",")
+		default:
+			p.errorf(t, "expected `,` or `}`, found %s", t)
+		}
+	})
+	p.close()
+}
+
+// resourceItem formats a constructor, method, or static function in a resource body.
+func (p *printer) resourceItem() {
+	p.attributes()
+	if p.peek().Is("constructor") {
+		p.emit()
+		p.params()
+		p.results()
+		p.expect(";")
+		return
+	}
+	p.ident()
+	p.expect(":")
+	p.space()
+	if p.peek().Is("static") {
+		p.emit()
+		p.space()
+	}
+	p.funcType()
+	p.expect(";")
+}
+
+// funcType formats a function type, such as func(a: u32) -> string.
+func (p *printer) funcType() {
+	if p.peek().Is("async") {
+		p.emit()
+		p.space()
+	}
+	p.expect("func")
+	p.params()
+	p.results()
+}
+
+func (p *printer) params() {
+	p.expect("(")
+	p.list(")", func() {
+		p.ident()
+		p.expect(":")
+		p.space()
+		p.typ()
+	})
+	p.expect(")")
+}
+
+func (p *printer) results() {
+	if !p.peek().Is("->") {
+		return
+	}
+	p.space()
+	p.emit()
+	p.space()
+	if p.peek().Is("(") {
+		p.params()
+	} else {
+		p.typ()
+	}
+}
+
+// typ formats a type, such as u32, list<string>, or result<_, error>.
+func (p *printer) typ() {
+	t := p.peek()
+	if t.Is("_") || t.Kind == witlex.Int {
+		p.emit()
+		return
+	}
+	p.ident()
+	if p.peek().Is("<") {
+		p.emit()
+		p.list(">", p.typ)
+		p.expect(">")
+	}
+}
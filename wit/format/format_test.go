@@ -0,0 +1,188 @@
+package format
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"go.bytecodealliance.org/internal/relpath"
+	"go.bytecodealliance.org/wit"
+)
+
+const testdataPath = "../../testdata"
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"package", "package  foo:bar@1.0.0 ;", "package foo:bar@1.0.0;\n"},
+		{
+			"items",
+			"package foo:bar;\ninterface a{}\n\n\n\nworld w{import a;export b:func();}",
+			"package foo:bar;\n\ninterface a {}\n\nworld w {\n\timport a;\n\texport b: func();\n}\n",
+		},
+		{
+			"blank lines",
+			"interface a {\n\n  f: func();\n\n\n  g: func();\n  h: func();\n\n}\n",
+			"interface a {\n\tf: func();\n\n\tg: func();\n\th: func();\n}\n",
+		},
+		{
+			"functions",
+			"interface a {\n f:func( x:u32 , y : list< u8 > , )->result<_,string>;\n g: async func() -> (a: u32, b: u32);\n}",
+			"interface a {\n\tf: func(x: u32, y: list<u8>) -> result<_, string>;\n\tg: async func() -> (a: u32, b: u32);\n}\n",
+		},
+		{
+			"types",
+			"interface a {\n type t = tuple<u32,string>;\n record r { a: u32, b: u32, }\n enum e {\n  x, y\n }\n variant v { a(u32), b }\n flags f {}\n resource x;\n}",
+			"interface a {\n\ttype t = tuple<u32, string>;\n\trecord r { a: u32, b: u32 }\n\tenum e {\n\t\tx,\n\t\ty,\n\t}\n\tvariant v { a(u32), b }\n\tflags f {}\n\tresource x;\n}\n",
+		},
+		{
+			"resource",
+			"interface a {\n resource r {\n  constructor(a:u32);\n  get: func() -> u32;\n  make: static func() -> r;\n }\n}",
+			"interface a {\n\tresource r {\n\t\tconstructor(a: u32);\n\t\tget: func() -> u32;\n\t\tmake: static func() -> r;\n\t}\n}\n",
+		},
+		{
+			"use",
+			"package foo:bar;\nuse wasi:io/streams@0.2.0 as s;\nuse wasi:io/error@0.2.0;\ninterface a {\n use wasi:io/streams@0.2.0.{ input-stream , output-stream as out };\n}",
+			"package foo:bar;\n\nuse wasi:io/streams@0.2.0 as s;\nuse wasi:io/error@0.2.0;\n\ninterface a {\n\tuse wasi:io/streams@0.2.0.{input-stream, output-stream as out};\n}\n",
+		},
+		{
+			"world",
+			"world w {\n include foo:bar/baz;\n import i: interface { f: func(); }\n export wasi:cli/run@0.2.0;\n}",
+			"world w {\n\tinclude foo:bar/baz;\n\timport i: interface {\n\t\tf: func();\n\t}\n\texport wasi:cli/run@0.2.0;\n}\n",
+		},
+		{
+			"include with",
+			"world w {\n include foo:bar/baz with { a as b, c as d }\n include x with {\n  e as f,\n }\n import g: func();\n}",
+			"world w {\n\tinclude foo:bar/baz with { a as b, c as d }\n\tinclude x with { e as f }\n\timport g: func();\n}\n",
+		},
+		{
+			"attributes",
+			"interface a {\n  @since(version = 0.2.0) @deprecated(version=0.2.1)\n  f: func();\n  @unstable(feature=x)\n  g: func();\n}",
+			"interface a {\n\t@since(version = 0.2.0)\n\t@deprecated(version = 0.2.1)\n\tf: func();\n\t@unstable(feature = x)\n\tg: func();\n}\n",
+		},
+		{
+			"nested package",
+			"package foo:bar {\ninterface a {}\ninterface b {}\n}",
+			"package foo:bar {\n\tinterface a {}\n\n\tinterface b {}\n}\n",
+		},
+		{
+			"escaped identifiers",
+			"interface %interface {\n %type: func(%record: u32);\n}",
+			"interface %interface {\n\t%type: func(%record: u32);\n}\n",
+		},
+		{
+			"comments",
+			"// Copyright\n\n/// Package docs.\npackage foo:bar;\n/// Interface docs.\ninterface a { // trailing\n   /// Function docs.\n   f: func(); // after f\n\n   // before close\n}\n// end\n",
+			"// Copyright\n\n/// Package docs.\npackage foo:bar;\n\n/// Interface docs.\ninterface a { // trailing\n\t/// Function docs.\n\tf: func(); // after f\n\n\t// before close\n}\n// end\n",
+		},
+		{
+			"block comments",
+			"/* header\n * text\n */\ninterface a {\n f: func(a: u32 /* inline */, b: u32);\n}",
+			"/* header\n * text\n */\ninterface a {\n\tf: func(a: u32 /* inline */, b: u32);\n}\n",
+		},
+		{
+			"record comments",
+			"interface a {\n record r { a: u32, /* b */ b: u32 }\n record s {\n  // x\n  x: u32 // y\n }\n}",
+			"interface a {\n\trecord r {\n\t\ta: u32,\n\t\t/* b */ b: u32,\n\t}\n\trecord s {\n\t\t// x\n\t\tx: u32, // y\n\t}\n}\n",
+		},
+		{
+			"block comment before field",
+			"interface a {\n record r { a: u32, /* c */ b: u32 }\n}",
+			"interface a {\n\trecord r {\n\t\ta: u32,\n\t\t/* c */ b: u32,\n\t}\n}\n",
+		},
+		{
+			"block comment at end of line",
+			"interface a {\n record r {\n  a: u32, /* c */\n  b: u32 /* d */,\n }\n}",
+			"interface a {\n\trecord r {\n\t\ta: u32, /* c */\n\t\tb: u32 /* d */,\n\t}\n}\n",
+		},
+		{
+			"block comment before param",
+			"interface a {\n f: func(/* c */ a: u32, /* d */ b: u32);\n}",
+			"interface a {\n\tf: func(/* c */ a: u32, /* d */ b: u32);\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Source([]byte(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Source:\n%s\nexpected:\n%s", got, tt.want)
+			}
+			again, err := Source(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(again, got) {
+				t.Errorf("Source is not idempotent:\n%s\nexpected:\n%s", again, got)
+			}
+		})
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"interface a {", "1:14: expected `}`, found end of file"},
+		{"interface a {}\n}", "2:1: unexpected `}`"},
+		{"world w {\n\tfoo;\n}", "2:2: expected world item, found `foo`"},
+		{"interface a {\n\tf: func(x u32);\n}", "2:12: expected `:`, found `u32`"},
+		{"package foo:bar@;", "1:17: expected version, found `;`"},
+		{"world w {\n\tinclude x with { a as b };\n}", "2:27: expected world item, found `;`"},
+		{"/* unterminated", "1:1: unterminated block comment"},
+		{"interface a { $ }", "1:15: unexpected character '$'"},
+	}
+	for _, tt := range tests {
+		_, err := Source([]byte(tt.src))
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Source(%q): %v, expected %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+// TestTestdata verifies that formatting WIT files in testdata does not change their meaning.
+func TestTestdata(t *testing.T) {
+	err := relpath.Walk(testdataPath, func(path string) error {
+		t.Run(path, func(t *testing.T) {
+			src, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Source(src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again, err := Source(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(again, got) {
+				t.Errorf("Source is not idempotent for %s:\n%s", path, again)
+			}
+
+			want, err := wit.DecodeWIT(bytes.NewReader(src))
+			if err != nil {
+				// Skip files that depend on other files or are invalid on purpose.
+				return
+			}
+			res, err := wit.DecodeWIT(bytes.NewReader(got))
+			if err != nil {
+				t.Fatalf("formatted %s: %v\n%s", path, err, got)
+			}
+			if res.WIT(nil, "") != want.WIT(nil, "") {
+				t.Errorf("formatted %s does not match the original:\n%s", path, got)
+			}
+		})
+		return nil
+	}, "*.wit")
+	if err != nil {
+		t.Error(err)
+	}
+}
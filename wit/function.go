@@ -15,10 +15,40 @@ type Function struct {
 	Kind      FunctionKind
 	Params    []Param   // arguments to the function
 	Results   []Param   // a function can have a single anonymous result, or > 1 named results
+	Async     bool      // true if the function is async
 	Stability Stability // WIT @since or @unstable (nil if unknown)
 	Docs      Docs
 }
 
+// HasAsync returns true if [Node] node has an async [Function].
+// A [World] has the functions it imports or exports directly, and the functions
+// of each [Interface] it imports or exports. A [Resolve] has the functions of each
+// [World] and [Interface] it contains. Other nodes have no functions.
+func HasAsync(node Node) bool {
+	var found bool
+	check := func(f *Function) bool {
+		found = f.Async
+		return !found
+	}
+	switch node := node.(type) {
+	case *Resolve:
+		node.AllFunctions()(check)
+	case *World:
+		node.AllFunctions()(check)
+		node.AllInterfaces()(func(_ string, i *Interface) bool {
+			if !found {
+				i.AllFunctions()(check)
+			}
+			return !found
+		})
+	case *Interface:
+		node.AllFunctions()(check)
+	case *Function:
+		found = node.Async
+	}
+	return found
+}
+
 // BaseName returns the base name of [Function] f.
 // For static functions, this returns the function name unchanged.
 // For constructors, this removes the [constructor] and type prefix.
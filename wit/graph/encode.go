@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Fill colors of imported and exported interfaces and functions.
+const (
+	importedColor = "#cfe2ff"
+	exportedColor = "#d1e7dd"
+	bothColor     = "#fff3cd" // imported and exported
+)
+
+// EncodeJSON writes g to w as a JSON object with nodes and edges arrays.
+func EncodeJSON(w io.Writer, g *Graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// EncodeDOT writes g to w in the [DOT] language of Graphviz.
+// Imported and exported nodes are filled with different colors,
+// and edges other than [Uses] are labeled with their kind.
+//
+// [DOT]: https://graphviz.org/doc/info/lang.html
+func EncodeDOT(w io.Writer, g *Graph) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("digraph wit {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [fontname=\"Helvetica\"];\n")
+	b.WriteString("\tedge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + strconv.Quote(n.Label), "shape=" + dotShapes[n.Kind]}
+		if color := fillColor(n); color != "" {
+			attrs = append(attrs, "style=filled", "fillcolor="+strconv.Quote(color))
+		}
+		fmt.Fprintf(b, "\t%s [%s];\n", strconv.Quote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(b, "\t%s -> %s", strconv.Quote(e.From), strconv.Quote(e.To))
+		switch e.Kind {
+		case Contains:
+			b.WriteString(" [style=dashed, arrowhead=none]")
+		case Imports, Exports:
+			fmt.Fprintf(b, " [label=%q]", e.Kind)
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.Flush()
+}
+
+var dotShapes = map[NodeKind]string{
+	PackageNode:   "tab",
+	WorldNode:     "component",
+	InterfaceNode: "box",
+	TypeNode:      "ellipse",
+	FunctionNode:  "cds",
+}
+
+// EncodeMermaid writes g to w as a [Mermaid] flowchart.
+// Imported and exported nodes are assigned the classes imported, exported, or both.
+//
+// [Mermaid]: https://mermaid.js.org/syntax/flowchart.html
+func EncodeMermaid(w io.Writer, g *Graph) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("flowchart LR\n")
+	ids := make(map[string]string, len(g.Nodes))
+	classes := make(map[string][]string)
+	for i, n := range g.Nodes {
+		id := "n" + strconv.Itoa(i)
+		ids[n.ID] = id
+		shape := mermaidShapes[n.Kind]
+		fmt.Fprintf(b, "\t%s%s\"%s\"%s\n", id, shape[0], mermaidEscape(n.Label), shape[1])
+		switch {
+		case n.Imported && n.Exported:
+			classes["both"] = append(classes["both"], id)
+		case n.Imported:
+			classes["imported"] = append(classes["imported"], id)
+		case n.Exported:
+			classes["exported"] = append(classes["exported"], id)
+		}
+	}
+	for _, e := range g.Edges {
+		switch e.Kind {
+		case Contains:
+			fmt.Fprintf(b, "\t%s -.- %s\n", ids[e.From], ids[e.To])
+		case Imports, Exports:
+			fmt.Fprintf(b, "\t%s -->|%s| %s\n", ids[e.From], e.Kind, ids[e.To])
+		default:
+			fmt.Fprintf(b, "\t%s --> %s\n", ids[e.From], ids[e.To])
+		}
+	}
+	for _, class := range []struct{ name, color string }{
+		{"imported", importedColor},
+		{"exported", exportedColor},
+		{"both", bothColor},
+	} {
+		if len(classes[class.name]) > 0 {
+			fmt.Fprintf(b, "\tclassDef %s fill:%s\n", class.name, class.color)
+			fmt.Fprintf(b, "\tclass %s %s\n", strings.Join(classes[class.name], ","), class.name)
+		}
+	}
+	return b.Flush()
+}
+
+var mermaidShapes = map[NodeKind][2]string{
+	PackageNode:   {"[/", "/]"},
+	WorldNode:     {"{{", "}}"},
+	InterfaceNode: {"[", "]"},
+	TypeNode:      {"([", "])"},
+	FunctionNode:  {"(", ")"},
+}
+
+// mermaidEscape escapes s for use in a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+func fillColor(n *Node) string {
+	switch {
+	case n.Imported && n.Exported:
+		return bothColor
+	case n.Imported:
+		return importedColor
+	case n.Exported:
+		return exportedColor
+	}
+	return ""
+}
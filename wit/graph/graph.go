@@ -0,0 +1,421 @@
+// Package graph builds the dependency graph of WIT packages, worlds, interfaces, types, and functions.
+package graph
+
+import (
+	"fmt"
+
+	"go.bytecodealliance.org/wit"
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// NodeKind is the kind of WIT item represented by a [Node].
+type NodeKind string
+
+const (
+	PackageNode   NodeKind = "package"
+	WorldNode     NodeKind = "world"
+	InterfaceNode NodeKind = "interface"
+	TypeNode      NodeKind = "type"
+	FunctionNode  NodeKind = "function"
+)
+
+// EdgeKind is the relationship between the nodes of an [Edge].
+type EdgeKind string
+
+const (
+	// Contains is the relationship of a package to its interfaces and worlds,
+	// of an interface to its types and functions, and of a world to its types.
+	Contains EdgeKind = "contains"
+
+	// Imports is the relationship of a world to an imported interface or function.
+	Imports EdgeKind = "imports"
+
+	// Exports is the relationship of a world to an exported interface or function.
+	Exports EdgeKind = "exports"
+
+	// Uses is the relationship of a type or function to a type it refers to,
+	// and of an interface to another interface whose types it refers to.
+	Uses EdgeKind = "uses"
+)
+
+// Node is a WIT package, world, interface, type, or function in a [Graph].
+type Node struct {
+	// ID uniquely identifies the node, such as "wasi:io/streams@0.2.0",
+	// "wasi:io/streams@0.2.0#[method]input-stream.read", or "wasi:cli/command@0.2.0 export run".
+	ID string `json:"id"`
+
+	Kind  NodeKind `json:"kind"`
+	Label string   `json:"label"`
+
+	// Imported and Exported report whether an interface or function is imported
+	// or exported by a world in the graph.
+	Imported bool `json:"imported,omitempty"`
+	Exported bool `json:"exported,omitempty"`
+}
+
+// Edge is a directed relationship between two nodes in a [Graph], identified by their IDs.
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+}
+
+// Graph is the dependency graph of the WIT definitions in a [wit.Resolve].
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []*Edge `json:"edges"`
+}
+
+// Level is the level of detail of a [Graph].
+type Level int
+
+const (
+	// InterfaceLevel includes packages, worlds, and interfaces.
+	InterfaceLevel Level = iota + 1
+
+	// TypeLevel includes packages, worlds, interfaces, and types.
+	TypeLevel
+
+	// FunctionLevel includes packages, worlds, interfaces, types, and functions.
+	FunctionLevel
+)
+
+// Option represents a single configuration option for [Build].
+type Option interface {
+	applyOption(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) applyOption(opts *options) {
+	f(opts)
+}
+
+type options struct {
+	world string
+	level Level
+}
+
+func (opts *options) apply(o ...Option) {
+	for _, o := range o {
+		o.applyOption(opts)
+	}
+}
+
+// World returns an [Option] that limits the graph to a single world and the interfaces,
+// types, and functions it depends on. The pattern matches a world name, such as "command",
+// or a qualified name, such as "wasi:cli/command" or "wasi:cli/command@0.2.0".
+// Only the imports and exports of this world are marked as imported or exported.
+func World(pattern string) Option {
+	return optionFunc(func(opts *options) {
+		opts.world = pattern
+	})
+}
+
+// Detail returns an [Option] that sets the level of detail of the graph.
+// The default is [FunctionLevel]. Dependencies between interfaces are
+// included at every level.
+func Detail(level Level) Option {
+	return optionFunc(func(opts *options) {
+		opts.level = level
+	})
+}
+
+// Build builds the dependency graph of the packages in res.
+func Build(res *wit.Resolve, opts ...Option) (*Graph, error) {
+	o := options{level: FunctionLevel}
+	o.apply(opts...)
+
+	var world *wit.World
+	if o.world != "" {
+		for _, w := range res.Worlds {
+			if w.Match(o.world) {
+				world = w
+				break
+			}
+		}
+		if world == nil {
+			return nil, fmt.Errorf("world %s not found", o.world)
+		}
+	}
+
+	b := &builder{
+		world:  world,
+		nodes:  make(map[string]*Node),
+		ids:    make(map[wit.Node]string),
+		owners: make(map[string]string),
+		edges:  make(map[Edge]bool),
+	}
+	for _, pkg := range res.Packages {
+		b.addPackage(pkg)
+	}
+	b.addDependencies()
+
+	g := &b.graph
+	if world != nil {
+		g = g.reachable(b.ids[world])
+	}
+	return g.filter(o.level), nil
+}
+
+// builder builds a [Graph].
+type builder struct {
+	graph Graph
+	world *wit.World // if non-nil, only the imports and exports of world are marked
+
+	nodes  map[string]*Node
+	ids    map[wit.Node]string
+	items  []wit.Node        // in the order added
+	owners map[string]string // type or function ID → interface or world ID
+	edges  map[Edge]bool
+}
+
+func (b *builder) node(item wit.Node, id string, kind NodeKind, label string) *Node {
+	b.ids[item] = id
+	b.items = append(b.items, item)
+	n := &Node{ID: id, Kind: kind, Label: label}
+	b.nodes[id] = n
+	b.graph.Nodes = append(b.graph.Nodes, n)
+	return n
+}
+
+func (b *builder) edge(from, to string, kind EdgeKind) {
+	e := Edge{From: from, To: to, Kind: kind}
+	if from == to || b.edges[e] {
+		return
+	}
+	b.edges[e] = true
+	b.graph.Edges = append(b.graph.Edges, &e)
+}
+
+func (b *builder) addPackage(pkg *wit.Package) {
+	pkgID := pkg.Name.String()
+	b.node(pkg, pkgID, PackageNode, pkgID)
+	pkg.Interfaces.All()(func(name string, i *wit.Interface) bool {
+		id := pkg.Name
+		id.Extension = name
+		b.edge(pkgID, id.String(), Contains)
+		b.addInterface(i, id.String(), name)
+		return true
+	})
+	pkg.Worlds.All()(func(name string, w *wit.World) bool {
+		id := pkg.Name
+		id.Extension = name
+		b.edge(pkgID, id.String(), Contains)
+		b.addWorld(w, id.String())
+		return true
+	})
+}
+
+func (b *builder) addInterface(i *wit.Interface, id, label string) {
+	b.node(i, id, InterfaceNode, label)
+	i.TypeDefs.All()(func(name string, t *wit.TypeDef) bool {
+		b.member(id, Contains, t, id+"#"+name, TypeNode, name)
+		return true
+	})
+	i.Functions.All()(func(name string, f *wit.Function) bool {
+		b.member(id, Contains, f, id+"#"+name, FunctionNode, functionLabel(f))
+		return true
+	})
+}
+
+// member adds a type or function of the interface or world with ID owner,
+// with an edge of kind edge from owner.
+func (b *builder) member(owner string, edge EdgeKind, item wit.Node, id string, kind NodeKind, label string) *Node {
+	n := b.node(item, id, kind, label)
+	b.edge(owner, id, edge)
+	b.owners[id] = owner
+	return n
+}
+
+func (b *builder) addWorld(w *wit.World, id string) {
+	b.node(w, id, WorldNode, w.Name)
+	mark := b.world == nil || b.world == w
+	addItems := func(items *ordered.Map[string, wit.WorldItem], kind EdgeKind, prefix string) {
+		items.All()(func(name string, item wit.WorldItem) bool {
+			var n *Node
+			switch item := item.(type) {
+			case *wit.InterfaceRef:
+				if item.Interface.Name == nil {
+					b.addInterface(item.Interface, prefix+name, name)
+				}
+				// Packages are sorted topologically, so named interfaces have been added.
+				n = b.nodes[b.ids[item.Interface]]
+			case *wit.TypeDef:
+				b.member(id, Contains, item, prefix+name, TypeNode, name)
+				return true
+			case *wit.Function:
+				n = b.member(id, kind, item, prefix+name, FunctionNode, functionLabel(item))
+			}
+			b.edge(id, n.ID, kind)
+			if mark {
+				n.Imported = n.Imported || kind == Imports
+				n.Exported = n.Exported || kind == Exports
+			}
+			return true
+		})
+	}
+	addItems(&w.Imports, Imports, id+" import ")
+	addItems(&w.Exports, Exports, id+" export ")
+}
+
+// addDependencies adds the types used by each type and function,
+// and the interfaces used by each interface through its types and functions.
+func (b *builder) addDependencies() {
+	for _, item := range b.items {
+		from := b.ids[item]
+		switch item := item.(type) {
+		case *wit.TypeDef:
+			typeDeps(item.Kind, func(t *wit.TypeDef) { b.uses(from, t) })
+		case *wit.Function:
+			for _, p := range item.Params {
+				typeDeps(p.Type, func(t *wit.TypeDef) { b.uses(from, t) })
+			}
+			for _, r := range item.Results {
+				typeDeps(r.Type, func(t *wit.TypeDef) { b.uses(from, t) })
+			}
+		}
+	}
+}
+
+// uses adds an edge from the type or function with ID from to type t, and
+// from the interface that contains from to the interface that contains t, if they differ.
+func (b *builder) uses(from string, t *wit.TypeDef) {
+	to, ok := b.ids[t]
+	if !ok {
+		return
+	}
+	b.edge(from, to, Uses)
+	fromOwner, toOwner := b.nodes[b.owners[from]], b.nodes[b.owners[to]]
+	if fromOwner != nil && toOwner != nil && fromOwner.Kind == InterfaceNode && toOwner.Kind == InterfaceNode {
+		b.edge(fromOwner.ID, toOwner.ID, Uses)
+	}
+}
+
+// typeDeps calls yield for each named type that t refers to directly,
+// including the named types referred to by the anonymous types in t.
+func typeDeps(t wit.TypeDefKind, yield func(*wit.TypeDef)) {
+	switch t := t.(type) {
+	case *wit.TypeDef:
+		if t.Name != nil {
+			yield(t)
+			return
+		}
+		typeDeps(t.Kind, yield)
+	case *wit.Record:
+		for _, f := range t.Fields {
+			typeDeps(f.Type, yield)
+		}
+	case *wit.Variant:
+		for _, c := range t.Cases {
+			if c.Type != nil {
+				typeDeps(c.Type, yield)
+			}
+		}
+	case *wit.Tuple:
+		for _, t := range t.Types {
+			typeDeps(t, yield)
+		}
+	case *wit.Option:
+		typeDeps(t.Type, yield)
+	case *wit.Result:
+		if t.OK != nil {
+			typeDeps(t.OK, yield)
+		}
+		if t.Err != nil {
+			typeDeps(t.Err, yield)
+		}
+	case *wit.List:
+		typeDeps(t.Type, yield)
+	case *wit.Future:
+		if t.Type != nil {
+			typeDeps(t.Type, yield)
+		}
+	case *wit.Stream:
+		if t.Type != nil {
+			typeDeps(t.Type, yield)
+		}
+	case *wit.Own:
+		typeDeps(t.Type, yield)
+	case *wit.Borrow:
+		typeDeps(t.Type, yield)
+	}
+}
+
+func functionLabel(f *wit.Function) string {
+	if t := f.Type(); t != nil {
+		return t.TypeName() + "." + f.BaseName()
+	}
+	return f.BaseName()
+}
+
+// reachable returns the subgraph of g reachable from the node with ID root, without
+// following edges from packages, together with the packages that contain its nodes.
+func (g *Graph) reachable(root string) *Graph {
+	kinds := make(map[string]NodeKind)
+	for _, n := range g.Nodes {
+		kinds[n.ID] = n.Kind
+	}
+	out := make(map[string][]*Edge)
+	for _, e := range g.Edges {
+		out[e.From] = append(out[e.From], e)
+	}
+
+	keep := map[string]bool{root: true}
+	queue := []string{root}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, e := range out[id] {
+			if !keep[e.To] {
+				keep[e.To] = true
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	for _, e := range g.Edges {
+		if kinds[e.From] == PackageNode && keep[e.To] {
+			keep[e.From] = true
+		}
+	}
+
+	sub := &Graph{}
+	for _, n := range g.Nodes {
+		if keep[n.ID] {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub
+}
+
+// filter returns the subgraph of g with the nodes included at level.
+func (g *Graph) filter(level Level) *Graph {
+	include := func(n *Node) bool {
+		switch n.Kind {
+		case TypeNode:
+			return level >= TypeLevel
+		case FunctionNode:
+			return level >= FunctionLevel
+		}
+		return true
+	}
+	keep := make(map[string]bool)
+	sub := &Graph{Nodes: []*Node{}, Edges: []*Edge{}}
+	for _, n := range g.Nodes {
+		if include(n) {
+			keep[n.ID] = true
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub
+}
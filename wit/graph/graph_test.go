@@ -0,0 +1,217 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+const graphWIT = `package example:graph@0.1.0;
+
+interface types {
+	record point { x: s32, y: s32 }
+	resource r {
+		get: func() -> option<point>;
+	}
+}
+
+interface api {
+	use types.{point, r};
+	get: func() -> list<point>;
+	open: func() -> r;
+}
+
+interface unused {
+	f: func();
+}
+
+world app {
+	import api;
+	export run: func();
+	export api;
+}
+
+world other {
+	import unused;
+}
+`
+
+func loadGraphWIT(t *testing.T) *wit.Resolve {
+	t.Helper()
+	res, err := wit.DecodeWIT(strings.NewReader(graphWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func nodeStrings(g *Graph) []string {
+	var s []string
+	for _, n := range g.Nodes {
+		v := string(n.Kind) + " " + n.ID
+		if n.Imported {
+			v += " (imported)"
+		}
+		if n.Exported {
+			v += " (exported)"
+		}
+		s = append(s, v)
+	}
+	return s
+}
+
+func edgeStrings(g *Graph) []string {
+	var s []string
+	for _, e := range g.Edges {
+		s = append(s, e.From+" "+string(e.Kind)+" "+e.To)
+	}
+	return s
+}
+
+func TestBuild(t *testing.T) {
+	res := loadGraphWIT(t)
+
+	g, err := Build(res, World("example:graph/app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNodes := []string{
+		"package example:graph@0.1.0",
+		"interface example:graph/types@0.1.0 (imported)",
+		"type example:graph/types@0.1.0#point",
+		"type example:graph/types@0.1.0#r",
+		"function example:graph/types@0.1.0#[method]r.get",
+		"interface example:graph/api@0.1.0 (imported) (exported)",
+		"type example:graph/api@0.1.0#point",
+		"type example:graph/api@0.1.0#r",
+		"function example:graph/api@0.1.0#get",
+		"function example:graph/api@0.1.0#open",
+		"world example:graph/app@0.1.0",
+		"function example:graph/app@0.1.0 export run (exported)",
+	}
+	if got := nodeStrings(g); !slices.Equal(got, wantNodes) {
+		t.Errorf("Build: nodes:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(wantNodes, "\n"))
+	}
+	wantEdges := []string{
+		"example:graph@0.1.0 contains example:graph/types@0.1.0",
+		"example:graph/types@0.1.0 contains example:graph/types@0.1.0#point",
+		"example:graph/types@0.1.0 contains example:graph/types@0.1.0#r",
+		"example:graph/types@0.1.0 contains example:graph/types@0.1.0#[method]r.get",
+		"example:graph@0.1.0 contains example:graph/api@0.1.0",
+		"example:graph/api@0.1.0 contains example:graph/api@0.1.0#point",
+		"example:graph/api@0.1.0 contains example:graph/api@0.1.0#r",
+		"example:graph/api@0.1.0 contains example:graph/api@0.1.0#get",
+		"example:graph/api@0.1.0 contains example:graph/api@0.1.0#open",
+		"example:graph@0.1.0 contains example:graph/app@0.1.0",
+		"example:graph/app@0.1.0 imports example:graph/types@0.1.0",
+		"example:graph/app@0.1.0 imports example:graph/api@0.1.0",
+		"example:graph/app@0.1.0 exports example:graph/app@0.1.0 export run",
+		"example:graph/app@0.1.0 exports example:graph/api@0.1.0",
+		"example:graph/types@0.1.0#[method]r.get uses example:graph/types@0.1.0#r",
+		"example:graph/types@0.1.0#[method]r.get uses example:graph/types@0.1.0#point",
+		"example:graph/api@0.1.0#point uses example:graph/types@0.1.0#point",
+		"example:graph/api@0.1.0 uses example:graph/types@0.1.0",
+		"example:graph/api@0.1.0#r uses example:graph/types@0.1.0#r",
+		"example:graph/api@0.1.0#get uses example:graph/api@0.1.0#point",
+		"example:graph/api@0.1.0#open uses example:graph/api@0.1.0#r",
+	}
+	if got := edgeStrings(g); !slices.Equal(got, wantEdges) {
+		t.Errorf("Build: edges:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(wantEdges, "\n"))
+	}
+
+	g, err = Build(res, World("other"), Detail(InterfaceLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNodes = []string{
+		"package example:graph@0.1.0",
+		"interface example:graph/unused@0.1.0 (imported)",
+		"world example:graph/other@0.1.0",
+	}
+	if got := nodeStrings(g); !slices.Equal(got, wantNodes) {
+		t.Errorf("Build(World(%q)): nodes:\n%s\nexpected:\n%s", "other", strings.Join(got, "\n"), strings.Join(wantNodes, "\n"))
+	}
+
+	g, err = Build(res, Detail(TypeLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range g.Nodes {
+		if n.Kind == FunctionNode {
+			t.Errorf("Build(Detail(TypeLevel)): unexpected function node %s", n.ID)
+		}
+	}
+	if got := edgeStrings(g); !slices.Contains(got, "example:graph/api@0.1.0 uses example:graph/types@0.1.0") {
+		t.Errorf("Build(Detail(TypeLevel)): missing interface dependency:\n%s", strings.Join(got, "\n"))
+	}
+
+	if _, err := Build(res, World("missing")); err == nil {
+		t.Errorf("Build(World(%q)): nil error, expected error", "missing")
+	}
+}
+
+func TestEncode(t *testing.T) {
+	g, err := Build(loadGraphWIT(t), World("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Graph
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, g) {
+		t.Errorf("EncodeJSON: decoded %v, expected %v", decoded, g)
+	}
+
+	buf.Reset()
+	if err := EncodeDOT(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	wantDOT := `digraph wit {
+	rankdir=LR;
+	node [fontname="Helvetica"];
+	edge [fontname="Helvetica", fontsize=10];
+	"example:graph@0.1.0" [label="example:graph@0.1.0", shape=tab];
+	"example:graph/unused@0.1.0" [label="unused", shape=box, style=filled, fillcolor="#cfe2ff"];
+	"example:graph/unused@0.1.0#f" [label="f", shape=cds];
+	"example:graph/other@0.1.0" [label="other", shape=component];
+	"example:graph@0.1.0" -> "example:graph/unused@0.1.0" [style=dashed, arrowhead=none];
+	"example:graph/unused@0.1.0" -> "example:graph/unused@0.1.0#f" [style=dashed, arrowhead=none];
+	"example:graph@0.1.0" -> "example:graph/other@0.1.0" [style=dashed, arrowhead=none];
+	"example:graph/other@0.1.0" -> "example:graph/unused@0.1.0" [label="imports"];
+}
+`
+	if got := buf.String(); got != wantDOT {
+		t.Errorf("EncodeDOT:\n%s\nexpected:\n%s", got, wantDOT)
+	}
+
+	buf.Reset()
+	if err := EncodeMermaid(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	wantMermaid := `flowchart LR
+	n0[/"example:graph@0.1.0"/]
+	n1["unused"]
+	n2("f")
+	n3{{"other"}}
+	n0 -.- n1
+	n1 -.- n2
+	n0 -.- n3
+	n3 -->|imports| n1
+	classDef imported fill:#cfe2ff
+	class n1 imported
+`
+	if got := buf.String(); got != wantMermaid {
+		t.Errorf("EncodeMermaid:\n%s\nexpected:\n%s", got, wantMermaid)
+	}
+}
@@ -0,0 +1,37 @@
+package wit
+
+import (
+	"go.bytecodealliance.org/internal/witlex"
+)
+
+// position represents a line and column position in a WIT source file.
+type position = witlex.Pos
+
+// token represents a single lexical token in WIT source.
+type token = witlex.Token
+
+// isIdent returns true if t is an identifier that is not a reserved WIT keyword.
+func isIdent(t *token) bool {
+	return t.Kind == witlex.Ident && (t.Escaped || !isKeyword(t.Text))
+}
+
+// describe returns a description of token t for error messages.
+func describe(t *token) string {
+	if t.Kind == witlex.Ident {
+		if t.Escaped {
+			return "identifier `%" + t.Text + "`"
+		}
+		if isKeyword(t.Text) {
+			return "keyword `" + t.Text + "`"
+		}
+		return "identifier `" + t.Text + "`"
+	}
+	return t.String()
+}
+
+// isKeyword returns true if s is a reserved WIT keyword.
+// The wit keyword is escaped when printing WIT for compatibility with
+// older parsers, but is otherwise a valid identifier.
+func isKeyword(s string) bool {
+	return witKeywords[s] && s != "wit"
+}
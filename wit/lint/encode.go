@@ -0,0 +1,115 @@
+package lint
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+
+	"go.bytecodealliance.org/internal/module"
+)
+
+// EncodeJSON writes diags to w as a JSON array of [Diagnostic] objects.
+func EncodeJSON(w io.Writer, diags []Diagnostic) error {
+	if diags == nil {
+		diags = []Diagnostic{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(diags)
+}
+
+// EncodeSARIF writes diags to w as a [SARIF] 2.1.0 log with a single run,
+// describing each [Rule] and reporting the path of each [Diagnostic] as a logical location.
+//
+// [SARIF]: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+func EncodeSARIF(w io.Writer, diags []Diagnostic) error {
+	rules := Rules()
+	driver := sarifDriver{
+		Name:           "wit-bindgen-go",
+		Version:        module.Version(),
+		InformationURI: "https://pkg.go.dev/go.bytecodealliance.org/wit/lint",
+	}
+	for _, r := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   r.Name,
+			ShortDescription:     sarifMessage{Text: r.Doc},
+			DefaultConfiguration: sarifConfiguration{Level: r.Level},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, d := range diags {
+		results = append(results, sarifResult{
+			RuleID:    d.Rule,
+			RuleIndex: slices.IndexFunc(rules, func(r *Rule) bool { return r.Name == d.Rule }),
+			Level:     d.Level,
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: d.Path}},
+			}},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+		}},
+	})
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level Level `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     Level           `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
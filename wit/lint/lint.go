@@ -0,0 +1,252 @@
+// Package lint reports style and portability problems in WIT definitions.
+package lint
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"go.bytecodealliance.org/wit"
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// Level is the severity of a [Diagnostic]. The levels match those of SARIF results.
+type Level string
+
+const (
+	// Error is the level of a problem that should be fixed.
+	Error Level = "error"
+
+	// Warning is the level of a likely problem.
+	Warning Level = "warning"
+
+	// Note is the level of a stylistic or informational problem.
+	Note Level = "note"
+
+	// Off disables a [Rule].
+	Off Level = "off"
+)
+
+// ParseLevel parses s as a [Level].
+func ParseLevel(s string) (Level, error) {
+	switch l := Level(s); l {
+	case Error, Warning, Note, Off:
+		return l, nil
+	}
+	return "", fmt.Errorf("invalid lint level %q (expected error, warning, note, or off)", s)
+}
+
+// Diagnostic is a problem in a WIT definition reported by a [Rule].
+type Diagnostic struct {
+	// Rule is the name of the rule that reported the problem.
+	Rule string `json:"rule"`
+
+	// Level is the severity of the problem.
+	Level Level `json:"level"`
+
+	// Path identifies the item with the problem, such as "wasi:http/types@0.2.0",
+	// "wasi:http/types@0.2.0#[method]fields.get", or "wasi:cli/command@0.2.0 import run".
+	Path string `json:"path"`
+
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// String implements [fmt.Stringer], returning a string representation of d.
+func (d *Diagnostic) String() string {
+	return string(d.Level) + ": " + d.Path + ": " + d.Message + " (" + d.Rule + ")"
+}
+
+// Option represents a single configuration option for [Lint].
+type Option interface {
+	applyOption(*options)
+}
+
+type optionFunc func(*options)
+
+func (f optionFunc) applyOption(opts *options) {
+	f(opts)
+}
+
+type options struct {
+	levels   map[string]Level
+	packages []string
+}
+
+func (opts *options) apply(o ...Option) {
+	for _, o := range o {
+		o.applyOption(opts)
+	}
+}
+
+// Levels returns an [Option] that overrides the default [Level] of rules by name.
+// A rule with level [Off] is disabled.
+func Levels(levels map[string]Level) Option {
+	return optionFunc(func(opts *options) {
+		for name, level := range levels {
+			opts.levels[name] = level
+		}
+	})
+}
+
+// Packages returns an [Option] that limits linting to WIT packages matching
+// any of the glob patterns, such as "wasi:http" or "example:*@0.1.0".
+// A pattern without a version matches any version of a package.
+// By default, every package in a [wit.Resolve] is linted.
+func Packages(patterns ...string) Option {
+	return optionFunc(func(opts *options) {
+		opts.packages = append(opts.packages, patterns...)
+	})
+}
+
+// Lint checks the WIT definitions in res with each enabled [Rule], returning the
+// problems found, ordered by path. Types and functions in packages that are not
+// linted are still considered when checking whether a type is used.
+func Lint(res *wit.Resolve, opts ...Option) ([]Diagnostic, error) {
+	o := options{levels: make(map[string]Level)}
+	o.apply(opts...)
+
+	rules := Rules()
+	for name, level := range o.levels {
+		i := slices.IndexFunc(rules, func(r *Rule) bool { return r.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+		if _, err := ParseLevel(string(level)); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &linter{res: res, paths: make(map[wit.Node]string)}
+	for _, pattern := range o.packages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid package pattern %q: %w", pattern, err)
+		}
+	}
+	for _, pkg := range res.Packages {
+		if len(o.packages) == 0 || slices.ContainsFunc(o.packages, func(pattern string) bool { return matchPackage(pkg, pattern) }) {
+			l.add(pkg)
+		}
+	}
+	if len(o.packages) > 0 && len(l.packages) == 0 {
+		return nil, fmt.Errorf("no WIT packages match %s", strings.Join(o.packages, ", "))
+	}
+
+	for _, r := range rules {
+		level, ok := o.levels[r.Name]
+		if !ok {
+			level = r.Level
+		}
+		if level == Off {
+			continue
+		}
+		l.rule, l.level = r.Name, level
+		r.check(l)
+	}
+	slices.SortStableFunc(l.diags, func(a, b Diagnostic) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return l.diags, nil
+}
+
+func matchPackage(pkg *wit.Package, pattern string) bool {
+	name := pkg.Name.UnversionedString()
+	if strings.Contains(pattern, "@") {
+		name = pkg.Name.String()
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// linter holds the items of the linted packages and the diagnostics reported by each [Rule].
+type linter struct {
+	res        *wit.Resolve
+	packages   []*wit.Package
+	interfaces []*wit.Interface
+	worlds     []*wit.World
+	refs       []*wit.InterfaceRef
+	typeDefs   []*wit.TypeDef // named types
+	functions  []*wit.Function
+	paths      map[wit.Node]string
+
+	rule  string
+	level Level
+	diags []Diagnostic
+}
+
+// add adds the interfaces, worlds, types, and functions in pkg to l.
+func (l *linter) add(pkg *wit.Package) {
+	l.packages = append(l.packages, pkg)
+	pkg.Interfaces.All()(func(_ string, i *wit.Interface) bool {
+		id := pkg.Name
+		id.Extension = *i.Name
+		l.addInterface(i, id.String())
+		return true
+	})
+	pkg.Worlds.All()(func(_ string, w *wit.World) bool {
+		id := pkg.Name
+		id.Extension = w.Name
+		path := id.String()
+		l.worlds = append(l.worlds, w)
+		l.paths[w] = path
+		l.addWorldItems(path+" import ", &w.Imports)
+		l.addWorldItems(path+" export ", &w.Exports)
+		return true
+	})
+}
+
+func (l *linter) addWorldItems(prefix string, items *ordered.Map[string, wit.WorldItem]) {
+	items.All()(func(name string, item wit.WorldItem) bool {
+		switch item := item.(type) {
+		case *wit.InterfaceRef:
+			l.refs = append(l.refs, item)
+			l.paths[item] = prefix + name
+			// Named interfaces are linted with their package.
+			if item.Interface.Name == nil {
+				l.addInterface(item.Interface, prefix+name)
+			}
+		case *wit.TypeDef:
+			l.addTypeDef(item, prefix+name)
+		case *wit.Function:
+			l.addFunction(item, prefix+name)
+		}
+		return true
+	})
+}
+
+func (l *linter) addInterface(i *wit.Interface, path string) {
+	l.interfaces = append(l.interfaces, i)
+	l.paths[i] = path
+	i.TypeDefs.All()(func(name string, t *wit.TypeDef) bool {
+		l.addTypeDef(t, path+"#"+name)
+		return true
+	})
+	i.Functions.All()(func(name string, f *wit.Function) bool {
+		l.addFunction(f, path+"#"+name)
+		return true
+	})
+}
+
+func (l *linter) addTypeDef(t *wit.TypeDef, path string) {
+	if t.Name == nil {
+		return
+	}
+	l.typeDefs = append(l.typeDefs, t)
+	l.paths[t] = path
+}
+
+func (l *linter) addFunction(f *wit.Function, path string) {
+	l.functions = append(l.functions, f)
+	l.paths[f] = path
+}
+
+// report reports a problem with node for the current rule.
+func (l *linter) report(node wit.Node, format string, args ...any) {
+	l.diags = append(l.diags, Diagnostic{
+		Rule:    l.rule,
+		Level:   l.level,
+		Path:    l.paths[node],
+		Message: fmt.Sprintf(format, args...),
+	})
+}
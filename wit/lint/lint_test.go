@@ -0,0 +1,175 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+const lintWIT = `package example:lint@0.1.0;
+
+interface %type {
+	/// A point.
+	record point { x: s32, y: s32 }
+	record unused { a: u32 }
+	variant value { text(string), number(u32) }
+	record names { http-url: u32, http-u-r-l: u32 }
+
+	/// Returns a point.
+	get: func(%func: u32) -> point;
+	set: func(v: value, n: names);
+	/// Passes many values.
+	many: func(a: u64, b: u64, c: u64, d: u64, e: u64, f: u64, g: u64, h: u64, i: u64, j: u64, k: u64, l: u64, m: u64, n: u64, o: u64, p: u64, q: u64);
+	/// Passes a few values.
+	few: async func(a: u64, b: u64, c: u64, d: u64, e: u64);
+}
+
+world app {
+	import %type;
+	/// Runs the app.
+	export run: func();
+}
+`
+
+func loadLintWIT(t *testing.T) *wit.Resolve {
+	t.Helper()
+	res, err := wit.DecodeWIT(strings.NewReader(lintWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func diagStrings(diags []Diagnostic) []string {
+	var s []string
+	for _, d := range diags {
+		s = append(s, d.String())
+	}
+	return s
+}
+
+func TestLint(t *testing.T) {
+	res := loadLintWIT(t)
+	// The WIT parser rejects @unstable without a feature, but JSON may not.
+	res.Interfaces[0].Functions.Get("get").Stability = &wit.Unstable{}
+
+	diags, err := Lint(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"warning: example:lint/%type@0.1.0: interface type has Go package name type, which is a Go keyword (go-keyword)",
+		"warning: example:lint/%type@0.1.0#few: async function few has 5 flattened parameters, more than the limit of 4 for imported async functions, so its parameters are passed indirectly in a _params record when imported (flat-params)",
+		"warning: example:lint/%type@0.1.0#get: parameter func has Go name func, which is a Go keyword (go-keyword)",
+		"error: example:lint/%type@0.1.0#get: function is @unstable without a feature name (unstable-feature)",
+		"warning: example:lint/%type@0.1.0#many: function many has 17 flattened parameters, more than the limit of 16, so its parameters are passed indirectly in a _params record (flat-params)",
+		"error: example:lint/%type@0.1.0#names: http-url and http-u-r-l have the same Go name HTTPURL (go-name-collision)",
+		"note: example:lint/%type@0.1.0#set: function set has no doc comment (missing-docs)",
+		"warning: example:lint/%type@0.1.0#unused: record unused is not used by any function or type (unused-type)",
+		"note: example:lint/%type@0.1.0#value: variant value cannot store its cases in shared storage that is safe for the Go garbage collector, so the values of its cases are stored separately (variant-shape)",
+	}
+	if got := diagStrings(diags); !slices.Equal(got, want) {
+		t.Errorf("Lint:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLintOptions(t *testing.T) {
+	res := loadLintWIT(t)
+
+	diags, err := Lint(res, Levels(map[string]Level{
+		"flat-params":       Off,
+		"go-keyword":        Off,
+		"go-name-collision": Off,
+		"unused-type":       Off,
+		"variant-shape":     Off,
+		"missing-docs":      Error,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"error: example:lint/%type@0.1.0#set: function set has no doc comment (missing-docs)"}
+	if got := diagStrings(diags); !slices.Equal(got, want) {
+		t.Errorf("Lint:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	diags, err = Lint(res, Packages("example:other"))
+	if err == nil {
+		t.Errorf("Lint(Packages(%q)): %v, expected error", "example:other", diags)
+	}
+	for _, pattern := range []string{"example:*", "example:lint@0.1.0"} {
+		diags, err = Lint(res, Packages(pattern))
+		if err != nil || len(diags) == 0 {
+			t.Errorf("Lint(Packages(%q)): %d diagnostics, %v", pattern, len(diags), err)
+		}
+	}
+
+	for _, levels := range []map[string]Level{{"no-such-rule": Off}, {"missing-docs": "fatal"}} {
+		if _, err := Lint(res, Levels(levels)); err == nil {
+			t.Errorf("Lint(Levels(%v)): nil error, expected error", levels)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	diags, err := Lint(loadLintWIT(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, diags); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Diagnostic
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(decoded, diags) {
+		t.Errorf("EncodeJSON: decoded %v, expected %v", decoded, diags)
+	}
+
+	buf.Reset()
+	if err := EncodeSARIF(&buf, diags); err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Locations []struct {
+					LogicalLocations []struct {
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != len(diags) {
+		t.Fatalf("EncodeSARIF: unexpected log:\n%s", buf.String())
+	}
+	run := log.Runs[0]
+	for i, r := range run.Results {
+		d := diags[i]
+		if r.RuleID != d.Rule || run.Tool.Driver.Rules[r.RuleIndex].ID != d.Rule || r.Level != string(d.Level) ||
+			r.Locations[0].LogicalLocations[0].FullyQualifiedName != d.Path {
+			t.Errorf("EncodeSARIF: result %d does not match %s", i, d.String())
+		}
+	}
+}
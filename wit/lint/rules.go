@@ -0,0 +1,276 @@
+package lint
+
+import (
+	"go/token"
+	"strings"
+
+	"go.bytecodealliance.org/wit"
+	"go.bytecodealliance.org/wit/bindgen"
+)
+
+// Rule is a lint rule that checks WIT definitions for one kind of problem.
+type Rule struct {
+	// Name identifies the rule, such as "missing-docs".
+	Name string
+
+	// Doc describes the problems reported by the rule.
+	Doc string
+
+	// Level is the default level of problems reported by the rule.
+	Level Level
+
+	check func(*linter)
+}
+
+// Rules returns the lint rules, ordered by name.
+func Rules() []*Rule {
+	return []*Rule{
+		{
+			Name:  "flat-params",
+			Doc:   "functions with more flattened parameters than the Canonical ABI passes directly, which are passed indirectly in a _params record",
+			Level: Warning,
+			check: checkFlatParams,
+		},
+		{
+			Name:  "go-keyword",
+			Doc:   "names of interfaces, worlds, parameters, and results that are Go keywords in generated Go code",
+			Level: Warning,
+			check: checkGoKeywords,
+		},
+		{
+			Name:  "go-name-collision",
+			Doc:   "names in the same scope that collide after conversion to Go names",
+			Level: Error,
+			check: checkGoNameCollisions,
+		},
+		{
+			Name:  "missing-docs",
+			Doc:   "functions without a doc comment",
+			Level: Note,
+			check: checkMissingDocs,
+		},
+		{
+			Name:  "unstable-feature",
+			Doc:   "@unstable items without a feature name",
+			Level: Error,
+			check: checkUnstableFeatures,
+		},
+		{
+			Name:  "unused-type",
+			Doc:   "types that are not used by any function or other type",
+			Level: Warning,
+			check: checkUnusedTypes,
+		},
+		{
+			Name:  "variant-shape",
+			Doc:   "variants whose cases cannot share storage that is safe for the Go garbage collector, which are stored separately",
+			Level: Note,
+			check: checkVariantShapes,
+		},
+	}
+}
+
+func checkFlatParams(l *linter) {
+	for _, f := range l.functions {
+		var n int
+		for _, p := range f.Params {
+			n += len(p.Type.Flat())
+		}
+		switch {
+		case n > wit.MaxFlatParams:
+			l.report(f, "%s %s has %d flattened parameters, more than the limit of %d, so its parameters are passed indirectly in a _params record",
+				f.WITKind(), f.BaseName(), n, wit.MaxFlatParams)
+		case f.Async && n > wit.MaxFlatAsyncParams:
+			l.report(f, "async %s %s has %d flattened parameters, more than the limit of %d for imported async functions, so its parameters are passed indirectly in a _params record when imported",
+				f.WITKind(), f.BaseName(), n, wit.MaxFlatAsyncParams)
+		}
+	}
+}
+
+func checkGoKeywords(l *linter) {
+	for _, i := range l.interfaces {
+		if i.Name != nil {
+			if name := bindgen.GoPackageName(*i.Name); token.IsKeyword(name) {
+				l.report(i, "interface %s has Go package name %s, which is a Go keyword", *i.Name, name)
+			}
+		}
+	}
+	for _, w := range l.worlds {
+		if name := bindgen.GoPackageName(w.Name); token.IsKeyword(name) {
+			l.report(w, "world %s has Go package name %s, which is a Go keyword", w.Name, name)
+		}
+	}
+	for _, f := range l.functions {
+		for _, p := range f.Params {
+			if name := bindgen.GoName(p.Name, false); token.IsKeyword(name) {
+				l.report(f, "parameter %s has Go name %s, which is a Go keyword", p.Name, name)
+			}
+		}
+		for _, r := range f.Results {
+			if name := bindgen.GoName(r.Name, false); r.Name != "" && token.IsKeyword(name) {
+				l.report(f, "result %s has Go name %s, which is a Go keyword", r.Name, name)
+			}
+		}
+	}
+}
+
+// goNames reports WIT names that have the same Go name in a scope of node.
+type goNames struct {
+	l     *linter
+	node  wit.Node
+	names map[string]string // Go name → WIT name
+}
+
+func (l *linter) goNames(node wit.Node) *goNames {
+	return &goNames{l: l, node: node, names: make(map[string]string)}
+}
+
+func (g *goNames) add(witName, goName string) {
+	if prev, ok := g.names[goName]; ok {
+		g.l.report(g.node, "%s and %s have the same Go name %s", prev, witName, goName)
+		return
+	}
+	g.names[goName] = witName
+}
+
+// addFunction adds the Go name of a freestanding function, constructor, or static function
+// declared in the Go package of an interface or world.
+func (g *goNames) addFunction(f *wit.Function) {
+	switch f.Kind.(type) {
+	case *wit.Freestanding:
+		g.add(f.Name, bindgen.GoName(f.Name, true))
+	case *wit.Constructor:
+		g.add(f.Name, "New"+bindgen.GoName(f.Type().TypeName(), true))
+	case *wit.Static:
+		g.add(f.Name, bindgen.GoName(f.Type().TypeName(), true)+bindgen.GoName(f.BaseName(), true))
+	}
+}
+
+func checkGoNameCollisions(l *linter) {
+	for _, i := range l.interfaces {
+		names := l.goNames(i)
+		i.TypeDefs.All()(func(name string, _ *wit.TypeDef) bool {
+			names.add(name, bindgen.GoName(name, true))
+			return true
+		})
+		i.Functions.All()(func(_ string, f *wit.Function) bool {
+			names.addFunction(f)
+			return true
+		})
+	}
+
+	// Exported functions of a world are declared in a separate Exports scope.
+	for _, w := range l.worlds {
+		names := l.goNames(w)
+		w.Imports.All()(func(name string, item wit.WorldItem) bool {
+			switch item := item.(type) {
+			case *wit.TypeDef:
+				names.add(name, bindgen.GoName(name, true))
+			case *wit.Function:
+				names.addFunction(item)
+			}
+			return true
+		})
+	}
+
+	for _, t := range l.typeDefs {
+		names := l.goNames(t)
+		switch kind := t.Kind.(type) {
+		case *wit.Resource:
+			for _, f := range t.Methods() {
+				names.add(f.Name, bindgen.GoName(f.BaseName(), true))
+			}
+		case *wit.Record:
+			for _, f := range kind.Fields {
+				names.add(f.Name, bindgen.GoName(f.Name, true))
+			}
+		case *wit.Flags:
+			for _, f := range kind.Flags {
+				names.add(f.Name, bindgen.GoName(f.Name, true))
+			}
+		case *wit.Enum:
+			for _, c := range kind.Cases {
+				names.add(c.Name, bindgen.GoName(c.Name, true))
+			}
+		case *wit.Variant:
+			for _, c := range kind.Cases {
+				names.add(c.Name, bindgen.GoName(c.Name, true))
+			}
+		}
+	}
+}
+
+func checkMissingDocs(l *linter) {
+	for _, f := range l.functions {
+		if !f.IsAdmin() && strings.TrimSpace(f.Docs.Contents) == "" {
+			l.report(f, "%s %s has no doc comment", f.WITKind(), f.BaseName())
+		}
+	}
+}
+
+func checkUnstableFeatures(l *linter) {
+	check := func(node wit.Node, s wit.Stability) {
+		if u, ok := s.(*wit.Unstable); ok && u.Feature == "" {
+			l.report(node, "%s is @unstable without a feature name", node.WITKind())
+		}
+	}
+	for _, i := range l.interfaces {
+		check(i, i.Stability)
+	}
+	for _, w := range l.worlds {
+		check(w, w.Stability)
+	}
+	for _, ref := range l.refs {
+		check(ref, ref.Stability)
+	}
+	for _, t := range l.typeDefs {
+		check(t, t.Stability)
+	}
+	for _, f := range l.functions {
+		check(f, f.Stability)
+	}
+}
+
+func checkUnusedTypes(l *linter) {
+	// Types may be used by functions and types in any package.
+	var functions []*wit.Function
+	for _, i := range l.res.Interfaces {
+		i.Functions.All()(func(_ string, f *wit.Function) bool {
+			functions = append(functions, f)
+			return true
+		})
+	}
+	for _, w := range l.res.Worlds {
+		w.AllFunctions()(func(f *wit.Function) bool {
+			functions = append(functions, f)
+			return true
+		})
+	}
+
+	for _, t := range l.typeDefs {
+		used := false
+		for _, f := range functions {
+			if wit.DependsOn(f, t) {
+				used = true
+				break
+			}
+		}
+		for _, u := range l.res.TypeDefs {
+			if used {
+				break
+			}
+			used = u != t && wit.DependsOn(u.Kind, t)
+		}
+		if !used {
+			l.report(t, "%s %s is not used by any function or type", t.WITKind(), *t.Name)
+		}
+	}
+}
+
+func checkVariantShapes(l *linter) {
+	for _, t := range l.typeDefs {
+		if _, ok := t.Kind.(*wit.Variant); ok && bindgen.SplitStorage(t) {
+			l.report(t, "variant %s cannot store its cases in shared storage that is safe for the Go garbage collector, so the values of its cases are stored separately", *t.Name)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"testing/fstest"
 
 	"go.bytecodealliance.org/internal/wasmtools"
 )
@@ -25,29 +26,142 @@ func LoadJSON(path string) (*Resolve, error) {
 	return DecodeJSON(f)
 }
 
-// LoadWIT loads [WIT] data from path by processing it through [wasm-tools].
-// This will fail if wasm-tools is not in $PATH.
+// LoadWIT loads [WIT] data from path, which may be a WIT file, a directory
+// containing WIT files and an optional deps directory, or a WebAssembly binary.
+// WIT text is parsed natively. Binary-encoded WIT packages and components are
+// decoded by [wasm-tools], which is embedded in this module.
+// If path is "" or "-", WIT data is read from stdin.
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 // [wasm-tools]: https://crates.io/crates/wasm-tools
 func LoadWIT(path string) (*Resolve, error) {
-	return loadWIT(path, nil)
+	if path == "" || path == "-" {
+		return DecodeWIT(os.Stdin)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.IsDir() {
+		return loadWITDir(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isWasm(data) {
+		return loadWasm(path, nil)
+	}
+	f, err := parseFile(path, string(data))
+	if err != nil {
+		return nil, err
+	}
+	return resolveFiles([]*astFile{f}, nil)
 }
 
-// DecodeWIT decodes [WIT] data from Reader r by processing it through [wasm-tools].
-// This will fail if wasm-tools is not in $PATH.
+// DecodeWIT decodes [WIT] text or a binary-encoded WIT package from Reader r.
+// WIT text is parsed natively. Binary input is decoded by [wasm-tools].
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 // [wasm-tools]: https://crates.io/crates/wasm-tools
 func DecodeWIT(r io.Reader) (*Resolve, error) {
-	return loadWIT("", r)
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if isWasm(data) {
+		return loadWasm("", bytes.NewReader(data))
+	}
+	f, err := parseFile("", string(data))
+	if err != nil {
+		return nil, err
+	}
+	return resolveFiles([]*astFile{f}, nil)
+}
+
+// loadWITDir parses the WIT files in dir as a single package,
+// along with any dependencies in dir/deps.
+func loadWITDir(dir string) (*Resolve, error) {
+	main, err := parseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(main) == 0 {
+		return nil, fmt.Errorf("no WIT files found in directory %s", dir)
+	}
+
+	var deps [][]*astFile
+	depsDir := filepath.Join(dir, "deps")
+	entries, err := os.ReadDir(depsDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		path := filepath.Join(depsDir, e.Name())
+		fi, err := os.Stat(path) // follow symlinks
+		if err != nil {
+			return nil, err
+		}
+		var files []*astFile
+		switch {
+		case fi.IsDir():
+			files, err = parseDir(path)
+		case filepath.Ext(path) == ".wit":
+			var f *astFile
+			f, err = parseWITFile(path)
+			files = []*astFile{f}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(files) > 0 {
+			deps = append(deps, files)
+		}
+	}
+
+	return resolveFiles(main, deps)
+}
+
+// parseDir parses the WIT files in dir, sorted by name.
+// Subdirectories are not included.
+func parseDir(dir string) ([]*astFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*astFile
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".wit" {
+			continue
+		}
+		f, err := parseWITFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+func parseWITFile(path string) (*astFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseFile(path, string(data))
+}
+
+// isWasm returns true if data begins with the WebAssembly binary magic number.
+func isWasm(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("\x00asm"))
 }
 
-// loadWIT loads WIT data from path or reader by processing it through wasm-tools.
+// loadWasm loads a binary-encoded WIT package or component from path or reader
+// by processing it through wasm-tools.
 // It accepts either a path or an io.Reader as input, but not both.
-// If the path is not "" and "-", it will be used as the input file.
-// Otherwise, the reader will be used as the input.
-func loadWIT(path string, reader io.Reader) (*Resolve, error) {
+func loadWasm(path string, reader io.Reader) (*Resolve, error) {
 	if path != "" && reader != nil {
 		return nil, errors.New("cannot set both path and reader; provide only one")
 	}
@@ -55,21 +169,30 @@ func loadWIT(path string, reader io.Reader) (*Resolve, error) {
 	ctx := context.Background()
 	args := []string{"component", "wit", "-j", "--all-features"}
 	fsMap := make(map[string]fs.FS)
-	var stdin io.Reader
 
 	if path != "" {
 		args = append(args, path)
 		dir := filepath.Dir(path)
 		fsMap[dir] = os.DirFS(dir)
 	} else {
-		stdin = reader
+		// Mount the input as a file rather than passing it on stdin,
+		// which faults in wazero for larger inputs.
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		filename := "component.wasm"
+		args = append(args, filename)
+		fsMap[""] = fstest.MapFS{
+			filename: &fstest.MapFile{Data: data},
+		}
 	}
 	wasmTools, err := wasmtools.New(ctx)
 	if err != nil {
 		return nil, err
 	}
 	stdout := &bytes.Buffer{}
-	err = wasmTools.Run(ctx, stdin, stdout, nil, fsMap, args...)
+	err = wasmTools.Run(ctx, nil, stdout, nil, fsMap, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error executing wasm-tools: %w", err)
 	}
@@ -0,0 +1,166 @@
+package wit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeWITErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no package", "interface foo {}", "<input>:1:1: no `package` header"},
+		{"unexpected token", "package a:b;\ninterface foo {\n\tx: func() ->;\n}", "<input>:3:14: expected type, found `;`"},
+		{"keyword", "package a:b;\ninterface type {}", "<input>:2:11: expected identifier, found keyword `type`"},
+		{"undefined type", "package a:b;\ninterface foo {\n\tx: func(a: bar);\n}", "<input>:3:13: type `bar` does not exist"},
+		{"duplicate type", "package a:b;\ninterface foo {\n\ttype a = u8;\n\ttype a = u16;\n}", "<input>:4:2: type `a` is defined more than once"},
+		{"type cycle", "package a:b;\ninterface foo {\n\ttype a = list<b>;\n\ttype b = list<a>;\n}", "type `a` depends on itself"},
+		{"missing interface", "package a:b;\nworld w {\n\timport foo;\n}", "<input>:3:9: interface or world `foo` not found in package"},
+		{"missing package", "package a:b;\nworld w {\n\timport c:d/foo;\n}", "<input>:3:9: package `c:d` not found"},
+		{"unterminated comment", "package a:b;\n/* foo", "<input>:2:1: unterminated block comment"},
+		{"include with semicolon", "package a:b;\nworld a {}\nworld b {\n\tinclude a with { x as y };\n}", "<input>:4:27: expected type declaration, function, or use statement, found `;`"},
+		{"handle to non-resource", "package a:b;\ninterface foo {\n\ttype a = u8;\n\tx: func(a: borrow<a>);\n}", "<input>:4:20: type `a` used in a handle must be a resource"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeWIT(strings.NewReader(tt.src))
+			if err == nil {
+				t.Fatalf("DecodeWIT: expected error containing %q", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("DecodeWIT: got error %q, expected %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadWITDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.wit": `package example:app;
+
+world app {
+	import wasi:clocks/wall-clock@0.2.0;
+	export run: func(now: datetime);
+	use wasi:clocks/wall-clock@0.2.0.{datetime};
+}
+`,
+		"b.wit": `package example:app;
+
+interface util {
+	use wasi:clocks/wall-clock@0.2.0.{datetime};
+	elapsed: func(a: datetime, b: datetime) -> u64;
+}
+`,
+		"deps/clocks/wall-clock.wit": `package wasi:clocks@0.2.0;
+
+interface wall-clock {
+	record datetime {
+		seconds: u64,
+		nanoseconds: u32,
+	}
+	now: func() -> datetime;
+}
+`,
+		"deps/clocks/README.md": "not a WIT file",
+	}
+	writeFiles(t, dir, files)
+
+	res, err := LoadWIT(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(res.Packages), 2; got != want {
+		t.Fatalf("len(res.Packages): got %d, expected %d", got, want)
+	}
+	if got, want := res.Packages[0].Name.String(), "wasi:clocks@0.2.0"; got != want {
+		t.Errorf("res.Packages[0]: got %s, expected %s", got, want)
+	}
+	pkg := res.Packages[1]
+	if got, want := pkg.Name.String(), "example:app"; got != want {
+		t.Errorf("res.Packages[1]: got %s, expected %s", got, want)
+	}
+	if pkg.Interfaces.Get("util") == nil {
+		t.Errorf("interface util not found in package %s", pkg.Name.String())
+	}
+	w := pkg.Worlds.Get("app")
+	if w == nil {
+		t.Fatalf("world app not found in package %s", pkg.Name.String())
+	}
+	if _, ok := w.Imports.GetOK("datetime"); !ok {
+		t.Errorf("world %s: type datetime not imported", w.Name)
+	}
+	if _, ok := w.Exports.GetOK("run"); !ok {
+		t.Errorf("world %s: function run not exported", w.Name)
+	}
+}
+
+func TestForeignPackages(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.wit": `package example:app;
+
+use wasi:io/streams@0.2.0 as streams;
+
+world app {
+	import wasi:clocks/wall-clock@0.2.0;
+	export example:lib/run;
+	include wasi:cli/imports@0.2.0;
+	import inline: interface {
+		use wasi:random/random@0.2.0.{get-random-u64};
+	}
+}
+
+package example:lib {
+	interface run {
+		use wasi:io/streams@0.2.0.{output-stream};
+		run: func(out: output-stream);
+	}
+}
+`,
+		"b.wit": `package example:app;
+
+interface util {
+	use wasi:clocks/monotonic-clock.{instant};
+}
+`,
+		"deps/io/streams.wit": `package wasi:io@0.2.0;
+
+interface streams {
+	use wasi:poll/poll.{pollable};
+}
+`,
+	}
+	writeFiles(t, dir, files)
+
+	ids, err := ForeignPackages(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, id := range ids {
+		got = append(got, id.String())
+	}
+	want := []string{"wasi:cli@0.2.0", "wasi:clocks", "wasi:clocks@0.2.0", "wasi:io@0.2.0", "wasi:random@0.2.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ForeignPackages: got %v, expected %v", got, want)
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,1031 @@
+package wit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+
+	"go.bytecodealliance.org/internal/witlex"
+)
+
+// astFile represents a single parsed WIT source file.
+// A file may declare a top-level package and zero or more nested packages.
+type astFile struct {
+	path     string
+	pkg      *astPackage   // top-level package, which may be unnamed
+	packages []*astPackage // nested packages, in source order
+}
+
+// astPackage represents the contents of a WIT package within a single file.
+type astPackage struct {
+	name   *Ident // may be nil for the top-level package of a file without a package declaration
+	docs   Docs
+	pos    position
+	uses   []*astTopUse
+	ifaces []*astInterface
+	worlds []*astWorld
+	file   *astFile
+}
+
+// astTopUse is a top-level use statement, e.g. use wasi:io/streams@0.2.0 as streams;
+type astTopUse struct {
+	path astUsePath
+	as   string
+	pos  position
+}
+
+// astUsePath refers to an interface or world, either local to a package or fully qualified.
+type astUsePath struct {
+	pkg  *Ident // nil for local paths
+	name string
+	pos  position
+}
+
+func (p *astUsePath) String() string {
+	if p.pkg == nil {
+		return p.name
+	}
+	id := *p.pkg
+	id.Extension = p.name
+	return id.String()
+}
+
+// astAttrs contains the @since, @unstable, and @deprecated attributes on an item.
+type astAttrs struct {
+	stability Stability
+}
+
+type astInterface struct {
+	name  string
+	docs  Docs
+	attrs astAttrs
+	items []any // *astUse, *astTypeDecl, or *astFunc
+	pos   position
+}
+
+type astWorld struct {
+	name  string
+	docs  Docs
+	attrs astAttrs
+	items []any // *astUse, *astTypeDecl, *astExtern, or *astInclude
+	pos   position
+}
+
+// astUse is a use statement inside an interface or world.
+type astUse struct {
+	path  astUsePath
+	names []astUseName
+	attrs astAttrs
+	pos   position
+}
+
+type astUseName struct {
+	name string
+	as   string
+	pos  position
+}
+
+// astTypeDecl is a named type declaration.
+type astTypeDecl struct {
+	kind   string // type, record, flags, variant, enum, or resource
+	name   string
+	docs   Docs
+	attrs  astAttrs
+	pos    position
+	alias  *astType   // type
+	fields []astField // record, flags, variant, or enum
+	funcs  []*astFunc // resource
+}
+
+// astField is a record field, variant case, enum case, or flag.
+type astField struct {
+	name string
+	typ  *astType // may be nil
+	docs Docs
+	pos  position
+}
+
+// astFunc is a function declaration.
+type astFunc struct {
+	kind    string // freestanding, constructor, method, or static
+	async   bool
+	name    string
+	docs    Docs
+	attrs   astAttrs
+	params  []astParam
+	results []astParam // a single unnamed result, or zero or more named results
+	pos     position
+}
+
+type astParam struct {
+	name string
+	typ  *astType
+	pos  position
+}
+
+// astExtern is an import or export in a world.
+type astExtern struct {
+	export bool
+	name   string        // for named functions or inline interfaces
+	path   *astUsePath   // for interface references
+	fn     *astFunc      // for functions
+	iface  *astInterface // for inline interfaces
+	docs   Docs
+	attrs  astAttrs
+	pos    position
+}
+
+// astInclude is an include statement in a world.
+type astInclude struct {
+	path  astUsePath
+	with  map[string]string
+	attrs astAttrs
+	pos   position
+}
+
+// astType is a type expression.
+type astType struct {
+	kind string // primitive name, name, list, option, result, tuple, future, stream, own, borrow, or error-context
+	name string // for named types
+	args []*astType
+	pos  position
+}
+
+// parser parses WIT source into an [astFile].
+type parser struct {
+	lex *witlex.Lexer
+	tok token
+}
+
+// parseFile parses WIT source text from a file at path.
+func parseFile(path, src string) (*astFile, error) {
+	p := &parser{lex: witlex.New(path, src)}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	return p.parseFile()
+}
+
+func (p *parser) next() error {
+	var err error
+	p.tok, err = p.lex.Next()
+	if err != nil {
+		return err
+	}
+	if p.tok.Kind == witlex.Ident {
+		if err := validateName(p.tok.Text); err != nil {
+			return p.errorf(p.tok.Pos, "invalid identifier %q: %v", p.tok.Text, err)
+		}
+	}
+	return nil
+}
+
+func (p *parser) errorf(pos position, format string, args ...any) error {
+	return fmt.Errorf("%s: %s", pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) unexpected(want string) error {
+	return p.errorf(p.tok.Pos, "expected %s, found %s", want, describe(&p.tok))
+}
+
+// expect consumes the current token if it matches s, otherwise it returns an error.
+func (p *parser) expect(s string) error {
+	if !p.tok.Is(s) {
+		return p.unexpected("`" + s + "`")
+	}
+	return p.next()
+}
+
+// accept consumes the current token and returns true if it matches s.
+func (p *parser) accept(s string) (bool, error) {
+	if !p.tok.Is(s) {
+		return false, nil
+	}
+	return true, p.next()
+}
+
+// ident consumes and returns an identifier.
+func (p *parser) ident() (string, position, error) {
+	if !isIdent(&p.tok) {
+		return "", p.tok.Pos, p.unexpected("identifier")
+	}
+	name, pos := p.tok.Text, p.tok.Pos
+	return name, pos, p.next()
+}
+
+// version parses a version following the current @ or = token.
+func (p *parser) version() (*semver.Version, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.tok.Kind != witlex.Version {
+		return nil, p.unexpected("version")
+	}
+	v, err := semver.NewVersion(p.tok.Text)
+	if err != nil {
+		return nil, p.errorf(p.tok.Pos, "invalid version %q: %v", p.tok.Text, err)
+	}
+	return v, p.next()
+}
+
+// docs returns the documentation attached to the current token.
+// Leading slashes and trailing whitespace are trimmed from each comment,
+// then the common leading whitespace is removed from all lines.
+func (p *parser) docs() Docs {
+	if len(p.tok.Comments) == 0 {
+		return Docs{}
+	}
+	lines := make([]string, len(p.tok.Comments))
+	indent := -1
+	for i, c := range p.tok.Comments {
+		doc := c.Text
+		if d, ok := strings.CutPrefix(doc, "/**"); ok {
+			doc = strings.TrimSuffix(d, "*/")
+		} else {
+			doc = strings.TrimLeft(doc, "/")
+		}
+		doc = strings.TrimRight(doc, " \t\r\n")
+		lines[i] = doc
+		if doc == "" {
+			continue
+		}
+		n := len(doc) - len(strings.TrimLeft(doc, " \t\r\n"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		prefix := strings.Repeat(" ", indent)
+		for i := range lines {
+			lines[i] = strings.TrimPrefix(lines[i], prefix)
+		}
+	}
+	return Docs{Contents: strings.Join(lines, "\n")}
+}
+
+func (p *parser) parseFile() (*astFile, error) {
+	f := &astFile{path: p.lex.File()}
+	f.pkg = &astPackage{file: f, pos: p.tok.Pos}
+
+	// Optional top-level package declaration
+	docs := p.docs()
+	if p.tok.Is("package") {
+		pos := p.tok.Pos
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		name, err := p.packageName()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.Is("{") {
+			// File contains only nested packages
+			pkg, err := p.nestedPackage(f, name, docs, pos)
+			if err != nil {
+				return nil, err
+			}
+			f.packages = append(f.packages, pkg)
+		} else {
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+			f.pkg.name = &name
+			f.pkg.docs = docs
+			f.pkg.pos = pos
+		}
+	}
+
+	for p.tok.Kind != witlex.EOF {
+		docs := p.docs()
+		pos := p.tok.Pos
+		if p.tok.Is("package") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			name, err := p.packageName()
+			if err != nil {
+				return nil, err
+			}
+			pkg, err := p.nestedPackage(f, name, docs, pos)
+			if err != nil {
+				return nil, err
+			}
+			f.packages = append(f.packages, pkg)
+			continue
+		}
+		if err := p.packageItem(f.pkg); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) nestedPackage(f *astFile, name Ident, docs Docs, pos position) (*astPackage, error) {
+	pkg := &astPackage{name: &name, docs: docs, pos: pos, file: f}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.tok.Is("}") {
+		if p.tok.Kind == witlex.EOF {
+			return nil, p.unexpected("`}`")
+		}
+		if err := p.packageItem(pkg); err != nil {
+			return nil, err
+		}
+	}
+	return pkg, p.next()
+}
+
+// packageName parses a package name in the form namespace:name@version.
+func (p *parser) packageName() (Ident, error) {
+	var id Ident
+	var err error
+	id.Namespace, _, err = p.ident()
+	if err != nil {
+		return id, err
+	}
+	if err := p.expect(":"); err != nil {
+		return id, err
+	}
+	id.Package, _, err = p.ident()
+	if err != nil {
+		return id, err
+	}
+	if p.tok.Is("@") {
+		id.Version, err = p.version()
+	}
+	return id, err
+}
+
+// packageItem parses a top-level interface, world, or use statement.
+func (p *parser) packageItem(pkg *astPackage) error {
+	docs := p.docs()
+	attrs, err := p.attrs()
+	if err != nil {
+		return err
+	}
+	switch {
+	case p.tok.Is("interface"):
+		i, err := p.interfaceDecl(docs, attrs)
+		if err != nil {
+			return err
+		}
+		pkg.ifaces = append(pkg.ifaces, i)
+	case p.tok.Is("world"):
+		w, err := p.worldDecl(docs, attrs)
+		if err != nil {
+			return err
+		}
+		pkg.worlds = append(pkg.worlds, w)
+	case p.tok.Is("use"):
+		u := &astTopUse{pos: p.tok.Pos}
+		if err := p.next(); err != nil {
+			return err
+		}
+		u.path, err = p.usePath()
+		if err != nil {
+			return err
+		}
+		u.as = u.path.name
+		if ok, err := p.accept("as"); err != nil {
+			return err
+		} else if ok {
+			u.as, _, err = p.ident()
+			if err != nil {
+				return err
+			}
+		}
+		if err := p.expect(";"); err != nil {
+			return err
+		}
+		pkg.uses = append(pkg.uses, u)
+	default:
+		return p.unexpected("`interface`, `world`, `use`, or `package`")
+	}
+	return nil
+}
+
+// attrs parses zero or more @since, @unstable, or @deprecated attributes.
+func (p *parser) attrs() (astAttrs, error) {
+	var attrs astAttrs
+	var deprecated *semver.Version
+	for p.tok.Is("@") {
+		if err := p.next(); err != nil {
+			return attrs, err
+		}
+		name, pos, err := p.ident()
+		if err != nil {
+			return attrs, err
+		}
+		if err := p.expect("("); err != nil {
+			return attrs, err
+		}
+		args := make(map[string]any)
+		for !p.tok.Is(")") {
+			key, _, err := p.ident()
+			if err != nil {
+				return attrs, err
+			}
+			if !p.tok.Is("=") {
+				return attrs, p.unexpected("`=`")
+			}
+			switch key {
+			case "version":
+				args[key], err = p.version()
+			default:
+				if err = p.next(); err == nil {
+					args[key], _, err = p.ident()
+				}
+			}
+			if err != nil {
+				return attrs, err
+			}
+			if !p.tok.Is(")") {
+				if err := p.expect(","); err != nil {
+					return attrs, err
+				}
+			}
+		}
+		if err := p.next(); err != nil {
+			return attrs, err
+		}
+		switch name {
+		case "since":
+			v, ok := args["version"].(*semver.Version)
+			if !ok {
+				return attrs, p.errorf(pos, "@since requires a version")
+			}
+			attrs.stability = &Stable{Since: *v}
+		case "unstable":
+			f, ok := args["feature"].(string)
+			if !ok {
+				return attrs, p.errorf(pos, "@unstable requires a feature")
+			}
+			attrs.stability = &Unstable{Feature: f}
+		case "deprecated":
+			v, ok := args["version"].(*semver.Version)
+			if !ok {
+				return attrs, p.errorf(pos, "@deprecated requires a version")
+			}
+			deprecated = v
+		default:
+			return attrs, p.errorf(pos, "unknown attribute @%s", name)
+		}
+	}
+	if deprecated != nil {
+		switch s := attrs.stability.(type) {
+		case *Stable:
+			s.Deprecated = deprecated
+		case *Unstable:
+			s.Deprecated = deprecated
+		}
+	}
+	return attrs, nil
+}
+
+// usePath parses a local or fully-qualified path to an interface or world.
+func (p *parser) usePath() (astUsePath, error) {
+	var path astUsePath
+	path.pos = p.tok.Pos
+	name, _, err := p.ident()
+	if err != nil {
+		return path, err
+	}
+	if !p.tok.Is(":") {
+		path.name = name
+		return path, nil
+	}
+	if err := p.next(); err != nil {
+		return path, err
+	}
+	id := Ident{Namespace: name}
+	id.Package, _, err = p.ident()
+	if err != nil {
+		return path, err
+	}
+	if err := p.expect("/"); err != nil {
+		return path, err
+	}
+	path.name, _, err = p.ident()
+	if err != nil {
+		return path, err
+	}
+	if p.tok.Is("@") {
+		id.Version, err = p.version()
+		if err != nil {
+			return path, err
+		}
+	}
+	path.pkg = &id
+	return path, nil
+}
+
+func (p *parser) interfaceDecl(docs Docs, attrs astAttrs) (*astInterface, error) {
+	i := &astInterface{docs: docs, attrs: attrs, pos: p.tok.Pos}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var err error
+	i.name, _, err = p.ident()
+	if err != nil {
+		return nil, err
+	}
+	return i, p.interfaceBody(i)
+}
+
+func (p *parser) interfaceBody(i *astInterface) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.tok.Is("}") {
+		docs := p.docs()
+		attrs, err := p.attrs()
+		if err != nil {
+			return err
+		}
+		var item any
+		switch {
+		case p.tok.Is("use"):
+			item, err = p.useStmt(attrs)
+		case isIdent(&p.tok):
+			item, err = p.namedFunc(docs, attrs)
+		default:
+			item, err = p.typeDecl(docs, attrs)
+		}
+		if err != nil {
+			return err
+		}
+		i.items = append(i.items, item)
+	}
+	return p.next()
+}
+
+func (p *parser) worldDecl(docs Docs, attrs astAttrs) (*astWorld, error) {
+	w := &astWorld{docs: docs, attrs: attrs, pos: p.tok.Pos}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var err error
+	w.name, _, err = p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.tok.Is("}") {
+		docs := p.docs()
+		attrs, err := p.attrs()
+		if err != nil {
+			return nil, err
+		}
+		var item any
+		switch {
+		case p.tok.Is("use"):
+			item, err = p.useStmt(attrs)
+		case p.tok.Is("import"), p.tok.Is("export"):
+			item, err = p.extern(docs, attrs)
+		case p.tok.Is("include"):
+			item, err = p.include(attrs)
+		default:
+			item, err = p.typeDecl(docs, attrs)
+		}
+		if err != nil {
+			return nil, err
+		}
+		w.items = append(w.items, item)
+	}
+	return w, p.next()
+}
+
+// useStmt parses a use statement within an interface or world, e.g. use types.{a, b as c};
+func (p *parser) useStmt(attrs astAttrs) (*astUse, error) {
+	u := &astUse{attrs: attrs, pos: p.tok.Pos}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var err error
+	u.path, err = p.usePath()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("."); err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.tok.Is("}") {
+		var n astUseName
+		n.name, n.pos, err = p.ident()
+		if err != nil {
+			return nil, err
+		}
+		n.as = n.name
+		if ok, err := p.accept("as"); err != nil {
+			return nil, err
+		} else if ok {
+			n.as, _, err = p.ident()
+			if err != nil {
+				return nil, err
+			}
+		}
+		u.names = append(u.names, n)
+		if !p.tok.Is("}") {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	return u, p.expect(";")
+}
+
+func (p *parser) extern(docs Docs, attrs astAttrs) (*astExtern, error) {
+	e := &astExtern{export: p.tok.Is("export"), docs: docs, attrs: attrs, pos: p.tok.Pos}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	pos := p.tok.Pos
+	name, _, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if !p.tok.Is(":") {
+		// Local interface reference
+		e.path = &astUsePath{name: name, pos: pos}
+		return e, p.expect(";")
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.tok.Is("func"), p.tok.Is("async"):
+		e.name = name
+		e.fn = &astFunc{kind: "freestanding", name: name, docs: docs, attrs: attrs, pos: pos}
+		if err := p.funcType(e.fn); err != nil {
+			return nil, err
+		}
+		return e, p.expect(";")
+	case p.tok.Is("interface"):
+		e.name = name
+		e.iface = &astInterface{docs: docs, attrs: attrs, pos: pos}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		return e, p.interfaceBody(e.iface)
+	}
+	// Fully-qualified interface reference
+	id := Ident{Namespace: name}
+	id.Package, _, err = p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("/"); err != nil {
+		return nil, err
+	}
+	e.path = &astUsePath{pkg: &id, pos: pos}
+	e.path.name, _, err = p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.Is("@") {
+		id.Version, err = p.version()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e, p.expect(";")
+}
+
+func (p *parser) include(attrs astAttrs) (*astInclude, error) {
+	inc := &astInclude{attrs: attrs, pos: p.tok.Pos}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var err error
+	inc.path, err = p.usePath()
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := p.accept("with"); err != nil {
+		return nil, err
+	} else if ok {
+		inc.with = make(map[string]string)
+		if err := p.expect("{"); err != nil {
+			return nil, err
+		}
+		for !p.tok.Is("}") {
+			from, _, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("as"); err != nil {
+				return nil, err
+			}
+			to, _, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			inc.with[from] = to
+			if !p.tok.Is("}") {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+		// The with form is not terminated by a semicolon.
+		return inc, p.next()
+	}
+	return inc, p.expect(";")
+}
+
+// namedFunc parses a function declaration in the form name: func(...) -> ...;
+func (p *parser) namedFunc(docs Docs, attrs astAttrs) (*astFunc, error) {
+	f := &astFunc{kind: "freestanding", docs: docs, attrs: attrs, pos: p.tok.Pos}
+	var err error
+	f.name, _, err = p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	if err := p.funcType(f); err != nil {
+		return nil, err
+	}
+	return f, p.expect(";")
+}
+
+// funcType parses [async] func(params) [-> results].
+func (p *parser) funcType(f *astFunc) error {
+	var err error
+	f.async, err = p.accept("async")
+	if err != nil {
+		return err
+	}
+	if err := p.expect("func"); err != nil {
+		return err
+	}
+	f.params, err = p.params()
+	if err != nil {
+		return err
+	}
+	return p.results(f)
+}
+
+func (p *parser) params() ([]astParam, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var params []astParam
+	for !p.tok.Is(")") {
+		var param astParam
+		var err error
+		param.name, param.pos, err = p.ident()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		param.typ, err = p.typ()
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+		if !p.tok.Is(")") {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return params, p.next()
+}
+
+func (p *parser) results(f *astFunc) error {
+	if ok, err := p.accept("->"); err != nil || !ok {
+		return err
+	}
+	if p.tok.Is("(") {
+		var err error
+		f.results, err = p.params()
+		return err
+	}
+	pos := p.tok.Pos
+	t, err := p.typ()
+	if err != nil {
+		return err
+	}
+	f.results = []astParam{{typ: t, pos: pos}}
+	return nil
+}
+
+// typeDecl parses a named type declaration.
+func (p *parser) typeDecl(docs Docs, attrs astAttrs) (*astTypeDecl, error) {
+	d := &astTypeDecl{docs: docs, attrs: attrs, pos: p.tok.Pos}
+	switch {
+	case p.tok.Is("type"), p.tok.Is("record"), p.tok.Is("flags"), p.tok.Is("variant"), p.tok.Is("enum"), p.tok.Is("resource"):
+		d.kind = p.tok.Text
+	default:
+		return nil, p.unexpected("type declaration, function, or use statement")
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var err error
+	d.name, _, err = p.ident()
+	if err != nil {
+		return nil, err
+	}
+	switch d.kind {
+	case "type":
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		d.alias, err = p.typ()
+		if err != nil {
+			return nil, err
+		}
+		return d, p.expect(";")
+	case "resource":
+		if ok, err := p.accept(";"); err != nil || ok {
+			return d, err
+		}
+		return d, p.resourceBody(d)
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.tok.Is("}") {
+		f := astField{docs: p.docs()}
+		f.name, f.pos, err = p.ident()
+		if err != nil {
+			return nil, err
+		}
+		switch d.kind {
+		case "record":
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			f.typ, err = p.typ()
+		case "variant":
+			if ok, err2 := p.accept("("); err2 != nil {
+				return nil, err2
+			} else if ok {
+				f.typ, err = p.typ()
+				if err == nil {
+					err = p.expect(")")
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.fields = append(d.fields, f)
+		if !p.tok.Is("}") {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return d, p.next()
+}
+
+func (p *parser) resourceBody(d *astTypeDecl) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for !p.tok.Is("}") {
+		docs := p.docs()
+		attrs, err := p.attrs()
+		if err != nil {
+			return err
+		}
+		if p.tok.Is("constructor") {
+			f := &astFunc{kind: "constructor", name: "constructor", docs: docs, attrs: attrs, pos: p.tok.Pos}
+			if err := p.next(); err != nil {
+				return err
+			}
+			f.params, err = p.params()
+			if err != nil {
+				return err
+			}
+			if err := p.results(f); err != nil {
+				return err
+			}
+			if err := p.expect(";"); err != nil {
+				return err
+			}
+			d.funcs = append(d.funcs, f)
+			continue
+		}
+		f := &astFunc{kind: "method", docs: docs, attrs: attrs, pos: p.tok.Pos}
+		f.name, _, err = p.ident()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		if ok, err := p.accept("static"); err != nil {
+			return err
+		} else if ok {
+			f.kind = "static"
+		}
+		if err := p.funcType(f); err != nil {
+			return err
+		}
+		if err := p.expect(";"); err != nil {
+			return err
+		}
+		d.funcs = append(d.funcs, f)
+	}
+	return p.next()
+}
+
+// typ parses a type expression.
+func (p *parser) typ() (*astType, error) {
+	t := &astType{pos: p.tok.Pos}
+	if p.tok.Kind != witlex.Ident {
+		return nil, p.unexpected("type")
+	}
+	if p.tok.Escaped || !isKeyword(p.tok.Text) {
+		t.kind = "name"
+		t.name = p.tok.Text
+		return t, p.next()
+	}
+	t.kind = p.tok.Text
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	switch t.kind {
+	case "bool", "s8", "u8", "s16", "u16", "s32", "u32", "s64", "u64", "f32", "f64", "char", "string", "error-context":
+		return t, nil
+	case "list", "option", "stream", "future", "own", "borrow", "tuple", "result":
+	default:
+		return nil, p.errorf(t.pos, "expected type, found keyword `%s`", t.kind)
+	}
+	if !p.tok.Is("<") {
+		switch t.kind {
+		case "result", "future", "stream":
+			return t, nil // result, future, and stream can omit type parameters
+		}
+		return nil, p.unexpected("`<`")
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	switch t.kind {
+	case "own", "borrow":
+		name, pos, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		t.args = []*astType{{kind: "name", name: name, pos: pos}}
+	case "result":
+		if ok, err := p.accept("_"); err != nil {
+			return nil, err
+		} else if ok {
+			t.args = append(t.args, nil)
+		} else {
+			a, err := p.typ()
+			if err != nil {
+				return nil, err
+			}
+			t.args = append(t.args, a)
+		}
+		if ok, err := p.accept(","); err != nil {
+			return nil, err
+		} else if ok {
+			a, err := p.typ()
+			if err != nil {
+				return nil, err
+			}
+			t.args = append(t.args, a)
+		}
+	case "tuple":
+		for !p.tok.Is(">") {
+			a, err := p.typ()
+			if err != nil {
+				return nil, err
+			}
+			t.args = append(t.args, a)
+			if !p.tok.Is(">") {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	default:
+		a, err := p.typ()
+		if err != nil {
+			return nil, err
+		}
+		t.args = []*astType{a}
+	}
+	return t, p.expect(">")
+}
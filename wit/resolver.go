@@ -0,0 +1,1232 @@
+package wit
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// resolver resolves one or more parsed WIT files into a [Resolve].
+// It mirrors the resolution process in the [wit-parser] crate, which
+// topologically sorts packages, interfaces, worlds, and types, and
+// elaborates worlds with the transitive interface dependencies of their
+// imports and exports.
+//
+// [wit-parser]: https://docs.rs/wit-parser/latest/wit_parser/
+type resolver struct {
+	res      *Resolve
+	sources  []*sourcePackage
+	resolved map[*sourcePackage]*Package
+	ifaces   map[*Interface]int    // index of each Interface in res.Interfaces
+	anon     map[string]*TypeDef   // anonymous types in the current package
+	owns     map[*TypeDef]*TypeDef // own handles in the current package, keyed by resource
+}
+
+// sourcePackage is a WIT package that may be declared across multiple files.
+type sourcePackage struct {
+	name  Ident
+	docs  Docs
+	parts []*astPackage
+}
+
+// scope is a namespace for resolving names within a package, interface, or world.
+type scope struct {
+	pkg   *sourcePackage
+	part  *astPackage
+	owner TypeOwner
+	types map[string]*TypeDef
+}
+
+// resolveFiles resolves a main package, declared in one or more files,
+// along with its dependencies into a [Resolve]. Files in deps are
+// grouped by directory into packages.
+func resolveFiles(main []*astFile, deps [][]*astFile) (*Resolve, error) {
+	r := &resolver{
+		res:      &Resolve{},
+		resolved: make(map[*sourcePackage]*Package),
+		ifaces:   make(map[*Interface]int),
+	}
+
+	var order []*sourcePackage
+	for _, files := range deps {
+		pkgs, err := r.group(files)
+		if err != nil {
+			return nil, err
+		}
+		order = append(order, pkgs...)
+	}
+	pkgs, err := r.group(main)
+	if err != nil {
+		return nil, err
+	}
+	order = append(order, pkgs...)
+
+	sorted, err := r.sortPackages(order)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range sorted {
+		if err := r.resolvePackage(p); err != nil {
+			return nil, err
+		}
+	}
+	return r.res, nil
+}
+
+// group groups the packages declared in files into [sourcePackage] values.
+// The top-level package in each file is merged into a single package,
+// returned last, after any nested packages.
+func (r *resolver) group(files []*astFile) ([]*sourcePackage, error) {
+	var pkgs []*sourcePackage
+	var main *sourcePackage
+	for _, f := range files {
+		part := f.pkg
+		if part.name == nil {
+			continue
+		}
+		if main == nil {
+			main = &sourcePackage{name: *part.name}
+		} else if main.name.String() != part.name.String() {
+			return nil, fmt.Errorf("%s: package identifier `%s` does not match previous package name of `%s`", part.pos, part.name.String(), main.name.String())
+		}
+	}
+	for _, f := range files {
+		for _, part := range f.packages {
+			p := r.find(*part.name)
+			if p == nil {
+				p = &sourcePackage{name: *part.name}
+				r.sources = append(r.sources, p)
+				pkgs = append(pkgs, p)
+			}
+			p.parts = append(p.parts, part)
+			if part.docs.Contents != "" {
+				p.docs = part.docs
+			}
+		}
+		part := f.pkg
+		if part.name == nil && len(part.uses) == 0 && len(part.ifaces) == 0 && len(part.worlds) == 0 {
+			continue
+		}
+		if main == nil {
+			return nil, fmt.Errorf("%s: no `package` header was found in any WIT file for this package", part.pos)
+		}
+		main.parts = append(main.parts, part)
+		if part.docs.Contents != "" {
+			main.docs = part.docs
+		}
+	}
+	if main != nil {
+		if r.find(main.name) != nil {
+			return nil, fmt.Errorf("%s: package `%s` is defined more than once", main.parts[0].pos, main.name.String())
+		}
+		r.sources = append(r.sources, main)
+		pkgs = append(pkgs, main)
+	}
+	return pkgs, nil
+}
+
+// find finds a source package by name. If id has no version and there is
+// exactly one package with a matching unversioned name, it is returned.
+func (r *resolver) find(id Ident) *sourcePackage {
+	id.Extension = ""
+	name := id.String()
+	var match *sourcePackage
+	var n int
+	for _, p := range r.sources {
+		if p.name.String() == name {
+			return p
+		}
+		if id.Version == nil && p.name.UnversionedString() == name {
+			match = p
+			n++
+		}
+	}
+	if n == 1 {
+		return match
+	}
+	return nil
+}
+
+// packageDeps returns the foreign packages referenced by package p.
+func (r *resolver) packageDeps(p *sourcePackage) ([]*sourcePackage, error) {
+	var deps []*sourcePackage
+	add := func(path *astUsePath) error {
+		if path.pkg == nil {
+			return nil
+		}
+		dep := r.find(*path.pkg)
+		if dep == nil {
+			return fmt.Errorf("%s: package `%s` not found", path.pos, path.pkg.String())
+		}
+		if dep != p {
+			deps = append(deps, dep)
+		}
+		return nil
+	}
+	var err error
+	addItems := func(items []any) {
+		for _, item := range items {
+			if err != nil {
+				return
+			}
+			switch item := item.(type) {
+			case *astUse:
+				err = add(&item.path)
+			case *astInclude:
+				err = add(&item.path)
+			case *astExtern:
+				if item.path != nil {
+					err = add(item.path)
+				} else if item.iface != nil {
+					for _, item := range item.iface.items {
+						if u, ok := item.(*astUse); ok && err == nil {
+							err = add(&u.path)
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, part := range p.parts {
+		for _, u := range part.uses {
+			if err = add(&u.path); err != nil {
+				return nil, err
+			}
+		}
+		for _, i := range part.ifaces {
+			addItems(i.items)
+		}
+		for _, w := range part.worlds {
+			addItems(w.items)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return deps, nil
+}
+
+// sortPackages sorts packages so each package follows its dependencies.
+// Like wasm-tools, packages are visited in name order, and each package's
+// dependencies are visited depth-first in the order they are referenced.
+func (r *resolver) sortPackages(pkgs []*sourcePackage) ([]*sourcePackage, error) {
+	pkgs = slices.Clone(pkgs)
+	slices.SortStableFunc(pkgs, func(a, b *sourcePackage) int {
+		if c := cmp.Compare(a.name.Namespace, b.name.Namespace); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.name.Package, b.name.Package); c != 0 {
+			return c
+		}
+		switch {
+		case a.name.Version == nil && b.name.Version == nil:
+			return 0
+		case a.name.Version == nil:
+			return -1
+		case b.name.Version == nil:
+			return 1
+		}
+		return a.name.Version.Compare(*b.name.Version)
+	})
+
+	var sorted []*sourcePackage
+	done := make(map[*sourcePackage]bool)
+	visiting := make(map[*sourcePackage]bool)
+	var visit func(p *sourcePackage) error
+	visit = func(p *sourcePackage) error {
+		if done[p] {
+			return nil
+		}
+		if visiting[p] {
+			return fmt.Errorf("package `%s` depends on itself", p.name.String())
+		}
+		visiting[p] = true
+		deps, err := r.packageDeps(p)
+		if err != nil {
+			return err
+		}
+		for _, dep := range deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		visiting[p] = false
+		done[p] = true
+		sorted = append(sorted, p)
+		return nil
+	}
+	for _, p := range pkgs {
+		if err := visit(p); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// toposort sorts nodes topologically, preserving source order as much as possible.
+// Nodes are visited in order, and each node is emitted once all of its
+// dependencies have been emitted, matching the order produced by wasm-tools.
+func toposort[T comparable](nodes []T, kind string, name func(T) string, deps func(T) ([]T, error)) ([]T, error) {
+	remaining := make(map[T]int)
+	reverse := make(map[T][]T)
+	for _, n := range nodes {
+		ds, err := deps(n)
+		if err != nil {
+			return nil, err
+		}
+		remaining[n] = len(ds)
+		for _, d := range ds {
+			reverse[d] = append(reverse[d], n)
+		}
+	}
+
+	var sorted []T
+	visited := make(map[T]bool)
+	emitted := make(map[T]bool)
+	var emit func(n T)
+	emit = func(n T) {
+		if emitted[n] || !visited[n] || remaining[n] > 0 {
+			return
+		}
+		emitted[n] = true
+		sorted = append(sorted, n)
+		for _, r := range reverse[n] {
+			remaining[r]--
+			emit(r)
+		}
+	}
+	for _, n := range nodes {
+		visited[n] = true
+		emit(n)
+	}
+
+	for _, n := range nodes {
+		if !emitted[n] {
+			return nil, fmt.Errorf("%s `%s` depends on itself", kind, name(n))
+		}
+	}
+	return sorted, nil
+}
+
+type localInterface struct {
+	decl *astInterface
+	part *astPackage
+}
+
+type localWorld struct {
+	decl *astWorld
+	part *astPackage
+}
+
+func (r *resolver) resolvePackage(sp *sourcePackage) error {
+	pkg := &Package{Name: sp.name, Docs: sp.docs}
+	r.res.Packages = append(r.res.Packages, pkg)
+	r.resolved[sp] = pkg
+	r.anon = make(map[string]*TypeDef)
+	r.owns = make(map[*TypeDef]*TypeDef)
+	ntypes, ninterfaces, nworlds := len(r.res.TypeDefs), len(r.res.Interfaces), len(r.res.Worlds)
+	defer func() { r.placeOwns(ntypes, ninterfaces, nworlds) }()
+
+	// Collect local declarations
+	ifaces := make(map[string]*localInterface)
+	worlds := make(map[string]*localWorld)
+	var ifaceOrder []*localInterface
+	var worldOrder []*localWorld
+	for _, part := range sp.parts {
+		for _, i := range part.ifaces {
+			if ifaces[i.name] != nil || worlds[i.name] != nil {
+				return fmt.Errorf("%s: duplicate item named `%s`", i.pos, i.name)
+			}
+			li := &localInterface{i, part}
+			ifaces[i.name] = li
+			ifaceOrder = append(ifaceOrder, li)
+		}
+		for _, w := range part.worlds {
+			if ifaces[w.name] != nil || worlds[w.name] != nil {
+				return fmt.Errorf("%s: duplicate item named `%s`", w.pos, w.name)
+			}
+			lw := &localWorld{w, part}
+			worlds[w.name] = lw
+			worldOrder = append(worldOrder, lw)
+		}
+	}
+
+	// localName returns the local name referred to by path, or "" if path refers to a foreign package.
+	var localName func(part *astPackage, path *astUsePath) string
+	localName = func(part *astPackage, path *astUsePath) string {
+		if path.pkg == nil {
+			for _, u := range part.uses {
+				if u.as == path.name {
+					return localName(part, &u.path)
+				}
+			}
+			return path.name
+		}
+		if r.find(*path.pkg) == sp {
+			return path.name
+		}
+		return ""
+	}
+
+	// Sort and resolve interfaces
+	sortedIfaces, err := toposort(ifaceOrder, "interface", func(li *localInterface) string { return li.decl.name },
+		func(li *localInterface) ([]*localInterface, error) {
+			var deps []*localInterface
+			for _, item := range li.decl.items {
+				if u, ok := item.(*astUse); ok {
+					if name := localName(li.part, &u.path); name != "" {
+						dep := ifaces[name]
+						if dep == nil {
+							return nil, fmt.Errorf("%s: interface `%s` not found", u.path.pos, u.path.String())
+						}
+						deps = append(deps, dep)
+					}
+				}
+			}
+			return deps, nil
+		})
+	if err != nil {
+		return err
+	}
+	for _, li := range sortedIfaces {
+		name := li.decl.name
+		i := &Interface{
+			Name:      &name,
+			Package:   pkg,
+			Stability: li.decl.attrs.stability,
+			Docs:      li.decl.docs,
+		}
+		r.addInterface(i)
+		pkg.Interfaces.Set(name, i)
+		sc := &scope{pkg: sp, part: li.part, owner: i, types: make(map[string]*TypeDef)}
+		if err := r.resolveInterface(sc, i, li.decl.items); err != nil {
+			return err
+		}
+	}
+
+	// Sort and resolve worlds
+	sortedWorlds, err := toposort(worldOrder, "world", func(lw *localWorld) string { return lw.decl.name },
+		func(lw *localWorld) ([]*localWorld, error) {
+			var deps []*localWorld
+			for _, item := range lw.decl.items {
+				if inc, ok := item.(*astInclude); ok {
+					if name := localName(lw.part, &inc.path); name != "" {
+						dep := worlds[name]
+						if dep == nil {
+							return nil, fmt.Errorf("%s: world `%s` not found", inc.path.pos, inc.path.String())
+						}
+						deps = append(deps, dep)
+					}
+				}
+			}
+			return deps, nil
+		})
+	if err != nil {
+		return err
+	}
+	for _, lw := range sortedWorlds {
+		w := &World{
+			Name:      lw.decl.name,
+			Package:   pkg,
+			Stability: lw.decl.attrs.stability,
+			Docs:      lw.decl.docs,
+		}
+		r.res.Worlds = append(r.res.Worlds, w)
+		pkg.Worlds.Set(w.Name, w)
+		sc := &scope{pkg: sp, part: lw.part, owner: w, types: make(map[string]*TypeDef)}
+		if err := r.resolveWorld(sc, w, lw.decl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *resolver) addInterface(i *Interface) {
+	r.ifaces[i] = len(r.res.Interfaces)
+	r.res.Interfaces = append(r.res.Interfaces, i)
+}
+
+// interfaceKey returns the world key for a named [Interface], matching wasm-tools.
+func (r *resolver) interfaceKey(i *Interface) string {
+	return "interface-" + strconv.Itoa(r.ifaces[i])
+}
+
+// lookupInterface resolves path to an [Interface] from scope sc.
+func (r *resolver) lookupInterface(sc *scope, path *astUsePath) (*Interface, error) {
+	if path.pkg == nil {
+		for _, u := range sc.part.uses {
+			if u.as == path.name {
+				return r.lookupInterface(sc, &u.path)
+			}
+		}
+		if i := r.resolved[sc.pkg].Interfaces.Get(path.name); i != nil {
+			return i, nil
+		}
+		return nil, fmt.Errorf("%s: interface or world `%s` not found in package", path.pos, path.name)
+	}
+	sp := r.find(*path.pkg)
+	pkg := r.resolved[sp]
+	if pkg == nil {
+		return nil, fmt.Errorf("%s: package `%s` not found", path.pos, path.pkg.String())
+	}
+	if i := pkg.Interfaces.Get(path.name); i != nil {
+		return i, nil
+	}
+	return nil, fmt.Errorf("%s: interface `%s` not found in package `%s`", path.pos, path.name, pkg.Name.String())
+}
+
+// lookupWorld resolves path to a [World] from scope sc.
+func (r *resolver) lookupWorld(sc *scope, path *astUsePath) (*World, error) {
+	pkg := r.resolved[sc.pkg]
+	if path.pkg != nil {
+		pkg = r.resolved[r.find(*path.pkg)]
+		if pkg == nil {
+			return nil, fmt.Errorf("%s: package `%s` not found", path.pos, path.pkg.String())
+		}
+	} else {
+		for _, u := range sc.part.uses {
+			if u.as == path.name {
+				return r.lookupWorld(sc, &u.path)
+			}
+		}
+	}
+	if w := pkg.Worlds.Get(path.name); w != nil {
+		return w, nil
+	}
+	return nil, fmt.Errorf("%s: world `%s` not found in package `%s`", path.pos, path.name, pkg.Name.String())
+}
+
+// define adds a named [TypeDef] to scope sc.
+func (r *resolver) define(sc *scope, pos position, name string, td *TypeDef) error {
+	if sc.types[name] != nil {
+		return fmt.Errorf("%s: type `%s` is defined more than once", pos, name)
+	}
+	sc.types[name] = td
+	r.res.TypeDefs = append(r.res.TypeDefs, td)
+	return nil
+}
+
+// resolveUses resolves use statements, returning the types they declare in order.
+func (r *resolver) resolveUses(sc *scope, items []any) ([]*TypeDef, error) {
+	var tds []*TypeDef
+	for _, item := range items {
+		u, ok := item.(*astUse)
+		if !ok {
+			continue
+		}
+		i, err := r.lookupInterface(sc, &u.path)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range u.names {
+			t, ok := i.TypeDefs.GetOK(n.name)
+			if !ok {
+				return nil, fmt.Errorf("%s: type `%s` not defined in interface `%s`", n.pos, n.name, u.path.String())
+			}
+			name := n.as
+			td := &TypeDef{Name: &name, Kind: t, Owner: sc.owner, Stability: u.attrs.stability}
+			if err := r.define(sc, n.pos, name, td); err != nil {
+				return nil, err
+			}
+			tds = append(tds, td)
+		}
+	}
+	return tds, nil
+}
+
+// resolveTypeDecls resolves named type declarations in topological order,
+// returning the declared types in that order.
+func (r *resolver) resolveTypeDecls(sc *scope, items []any) ([]*TypeDef, error) {
+	decls := make(map[string]*astTypeDecl)
+	var order []*astTypeDecl
+	for _, item := range items {
+		if d, ok := item.(*astTypeDecl); ok {
+			if decls[d.name] != nil || sc.types[d.name] != nil {
+				return nil, fmt.Errorf("%s: type `%s` is defined more than once", d.pos, d.name)
+			}
+			decls[d.name] = d
+			order = append(order, d)
+		}
+	}
+	sorted, err := toposort(order, "type", func(d *astTypeDecl) string { return d.name },
+		func(d *astTypeDecl) ([]*astTypeDecl, error) {
+			var deps []*astTypeDecl
+			var walk func(t *astType)
+			walk = func(t *astType) {
+				if t == nil {
+					return
+				}
+				if t.kind == "name" && decls[t.name] != nil {
+					deps = append(deps, decls[t.name])
+				}
+				for _, a := range t.args {
+					walk(a)
+				}
+			}
+			walk(d.alias)
+			for _, f := range d.fields {
+				walk(f.typ)
+			}
+			return deps, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range sorted {
+		kind, err := r.resolveKind(sc, d)
+		if err != nil {
+			return nil, err
+		}
+		name := d.name
+		td := &TypeDef{Name: &name, Kind: kind, Owner: sc.owner, Stability: d.attrs.stability, Docs: d.docs}
+		if err := r.define(sc, d.pos, name, td); err != nil {
+			return nil, err
+		}
+	}
+
+	tds := make([]*TypeDef, len(sorted))
+	for i, d := range sorted {
+		tds[i] = sc.types[d.name]
+	}
+	return tds, nil
+}
+
+// resolveKind resolves the [TypeDefKind] of a named type declaration.
+func (r *resolver) resolveKind(sc *scope, d *astTypeDecl) (TypeDefKind, error) {
+	switch d.kind {
+	case "type":
+		return r.resolveAlias(sc, d.alias)
+	case "resource":
+		return &Resource{}, nil
+	case "record":
+		rec := &Record{}
+		for _, f := range d.fields {
+			t, err := r.resolveType(sc, f.typ)
+			if err != nil {
+				return nil, err
+			}
+			rec.Fields = append(rec.Fields, Field{Name: f.name, Type: t, Docs: f.docs})
+		}
+		return rec, nil
+	case "variant":
+		v := &Variant{}
+		for _, f := range d.fields {
+			var t Type
+			if f.typ != nil {
+				var err error
+				t, err = r.resolveType(sc, f.typ)
+				if err != nil {
+					return nil, err
+				}
+			}
+			v.Cases = append(v.Cases, Case{Name: f.name, Type: t, Docs: f.docs})
+		}
+		return v, nil
+	case "enum":
+		e := &Enum{}
+		for _, f := range d.fields {
+			e.Cases = append(e.Cases, EnumCase{Name: f.name, Docs: f.docs})
+		}
+		return e, nil
+	case "flags":
+		flags := &Flags{}
+		for _, f := range d.fields {
+			flags.Flags = append(flags.Flags, Flag{Name: f.name, Docs: f.docs})
+		}
+		return flags, nil
+	}
+	return nil, fmt.Errorf("%s: unknown type declaration %s", d.pos, d.kind)
+}
+
+// resolveType resolves a type expression into a [Type].
+// Anonymous types are deduplicated within a package.
+func (r *resolver) resolveType(sc *scope, t *astType) (Type, error) {
+	if t.kind == "name" {
+		td, err := r.lookupType(sc, t)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := td.Root().Kind.(*Resource); ok {
+			return r.ownType(td), nil
+		}
+		return td, nil
+	}
+	kind, err := r.resolveAnonKind(sc, t)
+	if err != nil {
+		return nil, err
+	}
+	switch kind := kind.(type) {
+	case nil:
+		return ParseType(t.kind)
+	case *Own:
+		return r.ownType(kind.Type), nil
+	}
+	return r.anonType(kind), nil
+}
+
+// resolveAlias resolves the [TypeDefKind] of a type alias.
+// Unlike [resolver.resolveType], a named resource is not converted to a handle.
+func (r *resolver) resolveAlias(sc *scope, t *astType) (TypeDefKind, error) {
+	if t.kind == "name" {
+		return r.lookupType(sc, t)
+	}
+	kind, err := r.resolveAnonKind(sc, t)
+	if err != nil {
+		return nil, err
+	}
+	if kind == nil {
+		return ParseType(t.kind)
+	}
+	return kind, nil
+}
+
+func (r *resolver) lookupType(sc *scope, t *astType) (*TypeDef, error) {
+	td := sc.types[t.name]
+	if td == nil {
+		return nil, fmt.Errorf("%s: type `%s` does not exist", t.pos, t.name)
+	}
+	return td, nil
+}
+
+// resolveAnonKind resolves the [TypeDefKind] of an anonymous type expression.
+// It returns nil if t is a primitive type.
+func (r *resolver) resolveAnonKind(sc *scope, t *astType) (TypeDefKind, error) {
+	switch t.kind {
+	case "own", "borrow":
+		td, err := r.lookupType(sc, t.args[0])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := td.Root().Kind.(*Resource); !ok {
+			return nil, fmt.Errorf("%s: type `%s` used in a handle must be a resource", t.args[0].pos, td.TypeName())
+		}
+		if t.kind == "own" {
+			return &Own{Type: td}, nil
+		}
+		return &Borrow{Type: td}, nil
+	case "error-context":
+		return &ErrorContext{}, nil
+	case "list", "option", "result", "tuple", "future", "stream":
+	default:
+		return nil, nil
+	}
+
+	args := make([]Type, len(t.args))
+	for i, a := range t.args {
+		if a == nil {
+			continue
+		}
+		var err error
+		args[i], err = r.resolveType(sc, a)
+		if err != nil {
+			return nil, err
+		}
+	}
+	arg := func(i int) Type {
+		if i < len(args) {
+			return args[i]
+		}
+		return nil
+	}
+	switch t.kind {
+	case "list":
+		return &List{Type: args[0]}, nil
+	case "option":
+		return &Option{Type: args[0]}, nil
+	case "result":
+		return &Result{OK: arg(0), Err: arg(1)}, nil
+	case "tuple":
+		return &Tuple{Types: args}, nil
+	case "future":
+		return &Future{Type: arg(0)}, nil
+	default: // stream
+		return &Stream{Type: arg(0)}, nil
+	}
+}
+
+// anonType returns an anonymous [TypeDef] for kind, reusing an existing
+// structurally identical type in the current package if present.
+func (r *resolver) anonType(kind TypeDefKind) *TypeDef {
+	key := anonKey(kind)
+	if td := r.anon[key]; td != nil {
+		return td
+	}
+	td := &TypeDef{Kind: kind}
+	r.anon[key] = td
+	r.res.TypeDefs = append(r.res.TypeDefs, td)
+	return td
+}
+
+// ownType returns an anonymous own handle [TypeDef] for resource td.
+// Like wasm-tools, own handles are allocated lazily by [resolver.placeOwns]
+// when first referenced by a type or function in the current package.
+func (r *resolver) ownType(td *TypeDef) *TypeDef {
+	if own := r.owns[td]; own != nil {
+		return own
+	}
+	own := &TypeDef{Kind: &Own{Type: td}}
+	r.owns[td] = own
+	r.anon[anonKey(own.Kind)] = own
+	return own
+}
+
+// placeOwns inserts the own handles created while resolving the current package
+// into res.TypeDefs. Each handle is placed immediately before the first type that
+// refers to it, or after all types if it is first referred to by a function.
+// The arguments are the number of types, interfaces, and worlds in the [Resolve]
+// before the current package was resolved.
+func (r *resolver) placeOwns(types, interfaces, worlds int) {
+	tds := r.res.TypeDefs[types:]
+	r.res.TypeDefs = append([]*TypeDef(nil), r.res.TypeDefs[:types]...)
+	placed := make(map[*TypeDef]bool)
+	place := func(t Type) {
+		td, ok := t.(*TypeDef)
+		if !ok || placed[td] {
+			return
+		}
+		if own, ok := td.Kind.(*Own); ok && r.owns[own.Type] == td {
+			placed[td] = true
+			r.res.TypeDefs = append(r.res.TypeDefs, td)
+		}
+	}
+	for _, td := range tds {
+		for _, t := range kindTypes(td.Kind) {
+			place(t)
+		}
+		r.res.TypeDefs = append(r.res.TypeDefs, td)
+	}
+	placeFunc := func(f *Function) {
+		for _, p := range f.Params {
+			place(p.Type)
+		}
+		for _, p := range f.Results {
+			place(p.Type)
+		}
+	}
+	for _, i := range r.res.Interfaces[interfaces:] {
+		i.Functions.All()(func(_ string, f *Function) bool {
+			placeFunc(f)
+			return true
+		})
+	}
+	for _, w := range r.res.Worlds[worlds:] {
+		w.AllFunctions()(func(f *Function) bool {
+			placeFunc(f)
+			return true
+		})
+	}
+}
+
+// kindTypes returns the types directly referenced by kind, in order.
+func kindTypes(kind TypeDefKind) []Type {
+	switch kind := kind.(type) {
+	case *Record:
+		types := make([]Type, len(kind.Fields))
+		for i, f := range kind.Fields {
+			types[i] = f.Type
+		}
+		return types
+	case *Variant:
+		types := make([]Type, len(kind.Cases))
+		for i, c := range kind.Cases {
+			types[i] = c.Type
+		}
+		return types
+	case *Tuple:
+		return kind.Types
+	case *List:
+		return []Type{kind.Type}
+	case *Option:
+		return []Type{kind.Type}
+	case *Result:
+		return []Type{kind.OK, kind.Err}
+	case *Future:
+		return []Type{kind.Type}
+	case *Stream:
+		return []Type{kind.Type}
+	}
+	return nil
+}
+
+func anonKey(kind TypeDefKind) string {
+	key := func(t Type) string {
+		switch t := t.(type) {
+		case nil:
+			return "_"
+		case *TypeDef:
+			return fmt.Sprintf("%p", t)
+		}
+		return t.WITKind()
+	}
+	switch kind := kind.(type) {
+	case *Own:
+		return "own<" + key(kind.Type) + ">"
+	case *Borrow:
+		return "borrow<" + key(kind.Type) + ">"
+	case *ErrorContext:
+		return "error-context"
+	case *List:
+		return "list<" + key(kind.Type) + ">"
+	case *Option:
+		return "option<" + key(kind.Type) + ">"
+	case *Result:
+		return "result<" + key(kind.OK) + "," + key(kind.Err) + ">"
+	case *Future:
+		return "future<" + key(kind.Type) + ">"
+	case *Stream:
+		return "stream<" + key(kind.Type) + ">"
+	case *Tuple:
+		keys := make([]string, len(kind.Types))
+		for i, t := range kind.Types {
+			keys[i] = key(t)
+		}
+		return "tuple<" + strings.Join(keys, ",") + ">"
+	}
+	panic(fmt.Sprintf("BUG: unexpected anonymous type %T", kind))
+}
+
+// resolveInterface resolves the use statements, types, and functions in an [Interface].
+func (r *resolver) resolveInterface(sc *scope, i *Interface, items []any) error {
+	uses, err := r.resolveUses(sc, items)
+	if err != nil {
+		return err
+	}
+	decls, err := r.resolveTypeDecls(sc, items)
+	if err != nil {
+		return err
+	}
+	for _, td := range append(uses, decls...) {
+		i.TypeDefs.Set(*td.Name, td)
+	}
+	for _, item := range items {
+		var funcs []*astFunc
+		var self *TypeDef
+		switch item := item.(type) {
+		case *astFunc:
+			funcs = []*astFunc{item}
+		case *astTypeDecl:
+			funcs = item.funcs
+			self = sc.types[item.name]
+		}
+		for _, af := range funcs {
+			f, err := r.resolveFunction(sc, af, self)
+			if err != nil {
+				return err
+			}
+			if _, ok := i.Functions.GetOK(f.Name); ok {
+				return fmt.Errorf("%s: function `%s` is defined more than once", af.pos, af.name)
+			}
+			i.Functions.Set(f.Name, f)
+		}
+	}
+	return nil
+}
+
+// resolveFunction resolves a function declaration. If self is non-nil,
+// the function is a constructor, method, or static function of resource self.
+func (r *resolver) resolveFunction(sc *scope, af *astFunc, self *TypeDef) (*Function, error) {
+	f := &Function{Name: af.name, Async: af.async, Stability: af.attrs.stability, Docs: af.docs}
+	switch af.kind {
+	case "freestanding":
+		f.Kind = &Freestanding{}
+	case "constructor":
+		f.Name = "[constructor]" + *self.Name
+		f.Kind = &Constructor{Type: self}
+	case "method":
+		f.Name = "[method]" + *self.Name + "." + af.name
+		f.Kind = &Method{Type: self}
+		f.Params = append(f.Params, Param{Name: "self", Type: r.anonType(&Borrow{Type: self})})
+	case "static":
+		f.Name = "[static]" + *self.Name + "." + af.name
+		f.Kind = &Static{Type: self}
+	}
+	names := make(map[string]bool)
+	for _, p := range af.params {
+		if names[p.name] {
+			return nil, fmt.Errorf("%s: param `%s` is defined more than once", p.pos, p.name)
+		}
+		names[p.name] = true
+		t, err := r.resolveType(sc, p.typ)
+		if err != nil {
+			return nil, err
+		}
+		f.Params = append(f.Params, Param{Name: p.name, Type: t})
+	}
+	for _, p := range af.results {
+		t, err := r.resolveType(sc, p.typ)
+		if err != nil {
+			return nil, err
+		}
+		f.Results = append(f.Results, Param{Name: p.name, Type: t})
+	}
+	if af.kind == "constructor" && len(f.Results) == 0 {
+		f.Results = []Param{{Type: r.ownType(self)}}
+	}
+	return f, nil
+}
+
+// resolveWorld resolves the items in a [World], then elaborates it.
+func (r *resolver) resolveWorld(sc *scope, w *World, decl *astWorld) error {
+	uses, err := r.resolveUses(sc, decl.items)
+	if err != nil {
+		return err
+	}
+	decls, err := r.resolveTypeDecls(sc, decl.items)
+	if err != nil {
+		return err
+	}
+	for _, td := range append(uses, decls...) {
+		w.Imports.Set(*td.Name, td)
+	}
+
+	var includes []*astInclude
+	for _, item := range decl.items {
+		switch item := item.(type) {
+		case *astInclude:
+			includes = append(includes, item)
+		case *astExtern:
+			items := &w.Imports
+			if item.export {
+				items = &w.Exports
+			}
+			var key string
+			var v WorldItem
+			switch {
+			case item.fn != nil:
+				f, err := r.resolveFunction(sc, item.fn, nil)
+				if err != nil {
+					return err
+				}
+				key, v = item.name, f
+			case item.iface != nil:
+				i := &Interface{Package: r.resolved[sc.pkg], Docs: item.docs}
+				r.addInterface(i)
+				isc := &scope{pkg: sc.pkg, part: sc.part, owner: i, types: make(map[string]*TypeDef)}
+				if err := r.resolveInterface(isc, i, item.iface.items); err != nil {
+					return err
+				}
+				key, v = item.name, &InterfaceRef{Interface: i, Stability: item.attrs.stability}
+			default:
+				i, err := r.lookupInterface(sc, item.path)
+				if err != nil {
+					return err
+				}
+				key, v = r.interfaceKey(i), &InterfaceRef{Interface: i, Stability: item.attrs.stability}
+			}
+			if _, ok := items.GetOK(key); ok {
+				return fmt.Errorf("%s: %s `%s` conflicts with a prior %s of the same name", item.pos, direction(item.export), key, direction(item.export))
+			}
+			if !item.export && sc.types[key] != nil {
+				return fmt.Errorf("%s: import `%s` conflicts with a type of the same name", item.pos, key)
+			}
+			items.Set(key, v)
+		}
+	}
+
+	// Resource functions are imported after other imports and exports.
+	for _, item := range decl.items {
+		d, ok := item.(*astTypeDecl)
+		if !ok {
+			continue
+		}
+		for _, af := range d.funcs {
+			f, err := r.resolveFunction(sc, af, sc.types[d.name])
+			if err != nil {
+				return err
+			}
+			if _, ok := w.Imports.GetOK(f.Name); ok {
+				return fmt.Errorf("%s: import `%s` conflicts with a prior import of the same name", af.pos, f.Name)
+			}
+			w.Imports.Set(f.Name, f)
+		}
+	}
+
+	for _, inc := range includes {
+		if err := r.include(sc, w, inc); err != nil {
+			return err
+		}
+	}
+
+	return r.elaborate(w)
+}
+
+func direction(export bool) string {
+	if export {
+		return "export"
+	}
+	return "import"
+}
+
+// include merges the imports and exports of another world into [World] w.
+func (r *resolver) include(sc *scope, w *World, inc *astInclude) error {
+	src, err := r.lookupWorld(sc, &inc.path)
+	if err != nil {
+		return err
+	}
+	used := make(map[string]bool)
+	merge := func(dst, srcItems *ordered.Map[string, WorldItem], export bool) error {
+		var err error
+		srcItems.All()(func(key string, v WorldItem) bool {
+			if ref, ok := v.(*InterfaceRef); ok && ref.Interface.Name != nil {
+				key = r.interfaceKey(ref.Interface)
+				if _, ok := dst.GetOK(key); !ok {
+					dst.Set(key, v)
+				}
+				return true
+			}
+			if to, ok := inc.with[key]; ok {
+				used[key] = true
+				key = to
+			}
+			if prev, ok := dst.GetOK(key); ok && prev != v {
+				err = fmt.Errorf("%s: %s of `%s` from world `%s` conflicts with a prior %s of the same name", inc.pos, direction(export), key, src.Name, direction(export))
+				return false
+			}
+			dst.Set(key, v)
+			return true
+		})
+		return err
+	}
+	if err := merge(&w.Imports, &src.Imports, false); err != nil {
+		return err
+	}
+	if err := merge(&w.Exports, &src.Exports, true); err != nil {
+		return err
+	}
+	for from := range inc.with {
+		if !used[from] {
+			return fmt.Errorf("%s: `%s` does not exist in world `%s`", inc.pos, from, src.Name)
+		}
+	}
+	return nil
+}
+
+// elaborate adds the transitive interface dependencies of the imports and
+// exports of [World] w as imports, in topological order.
+func (r *resolver) elaborate(w *World) error {
+	var imports ordered.Map[string, WorldItem]
+	var addImport func(i *Interface)
+	addImport = func(i *Interface) {
+		key := r.interfaceKey(i)
+		if _, ok := imports.GetOK(key); ok {
+			return
+		}
+		for _, dep := range interfaceDeps(i) {
+			addImport(dep)
+		}
+		imports.Set(key, &InterfaceRef{Interface: i})
+	}
+
+	w.Imports.All()(func(key string, v WorldItem) bool {
+		switch v := v.(type) {
+		case *InterfaceRef:
+			if _, ok := imports.GetOK(key); ok {
+				break
+			}
+			for _, dep := range interfaceDeps(v.Interface) {
+				addImport(dep)
+			}
+			imports.Set(key, v)
+		case *TypeDef:
+			if dep := typeDep(v); dep != nil {
+				addImport(dep)
+			}
+			imports.Set(key, v)
+		default:
+			imports.Set(key, v)
+		}
+		return true
+	})
+
+	// Exported functions precede exported interfaces. Exported interfaces
+	// import any dependencies that are not also exported.
+	var exports ordered.Map[string, WorldItem]
+	exported := make(map[*Interface]string)
+	w.Exports.All()(func(key string, v WorldItem) bool {
+		if ref, ok := v.(*InterfaceRef); ok {
+			exported[ref.Interface] = key
+		}
+		return true
+	})
+	required := make(map[*Interface]bool)
+	var addExport func(i *Interface, key string, v WorldItem, export bool) bool
+	addExport = func(i *Interface, key string, v WorldItem, export bool) bool {
+		if _, ok := exports.GetOK(key); ok {
+			return export
+		}
+		if !export && required[i] {
+			return true
+		}
+		for _, dep := range interfaceDeps(i) {
+			depKey, isExported := exported[dep]
+			if !isExported {
+				depKey = r.interfaceKey(dep)
+			}
+			if !addExport(dep, depKey, w.Exports.Get(depKey), export && isExported) {
+				return false
+			}
+		}
+		if export {
+			if required[i] {
+				return false
+			}
+			exports.Set(key, v)
+		} else {
+			required[i] = true
+			if _, ok := imports.GetOK(key); !ok {
+				imports.Set(key, &InterfaceRef{Interface: i})
+			}
+		}
+		return true
+	}
+	w.Exports.All()(func(key string, v WorldItem) bool {
+		if _, ok := v.(*InterfaceRef); !ok {
+			exports.Set(key, v)
+		}
+		return true
+	})
+	var err error
+	w.Exports.All()(func(key string, v WorldItem) bool {
+		if ref, ok := v.(*InterfaceRef); ok && !addExport(ref.Interface, key, v, true) {
+			err = fmt.Errorf("world `%s` exports interface `%s` that depends on an interface that is both imported and exported", w.Name, key)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Imports = imports
+	w.Exports = exports
+	return nil
+}
+
+// interfaceDeps returns the interfaces that [Interface] i directly depends on
+// through use statements, in order.
+func interfaceDeps(i *Interface) []*Interface {
+	var deps []*Interface
+	i.TypeDefs.All()(func(_ string, td *TypeDef) bool {
+		if dep := typeDep(td); dep != nil {
+			deps = append(deps, dep)
+		}
+		return true
+	})
+	return deps
+}
+
+// typeDep returns the [Interface] that [TypeDef] td was imported from with
+// a use statement, or nil.
+func typeDep(td *TypeDef) *Interface {
+	parent, ok := td.Kind.(*TypeDef)
+	if !ok || parent.Owner == td.Owner {
+		return nil
+	}
+	i, _ := parent.Owner.(*Interface)
+	return i
+}
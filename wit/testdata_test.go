@@ -66,6 +66,61 @@ func TestGoldenWITFiles(t *testing.T) {
 	}
 }
 
+func TestParseWIT(t *testing.T) {
+	err := relpath.Walk(testdataPath, func(path string) error {
+		golden := path + ".json.golden.wit"
+		if _, err := os.Stat(golden); err != nil {
+			return nil
+		}
+		t.Run(path, func(t *testing.T) {
+			res, err := LoadWIT(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			compareOrWrite(t, path, golden, res.WIT(nil, ""))
+		})
+		return nil
+	}, "*.wit")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEncodeJSON(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			err = EncodeJSON(&buf, res)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != string(want) {
+				dmp := diffmatchpatch.New()
+				dmp.PatchMargin = 3
+				diffs := dmp.DiffMain(string(want), got, false)
+				t.Errorf("EncodeJSON for %s did not match:\n%v", path, dmp.DiffPrettyText(diffs))
+			}
+
+			res2, err := DecodeJSON(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := res2.WIT(nil, ""), res.WIT(nil, ""); got != want {
+				t.Errorf("round-trip WIT for %s through EncodeJSON did not match", path)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestGoldenWITRoundTrip(t *testing.T) {
 	if testing.Short() {
 		// t.Skip is not available in TinyGo, requires runtime.Goexit()
@@ -79,6 +134,10 @@ func TestGoldenWITRoundTrip(t *testing.T) {
 	}
 
 	err = loadTestdata(func(path string, res *Resolve) error {
+		// TODO: remove this when the vendored wasm-tools supports async functions.
+		if HasAsync(res) {
+			return nil
+		}
 		data := res.WIT(nil, "")
 		t.Run(path, func(t *testing.T) {
 			args := []string{"component", "wit", "-j", "--all-features"}
@@ -1057,6 +1057,10 @@ func (f *Function) WIT(ctx Node, name string) string {
 		b.WriteString("export ")
 	}
 	var isConstructor, isMethod bool
+	async := ""
+	if f.Async {
+		async = "async "
+	}
 	switch f.Kind.(type) {
 	case *Constructor:
 		// constructor is a keyword in WIT, but should not be escaped as a function name
@@ -1065,11 +1069,11 @@ func (f *Function) WIT(ctx Node, name string) string {
 		isConstructor = true
 	case *Freestanding, *Method:
 		b.WriteString(escape(name))
-		b.WriteString(": func(")
+		b.WriteString(": " + async + "func(")
 		isMethod = true
 	case *Static:
 		b.WriteString(escape(name))
-		b.WriteString(": static func(")
+		b.WriteString(": static " + async + "func(")
 	}
 	b.WriteString(paramsWIT(f.Params, isMethod))
 	b.WriteRune(')')